@@ -0,0 +1,55 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"time"
+
+	"simple-pos/internal/platform/database"
+	"simple-pos/internal/platform/events"
+	"simple-pos/internal/repositories/gorm_repo"
+	"simple-pos/internal/services"
+	"simple-pos/pkg/config"
+	"simple-pos/pkg/logger"
+)
+
+// Recalculates the product sales stats of closed work periods, e.g. after refunds or an upgrade
+// Kapanmış çalışma dönemlerinin ürün satış istatistiklerini yeniden hesaplar, örn. iadelerden veya güncellemeden sonra
+func main() {
+	from := flag.String("from", "2000-01-01", "first day of the periods to rebuild (YYYY-MM-DD)")
+	to := flag.String("to", time.Now().Format("2006-01-02"), "last day of the periods to rebuild (YYYY-MM-DD)")
+	flag.Parse()
+
+	start, err := time.ParseInLocation("2006-01-02", *from, time.Local)
+	if err != nil {
+		log.Fatal("Invalid -from date: ", err)
+	}
+	end, err := time.ParseInLocation("2006-01-02", *to, time.Local)
+	if err != nil {
+		log.Fatal("Invalid -to date: ", err)
+	}
+	end = end.Add(24 * time.Hour)
+
+	cfg := config.LoadConfig()
+	logger.InitLogger(cfg)
+	database.Connect(cfg.DBPath)
+	database.Migrate()
+
+	db := database.DB
+	management := services.NewManagementService(
+		gorm_repo.NewWorkPeriodRepository(db),
+		gorm_repo.NewOrderRepository(db),
+		gorm_repo.NewPaymentRepository(db),
+		gorm_repo.NewTransactionRepository(db),
+		gorm_repo.NewProductStatRepository(db),
+		db,
+		events.NewBus(),
+		services.NewAuditService(gorm_repo.NewAuditRepository(db)),
+	)
+
+	periods, rows, err := management.RebuildProductStats(start, end)
+	if err != nil {
+		log.Fatal("Failed to rebuild product stats: ", err)
+	}
+	log.Printf("Rebuilt product stats of %d work periods (%d rows)", periods, rows)
+}
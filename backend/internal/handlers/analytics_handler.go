@@ -1,9 +1,12 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
+	"simple-pos/internal/models"
 	"simple-pos/internal/services"
 	"simple-pos/pkg/utils"
+	"strconv"
 	"strings"
 	"time"
 
@@ -43,13 +46,202 @@ func (h *AnalyticsHandler) GetDailyReport(c *fiber.Ctx) error {
 	return utils.Success(c, fiber.StatusOK, utils.CodeOK, "Daily report retrieved", report)
 }
 
-// GetReportHistory handles GET /analytics/history
-// Geçmiş raporları getirir
+// GetReportHistory handles GET /analytics/history?page=...&limit=...
+// Geçmiş raporları sayfa sayfa getirir
 func (h *AnalyticsHandler) GetReportHistory(c *fiber.Ctx) error {
-	reports, err := h.service.GetReportHistory()
+	reports, total, err := h.service.GetReportHistory(c.QueryInt("page", 1), c.QueryInt("limit", 50))
 	if err != nil {
 		return utils.InternalError(c, utils.CodeInternalError, "Failed to retrieve report history")
 	}
 
+	// The body stays a plain list for older clients, the total travels in a header
+	// Gövde eski istemciler için düz liste kalır, toplam başlıkta taşınır
+	c.Set("X-Total-Count", strconv.FormatInt(total, 10))
+
 	return utils.Success(c, fiber.StatusOK, utils.CodeOK, "Report history retrieved", reports)
 }
+
+// GetRangeReport handles GET /analytics/range?start_date=...&end_date=...&group_by=day|week|month&compare=none|previous|last_year
+// Tarih aralığı raporunu önceki dönemle karşılaştırmalı getirir
+func (h *AnalyticsHandler) GetRangeReport(c *fiber.Ctx) error {
+	startDate, endDate, err := parseDateRange(c)
+	if err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, err.Error())
+	}
+
+	report, err := h.service.GetRangeReport(startDate, endDate, c.Query("group_by", services.ReportGroupDay), c.Query("compare", services.ReportComparePrevious))
+	if err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, err.Error())
+	}
+
+	return utils.Success(c, fiber.StatusOK, utils.CodeOK, "Range report retrieved", report)
+}
+
+// GetModifierUsage handles GET /analytics/modifiers?start_date=...&end_date=...
+// Opsiyon kullanım istatistiklerini getirir
+func (h *AnalyticsHandler) GetModifierUsage(c *fiber.Ctx) error {
+	startDate, endDate, err := parseDateRange(c)
+	if err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, err.Error())
+	}
+
+	stats, err := h.service.GetModifierUsage(startDate, endDate)
+	if err != nil {
+		return utils.InternalError(c, utils.CodeInternalError, "Failed to retrieve modifier usage")
+	}
+
+	return utils.Success(c, fiber.StatusOK, utils.CodeOK, "Modifier usage retrieved", stats)
+}
+
+// GetSalesHeatmap handles GET /analytics/heatmap?start_date=...&end_date=...
+// Saatlik ve günlük satış yoğunluğunu getirir
+func (h *AnalyticsHandler) GetSalesHeatmap(c *fiber.Ctx) error {
+	startDate, endDate, err := parseDateRange(c)
+	if err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, err.Error())
+	}
+
+	heatmap, err := h.service.GetSalesHeatmap(startDate, endDate)
+	if err != nil {
+		return utils.InternalError(c, utils.CodeInternalError, "Failed to retrieve sales heatmap")
+	}
+
+	return utils.Success(c, fiber.StatusOK, utils.CodeOK, "Sales heatmap retrieved", heatmap)
+}
+
+// GetWaiterPerformance handles GET /analytics/waiters?start_date=...&end_date=...
+// Garson performansını getirir
+func (h *AnalyticsHandler) GetWaiterPerformance(c *fiber.Ctx) error {
+	startDate, endDate, err := parseDateRange(c)
+	if err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, err.Error())
+	}
+
+	stats, err := h.service.GetWaiterPerformance(startDate, endDate)
+	if err != nil {
+		return utils.InternalError(c, utils.CodeInternalError, "Failed to retrieve waiter performance")
+	}
+
+	return utils.Success(c, fiber.StatusOK, utils.CodeOK, "Waiter performance retrieved", stats)
+}
+
+// GetPromotionReport handles GET /analytics/promotions?start_date=...&end_date=...
+// Promosyonları manuel indirimlerden ayrı raporlar
+func (h *AnalyticsHandler) GetPromotionReport(c *fiber.Ctx) error {
+	startDate, endDate, err := parseDateRange(c)
+	if err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, err.Error())
+	}
+
+	report, err := h.service.GetPromotionReport(startDate, endDate)
+	if err != nil {
+		return utils.InternalError(c, utils.CodeInternalError, "Failed to retrieve promotion report")
+	}
+
+	return utils.Success(c, fiber.StatusOK, utils.CodeOK, "Promotion report retrieved", report)
+}
+
+// GetCouponReport handles GET /analytics/coupons?start_date=...&end_date=...
+// Kupon kullanımlarını ve maliyetlerini raporlar
+func (h *AnalyticsHandler) GetCouponReport(c *fiber.Ctx) error {
+	startDate, endDate, err := parseDateRange(c)
+	if err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, err.Error())
+	}
+
+	report, err := h.service.GetCouponReport(startDate, endDate)
+	if err != nil {
+		return utils.InternalError(c, utils.CodeInternalError, "Failed to retrieve coupon report")
+	}
+
+	return utils.Success(c, fiber.StatusOK, utils.CodeOK, "Coupon report retrieved", report)
+}
+
+// GetProductRanking handles GET /analytics/products?start_date=...&end_date=...&rank=top|bottom&metric=quantity|revenue&limit=...
+// En çok / en az satan ürünleri getirir
+func (h *AnalyticsHandler) GetProductRanking(c *fiber.Ctx) error {
+	startDate, endDate, err := parseDateRange(c)
+	if err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, err.Error())
+	}
+
+	rank := c.Query("rank", models.ProductRankTop)
+	metric := c.Query("metric", models.ProductMetricQuantity)
+	limit := c.QueryInt("limit", 10)
+
+	stats, err := h.service.GetProductRanking(startDate, endDate, rank, metric, limit)
+	if err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, err.Error())
+	}
+
+	return utils.Success(c, fiber.StatusOK, utils.CodeOK, "Product ranking retrieved", stats)
+}
+
+// GetCategoryMix handles GET /analytics/categories?start_date=...&end_date=...
+// Kategori satış dağılımını getirir
+func (h *AnalyticsHandler) GetCategoryMix(c *fiber.Ctx) error {
+	startDate, endDate, err := parseDateRange(c)
+	if err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, err.Error())
+	}
+
+	mix, err := h.service.GetCategoryMix(startDate, endDate)
+	if err != nil {
+		return utils.InternalError(c, utils.CodeInternalError, "Failed to retrieve category mix")
+	}
+
+	return utils.Success(c, fiber.StatusOK, utils.CodeOK, "Category mix retrieved", mix)
+}
+
+// GetProductTrend handles GET /analytics/products/:id/trend?start_date=...&end_date=...
+// Ürünün günlük satış eğilimini getirir
+func (h *AnalyticsHandler) GetProductTrend(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, "Invalid Product ID")
+	}
+	startDate, endDate, err := parseDateRange(c)
+	if err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, err.Error())
+	}
+	if endDate.Sub(startDate) > 366*24*time.Hour {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, "Date range cannot exceed one year")
+	}
+
+	trend, err := h.service.GetProductTrend(uint(id), startDate, endDate)
+	if err != nil {
+		return utils.InternalError(c, utils.CodeInternalError, "Failed to retrieve product trend")
+	}
+
+	return utils.Success(c, fiber.StatusOK, utils.CodeOK, "Product trend retrieved", trend)
+}
+
+// parseDateRange reads start_date/end_date (YYYY-MM-DD) query params, defaulting to today.
+// The returned end is exclusive and covers the whole end day.
+// start_date/end_date sorgu parametrelerini okur, varsayılan bugündür. Bitiş günü tamamen dahildir.
+func parseDateRange(c *fiber.Ctx) (time.Time, time.Time, error) {
+	now := time.Now()
+	startDate := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	endDate := startDate.Add(24 * time.Hour)
+
+	if startStr := c.Query("start_date"); startStr != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", startStr, now.Location())
+		if err != nil {
+			return startDate, endDate, errors.New("invalid start_date format (use YYYY-MM-DD)")
+		}
+		startDate = parsed
+		endDate = startDate.Add(24 * time.Hour)
+	}
+	if endStr := c.Query("end_date"); endStr != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", endStr, now.Location())
+		if err != nil {
+			return startDate, endDate, errors.New("invalid end_date format (use YYYY-MM-DD)")
+		}
+		endDate = parsed.Add(24 * time.Hour)
+	}
+	if !endDate.After(startDate) {
+		return startDate, endDate, errors.New("end_date must not be before start_date")
+	}
+
+	return startDate, endDate, nil
+}
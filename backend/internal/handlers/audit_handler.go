@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"simple-pos/internal/middleware"
+	"simple-pos/internal/models"
+	"simple-pos/internal/services"
+	"simple-pos/pkg/constants"
+	"simple-pos/pkg/utils"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type AuditHandler struct {
+	service *services.AuditService
+}
+
+func NewAuditHandler(service *services.AuditService) *AuditHandler {
+	return &AuditHandler{service: service}
+}
+
+// ListLogs handles GET /audit-logs?user_id=...&entity_type=...&entity_id=...&action=...&start_date=...&end_date=...&limit=...
+// Denetim kayıtlarını listeler
+func (h *AuditHandler) ListLogs(c *fiber.Ctx) error {
+	startDate, endDate, err := parseDateRange(c)
+	if err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, err.Error())
+	}
+
+	filter := models.AuditFilter{
+		EntityType: c.Query("entity_type"),
+		Action:     c.Query("action"),
+		Start:      startDate,
+		End:        endDate,
+		Limit:      c.QueryInt("limit"),
+	}
+	if filter.UserID, err = queryUint(c, "user_id"); err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, "Invalid user ID")
+	}
+	if filter.EntityID, err = queryUint(c, "entity_id"); err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, "Invalid entity ID")
+	}
+
+	logs, err := h.service.List(filter)
+	if err != nil {
+		return utils.InternalError(c, utils.CodeInternalError, "Could not fetch audit logs")
+	}
+
+	return middleware.SuccessResponse(c, constants.CODE_SUCCESS, "Audit logs retrieved", logs)
+}
+
+// queryUint reads an optional unsigned id query param, nil when absent
+// İsteğe bağlı işaretsiz id sorgu parametresini okur, yoksa nil döner
+func queryUint(c *fiber.Ctx, key string) (*uint, error) {
+	raw := c.Query(key)
+	if raw == "" {
+		return nil, nil
+	}
+	id, err := strconv.ParseUint(raw, 10, 32)
+	if err != nil {
+		return nil, err
+	}
+	value := uint(id)
+	return &value, nil
+}
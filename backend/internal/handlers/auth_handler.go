@@ -43,6 +43,7 @@ func (h *AuthHandler) Login(c *fiber.Ctx) error {
 	if err != nil {
 		return utils.BadRequestError(c, utils.CodeUnauthorized, "Invalid credentials")
 	}
+	permissions, _ := h.service.GetPermissions(user)
 
 	// Check if day is open
 	activePeriod, _ := h.workPeriodRepo.FindActivePeriod()
@@ -55,6 +56,7 @@ func (h *AuthHandler) Login(c *fiber.Ctx) error {
 	return utils.Success(c, fiber.StatusOK, utils.CodeOK, "Login successful", fiber.Map{
 		"token":          token,
 		"role":           user.Role,
+		"permissions":    permissions,
 		"userID":         user.ID,
 		"is_day_open":    isDayOpen,
 		"work_period_id": workPeriodID,
@@ -72,6 +74,13 @@ func (h *AuthHandler) Me(c *fiber.Ctx) error {
 		return utils.BadRequestError(c, utils.CodeNotFound, "User not found")
 	}
 
+	// Read from the role so the frontend sees permission changes without a new login
+	// Yetkiler rolden okunur, böylece önyüz değişiklikleri yeniden giriş yapmadan görür
+	permissions, err := h.service.GetPermissions(user)
+	if err != nil {
+		permissions = []string{}
+	}
+
 	// Check day status
 	activePeriod, _ := h.workPeriodRepo.FindActivePeriod()
 	isDayOpen := activePeriod != nil
@@ -80,6 +89,7 @@ func (h *AuthHandler) Me(c *fiber.Ctx) error {
 		"userID":      user.ID,
 		"name":        user.Name,
 		"role":        user.Role,
+		"permissions": permissions,
 		"is_day_open": isDayOpen,
 	})
 }
@@ -22,6 +22,8 @@ type CreateCategoryRequest struct {
 	Icon      string `json:"icon"`
 	Color     string `json:"color"`
 	SortOrder int    `json:"sort_order"`
+	StationID *uint  `json:"station_id"`                                  // Kitchen station routing
+	TaxRate   *int   `json:"tax_rate" validate:"omitempty,min=0,max=100"` // KDV % (nil = default)
 }
 
 // Create handles new category creation
@@ -32,7 +34,7 @@ func (h *CategoryHandler) Create(c *fiber.Ctx) error {
 		return err
 	}
 
-	category, err := h.service.CreateCategory(req.Name, req.Icon, req.Color, req.SortOrder)
+	category, err := h.service.CreateCategory(req.Name, req.Icon, req.Color, req.SortOrder, req.StationID, req.TaxRate)
 	if err != nil {
 		return fiber.NewError(fiber.StatusInternalServerError, "Could not create category")
 	}
@@ -64,7 +66,7 @@ func (h *CategoryHandler) Update(c *fiber.Ctx) error {
 		return err
 	}
 
-	category, err := h.service.UpdateCategory(uint(id), req.Name, req.Icon, req.Color, req.SortOrder)
+	category, err := h.service.UpdateCategory(uint(id), req.Name, req.Icon, req.Color, req.SortOrder, req.StationID, req.TaxRate)
 	if err != nil {
 		return fiber.NewError(fiber.StatusInternalServerError, "Could not update category")
 	}
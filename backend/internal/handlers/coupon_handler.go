@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"errors"
+	"simple-pos/internal/middleware"
+	"simple-pos/internal/services"
+	"simple-pos/pkg/utils"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type CouponHandler struct {
+	service *services.CouponService
+}
+
+func NewCouponHandler(service *services.CouponService) *CouponHandler {
+	return &CouponHandler{service: service}
+}
+
+type CouponRequest struct {
+	Code             string     `json:"code" validate:"required,max=50"`
+	Description      string     `json:"description" validate:"max=255"`
+	DiscountType     string     `json:"discount_type" validate:"required,oneof=AMOUNT PERCENTAGE"`
+	Value            int64      `json:"value" validate:"required,min=1"`
+	MinSpend         int64      `json:"min_spend" validate:"min=0"`
+	UsageLimit       int        `json:"usage_limit" validate:"min=0"`        // 1 = single use, 0 = unlimited
+	PerCustomerLimit int        `json:"per_customer_limit" validate:"min=0"` // 0 = unlimited
+	ExpiresAt        *time.Time `json:"expires_at"`
+	IsActive         *bool      `json:"is_active"` // Defaults to true
+}
+
+func (r CouponRequest) input() services.CouponInput {
+	isActive := true
+	if r.IsActive != nil {
+		isActive = *r.IsActive
+	}
+	return services.CouponInput{
+		Code:             r.Code,
+		Description:      r.Description,
+		DiscountType:     r.DiscountType,
+		Value:            r.Value,
+		MinSpend:         r.MinSpend,
+		UsageLimit:       r.UsageLimit,
+		PerCustomerLimit: r.PerCustomerLimit,
+		ExpiresAt:        r.ExpiresAt,
+		IsActive:         isActive,
+	}
+}
+
+// ListCoupons handles GET /coupons?include_inactive=true
+// Kuponları listeler
+func (h *CouponHandler) ListCoupons(c *fiber.Ctx) error {
+	coupons, err := h.service.ListCoupons(c.QueryBool("include_inactive"))
+	if err != nil {
+		return utils.InternalError(c, utils.CodeInternalError, "Could not fetch coupons")
+	}
+	return utils.Success(c, fiber.StatusOK, utils.CodeOK, "Coupons retrieved", coupons)
+}
+
+// GetCoupon handles GET /coupons/:id
+// Kupon detayını getirir
+func (h *CouponHandler) GetCoupon(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, "Invalid Coupon ID")
+	}
+
+	coupon, err := h.service.GetCoupon(uint(id))
+	if err != nil {
+		return couponError(c, err)
+	}
+	return utils.Success(c, fiber.StatusOK, utils.CodeOK, "Coupon retrieved", coupon)
+}
+
+// CreateCoupon handles POST /coupons
+// Yeni kupon kodu oluşturur
+func (h *CouponHandler) CreateCoupon(c *fiber.Ctx) error {
+	var req CouponRequest
+	if err := middleware.ValidateBody(c, &req); err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, err.Error())
+	}
+
+	coupon, err := h.service.CreateCoupon(req.input())
+	if err != nil {
+		return couponError(c, err)
+	}
+	return utils.Success(c, fiber.StatusCreated, utils.CodeOK, "Coupon created", coupon)
+}
+
+// UpdateCoupon handles PUT /coupons/:id
+// Kuponu günceller
+func (h *CouponHandler) UpdateCoupon(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, "Invalid Coupon ID")
+	}
+
+	var req CouponRequest
+	if err := middleware.ValidateBody(c, &req); err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, err.Error())
+	}
+
+	coupon, err := h.service.UpdateCoupon(uint(id), req.input())
+	if err != nil {
+		return couponError(c, err)
+	}
+	return utils.Success(c, fiber.StatusOK, utils.CodeOK, "Coupon updated", coupon)
+}
+
+// DeleteCoupon handles DELETE /coupons/:id
+// Kuponu siler
+func (h *CouponHandler) DeleteCoupon(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, "Invalid Coupon ID")
+	}
+
+	if err := h.service.DeleteCoupon(uint(id)); err != nil {
+		return couponError(c, err)
+	}
+	return utils.Success(c, fiber.StatusOK, utils.CodeOK, "Coupon deleted", nil)
+}
+
+// couponError maps coupon errors to responses, a coupon that cannot be used answers 409
+// Kupon hatalarını yanıtlara eşler, kullanılamayan kupon 409 döner
+func couponError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, services.ErrCouponNotFound):
+		return utils.NotFoundError(c, utils.CodeResourceNotFound, err.Error())
+	case errors.Is(err, services.ErrCouponInactive), errors.Is(err, services.ErrCouponExpired),
+		errors.Is(err, services.ErrCouponUsedUp), errors.Is(err, services.ErrCouponMinSpend),
+		errors.Is(err, services.ErrCouponCustomerRequired), errors.Is(err, services.ErrCouponCustomerLimit),
+		errors.Is(err, services.ErrCouponCodeTaken):
+		return utils.Error(c, fiber.StatusConflict, utils.CodeInvalidInput, err.Error())
+	default:
+		return utils.BadRequestError(c, utils.CodeInvalidInput, err.Error())
+	}
+}
@@ -0,0 +1,188 @@
+package handlers
+
+import (
+	"errors"
+	"simple-pos/internal/middleware"
+	"simple-pos/internal/models"
+	"simple-pos/internal/services"
+	"simple-pos/pkg/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type CustomerHandler struct {
+	service *services.CustomerService
+}
+
+func NewCustomerHandler(service *services.CustomerService) *CustomerHandler {
+	return &CustomerHandler{service: service}
+}
+
+type CustomerRequest struct {
+	Name        string `json:"name" validate:"required,max=100"`
+	Phone       string `json:"phone" validate:"max=30"`
+	Email       string `json:"email" validate:"omitempty,email,max=255"`
+	Note        string `json:"note" validate:"max=255"`
+	CreditLimit int64  `json:"credit_limit" validate:"min=0"` // 0 = no limit
+	IsActive    *bool  `json:"is_active"`                     // Defaults to true
+}
+
+func (r CustomerRequest) input() services.CustomerInput {
+	isActive := true
+	if r.IsActive != nil {
+		isActive = *r.IsActive
+	}
+	return services.CustomerInput{
+		Name:        r.Name,
+		Phone:       r.Phone,
+		Email:       r.Email,
+		Note:        r.Note,
+		CreditLimit: r.CreditLimit,
+		IsActive:    isActive,
+	}
+}
+
+type CustomerPaymentRequest struct {
+	Amount int64  `json:"amount" validate:"required,min=1"`
+	Method string `json:"method" validate:"required,oneof=CASH CREDIT_CARD"`
+	Note   string `json:"note" validate:"max=255"`
+}
+
+// ListCustomers handles GET /customers?search=...&with_balance=true&include_inactive=true
+// Müşterileri listeler
+func (h *CustomerHandler) ListCustomers(c *fiber.Ctx) error {
+	customers, err := h.service.ListCustomers(models.CustomerFilter{
+		Search:          c.Query("search"),
+		WithBalance:     c.QueryBool("with_balance"),
+		IncludeInactive: c.QueryBool("include_inactive"),
+	})
+	if err != nil {
+		return utils.InternalError(c, utils.CodeInternalError, "Could not fetch customers")
+	}
+	return utils.Success(c, fiber.StatusOK, utils.CodeOK, "Customers retrieved", customers)
+}
+
+// GetCustomer handles GET /customers/:id
+// Müşteri detayını getirir
+func (h *CustomerHandler) GetCustomer(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, "Invalid Customer ID")
+	}
+
+	customer, err := h.service.GetCustomer(uint(id))
+	if err != nil {
+		return customerError(c, err)
+	}
+	return utils.Success(c, fiber.StatusOK, utils.CodeOK, "Customer retrieved", customer)
+}
+
+// CreateCustomer handles POST /customers
+// Yeni müşteri hesabı açar
+func (h *CustomerHandler) CreateCustomer(c *fiber.Ctx) error {
+	var req CustomerRequest
+	if err := middleware.ValidateBody(c, &req); err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, err.Error())
+	}
+
+	customer, err := h.service.CreateCustomer(req.input())
+	if err != nil {
+		return customerError(c, err)
+	}
+	return utils.Success(c, fiber.StatusCreated, utils.CodeOK, "Customer created", customer)
+}
+
+// UpdateCustomer handles PUT /customers/:id
+// Müşteri bilgilerini günceller
+func (h *CustomerHandler) UpdateCustomer(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, "Invalid Customer ID")
+	}
+
+	var req CustomerRequest
+	if err := middleware.ValidateBody(c, &req); err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, err.Error())
+	}
+
+	userID, _ := c.Locals("userID").(uint)
+
+	customer, err := h.service.UpdateCustomer(uint(id), req.input(), userID)
+	if err != nil {
+		return customerError(c, err)
+	}
+	return utils.Success(c, fiber.StatusOK, utils.CodeOK, "Customer updated", customer)
+}
+
+// DeleteCustomer handles DELETE /customers/:id
+// Bakiyesi kapanmış müşteri hesabını siler
+func (h *CustomerHandler) DeleteCustomer(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, "Invalid Customer ID")
+	}
+
+	if err := h.service.DeleteCustomer(uint(id)); err != nil {
+		return customerError(c, err)
+	}
+	return utils.Success(c, fiber.StatusOK, utils.CodeOK, "Customer deleted", nil)
+}
+
+// RecordPayment handles POST /customers/:id/payments
+// Müşterinin veresiye borcuna karşı ödeme alır
+func (h *CustomerHandler) RecordPayment(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, "Invalid Customer ID")
+	}
+
+	var req CustomerPaymentRequest
+	if err := middleware.ValidateBody(c, &req); err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, err.Error())
+	}
+
+	userID, _ := c.Locals("userID").(uint)
+
+	entry, customer, err := h.service.RecordPayment(uint(id), req.Amount, req.Method, req.Note, userID)
+	if err != nil {
+		return customerError(c, err)
+	}
+	return utils.Success(c, fiber.StatusCreated, utils.CodeOK, "Payment recorded", fiber.Map{
+		"entry":    entry,
+		"customer": customer,
+	})
+}
+
+// GetStatement handles GET /customers/:id/statement?start_date=...&end_date=...
+// Müşterinin hesap ekstresini getirir (varsayılan: bugün)
+func (h *CustomerHandler) GetStatement(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, "Invalid Customer ID")
+	}
+
+	startDate, endDate, err := parseDateRange(c)
+	if err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, err.Error())
+	}
+
+	statement, err := h.service.GetStatement(uint(id), startDate, endDate)
+	if err != nil {
+		return customerError(c, err)
+	}
+	return utils.Success(c, fiber.StatusOK, utils.CodeOK, "Statement retrieved", statement)
+}
+
+// customerError maps customer service errors to responses
+// Müşteri servis hatalarını yanıtlara eşler
+func customerError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, services.ErrCustomerNotFound):
+		return utils.NotFoundError(c, utils.CodeResourceNotFound, err.Error())
+	case errors.Is(err, services.ErrCustomerHasBalance), errors.Is(err, services.ErrPaymentExceedsBalance),
+		errors.Is(err, services.ErrCustomerPhoneTaken):
+		return utils.Error(c, fiber.StatusConflict, utils.CodeInvalidInput, err.Error())
+	default:
+		return utils.BadRequestError(c, utils.CodeInvalidInput, err.Error())
+	}
+}
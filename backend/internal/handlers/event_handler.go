@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"simple-pos/internal/middleware"
+	"simple-pos/internal/platform/events"
+	"simple-pos/pkg/logger"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/valyala/fasthttp"
+)
+
+// heartbeatInterval keeps idle connections alive through proxies
+// Boşta kalan bağlantıları proxyler üzerinden canlı tutar
+const heartbeatInterval = 15 * time.Second
+
+type EventHandler struct {
+	bus *events.Bus
+}
+
+func NewEventHandler(bus *events.Bus) *EventHandler {
+	return &EventHandler{bus: bus}
+}
+
+// Stream handles GET /events?types=order_created,item_added (Server-Sent Events)
+// Canlı sipariş ve masa değişikliklerini SSE ile iletir
+func (h *EventHandler) Stream(c *fiber.Ctx) error {
+	role, _ := c.Locals("role").(string)
+	userID, _ := c.Locals("userID").(uint)
+
+	// Optional client-side filter on event types
+	// İsteğe bağlı olay tipi filtresi
+	var types map[string]bool
+	if typesParam := c.Query("types"); typesParam != "" {
+		types = make(map[string]bool)
+		for _, t := range strings.Split(typesParam, ",") {
+			if t = strings.TrimSpace(t); t != "" {
+				types[t] = true
+			}
+		}
+	}
+
+	c.Set("Content-Type", "text/event-stream")
+	c.Set("Cache-Control", "no-cache")
+	c.Set("Connection", "keep-alive")
+	c.Set("X-Accel-Buffering", "no")
+
+	ch, unsubscribe := h.bus.Subscribe(middleware.Permissions(c))
+
+	c.Context().SetBodyStreamWriter(fasthttp.StreamWriter(func(w *bufio.Writer) {
+		defer unsubscribe()
+		logger.Info("Event stream opened", logger.Int("user_id", int(userID)), logger.String("role", role))
+
+		// Tell the client it is connected so it can refetch a fresh snapshot
+		// İstemciye bağlandığını bildir ki güncel durumu yeniden çeksin
+		fmt.Fprintf(w, "event: connected\ndata: {}\n\n")
+		if err := w.Flush(); err != nil {
+			return
+		}
+
+		ticker := time.NewTicker(heartbeatInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case event, ok := <-ch:
+				if !ok {
+					return
+				}
+				if types != nil && !types[event.Type] {
+					continue
+				}
+				payload, err := json.Marshal(event)
+				if err != nil {
+					logger.Error("Failed to encode event", logger.Err(err))
+					continue
+				}
+				fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, payload)
+			case <-ticker.C:
+				fmt.Fprintf(w, ": ping\n\n")
+			}
+
+			// A failed flush means the client went away
+			// Flush hatası istemcinin ayrıldığı anlamına gelir
+			if err := w.Flush(); err != nil {
+				logger.Info("Event stream closed", logger.Int("user_id", int(userID)))
+				return
+			}
+		}
+	}))
+
+	return nil
+}
@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"simple-pos/internal/platform/export"
+	"simple-pos/internal/services"
+	"simple-pos/pkg/logger"
+	"simple-pos/pkg/utils"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/valyala/fasthttp"
+)
+
+type ExportHandler struct {
+	service *services.ExportService
+}
+
+func NewExportHandler(s *services.ExportService) *ExportHandler {
+	return &ExportHandler{service: s}
+}
+
+// ExportPeriods handles GET /exports/periods?start_date=...&end_date=...&format=csv|xlsx
+// Dönem raporlarını dışa aktarır
+func (h *ExportHandler) ExportPeriods(c *fiber.Ctx) error {
+	startDate, endDate, err := parseDateRange(c)
+	if err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, err.Error())
+	}
+	sheet, err := h.service.PeriodsSheet(startDate, endDate)
+	if err != nil {
+		return utils.InternalError(c, utils.CodeInternalError, "Failed to export periods")
+	}
+	return h.sendSheet(c, sheet, "donemler", startDate, endDate)
+}
+
+// ExportOrders handles GET /exports/orders?start_date=...&end_date=...&scope=...&format=csv|xlsx
+// Sipariş listesini dışa aktarır
+func (h *ExportHandler) ExportOrders(c *fiber.Ctx) error {
+	startDate, endDate, err := parseDateRange(c)
+	if err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, err.Error())
+	}
+	sheet, err := h.service.OrdersSheet(startDate, endDate, c.Query("scope"))
+	if err != nil {
+		return utils.InternalError(c, utils.CodeInternalError, err.Error())
+	}
+	return h.sendSheet(c, sheet, "siparisler", startDate, endDate)
+}
+
+// ExportExpenses handles GET /exports/expenses?scope=...&format=csv|xlsx
+// Gider listesini dışa aktarır
+func (h *ExportHandler) ExportExpenses(c *fiber.Ctx) error {
+	startDate, endDate, err := parseDateRange(c)
+	if err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, err.Error())
+	}
+	sheet, err := h.service.ExpensesSheet(startDate, endDate, c.Query("scope"))
+	if err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, err.Error())
+	}
+	return h.sendSheet(c, sheet, "giderler", startDate, endDate)
+}
+
+// ExportProductSales handles GET /exports/product-sales?start_date=...&end_date=...&format=csv|xlsx
+// Ürün satışlarını dışa aktarır
+func (h *ExportHandler) ExportProductSales(c *fiber.Ctx) error {
+	startDate, endDate, err := parseDateRange(c)
+	if err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, err.Error())
+	}
+	sheet, err := h.service.ProductSalesSheet(startDate, endDate)
+	if err != nil {
+		return utils.InternalError(c, utils.CodeInternalError, "Failed to export product sales")
+	}
+	return h.sendSheet(c, sheet, "urun-satislari", startDate, endDate)
+}
+
+// ZReport handles GET /exports/z-report?period_id=... (latest closed period by default)
+// Gün sonu Z raporunu PDF olarak döndürür
+func (h *ExportHandler) ZReport(c *fiber.Ctx) error {
+	periodID := c.QueryInt("period_id", 0)
+	if periodID < 0 {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, "Invalid period ID")
+	}
+
+	// The PDF is small, render it fully so errors can still be answered as JSON
+	// PDF küçüktür, hatalar JSON olarak dönebilsin diye tamamen oluşturulur
+	var buf bytes.Buffer
+	if err := h.service.WriteZReport(&buf, uint(periodID)); err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, err.Error())
+	}
+
+	c.Set(fiber.HeaderContentType, "application/pdf")
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="z-raporu-%s.pdf"`, time.Now().Format("2006-01-02")))
+	return c.Send(buf.Bytes())
+}
+
+// sendSheet streams the sheet as a CSV or XLSX download, CSV by default
+// Sayfayı CSV veya XLSX indirmesi olarak akıtır, varsayılan CSV'dir
+func (h *ExportHandler) sendSheet(c *fiber.Ctx, sheet *export.Sheet, name string, startDate, endDate time.Time) error {
+	format := c.Query("format", services.ExportFormatCSV)
+	contentType := "text/csv; charset=utf-8"
+	switch format {
+	case services.ExportFormatCSV:
+	case services.ExportFormatXLSX:
+		contentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	default:
+		return utils.BadRequestError(c, utils.CodeInvalidInput, "format must be csv or xlsx")
+	}
+
+	filename := fmt.Sprintf("%s_%s_%s.%s", name, startDate.Format("2006-01-02"), endDate.Add(-time.Second).Format("2006-01-02"), format)
+	c.Set(fiber.HeaderContentType, contentType)
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s"`, filename))
+
+	c.Context().SetBodyStreamWriter(fasthttp.StreamWriter(func(w *bufio.Writer) {
+		if err := h.service.WriteSheet(w, sheet, format); err != nil {
+			logger.Error("Failed to write export", logger.String("file", filename), logger.Err(err))
+			return
+		}
+		if err := w.Flush(); err != nil {
+			logger.Error("Failed to send export", logger.String("file", filename), logger.Err(err))
+		}
+	}))
+	return nil
+}
@@ -0,0 +1,220 @@
+package handlers
+
+import (
+	"simple-pos/internal/middleware"
+	"simple-pos/internal/services"
+	"simple-pos/pkg/constants"
+	"simple-pos/pkg/utils"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type InventoryHandler struct {
+	service *services.InventoryService
+}
+
+func NewInventoryHandler(service *services.InventoryService) *InventoryHandler {
+	return &InventoryHandler{service: service}
+}
+
+type CreateStockItemRequest struct {
+	Name              string  `json:"name" validate:"required"`
+	Unit              string  `json:"unit" validate:"omitempty,oneof=piece g kg ml l"`
+	LowStockThreshold float64 `json:"low_stock_threshold" validate:"min=0"`
+	InitialQuantity   float64 `json:"initial_quantity" validate:"min=0"`
+}
+
+type UpdateStockItemRequest struct {
+	Name              string  `json:"name" validate:"required"`
+	Unit              string  `json:"unit" validate:"omitempty,oneof=piece g kg ml l"`
+	LowStockThreshold float64 `json:"low_stock_threshold" validate:"min=0"`
+	IsActive          *bool   `json:"is_active"`
+}
+
+type StockMovementRequest struct {
+	Type     string  `json:"type" validate:"required,oneof=IN ADJUSTMENT WASTE"`
+	Quantity float64 `json:"quantity" validate:"required"`
+	Note     string  `json:"note" validate:"max=255"`
+}
+
+type RecipeItemRequest struct {
+	StockItemID uint    `json:"stock_item_id" validate:"required"`
+	Quantity    float64 `json:"quantity" validate:"required,gt=0"`
+}
+
+type SetRecipeRequest struct {
+	Items []RecipeItemRequest `json:"items" validate:"dive"`
+}
+
+// CreateStockItem handles POST /inventory/items
+// Stok kalemi oluşturur
+func (h *InventoryHandler) CreateStockItem(c *fiber.Ctx) error {
+	var req CreateStockItemRequest
+	if err := middleware.ValidateBody(c, &req); err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, err.Error())
+	}
+
+	userID, _ := c.Locals("userID").(uint)
+
+	item, err := h.service.CreateStockItem(req.Name, req.Unit, req.LowStockThreshold, req.InitialQuantity, userID)
+	if err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, "Could not create stock item (Name might be duplicate)")
+	}
+
+	return utils.Success(c, fiber.StatusCreated, string(constants.CODE_CREATED), "Stock item created", item)
+}
+
+// ListStockItems handles GET /inventory/items
+// Stok kalemlerini listeler
+func (h *InventoryHandler) ListStockItems(c *fiber.Ctx) error {
+	items, err := h.service.ListStockItems()
+	if err != nil {
+		return utils.InternalError(c, utils.CodeInternalError, "Could not fetch stock items")
+	}
+
+	return middleware.SuccessResponse(c, constants.CODE_SUCCESS, "Stock items retrieved", items)
+}
+
+// UpdateStockItem handles PUT /inventory/items/:id
+// Stok kalemini günceller
+func (h *InventoryHandler) UpdateStockItem(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, "Invalid ID")
+	}
+
+	var req UpdateStockItemRequest
+	if err := middleware.ValidateBody(c, &req); err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, err.Error())
+	}
+
+	isActive := true
+	if req.IsActive != nil {
+		isActive = *req.IsActive
+	}
+
+	item, err := h.service.UpdateStockItem(uint(id), req.Name, req.Unit, req.LowStockThreshold, isActive)
+	if err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, err.Error())
+	}
+
+	return middleware.SuccessResponse(c, constants.CODE_UPDATED, "Stock item updated", item)
+}
+
+// DeleteStockItem handles DELETE /inventory/items/:id
+// Stok kalemini siler
+func (h *InventoryHandler) DeleteStockItem(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, "Invalid ID")
+	}
+
+	if err := h.service.DeleteStockItem(uint(id)); err != nil {
+		return utils.InternalError(c, utils.CodeInternalError, "Could not delete stock item")
+	}
+
+	return middleware.SuccessResponse(c, constants.CODE_DELETED, "Stock item deleted", nil)
+}
+
+// RecordMovement handles POST /inventory/items/:id/movements
+// Stok girişi, fire veya düzeltme kaydeder
+func (h *InventoryHandler) RecordMovement(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, "Invalid ID")
+	}
+
+	var req StockMovementRequest
+	if err := middleware.ValidateBody(c, &req); err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, err.Error())
+	}
+
+	userID, _ := c.Locals("userID").(uint)
+
+	movement, err := h.service.RecordMovement(uint(id), req.Type, req.Quantity, req.Note, userID)
+	if err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, err.Error())
+	}
+
+	return utils.Success(c, fiber.StatusCreated, string(constants.CODE_CREATED), "Stock movement recorded", movement)
+}
+
+// ListMovements handles GET /inventory/movements?stock_item_id=...&start_date=...&end_date=...
+// Stok hareketlerini listeler
+func (h *InventoryHandler) ListMovements(c *fiber.Ctx) error {
+	var stockItemID *uint
+	if idStr := c.Query("stock_item_id"); idStr != "" {
+		id, err := strconv.ParseUint(idStr, 10, 32)
+		if err != nil {
+			return utils.BadRequestError(c, utils.CodeInvalidInput, "Invalid stock item ID")
+		}
+		uid := uint(id)
+		stockItemID = &uid
+	}
+
+	startDate, endDate, err := parseDateRange(c)
+	if err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, err.Error())
+	}
+
+	movements, err := h.service.ListMovements(stockItemID, startDate, endDate)
+	if err != nil {
+		return utils.InternalError(c, utils.CodeInternalError, "Could not fetch stock movements")
+	}
+
+	return middleware.SuccessResponse(c, constants.CODE_SUCCESS, "Stock movements retrieved", movements)
+}
+
+// GetLowStock handles GET /inventory/alerts
+// Düşük stok uyarılarını listeler
+func (h *InventoryHandler) GetLowStock(c *fiber.Ctx) error {
+	items, err := h.service.GetLowStock()
+	if err != nil {
+		return utils.InternalError(c, utils.CodeInternalError, "Could not fetch low stock items")
+	}
+
+	return middleware.SuccessResponse(c, constants.CODE_SUCCESS, "Low stock items retrieved", items)
+}
+
+// GetRecipe handles GET /products/:id/recipe
+// Ürün reçetesini getirir
+func (h *InventoryHandler) GetRecipe(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, "Invalid product ID")
+	}
+
+	recipe, err := h.service.GetRecipe(uint(id))
+	if err != nil {
+		return utils.NotFoundError(c, utils.CodeResourceNotFound, err.Error())
+	}
+
+	return middleware.SuccessResponse(c, constants.CODE_SUCCESS, "Recipe retrieved", recipe)
+}
+
+// SetRecipe handles PUT /products/:id/recipe
+// Ürün reçetesini değiştirir
+func (h *InventoryHandler) SetRecipe(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, "Invalid product ID")
+	}
+
+	var req SetRecipeRequest
+	if err := middleware.ValidateBody(c, &req); err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, err.Error())
+	}
+
+	inputs := make([]services.RecipeInput, 0, len(req.Items))
+	for _, item := range req.Items {
+		inputs = append(inputs, services.RecipeInput{StockItemID: item.StockItemID, Quantity: item.Quantity})
+	}
+
+	recipe, err := h.service.SetRecipe(uint(id), inputs)
+	if err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, err.Error())
+	}
+
+	return middleware.SuccessResponse(c, constants.CODE_UPDATED, "Recipe updated", recipe)
+}
@@ -0,0 +1,164 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"simple-pos/internal/middleware"
+	"simple-pos/internal/models"
+	"simple-pos/internal/services"
+	"simple-pos/pkg/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type InvoiceHandler struct {
+	service *services.InvoiceService
+}
+
+func NewInvoiceHandler(service *services.InvoiceService) *InvoiceHandler {
+	return &InvoiceHandler{service: service}
+}
+
+type IssueInvoiceRequest struct {
+	TaxID     string `json:"tax_id" validate:"required,numeric,min=10,max=11"` // VKN or TCKN
+	Name      string `json:"name" validate:"required,max=255"`                 // Company title or full name
+	TaxOffice string `json:"tax_office" validate:"max=100"`                    // Required with a VKN
+	Address   string `json:"address" validate:"max=255"`
+	District  string `json:"district" validate:"max=100"`
+	City      string `json:"city" validate:"required,max=100"`
+	Country   string `json:"country" validate:"max=100"`
+	Email     string `json:"email" validate:"omitempty,email,max=255"`
+}
+
+// IssueInvoice handles POST /orders/:id/invoice
+// Tamamlanmış sipariş için e-Arşiv faturası keser
+func (h *InvoiceHandler) IssueInvoice(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, "Invalid Order ID")
+	}
+
+	var req IssueInvoiceRequest
+	if err := middleware.ValidateBody(c, &req); err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, err.Error())
+	}
+
+	userID, _ := c.Locals("userID").(uint)
+
+	invoice, err := h.service.IssueInvoice(uint(id), services.InvoiceCustomer{
+		TaxID:     req.TaxID,
+		Name:      req.Name,
+		TaxOffice: req.TaxOffice,
+		Address:   req.Address,
+		District:  req.District,
+		City:      req.City,
+		Country:   req.Country,
+		Email:     req.Email,
+	}, userID)
+	if err != nil {
+		return invoiceError(c, err)
+	}
+	return utils.Success(c, fiber.StatusCreated, utils.CodeOK, "Invoice issued", invoice)
+}
+
+// GetOrderInvoice handles GET /orders/:id/invoice
+// Siparişin faturasını getirir
+func (h *InvoiceHandler) GetOrderInvoice(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, "Invalid Order ID")
+	}
+
+	invoice, err := h.service.GetOrderInvoice(uint(id))
+	if err != nil {
+		return invoiceError(c, err)
+	}
+	return utils.Success(c, fiber.StatusOK, utils.CodeOK, "Invoice retrieved", invoice)
+}
+
+// ListInvoices handles GET /invoices?year=...&order_id=...&tax_id=...&status=...&limit=...
+// Faturaları listeler
+func (h *InvoiceHandler) ListInvoices(c *fiber.Ctx) error {
+	filter := models.InvoiceFilter{
+		Year:   c.QueryInt("year"),
+		TaxID:  c.Query("tax_id"),
+		Status: c.Query("status"),
+		Limit:  c.QueryInt("limit"),
+	}
+	orderID, err := queryUint(c, "order_id")
+	if err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, "Invalid order ID")
+	}
+	if orderID != nil {
+		filter.OrderID = *orderID
+	}
+
+	invoices, err := h.service.ListInvoices(filter)
+	if err != nil {
+		return utils.InternalError(c, utils.CodeInternalError, "Could not fetch invoices")
+	}
+	return utils.Success(c, fiber.StatusOK, utils.CodeOK, "Invoices retrieved", invoices)
+}
+
+// GetInvoice handles GET /invoices/:id
+// Fatura detayını getirir
+func (h *InvoiceHandler) GetInvoice(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, "Invalid Invoice ID")
+	}
+
+	invoice, err := h.service.GetInvoice(uint(id))
+	if err != nil {
+		return invoiceError(c, err)
+	}
+	return utils.Success(c, fiber.StatusOK, utils.CodeOK, "Invoice retrieved", invoice)
+}
+
+// DownloadXML handles GET /invoices/:id/xml
+// Faturanın UBL-TR XML dosyasını indirir
+func (h *InvoiceHandler) DownloadXML(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, "Invalid Invoice ID")
+	}
+
+	invoice, err := h.service.GetInvoice(uint(id))
+	if err != nil {
+		return invoiceError(c, err)
+	}
+
+	c.Set(fiber.HeaderContentType, "application/xml; charset=utf-8")
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s.xml"`, invoice.InvoiceNumber))
+	return c.SendString(invoice.XML)
+}
+
+// SendInvoice handles POST /invoices/:id/send, retrying delivery to the integrator
+// Faturayı entegratöre yeniden gönderir
+func (h *InvoiceHandler) SendInvoice(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, "Invalid Invoice ID")
+	}
+
+	invoice, err := h.service.SendInvoice(uint(id))
+	if err != nil {
+		return invoiceError(c, err)
+	}
+	return utils.Success(c, fiber.StatusOK, utils.CodeOK, "Invoice sent", invoice)
+}
+
+// invoiceError maps invoice service errors to responses
+// Fatura servis hatalarını yanıtlara eşler
+func invoiceError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, services.ErrInvoiceNotFound), errors.Is(err, services.ErrInvoiceOrderNotFound):
+		return utils.NotFoundError(c, utils.CodeResourceNotFound, err.Error())
+	case errors.Is(err, services.ErrOrderAlreadyInvoiced), errors.Is(err, services.ErrInvoiceAlreadySent):
+		return utils.Error(c, fiber.StatusConflict, utils.CodeInvalidInput, err.Error())
+	case errors.Is(err, services.ErrSellerNotConfigured):
+		return utils.InternalError(c, utils.CodeInternalError, err.Error())
+	default:
+		return utils.BadRequestError(c, utils.CodeInvalidInput, err.Error())
+	}
+}
@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"errors"
+	"simple-pos/internal/middleware"
+	"simple-pos/internal/services"
+	"simple-pos/pkg/constants"
+	"simple-pos/pkg/utils"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type KitchenHandler struct {
+	service *services.KitchenService
+}
+
+func NewKitchenHandler(service *services.KitchenService) *KitchenHandler {
+	return &KitchenHandler{service: service}
+}
+
+type KitchenStationRequest struct {
+	Name      string `json:"name" validate:"required"`
+	IsActive  *bool  `json:"is_active"`
+	SortOrder int    `json:"sort_order"`
+	Printer   string `json:"printer" validate:"max=255"`
+}
+
+type TicketStatusRequest struct {
+	Status string `json:"status" validate:"required,oneof=queued in_preparation ready served"`
+}
+
+// CreateStation handles POST /kitchen/stations
+// Mutfak istasyonu oluşturur
+func (h *KitchenHandler) CreateStation(c *fiber.Ctx) error {
+	var req KitchenStationRequest
+	if err := middleware.ValidateBody(c, &req); err != nil {
+		return err
+	}
+
+	station, err := h.service.CreateStation(req.Name, req.SortOrder, req.Printer)
+	if errors.Is(err, services.ErrInvalidPrinter) {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, err.Error())
+	}
+	if err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, "Could not create station (Name might be duplicate)")
+	}
+
+	return utils.Success(c, fiber.StatusCreated, string(constants.CODE_CREATED), "Kitchen station created", station)
+}
+
+// ListStations handles GET /kitchen/stations
+// Mutfak istasyonlarını listeler
+func (h *KitchenHandler) ListStations(c *fiber.Ctx) error {
+	stations, err := h.service.ListStations()
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "Could not fetch kitchen stations")
+	}
+
+	return utils.Success(c, fiber.StatusOK, utils.CodeOK, "Kitchen stations retrieved", stations)
+}
+
+// UpdateStation handles PUT /kitchen/stations/:id
+// Mutfak istasyonunu günceller
+func (h *KitchenHandler) UpdateStation(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, "Invalid ID")
+	}
+
+	var req KitchenStationRequest
+	if err := middleware.ValidateBody(c, &req); err != nil {
+		return err
+	}
+
+	isActive := true
+	if req.IsActive != nil {
+		isActive = *req.IsActive
+	}
+
+	station, err := h.service.UpdateStation(uint(id), req.Name, isActive, req.SortOrder, req.Printer)
+	if errors.Is(err, services.ErrInvalidPrinter) {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, err.Error())
+	}
+	if err != nil {
+		return utils.BadRequestError(c, utils.CodeResourceNotFound, "Could not update kitchen station")
+	}
+
+	return utils.Success(c, fiber.StatusOK, utils.CodeOK, "Kitchen station updated", station)
+}
+
+// DeleteStation handles DELETE /kitchen/stations/:id
+// Mutfak istasyonunu siler
+func (h *KitchenHandler) DeleteStation(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, "Invalid ID")
+	}
+
+	if err := h.service.DeleteStation(uint(id)); err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, err.Error())
+	}
+
+	return utils.Success(c, fiber.StatusOK, utils.CodeOK, "Kitchen station deleted", nil)
+}
+
+// ListTickets handles GET /kitchen/tickets?station_id=...&status=...
+// Mutfak ekranı için ticketları listeler
+func (h *KitchenHandler) ListTickets(c *fiber.Ctx) error {
+	var stationID *uint
+	if stationStr := c.Query("station_id"); stationStr != "" {
+		id, err := strconv.ParseUint(stationStr, 10, 32)
+		if err != nil {
+			return utils.BadRequestError(c, utils.CodeInvalidInput, "Invalid station ID")
+		}
+		uid := uint(id)
+		stationID = &uid
+	}
+
+	tickets, err := h.service.ListTickets(stationID, c.Query("status"))
+	if err != nil {
+		return utils.InternalError(c, utils.CodeInternalError, "Could not fetch kitchen tickets")
+	}
+
+	return utils.Success(c, fiber.StatusOK, utils.CodeOK, "Kitchen tickets retrieved", tickets)
+}
+
+// BumpTicket handles POST /kitchen/tickets/:id/bump
+// Ticket'ı bir sonraki duruma ilerletir
+func (h *KitchenHandler) BumpTicket(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, "Invalid ticket ID")
+	}
+
+	ticket, err := h.service.BumpTicket(uint(id))
+	if err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, err.Error())
+	}
+
+	return utils.Success(c, fiber.StatusOK, utils.CodeOK, "Ticket bumped", ticket)
+}
+
+// SetTicketStatus handles PUT /kitchen/tickets/:id/status
+// Ticket durumunu belirli bir değere ayarlar
+func (h *KitchenHandler) SetTicketStatus(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, "Invalid ticket ID")
+	}
+
+	var req TicketStatusRequest
+	if err := middleware.ValidateBody(c, &req); err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, err.Error())
+	}
+
+	ticket, err := h.service.SetTicketStatus(uint(id), req.Status)
+	if err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, err.Error())
+	}
+
+	return utils.Success(c, fiber.StatusOK, utils.CodeOK, "Ticket status updated", ticket)
+}
+
+// GetStationStats handles GET /kitchen/stats?start_date=...&end_date=...
+// İstasyon bazlı hazırlık süresi istatistiklerini getirir
+func (h *KitchenHandler) GetStationStats(c *fiber.Ctx) error {
+	startDate, endDate, err := parseDateRange(c)
+	if err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, err.Error())
+	}
+
+	stats, err := h.service.GetStationStats(startDate, endDate)
+	if err != nil {
+		return utils.InternalError(c, utils.CodeInternalError, "Could not compute kitchen stats")
+	}
+
+	return utils.Success(c, fiber.StatusOK, utils.CodeOK, "Kitchen stats retrieved", stats)
+}
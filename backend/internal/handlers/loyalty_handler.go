@@ -0,0 +1,187 @@
+package handlers
+
+import (
+	"errors"
+	"simple-pos/internal/middleware"
+	"simple-pos/internal/services"
+	"simple-pos/pkg/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type LoyaltyHandler struct {
+	service *services.LoyaltyService
+}
+
+func NewLoyaltyHandler(service *services.LoyaltyService) *LoyaltyHandler {
+	return &LoyaltyHandler{service: service}
+}
+
+type LoyaltyRuleRequest struct {
+	CategoryID      uint  `json:"category_id" validate:"required"`
+	PointsPerLira   int64 `json:"points_per_lira" validate:"min=0"`
+	RedeemPoints    bool  `json:"redeem_points"`
+	StampsPerItem   int   `json:"stamps_per_item" validate:"min=0"`
+	StampsForReward int   `json:"stamps_for_reward" validate:"min=0"`
+	IsActive        *bool `json:"is_active"` // Defaults to true
+}
+
+func (r LoyaltyRuleRequest) input() services.LoyaltyRuleInput {
+	isActive := true
+	if r.IsActive != nil {
+		isActive = *r.IsActive
+	}
+	return services.LoyaltyRuleInput{
+		CategoryID:      r.CategoryID,
+		PointsPerLira:   r.PointsPerLira,
+		RedeemPoints:    r.RedeemPoints,
+		StampsPerItem:   r.StampsPerItem,
+		StampsForReward: r.StampsForReward,
+		IsActive:        isActive,
+	}
+}
+
+type OrderCustomerRequest struct {
+	Phone string `json:"phone" validate:"max=30"` // Empty unlinks the customer
+}
+
+type RedeemLoyaltyRequest struct {
+	Points     int64            `json:"points" validate:"min=0"`
+	CategoryID *uint            `json:"category_id"` // Stamp card to redeem instead of points
+	Override   *OverrideRequest `json:"override"`
+}
+
+// ListRules handles GET /loyalty/rules
+// Sadakat kurallarını listeler
+func (h *LoyaltyHandler) ListRules(c *fiber.Ctx) error {
+	rules, err := h.service.ListRules()
+	if err != nil {
+		return utils.InternalError(c, utils.CodeInternalError, "Could not fetch loyalty rules")
+	}
+	return utils.Success(c, fiber.StatusOK, utils.CodeOK, "Loyalty rules retrieved", rules)
+}
+
+// CreateRule handles POST /loyalty/rules
+// Bir kategori için sadakat kuralı oluşturur
+func (h *LoyaltyHandler) CreateRule(c *fiber.Ctx) error {
+	var req LoyaltyRuleRequest
+	if err := middleware.ValidateBody(c, &req); err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, err.Error())
+	}
+
+	rule, err := h.service.CreateRule(req.input())
+	if err != nil {
+		return loyaltyError(c, err)
+	}
+	return utils.Success(c, fiber.StatusCreated, utils.CodeOK, "Loyalty rule created", rule)
+}
+
+// UpdateRule handles PUT /loyalty/rules/:id
+// Sadakat kuralını günceller
+func (h *LoyaltyHandler) UpdateRule(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, "Invalid Rule ID")
+	}
+
+	var req LoyaltyRuleRequest
+	if err := middleware.ValidateBody(c, &req); err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, err.Error())
+	}
+
+	rule, err := h.service.UpdateRule(uint(id), req.input())
+	if err != nil {
+		return loyaltyError(c, err)
+	}
+	return utils.Success(c, fiber.StatusOK, utils.CodeOK, "Loyalty rule updated", rule)
+}
+
+// DeleteRule handles DELETE /loyalty/rules/:id
+// Sadakat kuralını siler
+func (h *LoyaltyHandler) DeleteRule(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, "Invalid Rule ID")
+	}
+
+	if err := h.service.DeleteRule(uint(id)); err != nil {
+		return loyaltyError(c, err)
+	}
+	return utils.Success(c, fiber.StatusOK, utils.CodeOK, "Loyalty rule deleted", nil)
+}
+
+// AttachCustomer handles PUT /orders/:id/customer
+// Siparişi telefon numarasıyla bir müşteriye bağlar
+func (h *LoyaltyHandler) AttachCustomer(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, "Invalid Order ID")
+	}
+
+	var req OrderCustomerRequest
+	if err := middleware.ValidateBody(c, &req); err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, err.Error())
+	}
+
+	order, err := h.service.AttachCustomer(uint(id), req.Phone)
+	if err != nil {
+		return loyaltyError(c, err)
+	}
+	return utils.Success(c, fiber.StatusOK, utils.CodeOK, "Order customer updated", order)
+}
+
+// Redeem handles POST /orders/:id/loyalty/redeem
+// Puan veya damga kartını sipariş indirimi olarak kullanır
+func (h *LoyaltyHandler) Redeem(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, "Invalid Order ID")
+	}
+
+	var req RedeemLoyaltyRequest
+	if err := middleware.ValidateBody(c, &req); err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, err.Error())
+	}
+
+	userID, _ := c.Locals("userID").(uint)
+
+	order, err := h.service.Redeem(uint(id), services.RedeemInput{
+		Points:     req.Points,
+		CategoryID: req.CategoryID,
+		UserID:     userID,
+		Override:   req.Override.Input(),
+	})
+	if err != nil {
+		return loyaltyError(c, err)
+	}
+	return utils.Success(c, fiber.StatusOK, utils.CodeOK, "Loyalty redeemed", order)
+}
+
+// GetBalance handles GET /customers/:id/loyalty?limit=50
+// Müşterinin puanlarını, damga kartlarını ve geçmişini getirir
+func (h *LoyaltyHandler) GetBalance(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, "Invalid Customer ID")
+	}
+
+	balance, err := h.service.GetBalance(uint(id), c.QueryInt("limit", 50))
+	if err != nil {
+		return loyaltyError(c, err)
+	}
+	return utils.Success(c, fiber.StatusOK, utils.CodeOK, "Loyalty balance retrieved", balance)
+}
+
+// loyaltyError maps loyalty service errors to responses
+// Sadakat servis hatalarını yanıtlara eşler
+func loyaltyError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, services.ErrLoyaltyRuleNotFound), errors.Is(err, services.ErrCustomerNotFound):
+		return utils.NotFoundError(c, utils.CodeResourceNotFound, err.Error())
+	case errors.Is(err, services.ErrNotEnoughPoints), errors.Is(err, services.ErrNotEnoughStamps),
+		errors.Is(err, services.ErrOrderHasDiscount):
+		return utils.Error(c, fiber.StatusConflict, utils.CodeInvalidInput, err.Error())
+	default:
+		return overrideError(c, err, utils.CodeInvalidInput)
+	}
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"simple-pos/internal/middleware"
 	"simple-pos/internal/services"
 	"simple-pos/pkg/utils"
 
@@ -16,7 +17,15 @@ func NewManagementHandler(service *services.ManagementService) *ManagementHandle
 }
 
 type WorkPeriodRequest struct {
-	UserID uint `json:"user_id"`
+	UserID       uint   `json:"user_id"`
+	OpeningFloat int64  `json:"opening_float"` // Start-day: cash put into the drawer
+	CountedCash  *int64 `json:"counted_cash"`  // End-day: blind count of the drawer
+}
+
+type CashMovementRequest struct {
+	Type   string `json:"type" validate:"required,oneof=CASH_IN DROP PAYOUT"`
+	Amount int64  `json:"amount" validate:"required,min=1"`
+	Reason string `json:"reason" validate:"max=255"`
 }
 
 // StartDay handles the start day request
@@ -27,7 +36,7 @@ func (h *ManagementHandler) StartDay(c *fiber.Ctx) error {
 		return utils.BadRequestError(c, utils.CodeInvalidInput, "Invalid request body")
 	}
 
-	if err := h.service.StartDay(req.UserID); err != nil {
+	if err := h.service.StartDay(req.UserID, req.OpeningFloat); err != nil {
 		return utils.BadRequestError(c, utils.CodeTransactionFailed, err.Error())
 	}
 
@@ -64,7 +73,14 @@ func (h *ManagementHandler) EndDay(c *fiber.Ctx) error {
 		return utils.BadRequestError(c, utils.CodeInvalidInput, "Invalid request body")
 	}
 
-	report, err := h.service.EndDay(req.UserID)
+	// The authenticated user closes the day, the body value is kept for older clients
+	// Günü oturum açmış kullanıcı kapatır, gövdedeki değer eski istemciler için korunur
+	userID, _ := c.Locals("userID").(uint)
+	if userID == 0 {
+		userID = req.UserID
+	}
+
+	report, err := h.service.EndDay(userID, req.CountedCash)
 	if err != nil {
 		return utils.BadRequestError(c, utils.CodeTransactionFailed, err.Error())
 	}
@@ -73,3 +89,50 @@ func (h *ManagementHandler) EndDay(c *fiber.Ctx) error {
 		"report": report,
 	})
 }
+
+// RecordCashMovement records a cash in, drop or pay-out on the drawer
+// Kasaya nakit girişi, kasadan alım veya ödeme kaydeder
+func (h *ManagementHandler) RecordCashMovement(c *fiber.Ctx) error {
+	var req CashMovementRequest
+	if err := middleware.ValidateBody(c, &req); err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, err.Error())
+	}
+
+	userID, _ := c.Locals("userID").(uint)
+	movement, err := h.service.RecordCashMovement(userID, req.Type, req.Amount, req.Reason)
+	if err != nil {
+		return utils.BadRequestError(c, utils.CodeTransactionFailed, err.Error())
+	}
+
+	return utils.Success(c, fiber.StatusCreated, utils.CodeOK, "Cash movement recorded", movement)
+}
+
+// ListCashMovements returns the drawer movements of the active period
+// Aktif dönemin kasa hareketlerini döndürür
+func (h *ManagementHandler) ListCashMovements(c *fiber.Ctx) error {
+	movements, err := h.service.ListCashMovements()
+	if err != nil {
+		return utils.InternalError(c, utils.CodeInternalError, "Failed to fetch cash movements")
+	}
+
+	return utils.Success(c, fiber.StatusOK, utils.CodeOK, "Cash movements retrieved", movements)
+}
+
+// RebuildProductStats handles POST /management/product-stats/rebuild?start_date=...&end_date=...
+// Tarih aralığındaki kapanmış dönemlerin ürün satış istatistiklerini yeniden hesaplar
+func (h *ManagementHandler) RebuildProductStats(c *fiber.Ctx) error {
+	startDate, endDate, err := parseDateRange(c)
+	if err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, err.Error())
+	}
+
+	periods, rows, err := h.service.RebuildProductStats(startDate, endDate)
+	if err != nil {
+		return utils.InternalError(c, utils.CodeInternalError, "Failed to rebuild product stats")
+	}
+
+	return utils.Success(c, fiber.StatusOK, utils.CodeOK, "Product stats rebuilt", fiber.Map{
+		"periods": periods,
+		"rows":    rows,
+	})
+}
@@ -0,0 +1,177 @@
+package handlers
+
+import (
+	"simple-pos/internal/middleware"
+	"simple-pos/internal/services"
+	"simple-pos/pkg/constants"
+	"simple-pos/pkg/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ModifierHandler struct {
+	service *services.ModifierService
+}
+
+func NewModifierHandler(service *services.ModifierService) *ModifierHandler {
+	return &ModifierHandler{service: service}
+}
+
+type ModifierGroupRequest struct {
+	Name       string `json:"name" validate:"required"`
+	ProductID  *uint  `json:"product_id"`
+	CategoryID *uint  `json:"category_id"`
+	IsRequired bool   `json:"is_required"`
+	MinSelect  int    `json:"min_select" validate:"min=0"`
+	MaxSelect  int    `json:"max_select" validate:"min=0"`
+	SortOrder  int    `json:"sort_order"`
+}
+
+type CreateModifierOptionRequest struct {
+	Name       string `json:"name" validate:"required"`
+	PriceDelta int64  `json:"price_delta"`
+	SortOrder  int    `json:"sort_order"`
+}
+
+type UpdateModifierOptionRequest struct {
+	Name        string `json:"name" validate:"required"`
+	PriceDelta  int64  `json:"price_delta"`
+	IsAvailable bool   `json:"is_available"`
+	SortOrder   int    `json:"sort_order"`
+}
+
+// CreateGroup handles POST /modifier-groups
+// Yeni opsiyon grubu oluşturur
+func (h *ModifierHandler) CreateGroup(c *fiber.Ctx) error {
+	var req ModifierGroupRequest
+	if err := middleware.ValidateBody(c, &req); err != nil {
+		return err
+	}
+
+	group, err := h.service.CreateGroup(req.Name, req.ProductID, req.CategoryID, req.IsRequired, req.MinSelect, req.MaxSelect, req.SortOrder)
+	if err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, err.Error())
+	}
+
+	return utils.Success(c, fiber.StatusCreated, string(constants.CODE_CREATED), "Modifier group created", group)
+}
+
+// ListGroups handles GET /modifier-groups
+// Tüm opsiyon gruplarını listeler
+func (h *ModifierHandler) ListGroups(c *fiber.Ctx) error {
+	groups, err := h.service.ListGroups()
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "Could not fetch modifier groups")
+	}
+
+	return middleware.SuccessResponse(c, constants.CODE_SUCCESS, "Modifier groups retrieved", groups)
+}
+
+// GetProductModifiers handles GET /products/:id/modifiers
+// Ürüne uygulanan opsiyon gruplarını getirir
+func (h *ModifierHandler) GetProductModifiers(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid product ID")
+	}
+
+	groups, err := h.service.GetProductModifiers(uint(id))
+	if err != nil {
+		return utils.NotFoundError(c, utils.CodeResourceNotFound, err.Error())
+	}
+
+	return middleware.SuccessResponse(c, constants.CODE_SUCCESS, "Product modifiers retrieved", groups)
+}
+
+// UpdateGroup handles PUT /modifier-groups/:id
+// Opsiyon grubunu günceller
+func (h *ModifierHandler) UpdateGroup(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid ID")
+	}
+
+	var req ModifierGroupRequest
+	if err := middleware.ValidateBody(c, &req); err != nil {
+		return err
+	}
+
+	group, err := h.service.UpdateGroup(uint(id), req.Name, req.ProductID, req.CategoryID, req.IsRequired, req.MinSelect, req.MaxSelect, req.SortOrder)
+	if err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, err.Error())
+	}
+
+	return middleware.SuccessResponse(c, constants.CODE_UPDATED, "Modifier group updated", group)
+}
+
+// DeleteGroup handles DELETE /modifier-groups/:id
+// Opsiyon grubunu siler
+func (h *ModifierHandler) DeleteGroup(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid ID")
+	}
+
+	if err := h.service.DeleteGroup(uint(id)); err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "Could not delete modifier group")
+	}
+
+	return middleware.SuccessResponse(c, constants.CODE_DELETED, "Modifier group deleted", nil)
+}
+
+// AddOption handles POST /modifier-groups/:id/options
+// Opsiyon grubuna seçenek ekler
+func (h *ModifierHandler) AddOption(c *fiber.Ctx) error {
+	groupID, err := c.ParamsInt("id")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid ID")
+	}
+
+	var req CreateModifierOptionRequest
+	if err := middleware.ValidateBody(c, &req); err != nil {
+		return err
+	}
+
+	option, err := h.service.AddOption(uint(groupID), req.Name, req.PriceDelta, req.SortOrder)
+	if err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, err.Error())
+	}
+
+	return utils.Success(c, fiber.StatusCreated, string(constants.CODE_CREATED), "Modifier option created", option)
+}
+
+// UpdateOption handles PUT /modifier-options/:id
+// Opsiyon seçeneğini günceller
+func (h *ModifierHandler) UpdateOption(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid ID")
+	}
+
+	var req UpdateModifierOptionRequest
+	if err := middleware.ValidateBody(c, &req); err != nil {
+		return err
+	}
+
+	option, err := h.service.UpdateOption(uint(id), req.Name, req.PriceDelta, req.IsAvailable, req.SortOrder)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "Could not update modifier option")
+	}
+
+	return middleware.SuccessResponse(c, constants.CODE_UPDATED, "Modifier option updated", option)
+}
+
+// DeleteOption handles DELETE /modifier-options/:id
+// Opsiyon seçeneğini siler
+func (h *ModifierHandler) DeleteOption(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid ID")
+	}
+
+	if err := h.service.DeleteOption(uint(id)); err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "Could not delete modifier option")
+	}
+
+	return middleware.SuccessResponse(c, constants.CODE_DELETED, "Modifier option deleted", nil)
+}
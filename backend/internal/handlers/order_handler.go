@@ -1,9 +1,11 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
 	"simple-pos/internal/middleware"
 	"simple-pos/internal/services"
+	"simple-pos/pkg/constants"
 	"simple-pos/pkg/utils"
 	"time"
 
@@ -19,8 +21,9 @@ func NewOrderHandler(s *services.OrderService) *OrderHandler {
 }
 
 type CreateOrderRequest struct {
-	TableID  *uint `json:"table_id"`
-	WaiterID uint  `json:"waiter_id" validate:"required"`
+	TableID  *uint  `json:"table_id"`
+	WaiterID uint   `json:"waiter_id" validate:"required"`
+	Note     string `json:"note" validate:"max=255"`
 }
 
 // Create handles POST /orders
@@ -35,7 +38,7 @@ func (h *OrderHandler) Create(c *fiber.Ctx) error {
 	// Basit sipariş numarası oluştur
 	orderNumber := fmt.Sprintf("ORD-%d", time.Now().Unix())
 
-	order, err := h.service.CreateOrder(req.TableID, req.WaiterID, orderNumber)
+	order, err := h.service.CreateOrder(req.TableID, req.WaiterID, orderNumber, req.Note)
 	if err != nil {
 		// Could differentiate errors here if service returned typed errors
 		return utils.BadRequestError(c, utils.CodeOK, err.Error())
@@ -111,7 +114,10 @@ func (h *OrderHandler) GetOrdersByTable(c *fiber.Ctx) error {
 }
 
 type CloseOrderRequest struct {
-	PaymentMethod string `json:"payment_method" validate:"required,oneof=CASH CREDIT_CARD"`
+	// Optional once the order is fully paid via /payments
+	// Sipariş /payments ile tamamen ödendiyse opsiyoneldir
+	PaymentMethod string `json:"payment_method" validate:"omitempty,oneof=CASH CREDIT_CARD ON_ACCOUNT"`
+	CustomerID    *uint  `json:"customer_id"` // Required with ON_ACCOUNT
 }
 
 // Close handles POST /orders/:id/close
@@ -127,7 +133,9 @@ func (h *OrderHandler) Close(c *fiber.Ctx) error {
 		return utils.BadRequestError(c, utils.CodeInvalidInput, err.Error())
 	}
 
-	if err := h.service.CloseOrder(uint(id), req.PaymentMethod); err != nil {
+	userID, _ := c.Locals("userID").(uint)
+
+	if err := h.service.CloseOrder(uint(id), req.PaymentMethod, req.CustomerID, userID); err != nil {
 		return utils.BadRequestError(c, utils.CodeTransactionFailed, err.Error())
 	}
 
@@ -135,13 +143,19 @@ func (h *OrderHandler) Close(c *fiber.Ctx) error {
 }
 
 type AddItemRequest struct {
-	ProductID uint   `json:"product_id" validate:"required"`
-	Quantity  int    `json:"quantity" validate:"required,min=1"`
-	Note      string `json:"note"`
+	ProductID         uint   `json:"product_id" validate:"required"`
+	Quantity          int    `json:"quantity" validate:"required,min=1"`
+	Note              string `json:"note" validate:"max=255"`
+	ModifierOptionIDs []uint `json:"modifier_option_ids"`
 }
 
 type UpdateItemRequest struct {
-	Quantity int `json:"quantity" validate:"required,min=1"`
+	Quantity int     `json:"quantity" validate:"omitempty,min=1"`
+	Note     *string `json:"note" validate:"omitempty,max=255"` // nil leaves the note unchanged
+}
+
+type UpdateOrderNoteRequest struct {
+	Note string `json:"note" validate:"max=255"`
 }
 
 // AddItem adds an item to an order
@@ -157,18 +171,18 @@ func (h *OrderHandler) AddItem(c *fiber.Ctx) error {
 		return utils.BadRequestError(c, utils.CodeInvalidInput, err.Error())
 	}
 
-	item, err := h.service.AddOrderItem(uint(id), req.ProductID, req.Quantity, req.Note)
+	item, err := h.service.AddOrderItem(uint(id), req.ProductID, req.Quantity, req.Note, req.ModifierOptionIDs)
 	if err != nil {
 		// Differentiating strict errors would be better, but generic 400/500 is ok for now.
 		// Since validation happens in service (Closed order etc), 400 is often appropriate for business rule failure.
-		return utils.BadRequestError(c, utils.CodeInvalidInput, err.Error())
+		return utils.BadRequestError(c, itemErrorCode(err), err.Error())
 	}
 
 	return utils.Success(c, fiber.StatusCreated, utils.CodeOK, "Item added successfully", item)
 }
 
-// UpdateItem updates item quantity
-// Ürün adedini günceller
+// UpdateItem updates item quantity and/or note
+// Ürün adedini ve/veya notunu günceller
 func (h *OrderHandler) UpdateItem(c *fiber.Ctx) error {
 	orderID, err := c.ParamsInt("id")
 	if err != nil {
@@ -184,13 +198,46 @@ func (h *OrderHandler) UpdateItem(c *fiber.Ctx) error {
 		return utils.BadRequestError(c, utils.CodeInvalidInput, err.Error())
 	}
 
-	if err := h.service.UpdateItemQuantity(uint(orderID), uint(itemID), req.Quantity); err != nil {
-		return utils.BadRequestError(c, utils.CodeInvalidInput, err.Error())
+	if req.Quantity == 0 && req.Note == nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, "Nothing to update")
+	}
+
+	if req.Quantity > 0 {
+		if err := h.service.UpdateItemQuantity(uint(orderID), uint(itemID), req.Quantity); err != nil {
+			return utils.BadRequestError(c, itemErrorCode(err), err.Error())
+		}
+	}
+
+	if req.Note != nil {
+		if err := h.service.UpdateItemNote(uint(orderID), uint(itemID), *req.Note); err != nil {
+			return utils.BadRequestError(c, utils.CodeInvalidInput, err.Error())
+		}
 	}
 
 	return utils.Success(c, fiber.StatusOK, utils.CodeOK, "Item updated successfully", nil)
 }
 
+// UpdateNote handles PUT /orders/:id/note
+// Siparişin genel notunu günceller
+func (h *OrderHandler) UpdateNote(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, "Invalid Order ID")
+	}
+
+	var req UpdateOrderNoteRequest
+	if err := middleware.ValidateBody(c, &req); err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, err.Error())
+	}
+
+	order, err := h.service.UpdateOrderNote(uint(id), req.Note)
+	if err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, err.Error())
+	}
+
+	return utils.Success(c, fiber.StatusOK, utils.CodeOK, "Order note updated", order)
+}
+
 // RemoveItem removes an item from order
 // Siparişten ürün siler
 func (h *OrderHandler) RemoveItem(c *fiber.Ctx) error {
@@ -203,13 +250,35 @@ func (h *OrderHandler) RemoveItem(c *fiber.Ctx) error {
 		return utils.BadRequestError(c, utils.CodeInvalidInput, "Invalid Item ID")
 	}
 
-	if err := h.service.RemoveOrderItem(uint(orderID), uint(itemID)); err != nil {
+	userID, _ := c.Locals("userID").(uint)
+
+	if err := h.service.RemoveOrderItem(uint(orderID), uint(itemID), userID); err != nil {
 		return utils.BadRequestError(c, utils.CodeInvalidInput, err.Error())
 	}
 
 	return utils.Success(c, fiber.StatusOK, utils.CodeOK, "Item removed successfully", nil)
 }
 
+// OverrideRequest carries a manager PIN approving a restricted action
+// Kısıtlı bir işlemi onaylayan yönetici PIN'ini taşır
+type OverrideRequest struct {
+	ApproverID uint   `json:"approver_id" validate:"required"`
+	Pin        string `json:"pin" validate:"required,numeric,len=4"`
+}
+
+// Input converts the request to the service input, nil stays nil
+// İsteği servis girdisine çevirir, nil olarak kalır
+func (r *OverrideRequest) Input() *services.OverrideInput {
+	if r == nil {
+		return nil
+	}
+	return &services.OverrideInput{ApproverID: r.ApproverID, Pin: r.Pin}
+}
+
+type CancelOrderRequest struct {
+	Override *OverrideRequest `json:"override"`
+}
+
 // Cancel handles DELETE /orders/:id
 // Siparişi iptal eder (siler)
 func (h *OrderHandler) Cancel(c *fiber.Ctx) error {
@@ -218,17 +287,29 @@ func (h *OrderHandler) Cancel(c *fiber.Ctx) error {
 		return utils.BadRequestError(c, utils.CodeInvalidInput, "Invalid Order ID")
 	}
 
-	if err := h.service.CancelOrder(uint(id)); err != nil {
-		return utils.BadRequestError(c, utils.CodeInvalidInput, err.Error())
+	// The body is optional, it only carries the manager approval
+	// Gövde isteğe bağlıdır, yalnızca yönetici onayını taşır
+	var req CancelOrderRequest
+	if len(c.Body()) > 0 {
+		if err := middleware.ValidateBody(c, &req); err != nil {
+			return utils.BadRequestError(c, utils.CodeInvalidInput, err.Error())
+		}
+	}
+
+	userID, _ := c.Locals("userID").(uint)
+
+	if err := h.service.CancelOrder(uint(id), userID, req.Override.Input()); err != nil {
+		return overrideError(c, err, utils.CodeInvalidInput)
 	}
 
 	return utils.Success(c, fiber.StatusOK, utils.CodeOK, "Order cancelled successfully", nil)
 }
 
 type ApplyDiscountRequest struct {
-	Type   string `json:"type" validate:"required,oneof=AMOUNT PERCENTAGE NONE"`
-	Value  int64  `json:"value" validate:"min=0"`
-	Reason string `json:"reason" validate:"required,min=3"`
+	Type     string           `json:"type" validate:"required,oneof=AMOUNT PERCENTAGE NONE"`
+	Value    int64            `json:"value" validate:"min=0"`
+	Reason   string           `json:"reason" validate:"required,min=3"`
+	Override *OverrideRequest `json:"override"`
 }
 
 // ApplyDiscount handles POST /orders/:id/discount
@@ -244,10 +325,249 @@ func (h *OrderHandler) ApplyDiscount(c *fiber.Ctx) error {
 		return utils.BadRequestError(c, utils.CodeInvalidInput, err.Error())
 	}
 
-	order, err := h.service.ApplyDiscount(uint(id), req.Type, req.Value, req.Reason)
+	userID, _ := c.Locals("userID").(uint)
+
+	order, err := h.service.ApplyDiscount(uint(id), req.Type, req.Value, req.Reason, userID, req.Override.Input())
 	if err != nil {
-		return utils.BadRequestError(c, utils.CodeInvalidInput, err.Error())
+		return overrideError(c, err, utils.CodeInvalidInput)
 	}
 
 	return utils.Success(c, fiber.StatusOK, utils.CodeOK, "Discount applied successfully", order)
 }
+
+type ApplyCouponRequest struct {
+	Code string `json:"code" validate:"required,max=50"`
+}
+
+// ApplyCoupon handles POST /orders/:id/coupon
+// Siparişe kupon kodu uygular
+func (h *OrderHandler) ApplyCoupon(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, "Invalid Order ID")
+	}
+
+	var req ApplyCouponRequest
+	if err := middleware.ValidateBody(c, &req); err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, err.Error())
+	}
+
+	order, err := h.service.ApplyCoupon(uint(id), req.Code)
+	if err != nil {
+		return couponError(c, err)
+	}
+
+	return utils.Success(c, fiber.StatusOK, utils.CodeOK, "Coupon applied successfully", order)
+}
+
+// RemoveCoupon handles DELETE /orders/:id/coupon
+// Siparişten kuponu kaldırır
+func (h *OrderHandler) RemoveCoupon(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, "Invalid Order ID")
+	}
+
+	order, err := h.service.RemoveCoupon(uint(id))
+	if err != nil {
+		return couponError(c, err)
+	}
+
+	return utils.Success(c, fiber.StatusOK, utils.CodeOK, "Coupon removed successfully", order)
+}
+
+type PaymentItemRequest struct {
+	ItemID   uint `json:"item_id" validate:"required"`
+	Quantity int  `json:"quantity" validate:"required,min=1"`
+}
+
+type AddPaymentRequest struct {
+	Method     string               `json:"method" validate:"required,oneof=CASH CREDIT_CARD"`
+	SplitType  string               `json:"split_type" validate:"required,oneof=FULL AMOUNT ITEMS EQUAL"`
+	Amount     int64                `json:"amount" validate:"min=0"`
+	Items      []PaymentItemRequest `json:"items" validate:"dive"`
+	SplitCount int                  `json:"split_count" validate:"min=0"`
+	Shares     int                  `json:"shares" validate:"min=0"`
+}
+
+// AddPayment handles POST /orders/:id/payments
+// Siparişe kısmi ödeme ekler
+func (h *OrderHandler) AddPayment(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, "Invalid Order ID")
+	}
+
+	var req AddPaymentRequest
+	if err := middleware.ValidateBody(c, &req); err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, err.Error())
+	}
+
+	userID, _ := c.Locals("userID").(uint)
+
+	input := services.PaymentInput{
+		Method:     req.Method,
+		SplitType:  req.SplitType,
+		Amount:     req.Amount,
+		SplitCount: req.SplitCount,
+		Shares:     req.Shares,
+		UserID:     userID,
+	}
+	for _, item := range req.Items {
+		input.Items = append(input.Items, services.PaymentItemInput{ItemID: item.ItemID, Quantity: item.Quantity})
+	}
+
+	payment, order, err := h.service.AddPayment(uint(id), input)
+	if err != nil {
+		return utils.BadRequestError(c, utils.CodeTransactionFailed, err.Error())
+	}
+
+	return utils.Success(c, fiber.StatusCreated, utils.CodeOK, "Payment recorded", fiber.Map{
+		"payment":          payment,
+		"total_amount":     order.TotalAmount,
+		"paid_amount":      order.PaidAmount,
+		"remaining_amount": order.RemainingAmount(),
+	})
+}
+
+// GetPayments handles GET /orders/:id/payments
+// Siparişin ödemelerini ve kalan bakiyesini getirir
+func (h *OrderHandler) GetPayments(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, "Invalid Order ID")
+	}
+
+	order, err := h.service.GetPayments(uint(id))
+	if err != nil {
+		return utils.BadRequestError(c, utils.CodeResourceNotFound, "Order not found")
+	}
+
+	return utils.Success(c, fiber.StatusOK, utils.CodeOK, "Order payments", fiber.Map{
+		"payments":         order.Payments,
+		"total_amount":     order.TotalAmount,
+		"paid_amount":      order.PaidAmount,
+		"remaining_amount": order.RemainingAmount(),
+	})
+}
+
+type TransferOrderRequest struct {
+	TableID uint `json:"table_id" validate:"required"`
+}
+
+// Transfer handles POST /orders/:id/transfer
+// Siparişi başka bir masaya taşır
+func (h *OrderHandler) Transfer(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, "Invalid Order ID")
+	}
+
+	var req TransferOrderRequest
+	if err := middleware.ValidateBody(c, &req); err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, err.Error())
+	}
+
+	userID, _ := c.Locals("userID").(uint)
+
+	order, err := h.service.TransferOrder(uint(id), req.TableID, userID)
+	if err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, err.Error())
+	}
+
+	return utils.Success(c, fiber.StatusOK, utils.CodeOK, "Order transferred successfully", order)
+}
+
+type MoveItemRequest struct {
+	ItemID   uint `json:"item_id" validate:"required"`
+	Quantity int  `json:"quantity" validate:"required,min=1"`
+}
+
+type MoveItemsRequest struct {
+	TargetOrderID uint              `json:"target_order_id" validate:"required"`
+	Items         []MoveItemRequest `json:"items" validate:"required,min=1,dive"`
+}
+
+// MoveItems handles POST /orders/:id/move-items
+// Seçilen kalemleri başka bir siparişe taşır
+func (h *OrderHandler) MoveItems(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, "Invalid Order ID")
+	}
+
+	var req MoveItemsRequest
+	if err := middleware.ValidateBody(c, &req); err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, err.Error())
+	}
+
+	userID, _ := c.Locals("userID").(uint)
+
+	selections := make([]services.MoveItemInput, 0, len(req.Items))
+	for _, item := range req.Items {
+		selections = append(selections, services.MoveItemInput{ItemID: item.ItemID, Quantity: item.Quantity})
+	}
+
+	source, target, err := h.service.MoveItems(uint(id), req.TargetOrderID, selections, userID)
+	if err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, err.Error())
+	}
+
+	return utils.Success(c, fiber.StatusOK, utils.CodeOK, "Items moved successfully", fiber.Map{
+		"source_order": source,
+		"target_order": target,
+	})
+}
+
+type MergeTablesRequest struct {
+	TargetTableID uint `json:"target_table_id" validate:"required"`
+}
+
+// MergeTables handles POST /tables/:id/merge
+// Masanın açık siparişlerini hedef masanın siparişiyle birleştirir
+func (h *OrderHandler) MergeTables(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, "Invalid Table ID")
+	}
+
+	var req MergeTablesRequest
+	if err := middleware.ValidateBody(c, &req); err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, err.Error())
+	}
+
+	userID, _ := c.Locals("userID").(uint)
+
+	order, err := h.service.MergeTables(uint(id), req.TargetTableID, userID)
+	if err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, err.Error())
+	}
+
+	return utils.Success(c, fiber.StatusOK, utils.CodeOK, "Tables merged successfully", order)
+}
+
+// itemErrorCode maps order item errors to response codes so clients can tell stock shortages apart
+// Sipariş kalemi hatalarını yanıt kodlarına eşler, böylece istemci stok yetersizliğini ayırt edebilir
+func itemErrorCode(err error) string {
+	if errors.Is(err, services.ErrInsufficientStock) {
+		return string(constants.CODE_INSUFFICIENT_STOCK)
+	}
+	return utils.CodeInvalidInput
+}
+
+// overrideError answers missing or rejected manager approvals with 403 so clients can ask for a PIN,
+// a locked out approver with 429, other errors are reported as bad requests with the given code
+// Eksik veya reddedilen yönetici onaylarını 403 ile yanıtlar ki istemci PIN isteyebilsin,
+// kilitlenen onaylayanı 429 ile, diğer hatalar verilen kodla hatalı istek olarak bildirilir
+func overrideError(c *fiber.Ctx, err error, code string) error {
+	switch {
+	case errors.Is(err, services.ErrOverrideRequired):
+		return utils.Error(c, fiber.StatusForbidden, utils.CodeOverrideRequired, err.Error())
+	case errors.Is(err, services.ErrOverrideInvalid):
+		return utils.Error(c, fiber.StatusForbidden, utils.CodeOverrideInvalid, err.Error())
+	case errors.Is(err, services.ErrOverrideLocked):
+		return utils.Error(c, fiber.StatusTooManyRequests, utils.CodeOverrideLocked, err.Error())
+	default:
+		return utils.BadRequestError(c, code, err.Error())
+	}
+}
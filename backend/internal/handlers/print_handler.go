@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"errors"
+	"simple-pos/internal/platform/printing"
+	"simple-pos/internal/services"
+	"simple-pos/pkg/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type PrintHandler struct {
+	service *services.PrintService
+}
+
+func NewPrintHandler(service *services.PrintService) *PrintHandler {
+	return &PrintHandler{service: service}
+}
+
+// GetReceipt handles GET /orders/:id/receipt and returns the raw ESC/POS stream
+// Siparişin fişini ham ESC/POS akışı olarak döndürür
+func (h *PrintHandler) GetReceipt(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, "Invalid Order ID")
+	}
+
+	data, err := h.service.RenderReceipt(uint(id))
+	if err != nil {
+		return utils.NotFoundError(c, utils.CodeResourceNotFound, err.Error())
+	}
+
+	c.Set(fiber.HeaderContentType, fiber.MIMEOctetStream)
+	return c.Send(data)
+}
+
+// PrintReceipt handles POST /orders/:id/receipt/print
+// Siparişin fişini fiş yazıcısına (yeniden) yazdırır
+func (h *PrintHandler) PrintReceipt(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, "Invalid Order ID")
+	}
+
+	if err := h.service.PrintReceipt(uint(id)); err != nil {
+		return printError(c, err)
+	}
+
+	return utils.Success(c, fiber.StatusOK, utils.CodeOK, "Receipt sent to printer", nil)
+}
+
+// PrintKitchenTicket handles POST /kitchen/tickets/:id/print
+// Mutfak ticket'ını istasyon yazıcısına (yeniden) yazdırır
+func (h *PrintHandler) PrintKitchenTicket(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, "Invalid Ticket ID")
+	}
+
+	if err := h.service.PrintKitchenTicket(uint(id)); err != nil {
+		return printError(c, err)
+	}
+
+	return utils.Success(c, fiber.StatusOK, utils.CodeOK, "Ticket sent to printer", nil)
+}
+
+// printError maps printing failures: unconfigured printer is a client error, unreachable printer a server error
+// Yazdırma hatalarını eşler: yapılandırılmamış yazıcı istemci, ulaşılamayan yazıcı sunucu hatasıdır
+func printError(c *fiber.Ctx, err error) error {
+	if errors.Is(err, printing.ErrNoPrinter) {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, err.Error())
+	}
+	if errors.Is(err, services.ErrReceiptNotFound) || errors.Is(err, services.ErrTicketNotFound) {
+		return utils.NotFoundError(c, utils.CodeResourceNotFound, err.Error())
+	}
+	return utils.InternalError(c, utils.CodeInternalError, err.Error())
+}
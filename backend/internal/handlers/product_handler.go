@@ -24,6 +24,7 @@ type CreateProductRequest struct {
 	CategoryID  uint   `json:"category_id" validate:"required"`
 	Description string `json:"description"`
 	ImageURL    string `json:"image_url"`
+	TaxRate     *int   `json:"tax_rate" validate:"omitempty,min=0,max=100"` // KDV % (nil = inherit from category)
 }
 
 type UpdateProductRequest struct {
@@ -33,6 +34,7 @@ type UpdateProductRequest struct {
 	Description string `json:"description"`
 	ImageURL    string `json:"image_url"`
 	CategoryID  uint   `json:"category_id"`
+	TaxRate     *int   `json:"tax_rate" validate:"omitempty,min=0,max=100"` // KDV % (nil = inherit from category)
 }
 
 // Create handles new product creation
@@ -43,7 +45,7 @@ func (h *ProductHandler) Create(c *fiber.Ctx) error {
 		return err
 	}
 
-	product, err := h.service.CreateProduct(req.Name, req.Price, req.CategoryID, req.Description, req.ImageURL)
+	product, err := h.service.CreateProduct(req.Name, req.Price, req.CategoryID, req.Description, req.ImageURL, req.TaxRate)
 	if err != nil {
 		return fiber.NewError(fiber.StatusInternalServerError, "Could not create product")
 	}
@@ -85,7 +87,7 @@ func (h *ProductHandler) Update(c *fiber.Ctx) error {
 		return err
 	}
 
-	product, err := h.service.UpdateProduct(uint(id), req.Name, req.Price, req.IsAvailable, req.Description, req.ImageURL, req.CategoryID)
+	product, err := h.service.UpdateProduct(uint(id), req.Name, req.Price, req.IsAvailable, req.Description, req.ImageURL, req.CategoryID, req.TaxRate)
 	if err != nil {
 		return fiber.NewError(fiber.StatusInternalServerError, "Could not update product")
 	}
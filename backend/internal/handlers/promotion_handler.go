@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"errors"
+	"simple-pos/internal/middleware"
+	"simple-pos/internal/models"
+	"simple-pos/internal/services"
+	"simple-pos/pkg/utils"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type PromotionHandler struct {
+	service *services.PromotionService
+}
+
+func NewPromotionHandler(service *services.PromotionService) *PromotionHandler {
+	return &PromotionHandler{service: service}
+}
+
+type PromotionComboItemRequest struct {
+	ProductID uint `json:"product_id" validate:"required"`
+	Quantity  int  `json:"quantity" validate:"min=1"`
+}
+
+type PromotionRequest struct {
+	Name        string                      `json:"name" validate:"required,max=100"`
+	Type        string                      `json:"type" validate:"required,oneof=HAPPY_HOUR COMBO BUY_X_GET_Y CATEGORY_PERCENT"`
+	CategoryID  *uint                       `json:"category_id"`
+	ProductID   *uint                       `json:"product_id"`
+	Percent     int                         `json:"percent" validate:"min=0,max=100"`
+	BuyQuantity int                         `json:"buy_quantity" validate:"min=0"`
+	GetQuantity int                         `json:"get_quantity" validate:"min=0"`
+	ComboPrice  int64                       `json:"combo_price" validate:"min=0"`
+	ComboItems  []PromotionComboItemRequest `json:"combo_items" validate:"dive"`
+	StartTime   string                      `json:"start_time"` // HH:MM
+	EndTime     string                      `json:"end_time"`   // HH:MM
+	StartsAt    *time.Time                  `json:"starts_at"`
+	EndsAt      *time.Time                  `json:"ends_at"`
+	IsActive    *bool                       `json:"is_active"` // Defaults to true
+}
+
+func (r PromotionRequest) input() services.PromotionInput {
+	isActive := true
+	if r.IsActive != nil {
+		isActive = *r.IsActive
+	}
+	items := make([]models.PromotionComboItem, 0, len(r.ComboItems))
+	for _, item := range r.ComboItems {
+		items = append(items, models.PromotionComboItem{ProductID: item.ProductID, Quantity: item.Quantity})
+	}
+	return services.PromotionInput{
+		Name:        r.Name,
+		Type:        r.Type,
+		CategoryID:  r.CategoryID,
+		ProductID:   r.ProductID,
+		Percent:     r.Percent,
+		BuyQuantity: r.BuyQuantity,
+		GetQuantity: r.GetQuantity,
+		ComboPrice:  r.ComboPrice,
+		ComboItems:  items,
+		StartTime:   r.StartTime,
+		EndTime:     r.EndTime,
+		StartsAt:    r.StartsAt,
+		EndsAt:      r.EndsAt,
+		IsActive:    isActive,
+	}
+}
+
+// ListPromotions handles GET /promotions?include_inactive=true
+// Promosyonları listeler
+func (h *PromotionHandler) ListPromotions(c *fiber.Ctx) error {
+	promotions, err := h.service.ListPromotions(c.QueryBool("include_inactive"))
+	if err != nil {
+		return utils.InternalError(c, utils.CodeInternalError, "Could not fetch promotions")
+	}
+	return utils.Success(c, fiber.StatusOK, utils.CodeOK, "Promotions retrieved", promotions)
+}
+
+// GetPromotion handles GET /promotions/:id
+// Promosyon detayını getirir
+func (h *PromotionHandler) GetPromotion(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, "Invalid Promotion ID")
+	}
+
+	promotion, err := h.service.GetPromotion(uint(id))
+	if err != nil {
+		return promotionError(c, err)
+	}
+	return utils.Success(c, fiber.StatusOK, utils.CodeOK, "Promotion retrieved", promotion)
+}
+
+// CreatePromotion handles POST /promotions
+// Yeni promosyon oluşturur
+func (h *PromotionHandler) CreatePromotion(c *fiber.Ctx) error {
+	var req PromotionRequest
+	if err := middleware.ValidateBody(c, &req); err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, err.Error())
+	}
+
+	promotion, err := h.service.CreatePromotion(req.input())
+	if err != nil {
+		return promotionError(c, err)
+	}
+	return utils.Success(c, fiber.StatusCreated, utils.CodeOK, "Promotion created", promotion)
+}
+
+// UpdatePromotion handles PUT /promotions/:id
+// Promosyonu günceller
+func (h *PromotionHandler) UpdatePromotion(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, "Invalid Promotion ID")
+	}
+
+	var req PromotionRequest
+	if err := middleware.ValidateBody(c, &req); err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, err.Error())
+	}
+
+	promotion, err := h.service.UpdatePromotion(uint(id), req.input())
+	if err != nil {
+		return promotionError(c, err)
+	}
+	return utils.Success(c, fiber.StatusOK, utils.CodeOK, "Promotion updated", promotion)
+}
+
+// DeletePromotion handles DELETE /promotions/:id
+// Promosyonu siler
+func (h *PromotionHandler) DeletePromotion(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, "Invalid Promotion ID")
+	}
+
+	if err := h.service.DeletePromotion(uint(id)); err != nil {
+		return promotionError(c, err)
+	}
+	return utils.Success(c, fiber.StatusOK, utils.CodeOK, "Promotion deleted", nil)
+}
+
+// promotionError maps promotion service errors to responses
+// Promosyon servis hatalarını yanıtlara eşler
+func promotionError(c *fiber.Ctx, err error) error {
+	if errors.Is(err, services.ErrPromotionNotFound) {
+		return utils.NotFoundError(c, utils.CodeResourceNotFound, err.Error())
+	}
+	return utils.BadRequestError(c, utils.CodeInvalidInput, err.Error())
+}
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"simple-pos/internal/middleware"
+	"simple-pos/internal/services"
+	"simple-pos/pkg/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type RefundHandler struct {
+	service *services.RefundService
+}
+
+func NewRefundHandler(service *services.RefundService) *RefundHandler {
+	return &RefundHandler{service: service}
+}
+
+type RefundItemRequest struct {
+	ItemID   uint `json:"item_id" validate:"required"`
+	Quantity int  `json:"quantity" validate:"required,min=1"`
+}
+
+type RefundOrderRequest struct {
+	Type          string              `json:"type" validate:"required,oneof=ITEMS VOID"`
+	Items         []RefundItemRequest `json:"items" validate:"dive"`
+	ReasonCode    string              `json:"reason_code" validate:"required,oneof=CUSTOMER_COMPLAINT WRONG_ITEM QUALITY OVERCHARGE OTHER"`
+	Note          string              `json:"note" validate:"max=255"`
+	PaymentMethod string              `json:"payment_method" validate:"omitempty,oneof=CASH CREDIT_CARD ON_ACCOUNT"`
+	Restock       bool                `json:"restock"`
+	Override      *OverrideRequest    `json:"override"`
+}
+
+// RefundOrder handles POST /orders/:id/refunds
+// Tamamlanmış siparişin kalemlerini iade eder veya siparişi tamamen iptal eder
+func (h *RefundHandler) RefundOrder(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, "Invalid Order ID")
+	}
+
+	var req RefundOrderRequest
+	if err := middleware.ValidateBody(c, &req); err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, err.Error())
+	}
+
+	userID, _ := c.Locals("userID").(uint)
+
+	input := services.RefundInput{
+		Type:          req.Type,
+		ReasonCode:    req.ReasonCode,
+		Note:          req.Note,
+		PaymentMethod: req.PaymentMethod,
+		Restock:       req.Restock,
+		UserID:        userID,
+		Override:      req.Override.Input(),
+	}
+	for _, item := range req.Items {
+		input.Items = append(input.Items, services.RefundItemInput{ItemID: item.ItemID, Quantity: item.Quantity})
+	}
+
+	refund, err := h.service.RefundOrder(uint(id), input)
+	if err != nil {
+		return overrideError(c, err, utils.CodeTransactionFailed)
+	}
+
+	return utils.Success(c, fiber.StatusCreated, utils.CodeOK, "Refund recorded", refund)
+}
+
+// ListRefunds handles GET /orders/:id/refunds
+// Siparişin iadelerini listeler
+func (h *RefundHandler) ListRefunds(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, "Invalid Order ID")
+	}
+
+	refunds, err := h.service.ListRefunds(uint(id))
+	if err != nil {
+		return utils.NotFoundError(c, utils.CodeResourceNotFound, err.Error())
+	}
+
+	return utils.Success(c, fiber.StatusOK, utils.CodeOK, "Refunds retrieved", refunds)
+}
@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"simple-pos/internal/middleware"
+	"simple-pos/internal/models"
+	"simple-pos/internal/services"
+	"simple-pos/pkg/utils"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ReservationHandler struct {
+	service *services.ReservationService
+}
+
+func NewReservationHandler(service *services.ReservationService) *ReservationHandler {
+	return &ReservationHandler{service: service}
+}
+
+type ReservationRequest struct {
+	GuestName string     `json:"guest_name" validate:"required,max=100"`
+	Phone     string     `json:"phone" validate:"max=30"`
+	PartySize int        `json:"party_size" validate:"required,min=1"`
+	StartTime time.Time  `json:"start_time" validate:"required"`
+	EndTime   *time.Time `json:"end_time"` // Optional, default slot length when omitted
+	TableIDs  []uint     `json:"table_ids" validate:"required,min=1"`
+	Note      string     `json:"note" validate:"max=255"`
+}
+
+func (r ReservationRequest) input() services.ReservationInput {
+	return services.ReservationInput{
+		GuestName: r.GuestName,
+		Phone:     r.Phone,
+		PartySize: r.PartySize,
+		StartTime: r.StartTime,
+		EndTime:   r.EndTime,
+		TableIDs:  r.TableIDs,
+		Note:      r.Note,
+	}
+}
+
+type SeatReservationRequest struct {
+	TableID *uint `json:"table_id"` // Optional, first reserved table when omitted
+}
+
+// ListReservations handles GET /reservations?start_date=...&end_date=...&status=...&table_id=...
+// Rezervasyonları listeler (varsayılan: bugün)
+func (h *ReservationHandler) ListReservations(c *fiber.Ctx) error {
+	startDate, endDate, err := parseDateRange(c)
+	if err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, err.Error())
+	}
+
+	filter := models.ReservationFilter{
+		Start:  startDate,
+		End:    endDate,
+		Status: c.Query("status"),
+	}
+	if filter.TableID, err = queryUint(c, "table_id"); err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, "Invalid table ID")
+	}
+
+	reservations, err := h.service.ListReservations(filter)
+	if err != nil {
+		return utils.InternalError(c, utils.CodeInternalError, "Could not fetch reservations")
+	}
+	return utils.Success(c, fiber.StatusOK, utils.CodeOK, "Reservations retrieved", reservations)
+}
+
+// GetReservation handles GET /reservations/:id
+// Rezervasyon detayını getirir
+func (h *ReservationHandler) GetReservation(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, "Invalid Reservation ID")
+	}
+
+	reservation, err := h.service.GetReservation(uint(id))
+	if err != nil {
+		return reservationError(c, err)
+	}
+	return utils.Success(c, fiber.StatusOK, utils.CodeOK, "Reservation retrieved", reservation)
+}
+
+// CreateReservation handles POST /reservations
+// Yeni rezervasyon oluşturur
+func (h *ReservationHandler) CreateReservation(c *fiber.Ctx) error {
+	var req ReservationRequest
+	if err := middleware.ValidateBody(c, &req); err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, err.Error())
+	}
+
+	userID, _ := c.Locals("userID").(uint)
+
+	reservation, err := h.service.CreateReservation(req.input(), userID)
+	if err != nil {
+		return reservationError(c, err)
+	}
+	return utils.Success(c, fiber.StatusCreated, utils.CodeOK, "Reservation created", reservation)
+}
+
+// UpdateReservation handles PUT /reservations/:id
+// Rezervasyonu günceller
+func (h *ReservationHandler) UpdateReservation(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, "Invalid Reservation ID")
+	}
+
+	var req ReservationRequest
+	if err := middleware.ValidateBody(c, &req); err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, err.Error())
+	}
+
+	reservation, err := h.service.UpdateReservation(uint(id), req.input())
+	if err != nil {
+		return reservationError(c, err)
+	}
+	return utils.Success(c, fiber.StatusOK, utils.CodeOK, "Reservation updated", reservation)
+}
+
+// CancelReservation handles DELETE /reservations/:id
+// Rezervasyonu iptal eder
+func (h *ReservationHandler) CancelReservation(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, "Invalid Reservation ID")
+	}
+
+	reservation, err := h.service.CancelReservation(uint(id))
+	if err != nil {
+		return reservationError(c, err)
+	}
+	return utils.Success(c, fiber.StatusOK, utils.CodeOK, "Reservation cancelled", reservation)
+}
+
+// MarkNoShow handles POST /reservations/:id/no-show
+// Gelmeyen misafirin rezervasyonunu kapatır
+func (h *ReservationHandler) MarkNoShow(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, "Invalid Reservation ID")
+	}
+
+	reservation, err := h.service.MarkNoShow(uint(id))
+	if err != nil {
+		return reservationError(c, err)
+	}
+	return utils.Success(c, fiber.StatusOK, utils.CodeOK, "Reservation released", reservation)
+}
+
+// SeatReservation handles POST /reservations/:id/seat, the caller becomes the waiter of the new order
+// Misafiri masaya oturtur ve sipariş açar, çağıran yeni siparişin garsonu olur
+func (h *ReservationHandler) SeatReservation(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, "Invalid Reservation ID")
+	}
+
+	var req SeatReservationRequest
+	if len(c.Body()) > 0 {
+		if err := middleware.ValidateBody(c, &req); err != nil {
+			return utils.BadRequestError(c, utils.CodeInvalidInput, err.Error())
+		}
+	}
+
+	userID, _ := c.Locals("userID").(uint)
+	orderNumber := fmt.Sprintf("ORD-%d", time.Now().Unix())
+
+	reservation, order, err := h.service.SeatReservation(uint(id), req.TableID, userID, orderNumber)
+	if err != nil {
+		return reservationError(c, err)
+	}
+	return utils.Success(c, fiber.StatusCreated, utils.CodeOK, "Reservation seated", fiber.Map{
+		"reservation": reservation,
+		"order":       order,
+	})
+}
+
+// reservationError maps reservation service errors to responses
+// Rezervasyon servis hatalarını yanıtlara eşler
+func reservationError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, services.ErrReservationNotFound):
+		return utils.NotFoundError(c, utils.CodeResourceNotFound, err.Error())
+	case errors.Is(err, services.ErrReservationConflict):
+		return utils.Error(c, fiber.StatusConflict, utils.CodeInvalidInput, err.Error())
+	default:
+		return utils.BadRequestError(c, utils.CodeInvalidInput, err.Error())
+	}
+}
@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"errors"
+	"simple-pos/internal/middleware"
+	"simple-pos/internal/models"
+	"simple-pos/internal/services"
+	"simple-pos/pkg/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type RoleHandler struct {
+	service *services.RoleService
+}
+
+func NewRoleHandler(service *services.RoleService) *RoleHandler {
+	return &RoleHandler{service: service}
+}
+
+type CreateRoleRequest struct {
+	Name        string   `json:"name" validate:"required"`
+	Description string   `json:"description" validate:"max=255"`
+	Permissions []string `json:"permissions"`
+}
+
+type UpdateRoleRequest struct {
+	Description string   `json:"description" validate:"max=255"`
+	Permissions []string `json:"permissions"`
+}
+
+// ListPermissions returns the permission catalogue
+// Yetki kataloğunu döndürür
+func (h *RoleHandler) ListPermissions(c *fiber.Ctx) error {
+	return utils.Success(c, fiber.StatusOK, utils.CodeOK, "Permissions retrieved", models.AllPermissions)
+}
+
+// ListRoles returns all roles with their permissions
+// Tüm rolleri yetkileriyle döndürür
+func (h *RoleHandler) ListRoles(c *fiber.Ctx) error {
+	roles, err := h.service.ListRoles()
+	if err != nil {
+		return utils.InternalError(c, utils.CodeInternalError, "Failed to fetch roles")
+	}
+	return utils.Success(c, fiber.StatusOK, utils.CodeOK, "Roles retrieved", roles)
+}
+
+// CreateRole adds a new role
+// Yeni bir rol ekler
+func (h *RoleHandler) CreateRole(c *fiber.Ctx) error {
+	var req CreateRoleRequest
+	if err := middleware.ValidateBody(c, &req); err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, err.Error())
+	}
+
+	role, err := h.service.CreateRole(req.Name, req.Description, req.Permissions)
+	if err != nil {
+		return h.roleError(c, err)
+	}
+	return utils.Success(c, fiber.StatusCreated, utils.CodeOK, "Role created", role)
+}
+
+// UpdateRole replaces the permissions of a role
+// Rolün yetkilerini değiştirir
+func (h *RoleHandler) UpdateRole(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, "Invalid Role ID")
+	}
+
+	var req UpdateRoleRequest
+	if err := middleware.ValidateBody(c, &req); err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, err.Error())
+	}
+
+	role, err := h.service.UpdateRole(uint(id), req.Description, req.Permissions)
+	if err != nil {
+		return h.roleError(c, err)
+	}
+	return utils.Success(c, fiber.StatusOK, utils.CodeOK, "Role updated", role)
+}
+
+// DeleteRole removes an unused role
+// Kullanılmayan bir rolü siler
+func (h *RoleHandler) DeleteRole(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, "Invalid Role ID")
+	}
+
+	if err := h.service.DeleteRole(uint(id)); err != nil {
+		return h.roleError(c, err)
+	}
+	return utils.Success(c, fiber.StatusOK, utils.CodeOK, "Role deleted", nil)
+}
+
+// roleError maps role service errors to responses
+// Rol servis hatalarını yanıtlara eşler
+func (h *RoleHandler) roleError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, services.ErrRoleNotFound):
+		return utils.NotFoundError(c, utils.CodeResourceNotFound, err.Error())
+	case errors.Is(err, services.ErrRoleExists), errors.Is(err, services.ErrRoleIsSystem), errors.Is(err, services.ErrRoleInUse),
+		errors.Is(err, services.ErrInvalidRoleName), errors.Is(err, services.ErrUnknownPermission):
+		return utils.BadRequestError(c, utils.CodeInvalidInput, err.Error())
+	default:
+		return utils.InternalError(c, utils.CodeInternalError, "Role operation failed")
+	}
+}
@@ -16,9 +16,57 @@ func NewTableHandler(service *services.TableService) *TableHandler {
 	return &TableHandler{service: service}
 }
 
+type TableLayoutRequest struct {
+	PosX     int    `json:"pos_x" validate:"min=0"`
+	PosY     int    `json:"pos_y" validate:"min=0"`
+	Shape    string `json:"shape" validate:"omitempty,oneof=square round rectangle"` // Optional, default square
+	Seats    int    `json:"seats" validate:"min=0"`                                  // Optional, default 4
+	Rotation int    `json:"rotation"`                                                // Degrees clockwise
+}
+
+func (r TableLayoutRequest) layout() services.TableLayout {
+	return services.TableLayout{
+		PosX:     r.PosX,
+		PosY:     r.PosY,
+		Shape:    r.Shape,
+		Seats:    r.Seats,
+		Rotation: r.Rotation,
+	}
+}
+
 type CreateTableRequest struct {
-	Name    string `json:"name" validate:"required"`
-	Section string `json:"section"` // Optional, default salon
+	Name      string              `json:"name" validate:"required"`
+	Section   string              `json:"section"`    // Optional, default salon, created when missing
+	SectionID *uint               `json:"section_id"` // Optional, wins over section
+	Layout    *TableLayoutRequest `json:"layout"`     // Optional, nil keeps the current layout on update
+}
+
+func (r CreateTableRequest) input() services.TableInput {
+	input := services.TableInput{
+		Name:      r.Name,
+		Section:   r.Section,
+		SectionID: r.SectionID,
+	}
+	if r.Layout != nil {
+		layout := r.Layout.layout()
+		input.Layout = &layout
+	}
+	return input
+}
+
+type TablePlacementRequest struct {
+	TableID   uint  `json:"table_id" validate:"required"`
+	SectionID *uint `json:"section_id"` // Optional, keeps the current section
+	TableLayoutRequest
+}
+
+type SaveLayoutRequest struct {
+	Tables []TablePlacementRequest `json:"tables" validate:"required,min=1,dive"`
+}
+
+type SectionRequest struct {
+	Name      string `json:"name" validate:"required,max=50"`
+	SortOrder int    `json:"sort_order"`
 }
 
 // CreateTable handles table creation
@@ -28,7 +76,7 @@ func (h *TableHandler) CreateTable(c *fiber.Ctx) error {
 		return err
 	}
 
-	table, err := h.service.CreateTable(req.Name, req.Section)
+	table, err := h.service.CreateTable(req.input())
 	if err != nil {
 		// Assuming uniqueness constraint might fail
 		return utils.BadRequestError(c, utils.CodeInvalidInput, "Could not create table (Name might be duplicate)")
@@ -37,8 +85,16 @@ func (h *TableHandler) CreateTable(c *fiber.Ctx) error {
 	return utils.Success(c, fiber.StatusCreated, utils.CodeOK, "Table created", table)
 }
 
-// ListTables handles listing tables
+// ListTables handles listing tables, ?group=section groups them by section in floor plan order
 func (h *TableHandler) ListTables(c *fiber.Ctx) error {
+	if c.Query("group") == "section" {
+		groups, err := h.service.ListTablesBySection()
+		if err != nil {
+			return fiber.NewError(fiber.StatusInternalServerError, "Could not fetch tables")
+		}
+		return utils.Success(c, fiber.StatusOK, utils.CodeOK, "Tables retrieved", groups)
+	}
+
 	tables, err := h.service.ListTables()
 	if err != nil {
 		return fiber.NewError(fiber.StatusInternalServerError, "Could not fetch tables")
@@ -54,12 +110,12 @@ func (h *TableHandler) UpdateTable(c *fiber.Ctx) error {
 		return utils.BadRequestError(c, utils.CodeInvalidInput, "Invalid ID")
 	}
 
-	var req CreateTableRequest // Reusing same struct as we only need Name/Section
+	var req CreateTableRequest // Reusing same struct, layout is only changed when sent
 	if err := middleware.ValidateBody(c, &req); err != nil {
 		return err
 	}
 
-	table, err := h.service.UpdateTable(uint(id), req.Name, req.Section)
+	table, err := h.service.UpdateTable(uint(id), req.input())
 	if err != nil {
 		return utils.BadRequestError(c, utils.CodeResourceNotFound, "Could not update table")
 	}
@@ -81,3 +137,86 @@ func (h *TableHandler) DeleteTable(c *fiber.Ctx) error {
 
 	return utils.Success(c, fiber.StatusOK, utils.CodeOK, "Table deleted", nil)
 }
+
+// SaveLayout handles moving several tables on the floor plan at once
+func (h *TableHandler) SaveLayout(c *fiber.Ctx) error {
+	var req SaveLayoutRequest
+	if err := middleware.ValidateBody(c, &req); err != nil {
+		return err
+	}
+
+	inputs := make([]services.TableLayoutInput, 0, len(req.Tables))
+	for _, t := range req.Tables {
+		inputs = append(inputs, services.TableLayoutInput{
+			TableID:     t.TableID,
+			SectionID:   t.SectionID,
+			TableLayout: t.layout(),
+		})
+	}
+
+	tables, err := h.service.SaveLayout(inputs)
+	if err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, err.Error())
+	}
+
+	return utils.Success(c, fiber.StatusOK, utils.CodeOK, "Layout saved", tables)
+}
+
+// ListSections handles listing floor plan sections
+func (h *TableHandler) ListSections(c *fiber.Ctx) error {
+	sections, err := h.service.ListSections()
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "Could not fetch sections")
+	}
+
+	return utils.Success(c, fiber.StatusOK, utils.CodeOK, "Sections retrieved", sections)
+}
+
+// CreateSection handles section creation
+func (h *TableHandler) CreateSection(c *fiber.Ctx) error {
+	var req SectionRequest
+	if err := middleware.ValidateBody(c, &req); err != nil {
+		return err
+	}
+
+	section, err := h.service.CreateSection(req.Name, req.SortOrder)
+	if err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, err.Error())
+	}
+
+	return utils.Success(c, fiber.StatusCreated, utils.CodeOK, "Section created", section)
+}
+
+// UpdateSection handles renaming and reordering a section
+func (h *TableHandler) UpdateSection(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, "Invalid ID")
+	}
+
+	var req SectionRequest
+	if err := middleware.ValidateBody(c, &req); err != nil {
+		return err
+	}
+
+	section, err := h.service.UpdateSection(uint(id), req.Name, req.SortOrder)
+	if err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, err.Error())
+	}
+
+	return utils.Success(c, fiber.StatusOK, utils.CodeOK, "Section updated", section)
+}
+
+// DeleteSection handles deleting an empty section
+func (h *TableHandler) DeleteSection(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, "Invalid ID")
+	}
+
+	if err := h.service.DeleteSection(uint(id)); err != nil {
+		return utils.BadRequestError(c, utils.CodeInvalidInput, err.Error())
+	}
+
+	return utils.Success(c, fiber.StatusOK, utils.CodeOK, "Section deleted", nil)
+}
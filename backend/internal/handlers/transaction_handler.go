@@ -85,7 +85,9 @@ func (h *TransactionHandler) UpdateExpense(c *fiber.Ctx) error {
 		return err
 	}
 
-	updated, err := h.service.UpdateExpense(uint(id), req.Amount, req.Description)
+	userID, _ := c.Locals("userID").(uint)
+
+	updated, err := h.service.UpdateExpense(uint(id), req.Amount, req.Description, userID)
 	if err != nil {
 		return utils.BadRequestError(c, utils.CodeTransactionFailed, err.Error())
 	}
@@ -100,7 +102,9 @@ func (h *TransactionHandler) DeleteExpense(c *fiber.Ctx) error {
 		return utils.BadRequestError(c, utils.CodeInvalidInput, "Invalid ID")
 	}
 
-	if err := h.service.DeleteExpense(uint(id)); err != nil {
+	userID, _ := c.Locals("userID").(uint)
+
+	if err := h.service.DeleteExpense(uint(id), userID); err != nil {
 		return utils.BadRequestError(c, utils.CodeTransactionFailed, err.Error())
 	}
 
@@ -1,7 +1,9 @@
 package handlers
 
 import (
+	"errors"
 	"simple-pos/internal/middleware"
+	"simple-pos/internal/models"
 	"simple-pos/internal/services"
 	"simple-pos/pkg/constants"
 	"simple-pos/pkg/utils"
@@ -20,7 +22,7 @@ func NewUserHandler(service *services.UserService) *UserHandler {
 type CreateUserRequest struct {
 	Name string `json:"name" validate:"required,min=3,alphanum"`
 	Pin  string `json:"pin" validate:"required,numeric,len=4"`
-	Role string `json:"role" validate:"required,oneof=admin waiter"`
+	Role string `json:"role" validate:"required"`
 }
 
 type CreateUserResponse struct {
@@ -39,7 +41,7 @@ func (h *UserHandler) Create(c *fiber.Ctx) error {
 
 	// Restrict creating new Admins
 	// Yeni Admin oluşturmayı engelle
-	if req.Role == "admin" {
+	if req.Role == models.RoleAdmin {
 		return fiber.NewError(fiber.StatusForbidden, "Creating new admin users is not allowed")
 	}
 
@@ -48,6 +50,9 @@ func (h *UserHandler) Create(c *fiber.Ctx) error {
 		if err.Error() == "user already exists" {
 			return fiber.NewError(fiber.StatusConflict, "Username is already taken")
 		}
+		if errors.Is(err, services.ErrRoleNotFound) {
+			return fiber.NewError(fiber.StatusBadRequest, "Unknown role")
+		}
 		return fiber.NewError(fiber.StatusInternalServerError, "Could not create user")
 	}
 
@@ -85,7 +90,7 @@ func (h *UserHandler) GetUserByID(c *fiber.Ctx) error {
 
 type UpdateUserRequest struct {
 	Name     string `json:"name" validate:"required,min=3"`
-	Role     string `json:"role" validate:"required,oneof=admin waiter"`
+	Role     string `json:"role" validate:"required"`
 	IsActive bool   `json:"is_active"`
 }
 
@@ -103,6 +108,9 @@ func (h *UserHandler) UpdateUser(c *fiber.Ctx) error {
 	}
 
 	user, err := h.service.UpdateUser(uint(id), req.Name, req.Role, req.IsActive)
+	if errors.Is(err, services.ErrRoleNotFound) {
+		return fiber.NewError(fiber.StatusBadRequest, "Unknown role")
+	}
 	if err != nil {
 		return fiber.NewError(fiber.StatusInternalServerError, "Could not update user")
 	}
@@ -140,7 +148,9 @@ func (h *UserHandler) ChangePin(c *fiber.Ctx) error {
 		return err
 	}
 
-	if err := h.service.ChangePin(uint(id), req.Pin); err != nil {
+	actorID, _ := c.Locals("userID").(uint)
+
+	if err := h.service.ChangePin(uint(id), req.Pin, actorID); err != nil {
 		return fiber.NewError(fiber.StatusInternalServerError, "Could not update PIN")
 	}
 
@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"simple-pos/internal/models"
 	"simple-pos/pkg/constants"
 	"simple-pos/pkg/utils"
 	"strings"
@@ -8,9 +9,15 @@ import (
 	"github.com/gofiber/fiber/v2"
 )
 
+// AccessResolver looks up the current role and permissions of a signed in user
+// Oturum açmış kullanıcının güncel rolünü ve yetkilerini bulur
+type AccessResolver interface {
+	Access(userID uint) (role string, permissions []string, err error)
+}
+
 // Protected verifies the JWT token
 // JWT tokenını doğrular
-func Protected() fiber.Handler {
+func Protected(access AccessResolver) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		authHeader := c.Get("Authorization")
 		if authHeader == "" {
@@ -22,21 +29,51 @@ func Protected() fiber.Handler {
 			return ErrorResponseJSON(c, fiber.StatusUnauthorized, constants.CODE_UNAUTHORIZED, "Invalid authorization format")
 		}
 
-		tokenString := parts[1]
+		return authenticate(c, access, parts[1])
+	}
+}
 
-		claims, err := utils.ParseToken(tokenString)
-		if err != nil {
-			return ErrorResponseJSON(c, fiber.StatusUnauthorized, constants.CODE_UNAUTHORIZED, "Invalid or expired token")
+// ProtectedStream verifies the JWT token for streaming endpoints.
+// Browsers' EventSource cannot set headers, so the token may also come from the access_token query param.
+// Akış uç noktaları için JWT tokenını doğrular. EventSource header gönderemediği için token access_token parametresinden de okunabilir.
+func ProtectedStream(access AccessResolver) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if c.Get("Authorization") != "" {
+			return Protected(access)(c)
 		}
 
-		// Store in Locals for subsequent handlers
-		c.Locals("userID", claims.UserID)
-		c.Locals("role", claims.Role)
+		tokenString := c.Query("access_token")
+		if tokenString == "" {
+			return ErrorResponseJSON(c, fiber.StatusUnauthorized, constants.CODE_UNAUTHORIZED, "Missing authorization token")
+		}
 
-		return c.Next()
+		return authenticate(c, access, tokenString)
 	}
 }
 
+// authenticate parses the token, resolves the user's current role and stores both for subsequent handlers.
+// Role edits and deactivations take effect on the next request, not on the next login.
+// Tokenı çözümler, kullanıcının güncel rolünü bulur ve sonraki handlerlar için saklar.
+// Rol değişiklikleri ve pasifleştirmeler bir sonraki girişte değil, bir sonraki istekte geçerli olur.
+func authenticate(c *fiber.Ctx, access AccessResolver, tokenString string) error {
+	claims, err := utils.ParseToken(tokenString)
+	if err != nil {
+		return ErrorResponseJSON(c, fiber.StatusUnauthorized, constants.CODE_UNAUTHORIZED, "Invalid or expired token")
+	}
+
+	role, permissions, err := access.Access(claims.UserID)
+	if err != nil {
+		return ErrorResponseJSON(c, fiber.StatusUnauthorized, constants.CODE_UNAUTHORIZED, "User is disabled or no longer exists")
+	}
+
+	// Store in Locals for subsequent handlers
+	c.Locals("userID", claims.UserID)
+	c.Locals("role", role)
+	c.Locals("permissions", permissions)
+
+	return c.Next()
+}
+
 // RequireRole enforces role-based access
 // Rol tabanlı erişimi zorlar
 func RequireRole(role string) fiber.Handler {
@@ -49,6 +86,40 @@ func RequireRole(role string) fiber.Handler {
 	}
 }
 
+// RequirePermission allows the request when the user's current role grants the permission.
+// Admins always pass.
+// Kullanıcının güncel rolü yetkiyi veriyorsa isteğe izin verir.
+// Adminler her zaman geçer.
+func RequirePermission(permission string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if HasPermission(c, permission) {
+			return c.Next()
+		}
+		return ErrorResponseJSON(c, fiber.StatusForbidden, constants.CODE_FORBIDDEN, "Insufficient permissions")
+	}
+}
+
+// Permissions returns the effective permissions of the authenticated user
+// Kimliği doğrulanmış kullanıcının geçerli yetkilerini döndürür
+func Permissions(c *fiber.Ctx) []string {
+	if role, _ := c.Locals("role").(string); role == models.RoleAdmin {
+		return models.AllPermissions
+	}
+	permissions, _ := c.Locals("permissions").([]string)
+	return permissions
+}
+
+// HasPermission reports whether the authenticated user holds the permission
+// Kimliği doğrulanmış kullanıcının yetkiye sahip olup olmadığını belirtir
+func HasPermission(c *fiber.Ctx, permission string) bool {
+	for _, p := range Permissions(c) {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
 // Helper for standardized error response used within middleware
 func ErrorResponseJSON(c *fiber.Ctx, status int, appCode constants.AppCode, message string) error {
 	return c.Status(status).JSON(ErrorResponse{
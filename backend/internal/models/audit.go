@@ -0,0 +1,67 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Audit Action Enum
+const (
+	AuditActionOrderDiscount   = "ORDER_DISCOUNT"
+	AuditActionOrderItemRemove = "ORDER_ITEM_REMOVE"
+	AuditActionOrderCancel     = "ORDER_CANCEL"
+	AuditActionOrderRefund     = "ORDER_REFUND"
+	AuditActionOrderTransfer   = "ORDER_TRANSFER"
+	AuditActionOrderItemMove   = "ORDER_ITEM_MOVE"
+	AuditActionTableMerge      = "TABLE_MERGE"
+	AuditActionExpenseUpdate   = "EXPENSE_UPDATE"
+	AuditActionExpenseDelete   = "EXPENSE_DELETE"
+	AuditActionUserPinChange   = "USER_PIN_CHANGE"
+	AuditActionDayClose        = "DAY_CLOSE"
+	AuditActionCashMovement    = "CASH_MOVEMENT"
+	AuditActionInvoiceIssue    = "INVOICE_ISSUE"
+	AuditActionCustomerLimit   = "CUSTOMER_LIMIT"
+	AuditActionCustomerPayment = "CUSTOMER_PAYMENT"
+	AuditActionOverrideFailed  = "OVERRIDE_FAILED"
+)
+
+// Audit Entity Enum
+const (
+	AuditEntityOrder        = "order"
+	AuditEntityOrderItem    = "order_item"
+	AuditEntityTable        = "table"
+	AuditEntityTransaction  = "transaction"
+	AuditEntityUser         = "user"
+	AuditEntityWorkPeriod   = "work_period"
+	AuditEntityCashMovement = "cash_movement"
+	AuditEntityInvoice      = "invoice"
+	AuditEntityCustomer     = "customer"
+)
+
+// AuditLog is an append-only record of a sensitive action.
+// It has no UpdatedAt/DeletedAt on purpose: entries are never changed or removed.
+// Hassas bir işlemin yalnızca eklenebilir kaydı.
+// Bilerek UpdatedAt/DeletedAt içermez: kayıtlar asla değiştirilmez veya silinmez.
+type AuditLog struct {
+	ID         uint            `gorm:"primaryKey" json:"id"`
+	CreatedAt  time.Time       `gorm:"index" json:"created_at"`
+	UserID     uint            `gorm:"index" json:"user_id"` // Actor (0 = system)
+	Action     string          `gorm:"size:50;index;not null" json:"action"`
+	EntityType string          `gorm:"size:50;index:idx_audit_entity;not null" json:"entity_type"`
+	EntityID   uint            `gorm:"index:idx_audit_entity" json:"entity_id"`
+	Before     json.RawMessage `gorm:"type:text" json:"before"` // JSON snapshot before the change
+	After      json.RawMessage `gorm:"type:text" json:"after"`  // JSON snapshot after the change
+	Reason     string          `gorm:"size:255" json:"reason"`
+}
+
+// AuditFilter narrows down audit log queries, zero values are ignored
+// Denetim kaydı sorgularını daraltır, sıfır değerler yok sayılır
+type AuditFilter struct {
+	UserID     *uint
+	EntityType string
+	EntityID   *uint
+	Action     string
+	Start      time.Time
+	End        time.Time
+	Limit      int
+}
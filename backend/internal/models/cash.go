@@ -0,0 +1,37 @@
+package models
+
+// Cash Movement Type Enum
+const (
+	CashMovementIn     = "CASH_IN" // Change added to the drawer
+	CashMovementDrop   = "DROP"    // Excess cash moved from the drawer to the safe
+	CashMovementPayout = "PAYOUT"  // Cash paid out of the drawer (courier, small purchase)
+)
+
+// CashMovement is cash put into or taken out of the drawer during a work period
+// Çalışma dönemi içinde çekmeceye konan veya çekmeceden alınan nakit
+type CashMovement struct {
+	BaseModel
+	WorkPeriodID uint   `gorm:"index;not null" json:"work_period_id"`
+	Type         string `gorm:"size:20;not null" json:"type"`
+	Amount       int64  `gorm:"not null;check:amount > 0" json:"amount"`
+	Reason       string `gorm:"size:255" json:"reason"`
+	CreatedBy    uint   `json:"created_by"`
+}
+
+// CashDrawer reconciles the physical drawer of a work period.
+// Expected = opening float + cash sales + cash tab payments + cash in - drops - pay-outs - cash expenses - cash refunds.
+// Çalışma döneminin fiziksel kasasının mutabakatı.
+// Beklenen = açılış bakiyesi + nakit satış + nakit veresiye tahsilatı + giriş - kasadan alınan - ödemeler - nakit giderler - nakit iadeler.
+type CashDrawer struct {
+	OpeningFloat int64  `json:"opening_float"`
+	CashSales    int64  `json:"cash_sales"`
+	CashAccount  int64  `json:"cash_account"` // Cash customers paid off their tabs
+	CashIn       int64  `json:"cash_in"`
+	CashDrops    int64  `json:"cash_drops"`
+	Payouts      int64  `json:"payouts"`
+	CashExpenses int64  `json:"cash_expenses"`
+	CashRefunds  int64  `json:"cash_refunds"`
+	ExpectedCash int64  `json:"expected_cash"`
+	CountedCash  *int64 `json:"counted_cash"` // Blind count entered at end-day
+	Variance     *int64 `json:"variance"`     // Counted - expected (negative = short)
+}
@@ -0,0 +1,54 @@
+package models
+
+import "time"
+
+// Coupon is a code that takes a fixed amount or a percentage off an order.
+// It applies after the automatic promotions and before any manual discount.
+// Siparişten sabit tutar veya yüzde düşen kod.
+// Otomatik promosyonlardan sonra, manuel indirimden önce uygulanır.
+type Coupon struct {
+	BaseModel
+	Code             string     `gorm:"size:50;not null;uniqueIndex:idx_coupons_code,where:deleted_at IS NULL" json:"code"` // Stored upper case, unique among coupons not deleted
+	Description      string     `gorm:"size:255" json:"description"`
+	DiscountType     string     `gorm:"size:20;not null" json:"discount_type"` // AMOUNT, PERCENTAGE
+	Value            int64      `gorm:"not null" json:"value"`                 // Kuruş for AMOUNT, percent for PERCENTAGE
+	MinSpend         int64      `gorm:"default:0" json:"min_spend"`            // Subtotal after promotions the order must reach, 0 = none
+	UsageLimit       int        `gorm:"default:0" json:"usage_limit"`          // Total redemptions allowed, 1 = single use, 0 = unlimited
+	PerCustomerLimit int        `gorm:"default:0" json:"per_customer_limit"`   // Redemptions per customer, 0 = unlimited
+	UsedCount        int        `gorm:"default:0" json:"used_count"`
+	ExpiresAt        *time.Time `json:"expires_at"` // nil = never expires
+	IsActive         bool       `gorm:"default:true" json:"is_active"`
+}
+
+// CouponRedemption records a coupon used on a completed order and what it cost
+// Tamamlanan bir siparişte kullanılan kuponu ve maliyetini kaydeder
+type CouponRedemption struct {
+	BaseModel
+	CouponID     uint   `gorm:"index;not null" json:"coupon_id"`
+	CouponCode   string `gorm:"size:50" json:"coupon_code"` // Snapshot
+	OrderID      uint   `gorm:"uniqueIndex;not null" json:"order_id"`
+	OrderNumber  string `gorm:"size:50" json:"order_number"`
+	CustomerID   *uint  `gorm:"index" json:"customer_id"`
+	WorkPeriodID uint   `gorm:"index" json:"work_period_id"`
+	Amount       int64  `gorm:"not null" json:"amount"` // Discount given
+	RedeemedBy   uint   `json:"redeemed_by"`
+}
+
+// CouponStat is the redemptions of one coupon within a date range
+// Bir kuponun tarih aralığındaki kullanımları
+type CouponStat struct {
+	CouponID    uint   `json:"coupon_id"`
+	CouponCode  string `json:"coupon_code"`
+	Redemptions int64  `json:"redemptions"`
+	Customers   int64  `json:"customers"` // Distinct customers, orders without a customer are not counted
+	Amount      int64  `json:"amount"`    // Cost of the coupon
+}
+
+// CouponReport lists coupon redemptions of a date range with their total cost
+// Tarih aralığındaki kupon kullanımlarını toplam maliyetleriyle listeler
+type CouponReport struct {
+	Coupons     []CouponStat       `json:"coupons"`
+	Redemptions []CouponRedemption `json:"redemptions"`
+	TotalCount  int64              `json:"total_count"`
+	TotalAmount int64              `json:"total_amount"`
+}
@@ -0,0 +1,71 @@
+package models
+
+import "time"
+
+// Customer Ledger Entry Type Enum
+const (
+	LedgerEntryCharge  = "CHARGE"  // Order closed on account
+	LedgerEntryPayment = "PAYMENT" // Customer settled (part of) the balance
+	LedgerEntryRefund  = "REFUND"  // Refund of an order closed on account
+)
+
+// TransactionCategoryAccountPayment marks the INCOME transaction of a customer paying off their tab
+// Müşterinin veresiye borcunu ödediği GELİR işlemini işaretler
+const TransactionCategoryAccountPayment = "Account Payment"
+
+// Customer is a regular who can run a tab (veresiye) and settle it later
+// Veresiye hesabı açabilen ve daha sonra ödeyen müdavim müşteri
+type Customer struct {
+	BaseModel
+	Name        string `gorm:"size:100;not null" json:"name"`
+	Phone       string `gorm:"size:30;index" json:"phone"`
+	Email       string `gorm:"size:255" json:"email"`
+	Note        string `gorm:"size:255" json:"note"`
+	CreditLimit int64  `gorm:"default:0" json:"credit_limit"` // Highest balance allowed, 0 = no limit
+	Balance     int64  `gorm:"default:0" json:"balance"`      // Amount owed by the customer
+	IsActive    bool   `gorm:"default:true" json:"is_active"` // Inactive customers cannot take new charges
+
+	LoyaltyPoints int64 `gorm:"default:0" json:"loyalty_points"` // Unspent loyalty points
+}
+
+// CustomerLedgerEntry is a single movement on a customer's account.
+// Charges are stored positive, payments and refunds negative.
+// Müşteri hesabındaki tek bir hareket.
+// Borçlar pozitif, ödemeler ve iadeler negatif saklanır.
+type CustomerLedgerEntry struct {
+	BaseModel
+	CustomerID    uint   `gorm:"index;not null" json:"customer_id"`
+	Type          string `gorm:"size:20;not null" json:"type"` // CHARGE, PAYMENT, REFUND
+	Amount        int64  `gorm:"not null" json:"amount"`
+	Balance       int64  `gorm:"not null" json:"balance"` // Customer balance after the entry
+	OrderID       *uint  `gorm:"index" json:"order_id"`
+	OrderNumber   string `gorm:"size:50" json:"order_number"` // Snapshot for statements
+	RefundID      *uint  `json:"refund_id"`
+	PaymentMethod string `gorm:"size:50" json:"payment_method"` // PAYMENT only: CASH or CREDIT_CARD
+	TransactionID *uint  `json:"transaction_id"`                // INCOME transaction of a payment
+	WorkPeriodID  uint   `gorm:"index" json:"work_period_id"`
+	Note          string `gorm:"size:255" json:"note"`
+	CreatedBy     uint   `json:"created_by"`
+}
+
+// CustomerFilter narrows down customer queries, zero values are ignored
+// Müşteri sorgularını daraltır, sıfır değerler yok sayılır
+type CustomerFilter struct {
+	Search          string // Name or phone
+	WithBalance     bool   // Only customers who owe money
+	IncludeInactive bool
+}
+
+// CustomerStatement lists the account movements of a customer over a date range
+// Bir müşterinin tarih aralığındaki hesap hareketlerini listeler
+type CustomerStatement struct {
+	Customer       Customer              `json:"customer"`
+	StartDate      time.Time             `json:"start_date"`
+	EndDate        time.Time             `json:"end_date"`
+	OpeningBalance int64                 `json:"opening_balance"`
+	TotalCharges   int64                 `json:"total_charges"`
+	TotalPayments  int64                 `json:"total_payments"` // Positive
+	TotalRefunds   int64                 `json:"total_refunds"`  // Positive
+	ClosingBalance int64                 `json:"closing_balance"`
+	Entries        []CustomerLedgerEntry `json:"entries"`
+}
@@ -0,0 +1,61 @@
+package models
+
+// Stock Unit Enum
+const (
+	StockUnitPiece      = "piece"
+	StockUnitGram       = "g"
+	StockUnitKilogram   = "kg"
+	StockUnitMilliliter = "ml"
+	StockUnitLiter      = "l"
+)
+
+// Stock Movement Type Enum
+const (
+	StockMovementIn         = "IN"         // Purchase / delivery
+	StockMovementAdjustment = "ADJUSTMENT" // Manual correction after a count (signed)
+	StockMovementWaste      = "WASTE"      // Spoiled, dropped, expired
+	StockMovementSale       = "SALE"       // Consumed by a completed order
+	StockMovementReturn     = "RETURN"     // Restored after an item was removed or cancelled
+)
+
+// StockItem represents an ingredient or goods kept in stock (bread, cheese, sucuk)
+// Stokta tutulan malzeme veya ürün (ekmek, peynir, sucuk)
+type StockItem struct {
+	BaseModel
+	Name              string  `gorm:"size:100;not null;uniqueIndex:idx_stock_items_name,where:deleted_at IS NULL" json:"name" validate:"required"` // Unique among items not deleted
+	Unit              string  `gorm:"size:20;not null;default:'piece'" json:"unit" validate:"oneof=piece g kg ml l"`
+	Quantity          float64 `gorm:"default:0" json:"quantity"`            // Current on hand quantity
+	LowStockThreshold float64 `gorm:"default:0" json:"low_stock_threshold"` // Alert at or below this level (0 = no alert)
+	IsActive          bool    `gorm:"default:true" json:"is_active"`
+}
+
+// IsLow reports whether the item is at or below its alert threshold
+// Ürünün uyarı eşiğinde veya altında olup olmadığını belirtir
+func (s *StockItem) IsLow() bool {
+	return s.LowStockThreshold > 0 && s.Quantity <= s.LowStockThreshold
+}
+
+// RecipeItem maps a product to the stock it consumes per sold unit
+// Bir ürünü, satılan her adet için tükettiği stoğa eşler
+type RecipeItem struct {
+	BaseModel
+	ProductID   uint      `gorm:"uniqueIndex:idx_recipe_product_stock;not null" json:"product_id"`
+	StockItemID uint      `gorm:"uniqueIndex:idx_recipe_product_stock;not null" json:"stock_item_id"`
+	StockItem   StockItem `json:"stock_item,omitempty"`
+	Quantity    float64   `gorm:"not null;check:quantity > 0" json:"quantity"`
+}
+
+// StockMovement is a single change of stock quantity
+// Stok miktarındaki tek bir değişiklik
+type StockMovement struct {
+	BaseModel
+	StockItemID  uint      `gorm:"index;not null" json:"stock_item_id"`
+	StockItem    StockItem `json:"stock_item,omitempty"`
+	Type         string    `gorm:"size:20;not null" json:"type"`
+	Quantity     float64   `gorm:"not null" json:"quantity"`       // Signed delta (negative = out)
+	BalanceAfter float64   `gorm:"default:0" json:"balance_after"` // On hand quantity after this movement
+	OrderID      *uint     `gorm:"index" json:"order_id"`
+	OrderItemID  *uint     `gorm:"index" json:"order_item_id"`
+	Note         string    `gorm:"size:255" json:"note"`
+	CreatedBy    uint      `json:"created_by"`
+}
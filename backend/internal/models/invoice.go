@@ -0,0 +1,65 @@
+package models
+
+import "time"
+
+// Invoice Status Enum
+const (
+	InvoiceStatusIssued = "issued" // Numbered and stored, not delivered yet
+	InvoiceStatusSent   = "sent"   // Accepted by the integrator
+	InvoiceStatusFailed = "failed" // Delivery failed, can be sent again
+)
+
+// Invoice is an e-Arşiv invoice issued for a completed order, the UBL-TR XML is kept as issued
+// Tamamlanmış bir sipariş için kesilen e-Arşiv faturası, UBL-TR XML kesildiği haliyle saklanır
+type Invoice struct {
+	BaseModel
+	InvoiceNumber string    `gorm:"size:16;uniqueIndex;not null" json:"invoice_number"` // Prefix + year + 9 digit sequence
+	UUID          string    `gorm:"size:36;uniqueIndex;not null" json:"uuid"`           // ETTN
+	Year          int       `gorm:"index;not null" json:"year"`
+	Sequence      int       `gorm:"not null" json:"sequence"`
+	OrderID       uint      `gorm:"uniqueIndex;not null" json:"order_id"` // One invoice per order
+	Profile       string    `gorm:"size:20" json:"profile"`               // EARSIVFATURA
+	InvoiceType   string    `gorm:"size:20" json:"invoice_type"`          // SATIS
+	IssuedAt      time.Time `json:"issued_at"`
+
+	// Buyer
+	CustomerTaxID     string `gorm:"size:11;index;not null" json:"customer_tax_id"` // VKN (10) or TCKN (11)
+	CustomerName      string `gorm:"size:255;not null" json:"customer_name"`        // Company title or full name
+	CustomerTaxOffice string `gorm:"size:100" json:"customer_tax_office"`
+	CustomerAddress   string `gorm:"size:255" json:"customer_address"`
+	CustomerDistrict  string `gorm:"size:100" json:"customer_district"`
+	CustomerCity      string `gorm:"size:100" json:"customer_city"`
+	CustomerCountry   string `gorm:"size:100" json:"customer_country"`
+	CustomerEmail     string `gorm:"size:255" json:"customer_email"`
+
+	// Totals
+	NetAmount      int64  `gorm:"default:0" json:"net_amount"`      // Matrah after discount
+	DiscountAmount int64  `gorm:"default:0" json:"discount_amount"` // Without KDV
+	TaxAmount      int64  `gorm:"default:0" json:"tax_amount"`
+	PayableAmount  int64  `gorm:"default:0" json:"payable_amount"`
+	Currency       string `gorm:"size:3;default:'TRY'" json:"currency"`
+
+	XML          string     `gorm:"type:text" json:"-"` // UBL-TR 1.2 document
+	Status       string     `gorm:"size:20;index;default:'issued'" json:"status"`
+	Reference    string     `gorm:"size:255" json:"reference"`     // Reference returned by the integrator
+	ErrorMessage string     `gorm:"size:500" json:"error_message"` // Last delivery error
+	SentAt       *time.Time `json:"sent_at"`
+	CreatedBy    uint       `json:"created_by"`
+}
+
+// InvoiceSequence holds the last invoice number used in a year, numbers restart every year
+// Bir yılda kullanılan son fatura numarasını tutar, numaralar her yıl yeniden başlar
+type InvoiceSequence struct {
+	Year       int `gorm:"primaryKey;autoIncrement:false" json:"year"`
+	LastNumber int `gorm:"not null;default:0" json:"last_number"`
+}
+
+// InvoiceFilter narrows down invoice queries, zero values are ignored
+// Fatura sorgularını daraltır, sıfır değerler yok sayılır
+type InvoiceFilter struct {
+	Year    int
+	OrderID uint
+	TaxID   string
+	Status  string
+	Limit   int
+}
@@ -0,0 +1,56 @@
+package models
+
+import "time"
+
+// Kitchen Ticket Status Enum
+const (
+	KitchenStatusQueued        = "queued"
+	KitchenStatusInPreparation = "in_preparation"
+	KitchenStatusReady         = "ready"
+	KitchenStatusServed        = "served"
+	KitchenStatusCancelled     = "cancelled"
+)
+
+// KitchenStation represents a preparation point (e.g. toaster, bar)
+// Hazırlık noktası (örn. tost makinesi, bar)
+type KitchenStation struct {
+	BaseModel
+	Name      string `gorm:"size:50;uniqueIndex;not null" json:"name" validate:"required"`
+	IsActive  bool   `gorm:"default:true" json:"is_active"`
+	SortOrder int    `gorm:"default:0" json:"sort_order"`
+	Printer   string `gorm:"size:255" json:"printer"` // ESC/POS printer address for its tickets, empty = kitchen default
+}
+
+// KitchenTicket is an order item fired to a kitchen station
+// Mutfak istasyonuna gönderilen sipariş kalemi
+type KitchenTicket struct {
+	BaseModel
+	OrderID     uint       `gorm:"index;not null" json:"order_id"`
+	OrderItemID uint       `gorm:"index;not null" json:"order_item_id"`
+	StationID   *uint      `gorm:"index" json:"station_id"` // nil means default/unrouted station
+	StationName string     `gorm:"size:50" json:"station_name"`
+	TableName   string     `gorm:"size:50" json:"table_name"`    // Snapshot
+	ProductName string     `gorm:"size:100" json:"product_name"` // Snapshot
+	Quantity    int        `gorm:"not null" json:"quantity"`
+	Modifiers   string     `gorm:"size:500" json:"modifiers"` // Comma separated option names
+	Note        string     `gorm:"size:255" json:"note"`
+	Status      string     `gorm:"size:20;index;default:'queued'" json:"status" validate:"oneof=queued in_preparation ready served cancelled"`
+	StartedAt   *time.Time `json:"started_at"`
+	ReadyAt     *time.Time `json:"ready_at"`
+	ServedAt    *time.Time `json:"served_at"`
+}
+
+// NextKitchenStatus returns the state a ticket moves to when bumped, or "" if it cannot advance
+// Ticket ilerletildiğinde geçeceği durumu döndürür, ilerleyemiyorsa "" döner
+func NextKitchenStatus(status string) string {
+	switch status {
+	case KitchenStatusQueued:
+		return KitchenStatusInPreparation
+	case KitchenStatusInPreparation:
+		return KitchenStatusReady
+	case KitchenStatusReady:
+		return KitchenStatusServed
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,83 @@
+package models
+
+import "time"
+
+// Loyalty Entry Type Enum
+const (
+	LoyaltyEntryEarn   = "EARN"   // Points or stamps collected with a completed order
+	LoyaltyEntryRedeem = "REDEEM" // Points or stamps spent as an order discount
+)
+
+// LoyaltyRule sets how a category earns and burns loyalty.
+// Points are collected on what the customer paid, stamps per unit sold (the paper "10th toast free" card).
+// Bir kategorinin sadakat kazanma ve harcama kuralını belirler.
+// Puanlar müşterinin ödediği tutar üzerinden, damgalar satılan adet başına toplanır (kağıt "10. tost bedava" kartı).
+type LoyaltyRule struct {
+	BaseModel
+	CategoryID      uint      `gorm:"uniqueIndex;not null" json:"category_id"`
+	Category        *Category `json:"category,omitempty"`
+	PointsPerLira   int64     `gorm:"default:0" json:"points_per_lira"`   // Points earned per 1 TL paid, 0 = none
+	RedeemPoints    bool      `gorm:"default:false" json:"redeem_points"` // Points can pay for items of the category
+	StampsPerItem   int       `gorm:"default:0" json:"stamps_per_item"`   // Stamps earned per unit sold, 0 = no stamp card
+	StampsForReward int       `gorm:"default:0" json:"stamps_for_reward"` // Stamps that buy one free unit of the category
+	IsActive        bool      `gorm:"default:true" json:"is_active"`
+}
+
+// LoyaltyStampCard holds the stamps a customer collected in one category
+// Müşterinin bir kategoride topladığı damgaları tutar
+type LoyaltyStampCard struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	CustomerID uint      `gorm:"uniqueIndex:idx_stamp_card;not null" json:"customer_id"`
+	CategoryID uint      `gorm:"uniqueIndex:idx_stamp_card;not null" json:"category_id"`
+	Stamps     int       `gorm:"not null;default:0" json:"stamps"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// LoyaltyEntry is a line of a customer's loyalty history; earned amounts are positive, redeemed ones negative
+// Müşterinin sadakat geçmişindeki bir satır; kazanılanlar pozitif, harcananlar negatif
+type LoyaltyEntry struct {
+	BaseModel
+	CustomerID uint   `gorm:"index;not null" json:"customer_id"`
+	OrderID    *uint  `gorm:"index" json:"order_id"`
+	Type       string `gorm:"size:20;not null" json:"type"` // EARN, REDEEM
+	Points     int64  `gorm:"default:0" json:"points"`
+	CategoryID *uint  `json:"category_id"` // Stamp card the stamps belong to
+	Stamps     int    `gorm:"default:0" json:"stamps"`
+	Amount     int64  `gorm:"default:0" json:"amount"` // REDEEM only: discount given
+	CreatedBy  uint   `json:"created_by"`
+}
+
+// LoyaltyRedemption is a discount paid with points or stamps on an open order.
+// The balance is only spent when the order is closed with the discount still in place.
+// Açık bir siparişe puan veya damgayla uygulanan indirim.
+// Bakiye yalnızca sipariş indirim yerindeyken kapatılırsa harcanır.
+type LoyaltyRedemption struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	CreatedAt  time.Time `json:"created_at"`
+	OrderID    uint      `gorm:"uniqueIndex;not null" json:"order_id"`
+	CustomerID uint      `gorm:"not null" json:"customer_id"`
+	Points     int64     `gorm:"default:0" json:"points"`
+	CategoryID *uint     `json:"category_id"` // Stamp card being redeemed
+	Stamps     int       `gorm:"default:0" json:"stamps"`
+	Amount     int64     `gorm:"not null" json:"amount"` // Discount applied to the order
+}
+
+// LoyaltyStampBalance is a stamp card together with the rule of its category
+// Kategorisinin kuralıyla birlikte bir damga kartı
+type LoyaltyStampBalance struct {
+	CategoryID       uint   `json:"category_id"`
+	CategoryName     string `json:"category_name"`
+	Stamps           int    `json:"stamps"`
+	StampsForReward  int    `json:"stamps_for_reward"`
+	RewardsAvailable int    `json:"rewards_available"`
+}
+
+// LoyaltyBalance is the loyalty standing of a customer with the latest history
+// Müşterinin sadakat durumu ve son geçmişi
+type LoyaltyBalance struct {
+	CustomerID  uint                  `json:"customer_id"`
+	Points      int64                 `json:"points"`
+	PointsValue int64                 `json:"points_value"` // Discount the points are worth
+	StampCards  []LoyaltyStampBalance `json:"stamp_cards"`
+	History     []LoyaltyEntry        `json:"history"`
+}
@@ -14,15 +14,16 @@ type User struct {
 	BaseModel
 	Name     string `gorm:"size:100;not null" json:"name" validate:"required,min=2"`
 	PinCode  string `gorm:"size:255;not null" json:"-"` // Stores Bcrypt Hash
-	Role     string `gorm:"size:20;not null;default:'waiter'" json:"role" validate:"oneof=admin waiter"`
+	Role     string `gorm:"size:20;not null;default:'waiter'" json:"role" validate:"required"`
 	IsActive bool   `gorm:"default:true" json:"is_active"`
 }
 
 // JWTClaims represents the payload of the JWT
 // JWT içeriğini temsil eder
 type JWTClaims struct {
-	UserID uint   `json:"user_id"`
-	Role   string `json:"role"`
+	UserID      uint     `json:"user_id"`
+	Role        string   `json:"role"`        // Role at login, requests resolve the current one
+	Permissions []string `json:"permissions"` // Effective permissions at login, for the UI only
 	jwt.RegisteredClaims
 }
 
@@ -35,6 +36,8 @@ type Category struct {
 	Color     string    `gorm:"size:20" json:"color"`
 	SortOrder int       `gorm:"default:0" json:"sort_order"`
 	IsActive  bool      `gorm:"default:true" json:"is_active"`
+	StationID *uint     `json:"station_id"` // Kitchen station that prepares this category
+	TaxRate   *int      `json:"tax_rate"`   // KDV % for products without their own rate (nil = default)
 	Products  []Product `json:"products,omitempty"`
 }
 
@@ -50,6 +53,7 @@ type Product struct {
 	Description string   `json:"description"`
 	IsAvailable bool     `gorm:"default:true" json:"is_available"`
 	SortOrder   int      `gorm:"default:0" json:"sort_order"`
+	TaxRate     *int     `json:"tax_rate"` // KDV % (nil = inherit from category)
 }
 
 // Table Status Enum
@@ -59,15 +63,39 @@ const (
 	TableStatusReserved  = "reserved"
 )
 
+// Table Shape Enum
+const (
+	TableShapeSquare    = "square"
+	TableShapeRound     = "round"
+	TableShapeRectangle = "rectangle"
+)
+
 // Table represents a dining table
 // Masa
 type Table struct {
 	BaseModel
 	Name           string `gorm:"size:50;uniqueIndex;not null" json:"name" validate:"required"`
-	Section        string `gorm:"size:50;default:'salon'" json:"section"` // salon, garden, baloon
+	SectionID      *uint  `gorm:"index" json:"section_id"`
+	Section        string `gorm:"size:50;default:'salon'" json:"section"` // Name of the section, kept for older clients
 	Status         string `gorm:"size:20;default:'available'" json:"status" validate:"oneof=available occupied reserved"`
 	CurrentOrderID *uint  `json:"current_order_id,omitempty"`
 	OrderCount     int64  `gorm:"->" json:"order_count"` // Virtual field for active order count, -> means ReadOnly
+
+	// Floor plan layout
+	// Kat planı yerleşimi
+	PosX     int    `gorm:"default:0" json:"pos_x"` // Position on the section canvas
+	PosY     int    `gorm:"default:0" json:"pos_y"`
+	Shape    string `gorm:"size:20;default:'square'" json:"shape" validate:"oneof=square round rectangle"`
+	Seats    int    `gorm:"default:4" json:"seats"`
+	Rotation int    `gorm:"default:0" json:"rotation"` // Degrees clockwise, 0-359
+}
+
+// Section is an area of the floor plan such as the salon or the garden
+// Salon veya bahçe gibi kat planındaki bir alan
+type Section struct {
+	BaseModel
+	Name      string `gorm:"size:50;uniqueIndex;not null" json:"name" validate:"required"`
+	SortOrder int    `gorm:"default:0" json:"sort_order"`
 }
 
 // Order Status Enum
@@ -81,41 +109,117 @@ const (
 const (
 	PaymentMethodCash       = "CASH"
 	PaymentMethodCreditCard = "CREDIT_CARD"
+	PaymentMethodOnAccount  = "ON_ACCOUNT" // Charged to a customer's tab (veresiye)
+	PaymentMethodMixed      = "MIXED"      // Order settled with more than one method
+)
+
+// Payment Split Type Enum
+const (
+	PaymentSplitFull   = "FULL"   // Remaining balance in one go
+	PaymentSplitAmount = "AMOUNT" // Arbitrary partial amount
+	PaymentSplitItems  = "ITEMS"  // Selected items (or partial quantities)
+	PaymentSplitEqual  = "EQUAL"  // Equal shares of the order total
 )
 
 // Order represents a customer order
 // Müşteri siparişi
 type Order struct {
 	BaseModel
-	OrderNumber    string      `gorm:"size:50;uniqueIndex;not null" json:"order_number"` // UUID or Generated
-	WorkPeriodID   uint        `gorm:"index" json:"work_period_id"`                      // Link to WorkPeriod
-	TableID        *uint       `json:"table_id"`
-	TableName      string      `gorm:"size:50" json:"table_name"` // Snapshot of table name
-	WaiterID       *uint       `json:"waiter_id"`
-	Waiter         *User       `json:"waiter,omitempty"`
-	Status         string      `gorm:"size:20;default:'open'" json:"status" validate:"oneof=open completed cancelled"`
-	Subtotal       int64       `gorm:"default:0" json:"subtotal"` // Sum of items subtotal
-	TaxAmount      int64       `gorm:"default:0" json:"tax_amount"`
-	DiscountType   string      `gorm:"size:20;default:'NONE'" json:"discount_type"` // NONE, AMOUNT, PERCENTAGE
-	DiscountValue  int64       `gorm:"default:0" json:"discount_value"`             // Input value (e.g., 10 for 10%, 5000 for 50.00)
-	DiscountAmount int64       `gorm:"default:0" json:"discount_amount"`            // Calculated amount
-	DiscountReason string      `gorm:"size:255" json:"discount_reason"`             // Reason for discount
-	TotalAmount    int64       `gorm:"default:0" json:"total_amount"`               // Subtotal - Discount + Tax
-	PaymentMethod  string      `gorm:"size:50" json:"payment_method"`
-	CompletedAt    *time.Time  `json:"completed_at"`
-	Items          []OrderItem `json:"items,omitempty"`
+	OrderNumber        string           `gorm:"size:50;uniqueIndex;not null" json:"order_number"` // UUID or Generated
+	WorkPeriodID       uint             `gorm:"index" json:"work_period_id"`                      // Link to WorkPeriod
+	TableID            *uint            `json:"table_id"`
+	TableName          string           `gorm:"size:50" json:"table_name"` // Snapshot of table name
+	WaiterID           *uint            `json:"waiter_id"`
+	Waiter             *User            `json:"waiter,omitempty"`
+	Status             string           `gorm:"size:20;default:'open'" json:"status" validate:"oneof=open completed cancelled"`
+	Subtotal           int64            `gorm:"default:0" json:"subtotal"` // Sum of items subtotal
+	TaxAmount          int64            `gorm:"default:0" json:"tax_amount"`
+	TaxMode            string           `gorm:"size:10" json:"tax_mode"`           // INCLUSIVE, EXCLUSIVE (snapshot at creation)
+	PromotionAmount    int64            `gorm:"default:0" json:"promotion_amount"` // Automatic promotions, kept apart from the manual discount
+	CouponID           *uint            `gorm:"index" json:"coupon_id"`
+	CouponCode         string           `gorm:"size:50" json:"coupon_code"`
+	CouponType         string           `gorm:"size:20" json:"coupon_type"` // AMOUNT, PERCENTAGE (snapshot)
+	CouponValue        int64            `gorm:"default:0" json:"coupon_value"`
+	CouponAmount       int64            `gorm:"default:0" json:"coupon_amount"`              // Calculated amount
+	DiscountType       string           `gorm:"size:20;default:'NONE'" json:"discount_type"` // NONE, AMOUNT, PERCENTAGE
+	DiscountValue      int64            `gorm:"default:0" json:"discount_value"`             // Input value (e.g., 10 for 10%, 5000 for 50.00)
+	DiscountAmount     int64            `gorm:"default:0" json:"discount_amount"`            // Calculated amount
+	DiscountReason     string           `gorm:"size:255" json:"discount_reason"`             // Reason for discount
+	DiscountBy         *uint            `json:"discount_by"`                                 // User who applied the discount
+	DiscountApprovedBy *uint            `json:"discount_approved_by"`                        // Manager who approved a discount above the threshold
+	TotalAmount        int64            `gorm:"default:0" json:"total_amount"`               // Subtotal - Promotions - Coupon - Discount (+ Tax when EXCLUSIVE)
+	PaidAmount         int64            `gorm:"default:0" json:"paid_amount"`                // Sum of recorded payments
+	SplitCount         int              `gorm:"default:0" json:"split_count"`                // Guests of the running EQUAL split, 0 = none
+	SplitSharesPaid    int              `gorm:"default:0" json:"split_shares_paid"`          // EQUAL shares paid so far
+	RefundedAmount     int64            `gorm:"default:0" json:"refunded_amount"`            // Sum of refunds given back
+	Note               string           `gorm:"size:255" json:"note"`                        // Free-text note for the kitchen
+	PaymentMethod      string           `gorm:"size:50" json:"payment_method"`
+	CustomerID         *uint            `gorm:"index" json:"customer_id"` // Customer whose tab the order was charged to
+	CompletedAt        *time.Time       `json:"completed_at"`
+	CancelledBy        *uint            `json:"cancelled_by"`
+	CancelApprovedBy   *uint            `json:"cancel_approved_by"` // Manager who approved cancelling an order with items
+	Items              []OrderItem      `json:"items,omitempty"`
+	Payments           []Payment        `json:"payments,omitempty"`
+	Promotions         []OrderPromotion `json:"promotions,omitempty"`
+}
+
+// RemainingAmount returns the unpaid balance of the order
+// Siparişin ödenmemiş bakiyesini döndürür
+func (o *Order) RemainingAmount() int64 {
+	remaining := o.TotalAmount - o.PaidAmount
+	if remaining < 0 {
+		return 0
+	}
+	return remaining
 }
 
 // OrderItem represents an item in an order
 // Sipariş kalemi
 type OrderItem struct {
 	BaseModel
-	OrderID     uint   `json:"order_id"`
-	ProductID   uint   `json:"product_id"`
-	ProductName string `gorm:"size:100" json:"product_name"` // Snapshot
-	Quantity    int    `gorm:"not null;check:quantity > 0" json:"quantity" validate:"min=1"`
-	UnitPrice   int64  `gorm:"not null" json:"unit_price"` // Snapshot
-	Subtotal    int64  `gorm:"not null" json:"subtotal"`
+	OrderID          uint   `json:"order_id"`
+	ProductID        uint   `json:"product_id"`
+	ProductName      string `gorm:"size:100" json:"product_name"` // Snapshot
+	Quantity         int    `gorm:"not null;check:quantity > 0" json:"quantity" validate:"min=1"`
+	UnitPrice        int64  `gorm:"not null" json:"unit_price"` // Snapshot
+	Subtotal         int64  `gorm:"not null" json:"subtotal"`
+	PaidQuantity     int    `gorm:"default:0" json:"paid_quantity"`     // Units already settled by item-based payments
+	RefundedQuantity int    `gorm:"default:0" json:"refunded_quantity"` // Units given back after completion
+	Note             string `gorm:"size:255" json:"note"`               // Kitchen note, e.g. "well done"
+	KitchenStatus    string `gorm:"size:20" json:"kitchen_status"`      // Least advanced state of its kitchen tickets
+
+	TaxRate         int   `gorm:"default:0" json:"tax_rate"`         // KDV % snapshot
+	PromotionAmount int64 `gorm:"default:0" json:"promotion_amount"` // Reduction from automatic promotions
+	DiscountAmount  int64 `gorm:"default:0" json:"discount_amount"`  // Share of the order level coupon and discount
+	NetAmount       int64 `gorm:"default:0" json:"net_amount"`       // Taxable base after discount (matrah)
+	TaxAmount       int64 `gorm:"default:0" json:"tax_amount"`       // KDV of the line after discount
+
+	ModifierTotal int64               `gorm:"default:0" json:"modifier_total"` // Sum of option price deltas per unit
+	Modifiers     []OrderItemModifier `json:"modifiers,omitempty"`
+	Tickets       []KitchenTicket     `json:"kitchen_tickets,omitempty"`
+
+	// Snapshotted marks name, price and KDV rate as copied by the caller, zero values included
+	// Ad, fiyat ve KDV oranının çağıran tarafından sıfır değerler dahil kopyalandığını belirtir
+	Snapshotted bool `gorm:"-" json:"-"`
+}
+
+// LinePrice returns the per unit price including selected modifiers
+// Seçilen opsiyonlar dahil birim fiyatı döndürür
+func (item *OrderItem) LinePrice() int64 {
+	return item.UnitPrice + item.ModifierTotal
+}
+
+// Payment represents a (partial) settlement of an order
+// Siparişin (kısmi) ödemesi
+type Payment struct {
+	BaseModel
+	OrderID       uint   `gorm:"index;not null" json:"order_id"`
+	WorkPeriodID  uint   `gorm:"index" json:"work_period_id"`
+	Method        string `gorm:"size:50;not null" json:"method" validate:"oneof=CASH CREDIT_CARD ON_ACCOUNT"`
+	SplitType     string `gorm:"size:20;default:'AMOUNT'" json:"split_type"` // FULL, AMOUNT, ITEMS, EQUAL
+	Amount        int64  `gorm:"not null;check:amount > 0" json:"amount"`
+	TransactionID *uint  `json:"transaction_id"` // Linked INCOME transaction, none for ON_ACCOUNT
+	CreatedBy     uint   `json:"created_by"`
 }
 
 // Transaction represents financial movement
@@ -128,6 +232,7 @@ type Transaction struct {
 	Amount          int64     `gorm:"not null" json:"amount"`
 	Description     string    `json:"description"`
 	OrderID         *uint     `json:"order_id"`
+	RefundID        *uint     `gorm:"index" json:"refund_id"`      // Set on REFUND transactions
 	WorkPeriodID    uint      `gorm:"index" json:"work_period_id"` // Link to WorkPeriod
 	CreatedBy       uint      `json:"created_by"`
 	TransactionDate time.Time `json:"transaction_date"`
@@ -136,25 +241,39 @@ type Transaction struct {
 // DailyReport represents aggregated daily stats
 // Günlük rapor
 type DailyReport struct {
-	ReportDate    string    `gorm:"primaryKey;size:10" json:"report_date"` // YYYY-MM-DD
-	TotalOrders   int       `gorm:"default:0" json:"total_orders"`
-	TotalSales    int64     `gorm:"default:0" json:"total_sales"`
-	CashSales     int64     `gorm:"default:0" json:"cash_sales"`
-	PosSales      int64     `gorm:"default:0" json:"pos_sales"`
-	TotalExpenses int64     `gorm:"default:0" json:"total_expenses"`
-	NetProfit     int64     `gorm:"default:0" json:"net_profit"`
-	UpdatedAt     time.Time `json:"updated_at"`
+	ReportDate      string    `gorm:"primaryKey;size:10" json:"report_date"` // YYYY-MM-DD
+	TotalOrders     int       `gorm:"default:0" json:"total_orders"`
+	TotalSales      int64     `gorm:"default:0" json:"total_sales"`      // Gross, before refunds
+	TotalPromotions int64     `gorm:"default:0" json:"total_promotions"` // Automatic promotions given
+	TotalCoupons    int64     `gorm:"default:0" json:"total_coupons"`    // Coupon codes redeemed
+	TotalDiscounts  int64     `gorm:"default:0" json:"total_discounts"`  // Manual discounts given
+	TotalRefunds    int64     `gorm:"default:0" json:"total_refunds"`
+	NetSales        int64     `gorm:"default:0" json:"net_sales"` // Gross sales - refunds
+	CashSales       int64     `gorm:"default:0" json:"cash_sales"`
+	PosSales        int64     `gorm:"default:0" json:"pos_sales"`
+	TotalExpenses   int64     `gorm:"default:0" json:"total_expenses"`
+	NetProfit       int64     `gorm:"default:0" json:"net_profit"`
+	TotalTax        int64     `gorm:"default:0" json:"total_tax"`
+	UpdatedAt       time.Time `json:"updated_at"`
+
+	TaxBreakdown []TaxRateTotal `gorm:"-" json:"tax_breakdown,omitempty"` // KDV per rate
+	CashDrawer   *CashDrawer    `gorm:"-" json:"cash_drawer,omitempty"`   // Drawer reconciliation of a single period
 }
 
-// ProductSalesStat represents product performance
-// Ürün satış istatistikleri
+// ProductSalesStat represents product performance, one row per product and closed work period
+// Ürün satış istatistikleri, ürün ve kapanmış çalışma dönemi başına bir satır
 type ProductSalesStat struct {
-	ID           uint   `gorm:"primaryKey" json:"id"`
-	ReportDate   string `gorm:"index;size:10" json:"report_date"`
-	ProductID    uint   `gorm:"index" json:"product_id"`
-	ProductName  string `json:"product_name"`
-	QuantitySold int    `gorm:"default:0" json:"quantity_sold"`
-	TotalRevenue int64  `gorm:"default:0" json:"total_revenue"`
+	ID               uint   `gorm:"primaryKey" json:"id"`
+	ReportDate       string `gorm:"index;size:10" json:"report_date"` // Start day of the period, YYYY-MM-DD
+	WorkPeriodID     uint   `gorm:"index" json:"work_period_id"`
+	ProductID        uint   `gorm:"index" json:"product_id"`
+	ProductName      string `json:"product_name"`
+	CategoryID       uint   `gorm:"index" json:"category_id"`
+	CategoryName     string `gorm:"size:100" json:"category_name"`
+	QuantitySold     int    `gorm:"default:0" json:"quantity_sold"`
+	TotalRevenue     int64  `gorm:"default:0" json:"total_revenue"` // Gross after discounts, before refunds
+	RefundedQuantity int    `gorm:"default:0" json:"refunded_quantity"`
+	RefundedAmount   int64  `gorm:"default:0" json:"refunded_amount"`
 }
 
 // WorkPeriod represents a business day/shift
@@ -168,89 +287,74 @@ type WorkPeriod struct {
 
 	// Stats (Calculated on close)
 	TotalSales    int64 `gorm:"default:0" json:"total_sales"`
+	TotalRefunds  int64 `gorm:"default:0" json:"total_refunds"`
 	TotalOrders   int   `gorm:"default:0" json:"total_orders"`
 	TotalExpenses int64 `gorm:"default:0" json:"total_expenses"`
 	NetProfit     int64 `gorm:"default:0" json:"net_profit"`
+
+	// Cash drawer (float at start, reconciled on close)
+	OpeningFloat int64  `gorm:"default:0" json:"opening_float"`
+	ExpectedCash int64  `gorm:"default:0" json:"expected_cash"`
+	CountedCash  *int64 `json:"counted_cash"`  // Blind count, nil until the day is closed with a count
+	CashVariance *int64 `json:"cash_variance"` // Counted - expected (negative = short)
 }
 
 // HOOKS
 
+// BeforeCreate for Order: Snapshot the tax pricing mode
+// Sipariş için BeforeCreate: Vergi fiyatlandırma modunu snapshot al
+func (o *Order) BeforeCreate(tx *gorm.DB) (err error) {
+	if o.TaxMode == "" {
+		o.TaxMode = CurrentTaxMode()
+	}
+	return nil
+}
+
 // BeforeCreate for OrderItem: Snapshot product details and calculate subtotal
 // Sipariş kalemi için BeforeCreate: Ürün detaylarını快照 al ve toplam tutar hesapla
 func (item *OrderItem) BeforeCreate(tx *gorm.DB) (err error) {
-	// If Product details are not manually set (testing/overrides), fetch them
-	// Eğer ürün detayları manuel olarak ayarlanmamışsa (test/üzerindeki ayarlamalar), onları al
-	if item.UnitPrice == 0 || item.ProductName == "" {
+	// If Product details are not manually set (testing/overrides), fetch them.
+	// A snapshot keeps a free line free and its KDV rate as sold, even when the product changed since.
+	// Eğer ürün detayları manuel olarak ayarlanmamışsa (test/üzerindeki ayarlamalar), onları al.
+	// Snapshot ücretsiz satırı ücretsiz, KDV oranını satıldığı gibi tutar, ürün sonradan değişse bile.
+	if !item.Snapshotted && (item.UnitPrice == 0 || item.ProductName == "") {
 		var product Product
-		if err := tx.First(&product, item.ProductID).Error; err != nil {
+		if err := tx.Preload("Category").First(&product, item.ProductID).Error; err != nil {
 			return errors.New("product not found for item snapshot")
 		}
 		item.ProductName = product.Name
 		if item.UnitPrice == 0 {
 			item.UnitPrice = product.Price
 		}
+		// 0% is a valid KDV rate (exempt goods), so the rate is always resolved, never treated as unset
+		// %0 geçerli bir KDV oranıdır (muaf ürünler), bu yüzden oran her zaman çözülür, boş sayılmaz
+		item.TaxRate = product.EffectiveTaxRate()
+	}
+
+	// Modifier snapshots are attached before insert, fold their deltas into the unit price
+	// Opsiyon snapshot'ları eklemeden önce bağlanır, fiyat farklarını birim fiyata ekle
+	if len(item.Modifiers) > 0 {
+		item.ModifierTotal = 0
+		for _, m := range item.Modifiers {
+			item.ModifierTotal += m.PriceDelta
+		}
 	}
 
 	// Calculate Item Subtotal logic
 	// Sipariş kalemi toplam tutar hesaplaması
-	item.Subtotal = int64(item.Quantity) * item.UnitPrice
+	item.Subtotal = int64(item.Quantity) * item.LinePrice()
+	if item.Subtotal < 0 {
+		return errors.New("item price cannot be negative")
+	}
 	return nil
 }
 
 // AfterSave for OrderItem: Recalculate Order Totals
 // Sipariş kalemi için AfterSave: Sipariş toplam tutarını yeniden hesapla
 func (item *OrderItem) AfterSave(tx *gorm.DB) (err error) {
-	// Re-calculate the Order Total
-	// Sipariş toplam tutarını yeniden hesapla
-	var order Order
-	if err := tx.First(&order, item.OrderID).Error; err != nil {
-		return err
-	}
-
-	// Aggregate all items for this order
-	// We can use SQL sum for efficiency
-	// Tüm ürünleri toplam tutar için toplama hesapla
-	// Veritabanı için performans için SQL toplama kullanabiliriz
-	result := struct{ Total int64 }{}
-
-	// Sum all subtotals of items belonging to this order
-	// Bu siparişin ait tüm ürünleri toplam tutar için toplama hesapla
-	if err := tx.Model(&OrderItem{}).
-		Where("order_id = ?", item.OrderID).
-		Where("deleted_at IS NULL").
-		Select("sum(subtotal) as total").
-		Scan(&result).Error; err != nil {
-		return err
-	}
-
-	order.Subtotal = result.Total
-
-	// Recalculate Discount
-	if order.DiscountType == "PERCENTAGE" {
-		order.DiscountAmount = (order.Subtotal * order.DiscountValue) / 100
-	} else if order.DiscountType == "AMOUNT" {
-		// Fixed amount, but ensure it doesn't exceed subtotal
-		if order.DiscountValue > order.Subtotal {
-			order.DiscountAmount = order.Subtotal
-		} else {
-			order.DiscountAmount = order.DiscountValue
-		}
-	} else {
-		order.DiscountAmount = 0
-	}
-
-	order.TotalAmount = order.Subtotal - order.DiscountAmount + order.TaxAmount
-	// Note: Tax calculation logic might be complex (per item or global),
-	// for now we assume TaxAmount is set elsewhere or derived.
-	// If we want simply Tax = 0 or specific rule, we can add it.
-	// Let's keep it simple: Total = Subtotal (assuming inclusive tax or 0 tax for now unless logic added)
-
-	// Not: Vergi hesaplama mantığı ürün başına veya genel olabilir,
-	// şimdi TaxAmount başka yerde ayarlanmış veya hesaplanmış olarak kabul ediyoruz.
-	// Eğer basit Tax = 0 veya belirli bir kural istiyorsak, onu ekleyebiliriz.
-	// Basit tutar = toplam tutar (şimdiye kadar dahil edilmiş vergi veya 0 vergi için basit tutar)
-
-	return tx.Save(&order).Error
+	// Re-calculate the Order Total, discount split and KDV
+	// Sipariş toplamını, indirim dağılımını ve KDV'yi yeniden hesapla
+	return RecalculateOrderTotals(tx, item.OrderID)
 }
 
 // AfterDelete for OrderItem: Recalculate Order Totals
@@ -0,0 +1,38 @@
+package models
+
+// ModifierGroup groups selectable options of a product or a whole category (e.g. "Size", "Extras")
+// Bir ürünün veya tüm kategorinin seçilebilir opsiyon grubu (örn. "Boy", "Ekstralar")
+type ModifierGroup struct {
+	BaseModel
+	Name       string           `gorm:"size:100;not null" json:"name" validate:"required"`
+	ProductID  *uint            `gorm:"index" json:"product_id"`  // Attached to a single product
+	CategoryID *uint            `gorm:"index" json:"category_id"` // Or to every product of a category
+	IsRequired bool             `gorm:"default:false" json:"is_required"`
+	MinSelect  int              `gorm:"default:0" json:"min_select" validate:"min=0"`
+	MaxSelect  int              `gorm:"default:0" json:"max_select" validate:"min=0"` // 0 means unlimited
+	SortOrder  int              `gorm:"default:0" json:"sort_order"`
+	Options    []ModifierOption `gorm:"foreignKey:GroupID" json:"options,omitempty"`
+}
+
+// ModifierOption is a single choice inside a modifier group (e.g. "Extra Cheese", "No Onions")
+// Opsiyon grubundaki tek bir seçenek (örn. "Ekstra Kaşar", "Soğansız")
+type ModifierOption struct {
+	BaseModel
+	GroupID     uint   `gorm:"index;not null" json:"group_id"`
+	Name        string `gorm:"size:100;not null" json:"name" validate:"required"`
+	PriceDelta  int64  `gorm:"default:0" json:"price_delta"` // Added to unit price, in Kuruş (may be negative)
+	IsAvailable bool   `gorm:"default:true" json:"is_available"`
+	SortOrder   int    `gorm:"default:0" json:"sort_order"`
+}
+
+// OrderItemModifier is a snapshot of an option chosen for an order item
+// Sipariş kalemi için seçilen opsiyonun anlık görüntüsü
+type OrderItemModifier struct {
+	BaseModel
+	OrderItemID uint   `gorm:"index;not null" json:"order_item_id"`
+	GroupID     uint   `json:"group_id"`
+	OptionID    uint   `gorm:"index" json:"option_id"`
+	GroupName   string `gorm:"size:100" json:"group_name"`  // Snapshot
+	OptionName  string `gorm:"size:100" json:"option_name"` // Snapshot
+	PriceDelta  int64  `json:"price_delta"`                 // Snapshot
+}
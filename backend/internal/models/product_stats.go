@@ -0,0 +1,49 @@
+package models
+
+// Product ranking orders
+// Ürün sıralama yönleri
+const (
+	ProductRankTop    = "top"
+	ProductRankBottom = "bottom"
+)
+
+// Product ranking metrics
+// Ürün sıralama ölçütleri
+const (
+	ProductMetricQuantity = "quantity"
+	ProductMetricRevenue  = "revenue"
+)
+
+// ProductSalesSummary is the sales of a product summed over a date range
+// Bir ürünün tarih aralığındaki toplam satışı
+type ProductSalesSummary struct {
+	ProductID        uint   `json:"product_id"`
+	ProductName      string `json:"product_name"`
+	CategoryID       uint   `json:"category_id"`
+	CategoryName     string `json:"category_name"`
+	QuantitySold     int64  `json:"quantity_sold"`
+	TotalRevenue     int64  `json:"total_revenue"`
+	RefundedQuantity int64  `json:"refunded_quantity"`
+	RefundedAmount   int64  `json:"refunded_amount"`
+	NetQuantity      int64  `json:"net_quantity"` // Sold - refunded
+	NetRevenue       int64  `json:"net_revenue"`  // Revenue - refunds
+}
+
+// CategorySalesMix is the share of a category in the sales of a date range
+// Bir kategorinin tarih aralığındaki satışlar içindeki payı
+type CategorySalesMix struct {
+	CategoryID    uint    `json:"category_id"`
+	CategoryName  string  `json:"category_name"`
+	QuantitySold  int64   `json:"quantity_sold"`
+	NetRevenue    int64   `json:"net_revenue"`
+	QuantityShare float64 `json:"quantity_share"` // Percent of all units sold
+	RevenueShare  float64 `json:"revenue_share"`  // Percent of all net revenue
+}
+
+// ProductTrendPoint is the sales of a product on a single day
+// Bir ürünün tek bir gündeki satışı
+type ProductTrendPoint struct {
+	ReportDate   string `json:"report_date"` // YYYY-MM-DD
+	QuantitySold int64  `json:"quantity_sold"`
+	NetRevenue   int64  `json:"net_revenue"`
+}
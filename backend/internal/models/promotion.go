@@ -0,0 +1,308 @@
+package models
+
+import (
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Promotion Type Enum
+const (
+	PromotionHappyHour       = "HAPPY_HOUR"       // Percent off a category or product within a daily time window
+	PromotionCombo           = "COMBO"            // A set of products sold together at a fixed price
+	PromotionBuyXGetY        = "BUY_X_GET_Y"      // Every X units bought make the next Y units free (or percent off)
+	PromotionCategoryPercent = "CATEGORY_PERCENT" // Percent off a whole category
+)
+
+// Promotion is a price rule evaluated automatically on open orders whenever their items change.
+// Each unit sold gets at most one promotion: combos are matched first, then buy-X-get-Y,
+// and the remaining units take the best percent off they qualify for.
+// Açık siparişlerde kalemler her değiştiğinde otomatik uygulanan fiyat kuralı.
+// Her satılan adet en fazla bir promosyon alır: önce menüler, sonra X al Y öde eşleşir,
+// kalan adetler hak kazandıkları en yüksek yüzde indirimi alır.
+type Promotion struct {
+	BaseModel
+	Name        string               `gorm:"size:100;not null" json:"name"`
+	Type        string               `gorm:"size:20;not null" json:"type"` // HAPPY_HOUR, COMBO, BUY_X_GET_Y, CATEGORY_PERCENT
+	CategoryID  *uint                `json:"category_id"`                  // Target category, all but COMBO
+	ProductID   *uint                `json:"product_id"`                   // Target product instead of a category
+	Percent     int                  `gorm:"default:0" json:"percent"`     // Percent off; for BUY_X_GET_Y the discount on the Y units (100 = free)
+	BuyQuantity int                  `gorm:"default:0" json:"buy_quantity"`
+	GetQuantity int                  `gorm:"default:0" json:"get_quantity"`
+	ComboPrice  int64                `gorm:"default:0" json:"combo_price"` // Price of one bundle
+	ComboItems  []PromotionComboItem `json:"combo_items,omitempty"`
+	StartTime   string               `gorm:"size:5" json:"start_time"` // Daily window "15:00", empty = all day
+	EndTime     string               `gorm:"size:5" json:"end_time"`   // End of the window (exclusive), may pass midnight
+	StartsAt    *time.Time           `json:"starts_at"`                // Campaign start, nil = already running
+	EndsAt      *time.Time           `json:"ends_at"`                  // Campaign end, nil = open ended
+	IsActive    bool                 `gorm:"default:true" json:"is_active"`
+}
+
+// PromotionComboItem is a product and quantity a combo bundle is made of
+// Bir menü paketini oluşturan ürün ve adet
+type PromotionComboItem struct {
+	ID          uint `gorm:"primaryKey" json:"id"`
+	PromotionID uint `gorm:"index;not null" json:"promotion_id"`
+	ProductID   uint `gorm:"not null" json:"product_id"`
+	Quantity    int  `gorm:"not null;default:1" json:"quantity"`
+}
+
+// OrderPromotion is a promotion applied to an order, the itemized line shown on the order and receipt
+// Siparişe uygulanan promosyon, siparişte ve fişte ayrı satır olarak gösterilir
+type OrderPromotion struct {
+	ID            uint   `gorm:"primaryKey" json:"id"`
+	OrderID       uint   `gorm:"index;not null" json:"order_id"`
+	PromotionID   uint   `gorm:"index;not null" json:"promotion_id"`
+	PromotionName string `gorm:"size:100" json:"promotion_name"` // Snapshot
+	Type          string `gorm:"size:20" json:"type"`
+	Applications  int    `json:"applications"` // Bundles, groups or units the promotion was applied to
+	Amount        int64  `json:"amount"`       // Price reduction given
+}
+
+// inWindow reports whether t is inside the daily time window and the campaign dates
+// t'nin günlük saat aralığında ve kampanya tarihlerinde olup olmadığını bildirir
+func (p *Promotion) inWindow(t time.Time) bool {
+	if p.StartsAt != nil && t.Before(*p.StartsAt) {
+		return false
+	}
+	if p.EndsAt != nil && !t.Before(*p.EndsAt) {
+		return false
+	}
+	if p.StartTime == "" || p.EndTime == "" {
+		return true
+	}
+
+	clock := t.Local().Format("15:04")
+	if p.StartTime <= p.EndTime {
+		return clock >= p.StartTime && clock < p.EndTime
+	}
+	// The window passes midnight, e.g. 22:00-02:00
+	// Aralık gece yarısını geçiyor, örn. 22:00-02:00
+	return clock >= p.StartTime || clock < p.EndTime
+}
+
+// targets reports whether the promotion covers the product
+// Promosyonun ürünü kapsayıp kapsamadığını bildirir
+func (p *Promotion) targets(productID, categoryID uint) bool {
+	if p.ProductID != nil {
+		return *p.ProductID == productID
+	}
+	return p.CategoryID != nil && *p.CategoryID == categoryID
+}
+
+// promotionUnit is a single unit of an order item the engine hands promotions to
+// Motorun promosyon dağıttığı tek bir sipariş kalemi adedi
+type promotionUnit struct {
+	item     int
+	product  uint
+	category uint
+	price    int64
+	addedAt  time.Time
+	taken    bool
+}
+
+// ApplyPromotions sets the promotion amount of every item and returns the applied promotions.
+// A unit is eligible when the time it was added to the order falls in the promotion's window.
+// Her kalemin promosyon tutarını belirler ve uygulanan promosyonları döndürür.
+// Bir adet, siparişe eklendiği zaman promosyonun aralığına düşüyorsa uygundur.
+func ApplyPromotions(items []OrderItem, promotions []Promotion, categories map[uint]uint) []OrderPromotion {
+	var units []*promotionUnit
+	for i := range items {
+		items[i].PromotionAmount = 0
+		price := items[i].LinePrice()
+		if price <= 0 {
+			continue
+		}
+		for q := 0; q < items[i].Quantity; q++ {
+			units = append(units, &promotionUnit{
+				item:     i,
+				product:  items[i].ProductID,
+				category: categories[items[i].ProductID],
+				price:    price,
+				addedAt:  items[i].CreatedAt,
+			})
+		}
+	}
+	// Most expensive first, so bundles take the priciest units and free units are the cheapest of a group
+	// En pahalı önce, böylece menüler en pahalı adetleri alır ve bedava adetler grubun en ucuzları olur
+	sort.SliceStable(units, func(a, b int) bool { return units[a].price > units[b].price })
+
+	applied := make(map[uint]*OrderPromotion)
+	var order []uint
+	give := func(p *Promotion, discount []int64, consumed []*promotionUnit) {
+		line, ok := applied[p.ID]
+		if !ok {
+			line = &OrderPromotion{PromotionID: p.ID, PromotionName: p.Name, Type: p.Type}
+			applied[p.ID] = line
+			order = append(order, p.ID)
+		}
+		line.Applications++
+		for i, u := range consumed {
+			u.taken = true
+			items[u.item].PromotionAmount += discount[i]
+			line.Amount += discount[i]
+		}
+	}
+
+	for i := range promotions {
+		if p := &promotions[i]; p.Type == PromotionCombo {
+			applyCombo(p, units, give)
+		}
+	}
+	for i := range promotions {
+		if p := &promotions[i]; p.Type == PromotionBuyXGetY {
+			applyBuyXGetY(p, units, give)
+		}
+	}
+
+	for _, u := range units {
+		if u.taken {
+			continue
+		}
+		var best *Promotion
+		for i := range promotions {
+			p := &promotions[i]
+			if p.Type != PromotionHappyHour && p.Type != PromotionCategoryPercent {
+				continue
+			}
+			if !p.targets(u.product, u.category) || !p.inWindow(u.addedAt) {
+				continue
+			}
+			if best == nil || p.Percent > best.Percent {
+				best = p
+			}
+		}
+		if best != nil && best.Percent > 0 {
+			give(best, []int64{u.price * int64(best.Percent) / 100}, []*promotionUnit{u})
+		}
+	}
+
+	result := make([]OrderPromotion, 0, len(order))
+	for _, id := range order {
+		if applied[id].Amount > 0 {
+			result = append(result, *applied[id])
+		}
+	}
+	return result
+}
+
+// applyCombo sells as many bundles as the free units allow, each one at the combo price
+// Boştaki adetlerin izin verdiği kadar menüyü menü fiyatından satar
+func applyCombo(p *Promotion, units []*promotionUnit, give func(*Promotion, []int64, []*promotionUnit)) {
+	if len(p.ComboItems) == 0 {
+		return
+	}
+	for {
+		var bundle []*promotionUnit
+		picked := make(map[*promotionUnit]bool)
+		for _, part := range p.ComboItems {
+			need := part.Quantity
+			for _, u := range units {
+				if need == 0 {
+					break
+				}
+				if !u.taken && !picked[u] && u.product == part.ProductID && p.inWindow(u.addedAt) {
+					picked[u] = true
+					bundle = append(bundle, u)
+					need--
+				}
+			}
+			if need > 0 {
+				return
+			}
+		}
+
+		var full int64
+		for _, u := range bundle {
+			full += u.price
+		}
+		saving := full - p.ComboPrice
+		if saving <= 0 {
+			return
+		}
+		give(p, splitByPrice(bundle, saving, full), bundle)
+	}
+}
+
+// applyBuyXGetY groups the eligible units X+Y at a time, the cheapest Y of each group are discounted
+// Uygun adetleri X+Y'lik gruplara ayırır, her grubun en ucuz Y adedi indirim alır
+func applyBuyXGetY(p *Promotion, units []*promotionUnit, give func(*Promotion, []int64, []*promotionUnit)) {
+	size := p.BuyQuantity + p.GetQuantity
+	if p.BuyQuantity <= 0 || p.GetQuantity <= 0 {
+		return
+	}
+
+	var eligible []*promotionUnit
+	for _, u := range units {
+		if !u.taken && p.targets(u.product, u.category) && p.inWindow(u.addedAt) {
+			eligible = append(eligible, u)
+		}
+	}
+	for start := 0; start+size <= len(eligible); start += size {
+		group := eligible[start : start+size]
+		discount := make([]int64, size)
+		for i := p.BuyQuantity; i < size; i++ {
+			discount[i] = group[i].price * int64(p.Percent) / 100
+		}
+		give(p, discount, group)
+	}
+}
+
+// splitByPrice spreads an amount over units in proportion to their price, the rounding goes to the first unit
+// Bir tutarı adetlere fiyatlarıyla orantılı dağıtır, yuvarlama farkı ilk adede gider
+func splitByPrice(units []*promotionUnit, amount, total int64) []int64 {
+	shares := make([]int64, len(units))
+	var given int64
+	for i, u := range units {
+		shares[i] = amount * u.price / total
+		given += shares[i]
+	}
+	shares[0] += amount - given
+	return shares
+}
+
+// refreshPromotions re-evaluates the active promotions for the items of an open order and stores the itemized lines
+// Açık siparişin kalemleri için etkin promosyonları yeniden değerlendirir ve satırlarını kaydeder
+func refreshPromotions(tx *gorm.DB, orderID uint, items []OrderItem) error {
+	now := time.Now()
+	var promotions []Promotion
+	err := tx.Preload("ComboItems").
+		Where("is_active = ? AND (starts_at IS NULL OR starts_at <= ?) AND (ends_at IS NULL OR ends_at > ?)", true, now, now).
+		Order("id asc").
+		Find(&promotions).Error
+	if err != nil {
+		return err
+	}
+
+	categories := make(map[uint]uint, len(items))
+	if len(promotions) > 0 && len(items) > 0 {
+		productIDs := make([]uint, 0, len(items))
+		for _, item := range items {
+			productIDs = append(productIDs, item.ProductID)
+		}
+		var rows []struct {
+			ID         uint
+			CategoryID uint
+		}
+		if err := tx.Unscoped().Model(&Product{}).Select("id, category_id").Where("id IN ?", productIDs).Scan(&rows).Error; err != nil {
+			return err
+		}
+		for _, row := range rows {
+			categories[row.ID] = row.CategoryID
+		}
+	}
+
+	applied := ApplyPromotions(items, promotions, categories)
+
+	if err := tx.Where("order_id = ?", orderID).Delete(&OrderPromotion{}).Error; err != nil {
+		return err
+	}
+	for i := range applied {
+		applied[i].OrderID = orderID
+	}
+	if len(applied) > 0 {
+		return tx.Create(&applied).Error
+	}
+	return nil
+}
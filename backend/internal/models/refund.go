@@ -0,0 +1,50 @@
+package models
+
+// Refund Type Enum
+const (
+	RefundTypeItems = "ITEMS" // Selected items (or partial quantities)
+	RefundTypeVoid  = "VOID"  // Everything not refunded yet, reverses the whole sale
+)
+
+// Refund Reason Code Enum
+const (
+	RefundReasonCustomerComplaint = "CUSTOMER_COMPLAINT"
+	RefundReasonWrongItem         = "WRONG_ITEM"
+	RefundReasonQuality           = "QUALITY"
+	RefundReasonOvercharge        = "OVERCHARGE"
+	RefundReasonOther             = "OTHER"
+)
+
+// TransactionTypeRefund marks the negative transaction that reverses a sale
+// Satışı geri alan negatif işlemi belirtir
+const TransactionTypeRefund = "REFUND"
+
+// Refund reverses (part of) a completed order. The money goes back through REFUND transactions
+// with negative amounts, so the original INCOME records are never rewritten.
+// Tamamlanmış siparişin (bir kısmını) geri alır. Para negatif tutarlı REFUND işlemleriyle iade edilir,
+// böylece orijinal GELİR kayıtları hiçbir zaman değiştirilmez.
+type Refund struct {
+	BaseModel
+	OrderID       uint         `gorm:"index;not null" json:"order_id"`
+	WorkPeriodID  uint         `gorm:"index" json:"work_period_id"` // Period the money left the till
+	Type          string       `gorm:"size:20;not null" json:"type"`
+	ReasonCode    string       `gorm:"size:30;not null" json:"reason_code"`
+	Note          string       `gorm:"size:255" json:"note"`
+	PaymentMethod string       `gorm:"size:50" json:"payment_method"` // MIXED when a void spans several methods
+	Amount        int64        `gorm:"not null;check:amount > 0" json:"amount"`
+	Restock       bool         `gorm:"default:false" json:"restock"` // Refunded units were returned to stock
+	ApprovedBy    uint         `json:"approved_by"`
+	CreatedBy     uint         `json:"created_by"`
+	Items         []RefundItem `json:"items,omitempty"`
+}
+
+// RefundItem is a refunded quantity of an order item
+// Sipariş kaleminin iade edilen adedi
+type RefundItem struct {
+	BaseModel
+	RefundID    uint   `gorm:"index;not null" json:"refund_id"`
+	OrderItemID uint   `gorm:"index;not null" json:"order_item_id"`
+	ProductName string `gorm:"size:100" json:"product_name"` // Snapshot
+	Quantity    int    `gorm:"not null;check:quantity > 0" json:"quantity"`
+	Amount      int64  `gorm:"not null" json:"amount"`
+}
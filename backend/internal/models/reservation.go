@@ -0,0 +1,48 @@
+package models
+
+import "time"
+
+// Reservation Status Enum
+const (
+	ReservationStatusBooked    = "booked"
+	ReservationStatusSeated    = "seated"
+	ReservationStatusCancelled = "cancelled"
+	ReservationStatusNoShow    = "no_show"
+)
+
+// Reservation books one or more tables for a guest and time slot
+// Bir misafir ve zaman aralığı için bir veya daha fazla masayı ayırır
+type Reservation struct {
+	BaseModel
+	GuestName   string     `gorm:"size:100;not null" json:"guest_name"`
+	Phone       string     `gorm:"size:30" json:"phone"`
+	PartySize   int        `gorm:"not null;check:party_size > 0" json:"party_size"`
+	StartTime   time.Time  `gorm:"index;not null" json:"start_time"`
+	EndTime     time.Time  `gorm:"index;not null" json:"end_time"`
+	Note        string     `gorm:"size:255" json:"note"`
+	Status      string     `gorm:"size:20;index;default:'booked'" json:"status" validate:"oneof=booked seated cancelled no_show"`
+	Tables      []Table    `gorm:"many2many:reservation_tables;" json:"tables"`
+	OrderID     *uint      `json:"order_id"` // Order opened when the party was seated
+	SeatedAt    *time.Time `json:"seated_at"`
+	CancelledAt *time.Time `json:"cancelled_at"` // Also set when released as a no-show
+	CreatedBy   uint       `json:"created_by"`
+}
+
+// TableIDs returns the IDs of the reserved tables
+// Ayrılan masaların ID'lerini döndürür
+func (r *Reservation) TableIDs() []uint {
+	ids := make([]uint, 0, len(r.Tables))
+	for _, table := range r.Tables {
+		ids = append(ids, table.ID)
+	}
+	return ids
+}
+
+// ReservationFilter narrows down reservation queries, zero values are ignored
+// Rezervasyon sorgularını daraltır, sıfır değerler yok sayılır
+type ReservationFilter struct {
+	Start   time.Time // Slots ending after
+	End     time.Time // Slots starting before
+	Status  string
+	TableID *uint
+}
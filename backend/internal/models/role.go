@@ -0,0 +1,112 @@
+package models
+
+// Permission names checked by RequirePermission
+// RequirePermission tarafından kontrol edilen yetki adları
+const (
+	PermissionApplyDiscount      = "apply_discount"      // Discounts on open orders
+	PermissionVoidItem           = "void_item"           // Remove items from or cancel open orders
+	PermissionRefundOrder        = "refund_order"        // Refund or void completed orders
+	PermissionCloseDay           = "close_day"           // Start and end the work day
+	PermissionManageCashDrawer   = "manage_cash_drawer"  // Cash in, drops and pay-outs
+	PermissionViewReports        = "view_reports"        // Daily reports, history and analytics
+	PermissionManageMenu         = "manage_menu"         // Categories, products, modifiers, images
+	PermissionManageInventory    = "manage_inventory"    // Stock items, movements and recipes
+	PermissionManageTables       = "manage_tables"       // Table layout
+	PermissionManageReservations = "manage_reservations" // Book, change, cancel and seat reservations
+	PermissionManageKitchen      = "manage_kitchen"      // Kitchen stations and their stats
+	PermissionManageExpenses     = "manage_expenses"     // Expense records
+	PermissionIssueInvoice       = "issue_invoice"       // E-Arşiv invoices for completed orders
+	PermissionManageCustomers    = "manage_customers"    // Customer accounts, tab payments and statements
+	PermissionManageUsers        = "manage_users"        // Staff accounts and PINs
+	PermissionManageRoles        = "manage_roles"        // Roles and their permissions
+	PermissionViewAuditLog       = "view_audit_log"      // Audit log
+	PermissionApproveOverride    = "approve_override"    // Approve restricted actions of other users with a PIN
+)
+
+// AllPermissions lists every permission in display order
+// Tüm yetkileri gösterim sırasıyla listeler
+var AllPermissions = []string{
+	PermissionApplyDiscount,
+	PermissionVoidItem,
+	PermissionRefundOrder,
+	PermissionCloseDay,
+	PermissionManageCashDrawer,
+	PermissionViewReports,
+	PermissionManageMenu,
+	PermissionManageInventory,
+	PermissionManageTables,
+	PermissionManageReservations,
+	PermissionManageKitchen,
+	PermissionManageExpenses,
+	PermissionIssueInvoice,
+	PermissionManageCustomers,
+	PermissionManageUsers,
+	PermissionManageRoles,
+	PermissionViewAuditLog,
+	PermissionApproveOverride,
+}
+
+// Built-in role names
+// Yerleşik rol adları
+const (
+	RoleAdmin   = "admin"
+	RoleManager = "manager"
+	RoleCashier = "cashier"
+	RoleWaiter  = "waiter"
+	RoleKitchen = "kitchen"
+)
+
+// Role is a named set of permissions assigned to users by name.
+// The admin role is a system role that always holds every permission.
+// Kullanıcılara adıyla atanan isimli yetki kümesi.
+// Admin rolü her zaman tüm yetkilere sahip olan bir sistem rolüdür.
+type Role struct {
+	BaseModel
+	Name        string   `gorm:"size:20;uniqueIndex;not null" json:"name"`
+	Description string   `gorm:"size:255" json:"description"`
+	Permissions []string `gorm:"serializer:json;type:text" json:"permissions"`
+	IsSystem    bool     `gorm:"default:false" json:"is_system"` // Cannot be edited or deleted
+}
+
+// EffectivePermissions returns the permissions the role grants
+// Rolün verdiği yetkileri döndürür
+func (r *Role) EffectivePermissions() []string {
+	if r.Name == RoleAdmin {
+		return AllPermissions
+	}
+	if r.Permissions == nil {
+		return []string{}
+	}
+	return r.Permissions
+}
+
+// IsPermission reports whether name is a known permission
+// Adın bilinen bir yetki olup olmadığını belirtir
+func IsPermission(name string) bool {
+	for _, p := range AllPermissions {
+		if p == name {
+			return true
+		}
+	}
+	return false
+}
+
+// DefaultRoles are created on startup when missing; existing roles are never overwritten
+// Eksikse açılışta oluşturulur; mevcut roller asla üzerine yazılmaz
+var DefaultRoles = []Role{
+	{Name: RoleAdmin, Description: "Full access", IsSystem: true},
+	{Name: RoleManager, Description: "Runs the floor and the day", Permissions: []string{
+		PermissionApplyDiscount, PermissionVoidItem, PermissionRefundOrder, PermissionCloseDay,
+		PermissionManageCashDrawer, PermissionViewReports, PermissionManageMenu, PermissionManageInventory,
+		PermissionManageTables, PermissionManageReservations, PermissionManageKitchen, PermissionManageExpenses,
+		PermissionIssueInvoice, PermissionManageCustomers, PermissionViewAuditLog, PermissionApproveOverride,
+	}},
+	{Name: RoleCashier, Description: "Takes payments and keeps the drawer", Permissions: []string{
+		PermissionApplyDiscount, PermissionManageCashDrawer, PermissionManageReservations, PermissionIssueInvoice,
+		PermissionManageCustomers,
+	}},
+	{Name: RoleWaiter, Description: "Takes orders", Permissions: []string{
+		PermissionApplyDiscount, PermissionVoidItem, PermissionManageReservations,
+	}},
+	{Name: RoleKitchen, Description: "Works the kitchen display"},
+}
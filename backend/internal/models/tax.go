@@ -0,0 +1,235 @@
+package models
+
+import (
+	"sort"
+
+	"gorm.io/gorm"
+)
+
+// Tax Mode Enum
+const (
+	TaxModeInclusive = "INCLUSIVE" // Menu prices already contain KDV
+	TaxModeExclusive = "EXCLUSIVE" // KDV is added on top of menu prices
+)
+
+// Tax settings applied to new orders and products without an explicit rate, set at startup from config
+// Yeni siparişlere ve oranı belirtilmemiş ürünlere uygulanan vergi ayarları, açılışta konfigürasyondan atanır
+var (
+	DefaultTaxRate   = 20
+	PricesIncludeTax = true
+)
+
+// ConfigureTax sets the global tax settings
+// Genel vergi ayarlarını belirler
+func ConfigureTax(pricesIncludeTax bool, defaultRate int) {
+	PricesIncludeTax = pricesIncludeTax
+	DefaultTaxRate = defaultRate
+}
+
+// CurrentTaxMode returns the pricing mode new orders are created with
+// Yeni siparişlerin oluşturulduğu fiyatlandırma modunu döndürür
+func CurrentTaxMode() string {
+	if PricesIncludeTax {
+		return TaxModeInclusive
+	}
+	return TaxModeExclusive
+}
+
+// TaxRateTotal is the tax breakdown of sales for a single KDV rate
+// Tek bir KDV oranı için satışların vergi dökümü
+type TaxRateTotal struct {
+	TaxRate     int   `json:"tax_rate"`
+	NetAmount   int64 `json:"net_amount"`   // Taxable base (matrah)
+	TaxAmount   int64 `json:"tax_amount"`   // KDV
+	GrossAmount int64 `json:"gross_amount"` // Net + KDV
+}
+
+// EffectiveTaxRate resolves the KDV rate of the product, falling back to its category and then the default
+// Ürünün KDV oranını belirler; yoksa kategorisine, o da yoksa varsayılana düşer
+func (p *Product) EffectiveTaxRate() int {
+	if p.TaxRate != nil {
+		return *p.TaxRate
+	}
+	if p.Category.TaxRate != nil {
+		return *p.Category.TaxRate
+	}
+	return DefaultTaxRate
+}
+
+// CalculateTotals recomputes subtotal, discount, tax and total of the order from the given items.
+// Promotions are already on the items; a coupon applies to what is left after them, the manual
+// discount to what is left after the coupon. Both are spread over items proportionally so every
+// KDV bucket carries its share.
+// Siparişin ara toplam, indirim, vergi ve toplamını verilen kalemlerden yeniden hesaplar.
+// Promosyonlar kalemlerin üzerindedir; kupon onlardan kalan tutara, manuel indirim kupondan
+// kalan tutara uygulanır. İkisi de kalemlere orantılı dağıtılır, böylece her KDV oranı kendi payını taşır.
+func (o *Order) CalculateTotals(items []OrderItem) {
+	o.Subtotal = 0
+	o.PromotionAmount = 0
+	for _, item := range items {
+		o.Subtotal += item.Subtotal
+		o.PromotionAmount += item.PromotionAmount
+	}
+	afterPromotions := o.Subtotal - o.PromotionAmount
+
+	o.CouponAmount = 0
+	if o.CouponID != nil {
+		o.CouponAmount = reduction(o.CouponType, o.CouponValue, afterPromotions)
+	}
+	base := o.DiscountBase()
+	o.DiscountAmount = reduction(o.DiscountType, o.DiscountValue, base)
+
+	allocateDiscount(items, o.CouponAmount+o.DiscountAmount, afterPromotions)
+
+	o.TaxAmount = 0
+	for i := range items {
+		items[i].applyTax(o.TaxMode)
+		o.TaxAmount += items[i].TaxAmount
+	}
+
+	o.TotalAmount = base - o.DiscountAmount
+	if o.TaxMode == TaxModeExclusive {
+		o.TotalAmount += o.TaxAmount
+	}
+}
+
+// DiscountBase returns the subtotal after promotions and coupon, the amount a manual discount applies to
+// Promosyon ve kupondan sonraki ara toplamı, manuel indirimin uygulandığı tutarı döndürür
+func (o *Order) DiscountBase() int64 {
+	return o.Subtotal - o.PromotionAmount - o.CouponAmount
+}
+
+// reduction returns the amount a PERCENTAGE or AMOUNT value takes off the base, never more than the base
+// PERCENTAGE veya AMOUNT değerinin tutardan düştüğü miktarı döndürür, tutarı asla aşmaz
+func reduction(kind string, value, base int64) int64 {
+	switch kind {
+	case "PERCENTAGE":
+		return (base * value) / 100
+	case "AMOUNT":
+		// Fixed amount, but ensure it doesn't exceed the base
+		if value > base {
+			return base
+		}
+		return value
+	default:
+		return 0
+	}
+}
+
+// allocateDiscount splits the discount over items by subtotal after promotions using the largest remainder method
+// İndirimi en büyük kalan yöntemiyle kalemlere promosyon sonrası ara toplamlarına göre dağıtır
+func allocateDiscount(items []OrderItem, discount, subtotal int64) {
+	if discount <= 0 || subtotal <= 0 {
+		for i := range items {
+			items[i].DiscountAmount = 0
+		}
+		return
+	}
+
+	remainders := make([]int64, len(items))
+	var allocated int64
+	for i := range items {
+		share := (items[i].Subtotal - items[i].PromotionAmount) * discount
+		items[i].DiscountAmount = share / subtotal
+		remainders[i] = share % subtotal
+		allocated += items[i].DiscountAmount
+	}
+
+	order := make([]int, len(items))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return remainders[order[a]] > remainders[order[b]] })
+	for _, i := range order {
+		if allocated >= discount {
+			break
+		}
+		items[i].DiscountAmount++
+		allocated++
+	}
+}
+
+// applyTax computes the taxable base and KDV of the discounted line
+// İndirimli satırın matrahını ve KDV tutarını hesaplar
+func (item *OrderItem) applyTax(mode string) {
+	gross := item.Subtotal - item.PromotionAmount - item.DiscountAmount
+	rate := int64(item.TaxRate)
+
+	if mode == TaxModeExclusive {
+		item.NetAmount = gross
+		item.TaxAmount = (gross*rate + 50) / 100
+		return
+	}
+
+	// Inclusive (and legacy orders without a mode): KDV is carved out of the price
+	// Dahil (ve modu olmayan eski siparişler): KDV fiyatın içinden ayrılır
+	item.TaxAmount = includedTax(gross, rate)
+	item.NetAmount = gross - item.TaxAmount
+}
+
+// NetSubtotal returns the line before discount without KDV
+// Satırın indirimden önceki KDV hariç tutarını döndürür
+func (item *OrderItem) NetSubtotal(mode string) int64 {
+	if mode == TaxModeExclusive {
+		return item.Subtotal
+	}
+	return item.Subtotal - includedTax(item.Subtotal, int64(item.TaxRate))
+}
+
+// includedTax returns the KDV contained in a gross amount, rounded half up
+// Brüt tutarın içindeki KDV'yi yarım yukarı yuvarlayarak döndürür
+func includedTax(gross, rate int64) int64 {
+	return (gross*rate*2 + 100 + rate) / (2 * (100 + rate))
+}
+
+// PayableAmount returns what the customer owes for the whole line after discount and tax
+// Satırın indirim ve vergi sonrası müşteriye yansıyan tutarını döndürür
+func (item *OrderItem) PayableAmount() int64 {
+	return item.NetAmount + item.TaxAmount
+}
+
+// RecalculateOrderTotals reloads the order items and persists fresh totals and per item tax snapshots
+// Sipariş kalemlerini yeniden yükler, güncel toplamları ve kalem vergi snapshot'larını kaydeder
+func RecalculateOrderTotals(tx *gorm.DB, orderID uint) error {
+	var order Order
+	if err := tx.First(&order, orderID).Error; err != nil {
+		return err
+	}
+
+	var items []OrderItem
+	if err := tx.Where("order_id = ?", orderID).Order("id asc").Find(&items).Error; err != nil {
+		return err
+	}
+
+	// Completed orders keep the promotions they were sold with
+	// Tamamlanan siparişler satıldıkları promosyonları korur
+	if order.Status == "OPEN" {
+		if err := refreshPromotions(tx, order.ID, items); err != nil {
+			return err
+		}
+	}
+
+	order.CalculateTotals(items)
+
+	// UpdateColumns skips hooks so this does not recurse into AfterSave
+	// UpdateColumns hook'ları atlar, böylece AfterSave'e geri dönmez
+	for i := range items {
+		if err := tx.Model(&items[i]).UpdateColumns(map[string]interface{}{
+			"promotion_amount": items[i].PromotionAmount,
+			"discount_amount":  items[i].DiscountAmount,
+			"net_amount":       items[i].NetAmount,
+			"tax_amount":       items[i].TaxAmount,
+		}).Error; err != nil {
+			return err
+		}
+	}
+
+	return tx.Model(&order).UpdateColumns(map[string]interface{}{
+		"subtotal":         order.Subtotal,
+		"promotion_amount": order.PromotionAmount,
+		"coupon_amount":    order.CouponAmount,
+		"discount_amount":  order.DiscountAmount,
+		"tax_amount":       order.TaxAmount,
+		"total_amount":     order.TotalAmount,
+	}).Error
+}
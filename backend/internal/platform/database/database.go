@@ -45,15 +45,44 @@ func Migrate() {
 	// Otomatik migration
 	err := DB.AutoMigrate(
 		&models.User{},
+		&models.Role{},
 		&models.Category{},
 		&models.Product{},
+		&models.ModifierGroup{},
+		&models.ModifierOption{},
+		&models.Section{},
 		&models.Table{},
+		&models.Reservation{},
 		&models.Order{},
 		&models.OrderItem{},
+		&models.OrderItemModifier{},
+		&models.KitchenStation{},
+		&models.KitchenTicket{},
+		&models.StockItem{},
+		&models.RecipeItem{},
+		&models.StockMovement{},
+		&models.Payment{},
 		&models.Transaction{},
 		&models.DailyReport{},
 		&models.ProductSalesStat{},
 		&models.WorkPeriod{},
+		&models.CashMovement{},
+		&models.Refund{},
+		&models.RefundItem{},
+		&models.AuditLog{},
+		&models.Invoice{},
+		&models.InvoiceSequence{},
+		&models.Customer{},
+		&models.CustomerLedgerEntry{},
+		&models.LoyaltyRule{},
+		&models.LoyaltyStampCard{},
+		&models.LoyaltyEntry{},
+		&models.LoyaltyRedemption{},
+		&models.Promotion{},
+		&models.PromotionComboItem{},
+		&models.OrderPromotion{},
+		&models.Coupon{},
+		&models.CouponRedemption{},
 	)
 	// Error check
 	// Hata kontrolü
@@ -0,0 +1,158 @@
+package einvoice
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// UBL-TR profiles and invoice types
+// UBL-TR senaryoları ve fatura tipleri
+const (
+	ProfileEArchive = "EARSIVFATURA"
+	TypeSale        = "SATIS"
+	CurrencyTRY     = "TRY"
+)
+
+// Identification schemes of parties
+// Tarafların kimlik şemaları
+const (
+	SchemeVKN  = "VKN"  // 10 digit tax number of companies
+	SchemeTCKN = "TCKN" // 11 digit national ID of persons
+)
+
+// AnonymousTCKN is the ID GİB accepts for final consumers who do not give one
+// Kimlik vermeyen nihai tüketiciler için GİB'in kabul ettiği numara
+const AnonymousTCKN = "11111111111"
+
+// Party is the seller or the buyer of an invoice
+// Faturanın satıcısı veya alıcısı
+type Party struct {
+	TaxID     string // VKN or TCKN
+	Name      string // Company title, or full name of a person
+	TaxOffice string
+	Street    string
+	District  string
+	City      string
+	Country   string
+	Email     string
+}
+
+// Scheme returns VKN or TCKN by the length of the tax ID
+// Vergi numarasının uzunluğuna göre VKN veya TCKN döndürür
+func (p *Party) Scheme() string {
+	if len(p.TaxID) == 11 {
+		return SchemeTCKN
+	}
+	return SchemeVKN
+}
+
+// Line is a single invoiced product, amounts in kuruş
+// Faturalanan tek bir ürün, tutarlar kuruş cinsinden
+type Line struct {
+	Name        string
+	Quantity    int
+	GrossAmount int64 // Quantity x unit price before discount, without KDV
+	Discount    int64 // Discount on the line, without KDV
+	NetAmount   int64 // Gross - discount (matrah)
+	TaxRate     int
+	TaxAmount   int64
+}
+
+// TaxSubtotal is the KDV total of a single rate
+// Tek bir oranın KDV toplamı
+type TaxSubtotal struct {
+	TaxRate       int
+	TaxableAmount int64
+	TaxAmount     int64
+}
+
+// Document is an invoice ready to be written as UBL-TR
+// UBL-TR olarak yazılmaya hazır fatura
+type Document struct {
+	UUID     string
+	Number   string // 3 letter prefix + year + 9 digit sequence, e.g. EAR2026000000001
+	IssuedAt time.Time
+	Profile  string
+	Type     string
+	Currency string
+	Notes    []string
+	Supplier Party
+	Customer Party
+	Lines    []Line
+	Taxes    []TaxSubtotal
+}
+
+// Totals returns the line extension (net), discount, KDV and payable totals of the document
+// Belgenin net, indirim, KDV ve ödenecek toplamlarını döndürür
+func (d *Document) Totals() (lineExtension, discount, tax, payable int64) {
+	for _, l := range d.Lines {
+		lineExtension += l.NetAmount
+		discount += l.Discount
+	}
+	for _, t := range d.Taxes {
+		tax += t.TaxAmount
+	}
+	return lineExtension, discount, tax, lineExtension + tax
+}
+
+// ValidateTaxID checks a VKN (10 digits) or TCKN (11 digits) including its check digits
+// VKN (10 hane) veya TCKN (11 hane) numarasını kontrol haneleriyle birlikte doğrular
+func ValidateTaxID(id string) error {
+	id = strings.TrimSpace(id)
+	for _, r := range id {
+		if r < '0' || r > '9' {
+			return errors.New("tax ID must contain only digits")
+		}
+	}
+	switch len(id) {
+	case 10:
+		if !validVKN(id) {
+			return errors.New("invalid VKN")
+		}
+	case 11:
+		if id != AnonymousTCKN && !validTCKN(id) {
+			return errors.New("invalid TCKN")
+		}
+	default:
+		return errors.New("tax ID must be a 10 digit VKN or an 11 digit TCKN")
+	}
+	return nil
+}
+
+// validVKN applies the check digit algorithm of tax numbers
+// Vergi kimlik numarasının kontrol hanesi algoritmasını uygular
+func validVKN(id string) bool {
+	sum := 0
+	for i := 0; i < 9; i++ {
+		digit := (int(id[i]-'0') + 9 - i) % 10
+		value := (digit << (9 - i)) % 9
+		if digit != 0 && value == 0 {
+			value = 9
+		}
+		sum += value
+	}
+	return (10-sum%10)%10 == int(id[9]-'0')
+}
+
+// validTCKN applies the check digit algorithm of national IDs
+// T.C. kimlik numarasının kontrol hanesi algoritmasını uygular
+func validTCKN(id string) bool {
+	if id[0] == '0' {
+		return false
+	}
+	d := make([]int, 11)
+	for i := range d {
+		d[i] = int(id[i] - '0')
+	}
+	odd := d[0] + d[2] + d[4] + d[6] + d[8]
+	even := d[1] + d[3] + d[5] + d[7]
+	if ((odd*7-even)%10+10)%10 != d[9] {
+		return false
+	}
+	sum := 0
+	for _, digit := range d[:10] {
+		sum += digit
+	}
+	return sum%10 == d[10]
+}
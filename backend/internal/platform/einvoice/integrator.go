@@ -0,0 +1,55 @@
+package einvoice
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrNoIntegrator is returned when no integrator is configured
+// Entegratör yapılandırılmamışsa döner
+var ErrNoIntegrator = errors.New("no e-invoice integrator configured")
+
+// Integrator delivers a UBL invoice to GİB through a private integrator and returns its reference for the invoice
+// UBL faturasını özel entegratör üzerinden GİB'e iletir ve faturanın referansını döndürür
+type Integrator interface {
+	Send(number, uuid string, ubl []byte) (string, error)
+}
+
+// FileIntegrator stores every invoice as <number>.xml in a directory, a stand-in for testing without a provider
+// Her faturayı bir klasöre <numara>.xml olarak kaydeder, sağlayıcı olmadan test için yedek
+type FileIntegrator struct {
+	Dir string
+}
+
+func (i *FileIntegrator) Send(number, uuid string, ubl []byte) (string, error) {
+	if err := os.MkdirAll(i.Dir, 0o755); err != nil {
+		return "", err
+	}
+	path := filepath.Join(i.Dir, number+".xml")
+	if err := os.WriteFile(path, ubl, 0o644); err != nil {
+		return "", err
+	}
+	return "file:" + uuid, nil
+}
+
+// ParseIntegrator builds an integrator from its address, "file://dir" for the file stand-in.
+// Providers are added here as they are contracted.
+// Adresinden bir entegratör oluşturur, dosya yedeği için "file://dir".
+// Sağlayıcılar anlaşma yapıldıkça buraya eklenir.
+func ParseIntegrator(address string) (Integrator, error) {
+	address = strings.TrimSpace(address)
+	if address == "" {
+		return nil, ErrNoIntegrator
+	}
+
+	if dir, ok := strings.CutPrefix(address, "file://"); ok {
+		if dir == "" {
+			return nil, errors.New("file integrator needs a directory")
+		}
+		return &FileIntegrator{Dir: dir}, nil
+	}
+	return nil, fmt.Errorf("unsupported e-invoice integrator %q", address)
+}
@@ -0,0 +1,338 @@
+package einvoice
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// UBL namespaces used by UBL-TR 1.2
+// UBL-TR 1.2'nin kullandığı UBL isim alanları
+const (
+	nsInvoice = "urn:oasis:names:specification:ubl:schema:xsd:Invoice-2"
+	nsCAC     = "urn:oasis:names:specification:ubl:schema:xsd:CommonAggregateComponents-2"
+	nsCBC     = "urn:oasis:names:specification:ubl:schema:xsd:CommonBasicComponents-2"
+	nsEXT     = "urn:oasis:names:specification:ubl:schema:xsd:CommonExtensionComponents-2"
+)
+
+// kdvTaxTypeCode is the GİB code of KDV
+// KDV'nin GİB kodu
+const kdvTaxTypeCode = "0015"
+
+// unitPiece is the UN/ECE code for "one", used for menu items
+// Menü ürünleri için kullanılan "adet" UN/ECE kodu
+const unitPiece = "C62"
+
+// MarshalUBL writes the document as a UBL-TR 1.2 invoice.
+// The signature extension is left empty, the integrator signs the invoice.
+// Belgeyi UBL-TR 1.2 faturası olarak yazar.
+// İmza uzantısı boş bırakılır, faturayı entegratör imzalar.
+func MarshalUBL(doc *Document) ([]byte, error) {
+	lineExtension, discount, tax, payable := doc.Totals()
+	currency := doc.Currency
+	if currency == "" {
+		currency = CurrencyTRY
+	}
+	money := func(amount int64) ublAmount { return ublAmount{Currency: currency, Value: decimal(amount)} }
+
+	inv := ublInvoice{
+		XMLNS:           nsInvoice,
+		CAC:             nsCAC,
+		CBC:             nsCBC,
+		EXT:             nsEXT,
+		Extensions:      &ublExtensions{Extension: ublExtension{Content: ""}},
+		UBLVersionID:    "2.1",
+		CustomizationID: "TR1.2",
+		ProfileID:       doc.Profile,
+		ID:              doc.Number,
+		CopyIndicator:   false,
+		UUID:            doc.UUID,
+		IssueDate:       doc.IssuedAt.Format("2006-01-02"),
+		IssueTime:       doc.IssuedAt.Format("15:04:05"),
+		InvoiceTypeCode: doc.Type,
+		Notes:           doc.Notes,
+		CurrencyCode:    currency,
+		LineCount:       len(doc.Lines),
+		// e-Arşiv invoices carry how they reach the buyer
+		// e-Arşiv faturaları alıcıya nasıl ulaştıklarını taşır
+		References: []ublDocumentReference{{
+			ID:        "ELEKTRONIK",
+			IssueDate: doc.IssuedAt.Format("2006-01-02"),
+			TypeCode:  "SEND_TYPE",
+		}},
+		Supplier: ublPartyWrapper{Party: ublPartyOf(doc.Supplier)},
+		Customer: ublPartyWrapper{Party: ublPartyOf(doc.Customer)},
+		TaxTotal: ublTaxTotal{TaxAmount: money(tax)},
+		Monetary: ublMonetaryTotal{
+			LineExtension: money(lineExtension),
+			TaxExclusive:  money(lineExtension),
+			TaxInclusive:  money(payable),
+			Allowance:     money(discount),
+			Payable:       money(payable),
+		},
+	}
+	for _, t := range doc.Taxes {
+		inv.TaxTotal.Subtotals = append(inv.TaxTotal.Subtotals, ublTaxSubtotalOf(t.TaxableAmount, t.TaxAmount, t.TaxRate, money))
+	}
+
+	for i, l := range doc.Lines {
+		line := ublInvoiceLine{
+			ID:            strconv.Itoa(i + 1),
+			Quantity:      ublQuantity{UnitCode: unitPiece, Value: strconv.Itoa(l.Quantity)},
+			LineExtension: money(l.NetAmount),
+			TaxTotal: ublTaxTotal{
+				TaxAmount: money(l.TaxAmount),
+				Subtotals: []ublTaxSubtotal{ublTaxSubtotalOf(l.NetAmount, l.TaxAmount, l.TaxRate, money)},
+			},
+			Item:  ublItem{Name: l.Name},
+			Price: ublPrice{Amount: ublAmount{Currency: currency, Value: unitPrice(l.GrossAmount, l.Quantity)}},
+		}
+		if l.Discount > 0 {
+			rate := float64(l.Discount) / float64(l.GrossAmount)
+			line.Allowances = []ublAllowanceCharge{{
+				ChargeIndicator: false,
+				Multiplier:      strconv.FormatFloat(rate, 'f', 4, 64),
+				Amount:          money(l.Discount),
+				BaseAmount:      money(l.GrossAmount),
+			}}
+		}
+		inv.Lines = append(inv.Lines, line)
+	}
+
+	out, err := xml.MarshalIndent(inv, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), out...), nil
+}
+
+// decimal renders kuruş as a UBL amount, e.g. 123450 -> "1234.50"
+// Kuruşu UBL tutarı olarak yazar, örn. 123450 -> "1234.50"
+func decimal(amount int64) string {
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, amount/100, amount%100)
+}
+
+// unitPrice divides the line amount by the quantity, keeping up to 4 decimals so quantity x price adds up
+// Satır tutarını miktara böler, miktar x fiyat tutsun diye 4 ondalığa kadar korur
+func unitPrice(amount int64, quantity int) string {
+	if quantity <= 0 {
+		return decimal(amount)
+	}
+	price := strconv.FormatFloat(float64(amount)/100/float64(quantity), 'f', 4, 64)
+	price = strings.TrimRight(price, "0")
+	if dot := strings.IndexByte(price, '.'); len(price)-dot-1 < 2 {
+		price += strings.Repeat("0", 2-(len(price)-dot-1))
+	}
+	return price
+}
+
+func ublPartyOf(p Party) ublParty {
+	party := ublParty{
+		Identification: ublPartyIdentification{ID: ublSchemeID{Scheme: p.Scheme(), Value: p.TaxID}},
+		Address: ublAddress{
+			Street:   p.Street,
+			District: p.District,
+			City:     p.City,
+			Country:  ublCountry{Name: p.Country},
+		},
+		TaxScheme: ublPartyTaxScheme{TaxScheme: ublTaxScheme{Name: p.TaxOffice}},
+	}
+	if p.Email != "" {
+		party.Contact = &ublContact{Email: p.Email}
+	}
+
+	// Companies are named by title, persons by first and family name
+	// Şirketler unvanıyla, kişiler ad ve soyadıyla belirtilir
+	if p.Scheme() == SchemeTCKN {
+		first, family := splitName(p.Name)
+		party.Person = &ublPerson{FirstName: first, FamilyName: family}
+	} else {
+		party.Name = &ublPartyName{Name: p.Name}
+	}
+	return party
+}
+
+// splitName takes the last word as the family name
+// Son kelimeyi soyadı olarak alır
+func splitName(name string) (string, string) {
+	name = strings.TrimSpace(name)
+	if i := strings.LastIndex(name, " "); i > 0 {
+		return strings.TrimSpace(name[:i]), name[i+1:]
+	}
+	return name, name
+}
+
+func ublTaxSubtotalOf(taxable, tax int64, rate int, money func(int64) ublAmount) ublTaxSubtotal {
+	return ublTaxSubtotal{
+		Taxable:   money(taxable),
+		TaxAmount: money(tax),
+		Percent:   strconv.Itoa(rate),
+		Category: ublTaxCategory{
+			TaxScheme: ublTaxScheme{Name: "KDV", TypeCode: kdvTaxTypeCode},
+		},
+	}
+}
+
+// UBL-TR elements, names are written with their prefixes
+// UBL-TR öğeleri, adlar önekleriyle birlikte yazılır
+
+type ublInvoice struct {
+	XMLName         xml.Name               `xml:"Invoice"`
+	XMLNS           string                 `xml:"xmlns,attr"`
+	CAC             string                 `xml:"xmlns:cac,attr"`
+	CBC             string                 `xml:"xmlns:cbc,attr"`
+	EXT             string                 `xml:"xmlns:ext,attr"`
+	Extensions      *ublExtensions         `xml:"ext:UBLExtensions"`
+	UBLVersionID    string                 `xml:"cbc:UBLVersionID"`
+	CustomizationID string                 `xml:"cbc:CustomizationID"`
+	ProfileID       string                 `xml:"cbc:ProfileID"`
+	ID              string                 `xml:"cbc:ID"`
+	CopyIndicator   bool                   `xml:"cbc:CopyIndicator"`
+	UUID            string                 `xml:"cbc:UUID"`
+	IssueDate       string                 `xml:"cbc:IssueDate"`
+	IssueTime       string                 `xml:"cbc:IssueTime"`
+	InvoiceTypeCode string                 `xml:"cbc:InvoiceTypeCode"`
+	Notes           []string               `xml:"cbc:Note"`
+	CurrencyCode    string                 `xml:"cbc:DocumentCurrencyCode"`
+	LineCount       int                    `xml:"cbc:LineCountNumeric"`
+	References      []ublDocumentReference `xml:"cac:AdditionalDocumentReference"`
+	Supplier        ublPartyWrapper        `xml:"cac:AccountingSupplierParty"`
+	Customer        ublPartyWrapper        `xml:"cac:AccountingCustomerParty"`
+	TaxTotal        ublTaxTotal            `xml:"cac:TaxTotal"`
+	Monetary        ublMonetaryTotal       `xml:"cac:LegalMonetaryTotal"`
+	Lines           []ublInvoiceLine       `xml:"cac:InvoiceLine"`
+}
+
+type ublExtensions struct {
+	Extension ublExtension `xml:"ext:UBLExtension"`
+}
+
+type ublExtension struct {
+	Content string `xml:"ext:ExtensionContent"`
+}
+
+type ublDocumentReference struct {
+	ID        string `xml:"cbc:ID"`
+	IssueDate string `xml:"cbc:IssueDate"`
+	TypeCode  string `xml:"cbc:DocumentTypeCode"`
+}
+
+type ublPartyWrapper struct {
+	Party ublParty `xml:"cac:Party"`
+}
+
+type ublParty struct {
+	Identification ublPartyIdentification `xml:"cac:PartyIdentification"`
+	Name           *ublPartyName          `xml:"cac:PartyName,omitempty"`
+	Address        ublAddress             `xml:"cac:PostalAddress"`
+	TaxScheme      ublPartyTaxScheme      `xml:"cac:PartyTaxScheme"`
+	Contact        *ublContact            `xml:"cac:Contact,omitempty"`
+	Person         *ublPerson             `xml:"cac:Person,omitempty"`
+}
+
+type ublPartyIdentification struct {
+	ID ublSchemeID `xml:"cbc:ID"`
+}
+
+type ublSchemeID struct {
+	Scheme string `xml:"schemeID,attr"`
+	Value  string `xml:",chardata"`
+}
+
+type ublPartyName struct {
+	Name string `xml:"cbc:Name"`
+}
+
+type ublAddress struct {
+	Street   string     `xml:"cbc:StreetName,omitempty"`
+	District string     `xml:"cbc:CitySubdivisionName"`
+	City     string     `xml:"cbc:CityName"`
+	Country  ublCountry `xml:"cac:Country"`
+}
+
+type ublCountry struct {
+	Name string `xml:"cbc:Name"`
+}
+
+type ublPartyTaxScheme struct {
+	TaxScheme ublTaxScheme `xml:"cac:TaxScheme"`
+}
+
+type ublTaxScheme struct {
+	Name     string `xml:"cbc:Name,omitempty"`
+	TypeCode string `xml:"cbc:TaxTypeCode,omitempty"`
+}
+
+type ublContact struct {
+	Email string `xml:"cbc:ElectronicMail"`
+}
+
+type ublPerson struct {
+	FirstName  string `xml:"cbc:FirstName"`
+	FamilyName string `xml:"cbc:FamilyName"`
+}
+
+type ublAmount struct {
+	Currency string `xml:"currencyID,attr"`
+	Value    string `xml:",chardata"`
+}
+
+type ublQuantity struct {
+	UnitCode string `xml:"unitCode,attr"`
+	Value    string `xml:",chardata"`
+}
+
+type ublTaxTotal struct {
+	TaxAmount ublAmount        `xml:"cbc:TaxAmount"`
+	Subtotals []ublTaxSubtotal `xml:"cac:TaxSubtotal"`
+}
+
+type ublTaxSubtotal struct {
+	Taxable   ublAmount      `xml:"cbc:TaxableAmount"`
+	TaxAmount ublAmount      `xml:"cbc:TaxAmount"`
+	Percent   string         `xml:"cbc:Percent"`
+	Category  ublTaxCategory `xml:"cac:TaxCategory"`
+}
+
+type ublTaxCategory struct {
+	TaxScheme ublTaxScheme `xml:"cac:TaxScheme"`
+}
+
+type ublMonetaryTotal struct {
+	LineExtension ublAmount `xml:"cbc:LineExtensionAmount"`
+	TaxExclusive  ublAmount `xml:"cbc:TaxExclusiveAmount"`
+	TaxInclusive  ublAmount `xml:"cbc:TaxInclusiveAmount"`
+	Allowance     ublAmount `xml:"cbc:AllowanceTotalAmount"`
+	Payable       ublAmount `xml:"cbc:PayableAmount"`
+}
+
+type ublInvoiceLine struct {
+	ID            string               `xml:"cbc:ID"`
+	Quantity      ublQuantity          `xml:"cbc:InvoicedQuantity"`
+	LineExtension ublAmount            `xml:"cbc:LineExtensionAmount"`
+	Allowances    []ublAllowanceCharge `xml:"cac:AllowanceCharge"`
+	TaxTotal      ublTaxTotal          `xml:"cac:TaxTotal"`
+	Item          ublItem              `xml:"cac:Item"`
+	Price         ublPrice             `xml:"cac:Price"`
+}
+
+type ublAllowanceCharge struct {
+	ChargeIndicator bool      `xml:"cbc:ChargeIndicator"`
+	Multiplier      string    `xml:"cbc:MultiplierFactorNumeric"`
+	Amount          ublAmount `xml:"cbc:Amount"`
+	BaseAmount      ublAmount `xml:"cbc:BaseAmount"`
+}
+
+type ublItem struct {
+	Name string `xml:"cbc:Name"`
+}
+
+type ublPrice struct {
+	Amount ublAmount `xml:"cbc:PriceAmount"`
+}
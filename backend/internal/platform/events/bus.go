@@ -0,0 +1,140 @@
+package events
+
+import (
+	"sync"
+	"time"
+)
+
+// Event types pushed to connected clients
+// İstemcilere iletilen olay tipleri
+const (
+	OrderCreated       = "order_created"
+	OrderUpdated       = "order_updated"
+	OrderClosed        = "order_closed"
+	OrderCancelled     = "order_cancelled"
+	OrderRefunded      = "order_refunded"
+	ItemAdded          = "item_added"
+	ItemUpdated        = "item_updated"
+	ItemRemoved        = "item_removed"
+	PaymentAdded       = "payment_added"
+	TableStatusChanged = "table_status_changed"
+	TableUpdated       = "table_updated"
+	LayoutUpdated      = "layout_updated"
+	DayStarted         = "day_started"
+	DayEnded           = "day_ended"
+	StockLow           = "stock_low"
+	ReservationUpdated = "reservation_updated"
+)
+
+// subscriberBuffer is how many events a slow client may lag behind before events are dropped
+// Yavaş bir istemcinin olaylar düşürülmeden önce geride kalabileceği olay sayısı
+const subscriberBuffer = 64
+
+// Event is a single change notification
+// Tek bir değişiklik bildirimi
+type Event struct {
+	ID        uint64      `json:"id"`
+	Type      string      `json:"type"`
+	Data      interface{} `json:"data,omitempty"`
+	Timestamp time.Time   `json:"timestamp"`
+	Require   string      `json:"-"` // Permission the subscriber must hold, empty means everyone
+	Exclude   string      `json:"-"` // Permission the subscriber must not hold, empty means no one is excluded
+}
+
+type subscriber struct {
+	permissions map[string]bool
+	ch          chan Event
+}
+
+// Bus fans out published events to subscribers in-process
+// Yayınlanan olayları süreç içindeki abonelere dağıtır
+type Bus struct {
+	mu     sync.RWMutex
+	nextID uint64
+	subs   map[*subscriber]struct{}
+}
+
+func NewBus() *Bus {
+	return &Bus{subs: make(map[*subscriber]struct{})}
+}
+
+// Publish broadcasts an event to every subscriber.
+// A nil bus is a no-op so services can run without real-time wiring.
+// Olayı tüm abonelere yayınlar. Nil bus hiçbir şey yapmaz.
+func (b *Bus) Publish(eventType string, data interface{}) {
+	b.publish(Event{Type: eventType, Data: data})
+}
+
+// PublishTo broadcasts an event only to subscribers holding the permission
+// Olayı yalnızca yetkiye sahip abonelere yayınlar
+func (b *Bus) PublishTo(eventType string, data interface{}, permission string) {
+	b.publish(Event{Type: eventType, Data: data, Require: permission})
+}
+
+// PublishExcept broadcasts an event only to subscribers lacking the permission
+// Olayı yalnızca yetkiye sahip olmayan abonelere yayınlar
+func (b *Bus) PublishExcept(eventType string, data interface{}, permission string) {
+	b.publish(Event{Type: eventType, Data: data, Exclude: permission})
+}
+
+func (b *Bus) publish(event Event) {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	b.nextID++
+	event.ID = b.nextID
+	event.Timestamp = time.Now()
+	b.mu.Unlock()
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for sub := range b.subs {
+		if !event.VisibleTo(sub.permissions) {
+			continue
+		}
+		select {
+		case sub.ch <- event:
+		default:
+			// Drop instead of blocking publishers on a stuck client
+			// Takılı bir istemci yüzünden yayıncıyı bloklamak yerine düşür
+		}
+	}
+}
+
+// Subscribe registers a listener holding the given permissions and returns its channel and an unsubscribe func
+// Verilen yetkilere sahip bir dinleyici kaydeder; kanalı ve abonelikten çıkma fonksiyonunu döner
+func (b *Bus) Subscribe(permissions []string) (<-chan Event, func()) {
+	held := make(map[string]bool, len(permissions))
+	for _, p := range permissions {
+		held[p] = true
+	}
+	sub := &subscriber{permissions: held, ch: make(chan Event, subscriberBuffer)}
+
+	b.mu.Lock()
+	b.subs[sub] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	return sub.ch, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subs, sub)
+			b.mu.Unlock()
+			close(sub.ch)
+		})
+	}
+}
+
+// VisibleTo reports whether a subscriber holding the given permissions may receive the event
+// Verilen yetkilere sahip abonenin olayı alıp alamayacağını belirtir
+func (e Event) VisibleTo(permissions map[string]bool) bool {
+	if e.Require != "" && !permissions[e.Require] {
+		return false
+	}
+	if e.Exclude != "" && permissions[e.Exclude] {
+		return false
+	}
+	return true
+}
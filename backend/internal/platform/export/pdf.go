@@ -0,0 +1,155 @@
+package export
+
+import (
+	"io"
+	"os"
+	"strings"
+
+	"github.com/go-pdf/fpdf"
+)
+
+// pdfFamily is the family name the report font is registered under
+// Rapor yazı tipinin kaydedildiği aile adı
+const pdfFamily = "report"
+
+// turkishFallback replaces the Turkish letters missing from the built-in PDF fonts
+// Yerleşik PDF yazı tiplerinde olmayan Türkçe harfleri değiştirir
+var turkishFallback = strings.NewReplacer("ğ", "g", "Ğ", "G", "ş", "s", "Ş", "S", "ı", "i", "İ", "I")
+
+// PDF builds a simple A4 report of headings, label/value lines and tables
+// Başlık, etiket/değer satırı ve tablolardan oluşan basit bir A4 rapor oluşturur
+type PDF struct {
+	doc    *fpdf.Fpdf
+	family string
+	text   func(string) string
+	width  float64 // Printable width
+}
+
+// NewPDF starts a report using the TrueType font at fontPath for full Turkish text.
+// A bold variant next to it (DejaVuSans.ttf -> DejaVuSans-Bold.ttf) is used for headings when present.
+// Without the font the built-in Helvetica is used and ğ, ş, ı are written without their marks.
+// Tam Türkçe metin için fontPath'teki TrueType yazı tipiyle bir rapor başlatır.
+// Yanındaki kalın varyant (DejaVuSans.ttf -> DejaVuSans-Bold.ttf) varsa başlıklarda kullanılır.
+// Yazı tipi yoksa yerleşik Helvetica kullanılır ve ğ, ş, ı işaretsiz yazılır.
+func NewPDF(fontPath string) *PDF {
+	doc := fpdf.New("P", "mm", "A4", "")
+	doc.SetMargins(15, 15, 15)
+	doc.SetAutoPageBreak(true, 15)
+
+	p := &PDF{doc: doc}
+	if regular, err := os.ReadFile(fontPath); err == nil {
+		doc.AddUTF8FontFromBytes(pdfFamily, "", regular)
+		bold := regular
+		if b, err := os.ReadFile(strings.TrimSuffix(fontPath, ".ttf") + "-Bold.ttf"); err == nil {
+			bold = b
+		}
+		doc.AddUTF8FontFromBytes(pdfFamily, "B", bold)
+		p.family = pdfFamily
+		p.text = func(s string) string { return s }
+	} else {
+		p.family = "Helvetica"
+		translate := doc.UnicodeTranslatorFromDescriptor("cp1252")
+		p.text = func(s string) string { return translate(turkishFallback.Replace(s)) }
+	}
+
+	pageWidth, _ := doc.GetPageSize()
+	left, _, right, _ := doc.GetMargins()
+	p.width = pageWidth - left - right
+	doc.AddPage()
+	return p
+}
+
+// Title writes the centered report title
+// Ortalanmış rapor başlığını yazar
+func (p *PDF) Title(text string) *PDF {
+	p.doc.SetFont(p.family, "B", 16)
+	p.doc.CellFormat(p.width, 9, p.text(text), "", 1, "C", false, 0, "")
+	return p
+}
+
+// Subtitle writes a centered line under the title
+// Başlığın altına ortalanmış bir satır yazar
+func (p *PDF) Subtitle(text string) *PDF {
+	p.doc.SetFont(p.family, "", 10)
+	p.doc.CellFormat(p.width, 6, p.text(text), "", 1, "C", false, 0, "")
+	return p
+}
+
+// Section starts a titled block with a rule under the title
+// Başlığının altında çizgi olan bir blok başlatır
+func (p *PDF) Section(text string) *PDF {
+	p.doc.Ln(4)
+	p.doc.SetFont(p.family, "B", 11)
+	p.doc.CellFormat(p.width, 7, p.text(text), "B", 1, "L", false, 0, "")
+	p.doc.Ln(1)
+	return p
+}
+
+// Row writes a label on the left and its value on the right
+// Etiketi sola, değerini sağa yazar
+func (p *PDF) Row(label, value string) *PDF {
+	return p.row(label, value, "")
+}
+
+// TotalRow writes a label/value line in bold
+// Etiket/değer satırını kalın yazar
+func (p *PDF) TotalRow(label, value string) *PDF {
+	return p.row(label, value, "B")
+}
+
+func (p *PDF) row(label, value, style string) *PDF {
+	p.doc.SetFont(p.family, style, 10)
+	p.doc.CellFormat(p.width*0.6, 6, p.text(label), "", 0, "L", false, 0, "")
+	p.doc.CellFormat(p.width*0.4, 6, p.text(value), "", 1, "R", false, 0, "")
+	return p
+}
+
+// Table writes a bordered table, the first column left aligned and the rest right aligned
+// Kenarlıklı bir tablo yazar, ilk sütun sola, diğerleri sağa hizalı
+func (p *PDF) Table(headers []string, rows [][]string) *PDF {
+	if len(headers) == 0 {
+		return p
+	}
+	colWidth := p.width / float64(len(headers))
+	align := func(col int) string {
+		if col == 0 {
+			return "L"
+		}
+		return "R"
+	}
+
+	p.doc.SetFont(p.family, "B", 9)
+	p.doc.SetFillColor(235, 235, 235)
+	for col, title := range headers {
+		p.doc.CellFormat(colWidth, 6, p.text(title), "1", 0, align(col), true, 0, "")
+	}
+	p.doc.Ln(-1)
+
+	p.doc.SetFont(p.family, "", 9)
+	for _, row := range rows {
+		for col := range headers {
+			value := ""
+			if col < len(row) {
+				value = row[col]
+			}
+			p.doc.CellFormat(colWidth, 6, p.text(value), "1", 0, align(col), false, 0, "")
+		}
+		p.doc.Ln(-1)
+	}
+	return p
+}
+
+// Note writes a small paragraph
+// Küçük bir paragraf yazar
+func (p *PDF) Note(text string) *PDF {
+	p.doc.Ln(3)
+	p.doc.SetFont(p.family, "", 8)
+	p.doc.MultiCell(p.width, 4, p.text(text), "", "L", false)
+	return p
+}
+
+// Write renders the document
+// Belgeyi oluşturur
+func (p *PDF) Write(w io.Writer) error {
+	return p.doc.Output(w)
+}
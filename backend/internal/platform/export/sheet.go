@@ -0,0 +1,211 @@
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Money is an amount in kuruş, written as lira in exports
+// Kuruş cinsinden tutar, dışa aktarımlarda lira olarak yazılır
+type Money int64
+
+// Sheet is a titled table of rows, cells are string, int, int64, float64, Money, time.Time or *time.Time
+// Başlıklı bir satır tablosu, hücreler string, int, int64, float64, Money, time.Time veya *time.Time olabilir
+type Sheet struct {
+	Name    string
+	Headers []string
+	Rows    [][]interface{}
+}
+
+// NewSheet starts a sheet with the given column headers
+// Verilen sütun başlıklarıyla bir sayfa başlatır
+func NewSheet(name string, headers ...string) *Sheet {
+	return &Sheet{Name: name, Headers: headers}
+}
+
+// AddRow appends a row of cells
+// Bir hücre satırı ekler
+func (s *Sheet) AddRow(cells ...interface{}) {
+	s.Rows = append(s.Rows, cells)
+}
+
+// WriteCSV writes the sheet for Turkish spreadsheet programs: UTF-8 BOM, semicolon separated, decimal comma
+// Sayfayı Türkçe tablo programları için yazar: UTF-8 BOM, noktalı virgül ayraç, ondalık virgül
+func WriteCSV(w io.Writer, sheet *Sheet) error {
+	if _, err := io.WriteString(w, "\ufeff"); err != nil {
+		return err
+	}
+	cw := csv.NewWriter(w)
+	cw.Comma = ';'
+	if err := cw.Write(sheet.Headers); err != nil {
+		return err
+	}
+	record := make([]string, len(sheet.Headers))
+	for _, row := range sheet.Rows {
+		record = record[:0]
+		for _, cell := range row {
+			record = append(record, csvCell(cell))
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteXLSX writes the sheets into one workbook, amounts as numbers formatted in TL so they can be summed
+// Sayfaları tek bir çalışma kitabına yazar, tutarlar toplanabilmeleri için TL biçimli sayı olarak yazılır
+func WriteXLSX(w io.Writer, sheets ...*Sheet) error {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	header, err := f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}})
+	if err != nil {
+		return err
+	}
+	moneyFormat := `#,##0.00 "TL"`
+	money, err := f.NewStyle(&excelize.Style{CustomNumFmt: &moneyFormat})
+	if err != nil {
+		return err
+	}
+	dateFormat := "dd.mm.yyyy hh:mm"
+	date, err := f.NewStyle(&excelize.Style{CustomNumFmt: &dateFormat})
+	if err != nil {
+		return err
+	}
+
+	for i, sheet := range sheets {
+		name := sheetName(sheet.Name, i)
+		if i == 0 {
+			if err := f.SetSheetName("Sheet1", name); err != nil {
+				return err
+			}
+		} else if _, err := f.NewSheet(name); err != nil {
+			return err
+		}
+
+		for col, title := range sheet.Headers {
+			cell, _ := excelize.CoordinatesToCellName(col+1, 1)
+			if err := f.SetCellValue(name, cell, title); err != nil {
+				return err
+			}
+			if err := f.SetCellStyle(name, cell, cell, header); err != nil {
+				return err
+			}
+		}
+		for r, row := range sheet.Rows {
+			for col, value := range row {
+				cell, _ := excelize.CoordinatesToCellName(col+1, r+2)
+				style := 0
+				switch v := value.(type) {
+				case Money:
+					value = float64(v) / 100
+					style = money
+				case *time.Time:
+					if v == nil {
+						continue
+					}
+					value = wallClock(*v)
+					style = date
+				case time.Time:
+					value = wallClock(v)
+					style = date
+				}
+				if err := f.SetCellValue(name, cell, value); err != nil {
+					return err
+				}
+				if style != 0 {
+					if err := f.SetCellStyle(name, cell, cell, style); err != nil {
+						return err
+					}
+				}
+			}
+		}
+
+		if len(sheet.Headers) > 0 {
+			last, _ := excelize.ColumnNumberToName(len(sheet.Headers))
+			if err := f.SetColWidth(name, "A", last, 18); err != nil {
+				return err
+			}
+			if err := f.SetPanes(name, &excelize.Panes{Freeze: true, YSplit: 1, TopLeftCell: "A2", ActivePane: "bottomLeft"}); err != nil {
+				return err
+			}
+		}
+	}
+
+	return f.Write(w)
+}
+
+// FormatTL renders kuruş as lira with thousands dots and a decimal comma, e.g. 123450 -> "1.234,50 TL"
+// Kuruşu binlik nokta ve ondalık virgülle lira olarak yazar, örn. 123450 -> "1.234,50 TL"
+func FormatTL(amount int64) string {
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	lira := fmt.Sprintf("%d", amount/100)
+	var grouped strings.Builder
+	for i, digit := range lira {
+		if i > 0 && (len(lira)-i)%3 == 0 {
+			grouped.WriteByte('.')
+		}
+		grouped.WriteRune(digit)
+	}
+	return fmt.Sprintf("%s%s,%02d TL", sign, grouped.String(), amount%100)
+}
+
+// csvCell renders a cell for CSV
+// Hücreyi CSV için yazar
+func csvCell(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case Money:
+		sign := ""
+		amount := int64(v)
+		if amount < 0 {
+			sign = "-"
+			amount = -amount
+		}
+		return fmt.Sprintf("%s%d,%02d", sign, amount/100, amount%100)
+	case float64:
+		return strings.Replace(fmt.Sprintf("%.2f", v), ".", ",", 1)
+	case time.Time:
+		return v.Format("02.01.2006 15:04")
+	case *time.Time:
+		if v == nil {
+			return ""
+		}
+		return v.Format("02.01.2006 15:04")
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// wallClock keeps the local date and time of t, Excel cells have no time zone
+// t'nin yerel tarih ve saatini korur, Excel hücrelerinin saat dilimi yoktur
+func wallClock(t time.Time) time.Time {
+	t = t.Local()
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, time.UTC)
+}
+
+// sheetName keeps a worksheet name within the 31 characters Excel allows
+// Çalışma sayfası adını Excel'in izin verdiği 31 karakter içinde tutar
+func sheetName(name string, index int) string {
+	if name == "" {
+		name = fmt.Sprintf("Sheet%d", index+1)
+	}
+	if runes := []rune(name); len(runes) > 31 {
+		name = string(runes[:31])
+	}
+	return name
+}
@@ -0,0 +1,181 @@
+package printing
+
+import (
+	"bytes"
+	"strings"
+	"unicode/utf8"
+)
+
+// ESC/POS control bytes
+// ESC/POS kontrol baytları
+const (
+	esc = 0x1b
+	gs  = 0x1d
+	lf  = 0x0a
+)
+
+// codePagePC857 selects the Turkish code page on Epson compatible printers
+// Epson uyumlu yazıcılarda Türkçe kod sayfasını seçer
+const codePagePC857 = 13
+
+// Alignment of printed text
+// Yazdırılan metnin hizalaması
+type Alignment byte
+
+const (
+	AlignLeft   Alignment = 0
+	AlignCenter Alignment = 1
+	AlignRight  Alignment = 2
+)
+
+// pc857 maps characters outside ASCII to their PC857 byte
+// ASCII dışındaki karakterleri PC857 baytına eşler
+var pc857 = map[rune]byte{
+	'Ç': 0x80, 'ü': 0x81, 'é': 0x82, 'â': 0x83, 'ä': 0x84, 'à': 0x85, 'ç': 0x87,
+	'ê': 0x88, 'ë': 0x89, 'è': 0x8a, 'ï': 0x8b, 'î': 0x8c, 'ı': 0x8d, 'Ä': 0x8e,
+	'É': 0x90, 'ô': 0x93, 'ö': 0x94, 'ò': 0x95, 'û': 0x96, 'ù': 0x97, 'İ': 0x98,
+	'Ö': 0x99, 'Ü': 0x9a, 'Ş': 0x9e, 'ş': 0x9f, 'á': 0xa0, 'í': 0xa1, 'ó': 0xa2,
+	'ú': 0xa3, 'ñ': 0xa4, 'Ñ': 0xa5, 'Ğ': 0xa6, 'ğ': 0xa7,
+}
+
+// Document builds an ESC/POS byte stream for a fixed character width paper
+// Sabit karakter genişliğindeki kağıt için ESC/POS bayt akışı oluşturur
+type Document struct {
+	buf    bytes.Buffer
+	width  int
+	double bool
+}
+
+// NewDocument starts a document: resets the printer and selects the Turkish code page
+// Belge başlatır: yazıcıyı sıfırlar ve Türkçe kod sayfasını seçer
+func NewDocument(width int) *Document {
+	if width <= 0 {
+		width = 42
+	}
+	d := &Document{width: width}
+	d.buf.Write([]byte{esc, '@'})
+	d.buf.Write([]byte{esc, 't', codePagePC857})
+	return d
+}
+
+// Align sets the alignment of the following lines
+// Sonraki satırların hizalamasını ayarlar
+func (d *Document) Align(a Alignment) *Document {
+	d.buf.Write([]byte{esc, 'a', byte(a)})
+	return d
+}
+
+// Bold toggles emphasized text
+// Kalın yazıyı açar/kapatır
+func (d *Document) Bold(on bool) *Document {
+	d.buf.Write([]byte{esc, 'E', boolByte(on)})
+	return d
+}
+
+// DoubleSize toggles double width and height text, halving the characters per line
+// Çift genişlik ve yükseklikte yazıyı açar/kapatır, satır başına karakter yarıya iner
+func (d *Document) DoubleSize(on bool) *Document {
+	size := byte(0x00)
+	if on {
+		size = 0x11
+	}
+	d.buf.Write([]byte{gs, '!', size})
+	d.double = on
+	return d
+}
+
+// Line prints a line of text, cut to the paper width
+// Kağıt genişliğine göre kesilmiş bir metin satırı yazdırır
+func (d *Document) Line(text string) *Document {
+	d.writeText(truncate(text, d.columns()))
+	d.buf.WriteByte(lf)
+	return d
+}
+
+// Columns prints left and right aligned text on the same line.
+// A left part that does not fit leaves the right part on its own line.
+// Sol ve sağa hizalı metni aynı satıra yazdırır.
+// Sığmayan sol kısım, sağ kısmı ayrı bir satıra bırakır.
+func (d *Document) Columns(left, right string) *Document {
+	width := d.columns()
+	right = truncate(right, width)
+	gap := width - utf8.RuneCountInString(left) - utf8.RuneCountInString(right)
+	if gap < 1 {
+		d.Line(left)
+		gap = width - utf8.RuneCountInString(right)
+		left = ""
+	}
+	d.writeText(left + strings.Repeat(" ", gap) + right)
+	d.buf.WriteByte(lf)
+	return d
+}
+
+// Separator prints a dashed line across the paper
+// Kağıt boyunca kesikli çizgi yazdırır
+func (d *Document) Separator() *Document {
+	return d.Line(strings.Repeat("-", d.columns()))
+}
+
+// Feed advances the paper by n lines
+// Kağıdı n satır ilerletir
+func (d *Document) Feed(n int) *Document {
+	d.buf.Write([]byte{esc, 'd', byte(n)})
+	return d
+}
+
+// Cut feeds past the cutter and performs a partial cut
+// Kağıdı bıçağın ötesine ilerletir ve kısmi kesim yapar
+func (d *Document) Cut() *Document {
+	d.buf.Write([]byte{gs, 'V', 66, 0})
+	return d
+}
+
+// Bytes returns the rendered stream
+// Oluşturulan akışı döndürür
+func (d *Document) Bytes() []byte {
+	return d.buf.Bytes()
+}
+
+func (d *Document) columns() int {
+	if d.double {
+		return d.width / 2
+	}
+	return d.width
+}
+
+// writeText encodes text to PC857, unknown characters become '?'
+// Metni PC857'ye çevirir, bilinmeyen karakterler '?' olur
+func (d *Document) writeText(text string) {
+	for _, r := range text {
+		switch {
+		case r == '\n' || r == '\r' || r == '\t':
+			d.buf.WriteByte(' ')
+		case r < 0x20:
+			// Control characters would be read as printer commands
+			// Kontrol karakterleri yazıcı komutu olarak okunur
+			continue
+		case r < 0x80:
+			d.buf.WriteByte(byte(r))
+		default:
+			if b, ok := pc857[r]; ok {
+				d.buf.WriteByte(b)
+			} else {
+				d.buf.WriteByte('?')
+			}
+		}
+	}
+}
+
+func truncate(text string, width int) string {
+	if utf8.RuneCountInString(text) <= width {
+		return text
+	}
+	return string([]rune(text)[:width])
+}
+
+func boolByte(on bool) byte {
+	if on {
+		return 1
+	}
+	return 0
+}
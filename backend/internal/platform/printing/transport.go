@@ -0,0 +1,95 @@
+package printing
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultPort is the raw printing port of network receipt printers
+// Ağ fiş yazıcılarının ham yazdırma portu
+const DefaultPort = "9100"
+
+// ErrNoPrinter is returned when no printer is configured for a job
+// Bir iş için yazıcı yapılandırılmamışsa döner
+var ErrNoPrinter = errors.New("no printer configured")
+
+// Transport delivers a rendered ESC/POS stream to a printer
+// Oluşturulan ESC/POS akışını yazıcıya iletir
+type Transport interface {
+	Send(data []byte) error
+}
+
+// TCPTransport writes raw bytes to a network printer (port 9100)
+// Ham baytları ağ yazıcısına yazar (port 9100)
+type TCPTransport struct {
+	Addr    string
+	Timeout time.Duration
+}
+
+func (t *TCPTransport) Send(data []byte) error {
+	timeout := t.Timeout
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+
+	conn, err := net.DialTimeout("tcp", t.Addr, timeout)
+	if err != nil {
+		return fmt.Errorf("printer %s unreachable: %w", t.Addr, err)
+	}
+	defer conn.Close()
+
+	if err := conn.SetWriteDeadline(time.Now().Add(timeout)); err != nil {
+		return err
+	}
+	_, err = conn.Write(data)
+	return err
+}
+
+// FileTransport spools every job into its own file, for testing and printerless setups
+// Her işi ayrı bir dosyaya yazar, test ve yazıcısız kurulumlar için
+type FileTransport struct {
+	Dir string
+}
+
+var spoolSeq uint64
+
+func (t *FileTransport) Send(data []byte) error {
+	if err := os.MkdirAll(t.Dir, 0o755); err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%s-%d.bin", time.Now().Format("20060102-150405.000"), atomic.AddUint64(&spoolSeq, 1))
+	return os.WriteFile(filepath.Join(t.Dir, name), data, 0o644)
+}
+
+// ParseTransport builds a transport from a printer address:
+// "tcp://host[:port]" or "host[:port]" for network printers, "file://dir" for a spool directory.
+// Yazıcı adresinden transport oluşturur:
+// ağ yazıcıları için "tcp://host[:port]" veya "host[:port]", spool klasörü için "file://dir".
+func ParseTransport(address string) (Transport, error) {
+	address = strings.TrimSpace(address)
+	if address == "" {
+		return nil, ErrNoPrinter
+	}
+
+	if dir, ok := strings.CutPrefix(address, "file://"); ok {
+		if dir == "" {
+			return nil, errors.New("file printer needs a directory")
+		}
+		return &FileTransport{Dir: dir}, nil
+	}
+
+	host := strings.TrimPrefix(address, "tcp://")
+	if host == "" {
+		return nil, errors.New("network printer needs a host")
+	}
+	if _, _, err := net.SplitHostPort(host); err != nil {
+		host = net.JoinHostPort(host, DefaultPort)
+	}
+	return &TCPTransport{Addr: host}, nil
+}
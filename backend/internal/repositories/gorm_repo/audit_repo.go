@@ -0,0 +1,54 @@
+package gorm_repo
+
+import (
+	"simple-pos/internal/models"
+	"simple-pos/internal/repositories"
+
+	"gorm.io/gorm"
+)
+
+type auditRepository struct {
+	db *gorm.DB
+}
+
+func NewAuditRepository(db *gorm.DB) repositories.AuditRepository {
+	return &auditRepository{db: db}
+}
+
+// CreateWithTx appends an audit entry within an existing DB transaction
+// Mevcut bir veritabanı işlemi içinde denetim kaydı ekler
+func (r *auditRepository) CreateWithTx(tx *gorm.DB, entry *models.AuditLog) error {
+	return tx.Create(entry).Error
+}
+
+// Find lists audit entries matching the filter, newest first
+// Filtreye uyan denetim kayıtlarını yeniden eskiye listeler
+func (r *auditRepository) Find(filter models.AuditFilter) ([]models.AuditLog, error) {
+	var entries []models.AuditLog
+	query := r.db.Model(&models.AuditLog{})
+	if filter.UserID != nil {
+		query = query.Where("user_id = ?", *filter.UserID)
+	}
+	if filter.EntityType != "" {
+		query = query.Where("entity_type = ?", filter.EntityType)
+	}
+	if filter.EntityID != nil {
+		query = query.Where("entity_id = ?", *filter.EntityID)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if !filter.Start.IsZero() {
+		query = query.Where("created_at >= ?", filter.Start)
+	}
+	if !filter.End.IsZero() {
+		query = query.Where("created_at < ?", filter.End)
+	}
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+	if err := query.Order("created_at desc, id desc").Find(&entries).Error; err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
@@ -30,6 +30,8 @@ func (r *categoryRepository) Create(category *models.Category) error {
 			existingCategory.DeletedAt = gorm.DeletedAt{} // Restore / Geri yükle
 			existingCategory.Icon = category.Icon
 			existingCategory.Color = category.Color
+			existingCategory.StationID = category.StationID
+			existingCategory.TaxRate = category.TaxRate
 			existingCategory.IsActive = true
 
 			if saveErr := r.db.Save(&existingCategory).Error; saveErr != nil {
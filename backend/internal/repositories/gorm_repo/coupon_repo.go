@@ -0,0 +1,102 @@
+package gorm_repo
+
+import (
+	"simple-pos/internal/models"
+	"simple-pos/internal/repositories"
+
+	"gorm.io/gorm"
+)
+
+type couponRepository struct {
+	db *gorm.DB
+}
+
+// NewCouponRepository creates a new instance of CouponRepository
+// Yeni bir CouponRepository örneği oluşturur
+func NewCouponRepository(db *gorm.DB) repositories.CouponRepository {
+	return &couponRepository{db: db}
+}
+
+func (r *couponRepository) Create(coupon *models.Coupon) error {
+	return r.db.Create(coupon).Error
+}
+
+// Update saves the coupon details, the used count only changes with redemptions
+// Kupon bilgilerini kaydeder, kullanım sayısı yalnızca kullanımlarla değişir
+func (r *couponRepository) Update(coupon *models.Coupon) error {
+	return r.db.Omit("used_count").Save(coupon).Error
+}
+
+func (r *couponRepository) Delete(id uint) error {
+	return r.db.Delete(&models.Coupon{}, id).Error
+}
+
+func (r *couponRepository) FindByID(id uint) (*models.Coupon, error) {
+	var coupon models.Coupon
+	if err := r.db.First(&coupon, id).Error; err != nil {
+		return nil, err
+	}
+	return &coupon, nil
+}
+
+func (r *couponRepository) FindByCode(code string) (*models.Coupon, error) {
+	var coupon models.Coupon
+	if err := r.db.Where("code = ?", code).First(&coupon).Error; err != nil {
+		return nil, err
+	}
+	return &coupon, nil
+}
+
+func (r *couponRepository) FindAll(includeInactive bool) ([]models.Coupon, error) {
+	query := r.db.Model(&models.Coupon{})
+	if !includeInactive {
+		query = query.Where("is_active = ?", true)
+	}
+
+	var coupons []models.Coupon
+	if err := query.Order("id desc").Find(&coupons).Error; err != nil {
+		return nil, err
+	}
+	return coupons, nil
+}
+
+func (r *couponRepository) FindByIDWithTx(tx *gorm.DB, id uint) (*models.Coupon, error) {
+	var coupon models.Coupon
+	if err := tx.First(&coupon, id).Error; err != nil {
+		return nil, err
+	}
+	return &coupon, nil
+}
+
+func (r *couponRepository) CountCustomerRedemptionsWithTx(tx *gorm.DB, couponID, customerID uint) (int64, error) {
+	var count int64
+	err := tx.Model(&models.CouponRedemption{}).
+		Where("coupon_id = ? AND customer_id = ?", couponID, customerID).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *couponRepository) CreateRedemptionWithTx(tx *gorm.DB, redemption *models.CouponRedemption) error {
+	if err := tx.Create(redemption).Error; err != nil {
+		return err
+	}
+	return tx.Model(&models.Coupon{}).Where("id = ?", redemption.CouponID).
+		UpdateColumn("used_count", gorm.Expr("used_count + 1")).Error
+}
+
+// DeleteRedemptionWithTx does nothing when the order used no coupon
+// Sipariş kupon kullanmadıysa bir şey yapmaz
+func (r *couponRepository) DeleteRedemptionWithTx(tx *gorm.DB, orderID uint) error {
+	var redemptions []models.CouponRedemption
+	if err := tx.Where("order_id = ?", orderID).Limit(1).Find(&redemptions).Error; err != nil {
+		return err
+	}
+	if len(redemptions) == 0 {
+		return nil
+	}
+	if err := tx.Delete(&redemptions[0]).Error; err != nil {
+		return err
+	}
+	return tx.Model(&models.Coupon{}).Unscoped().Where("id = ? AND used_count > 0", redemptions[0].CouponID).
+		UpdateColumn("used_count", gorm.Expr("used_count - 1")).Error
+}
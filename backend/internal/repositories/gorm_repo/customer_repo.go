@@ -0,0 +1,127 @@
+package gorm_repo
+
+import (
+	"simple-pos/internal/models"
+	"simple-pos/internal/repositories"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type customerRepository struct {
+	db *gorm.DB
+}
+
+// NewCustomerRepository creates a new instance of CustomerRepository
+// Yeni bir CustomerRepository örneği oluşturur
+func NewCustomerRepository(db *gorm.DB) repositories.CustomerRepository {
+	return &customerRepository{db: db}
+}
+
+func (r *customerRepository) Create(customer *models.Customer) error {
+	return r.db.Create(customer).Error
+}
+
+// Update saves the customer details, the balance and loyalty points only change through their own entries
+// Müşteri bilgilerini kaydeder, bakiye ve sadakat puanı yalnızca kendi hareketleriyle değişir
+func (r *customerRepository) Update(customer *models.Customer) error {
+	return r.db.Omit("balance", "loyalty_points").Save(customer).Error
+}
+
+func (r *customerRepository) Delete(id uint) error {
+	return r.db.Delete(&models.Customer{}, id).Error
+}
+
+func (r *customerRepository) FindByID(id uint) (*models.Customer, error) {
+	var customer models.Customer
+	if err := r.db.First(&customer, id).Error; err != nil {
+		return nil, err
+	}
+	return &customer, nil
+}
+
+func (r *customerRepository) FindByPhone(phone string) (*models.Customer, error) {
+	var customer models.Customer
+	if err := r.db.Where("phone = ?", phone).Order("id asc").First(&customer).Error; err != nil {
+		return nil, err
+	}
+	return &customer, nil
+}
+
+// Find lists customers matching the filter by name
+// Filtreye uyan müşterileri ada göre listeler
+func (r *customerRepository) Find(filter models.CustomerFilter) ([]models.Customer, error) {
+	query := r.db.Model(&models.Customer{})
+	if filter.Search != "" {
+		like := "%" + filter.Search + "%"
+		query = query.Where("name LIKE ? OR phone LIKE ?", like, like)
+	}
+	if filter.WithBalance {
+		query = query.Where("balance > 0")
+	}
+	if !filter.IncludeInactive {
+		query = query.Where("is_active = ?", true)
+	}
+
+	var customers []models.Customer
+	if err := query.Order("name asc").Find(&customers).Error; err != nil {
+		return nil, err
+	}
+	return customers, nil
+}
+
+func (r *customerRepository) FindByIDWithTx(tx *gorm.DB, id uint) (*models.Customer, error) {
+	var customer models.Customer
+	if err := tx.First(&customer, id).Error; err != nil {
+		return nil, err
+	}
+	return &customer, nil
+}
+
+// CreateEntryWithTx moves the customer balance by the entry amount in the database,
+// then appends the entry with the balance read back from the updated row
+// Müşteri bakiyesini veritabanında hareket tutarı kadar değiştirir,
+// ardından hareketi güncellenen satırdan okunan bakiyeyle ekler
+func (r *customerRepository) CreateEntryWithTx(tx *gorm.DB, entry *models.CustomerLedgerEntry) error {
+	// UpdateColumn skips UpdatedAt on purpose, the balance is not a detail change
+	// UpdateColumn bilerek UpdatedAt'i atlar, bakiye bir bilgi değişikliği değildir
+	err := tx.Model(&models.Customer{}).Where("id = ?", entry.CustomerID).
+		UpdateColumn("balance", gorm.Expr("balance + ?", entry.Amount)).Error
+	if err != nil {
+		return err
+	}
+	if err := tx.Model(&models.Customer{}).Where("id = ?", entry.CustomerID).
+		Pluck("balance", &entry.Balance).Error; err != nil {
+		return err
+	}
+	return tx.Create(entry).Error
+}
+
+// FindEntries lists the ledger entries of a customer created in [start, end), oldest first
+// Müşterinin [start, end) aralığında oluşan hesap hareketlerini eskiden yeniye listeler
+func (r *customerRepository) FindEntries(customerID uint, start, end time.Time) ([]models.CustomerLedgerEntry, error) {
+	var entries []models.CustomerLedgerEntry
+	err := r.db.Where("customer_id = ? AND created_at >= ? AND created_at < ?", customerID, start, end).
+		Order("created_at asc, id asc").
+		Find(&entries).Error
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// BalanceAt sums the entries of a customer created before the given time
+// Müşterinin verilen zamandan önce oluşan hareketlerini toplar
+func (r *customerRepository) BalanceAt(customerID uint, t time.Time) (int64, error) {
+	var balance int64
+	err := r.db.Model(&models.CustomerLedgerEntry{}).
+		Select("COALESCE(sum(amount), 0)").
+		Where("customer_id = ? AND created_at < ?", customerID, t).
+		Scan(&balance).Error
+	return balance, err
+}
+
+// WithTransaction runs a function within a database transaction
+func (r *customerRepository) WithTransaction(fn func(tx *gorm.DB) error) error {
+	return r.db.Transaction(fn)
+}
@@ -0,0 +1,189 @@
+package gorm_repo
+
+import (
+	"simple-pos/internal/models"
+	"simple-pos/internal/repositories"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type inventoryRepository struct {
+	db *gorm.DB
+}
+
+// NewInventoryRepository creates a new instance of InventoryRepository
+// Yeni bir InventoryRepository örneği oluşturur
+func NewInventoryRepository(db *gorm.DB) repositories.InventoryRepository {
+	return &inventoryRepository{db: db}
+}
+
+// CreateStockItem creates a new stock item
+// Yeni bir stok kalemi oluşturur
+func (r *inventoryRepository) CreateStockItem(item *models.StockItem) error {
+	return r.db.Create(item).Error
+}
+
+// FindAllStockItems lists stock items by name
+// Stok kalemlerini isme göre listeler
+func (r *inventoryRepository) FindAllStockItems() ([]models.StockItem, error) {
+	var items []models.StockItem
+	if err := r.db.Order("name asc").Find(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// FindStockItemByID finds a stock item
+// ID ile stok kalemini bulur
+func (r *inventoryRepository) FindStockItemByID(id uint) (*models.StockItem, error) {
+	var item models.StockItem
+	if err := r.db.First(&item, id).Error; err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// UpdateStockItem updates stock item details (quantity only changes through movements)
+// Stok kalemi detaylarını günceller (miktar yalnızca hareketlerle değişir)
+func (r *inventoryRepository) UpdateStockItem(item *models.StockItem) error {
+	return r.db.Model(item).Select("name", "unit", "low_stock_threshold", "is_active").Updates(item).Error
+}
+
+// DeleteStockItem deletes a stock item and removes it from recipes
+// Stok kalemini siler ve reçetelerden çıkarır
+func (r *inventoryRepository) DeleteStockItem(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("stock_item_id = ?", id).Delete(&models.RecipeItem{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.StockItem{}, id).Error
+	})
+}
+
+// FindLowStockItems lists active items at or below their alert threshold
+// Uyarı eşiğinde veya altındaki aktif kalemleri listeler
+func (r *inventoryRepository) FindLowStockItems() ([]models.StockItem, error) {
+	var items []models.StockItem
+	if err := r.db.Where("is_active = ? AND low_stock_threshold > 0 AND quantity <= low_stock_threshold", true).
+		Order("name asc").Find(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// FindRecipe lists the ingredients of a product
+// Bir ürünün reçetesini listeler
+func (r *inventoryRepository) FindRecipe(productID uint) ([]models.RecipeItem, error) {
+	return r.FindRecipeWithTx(r.db, productID)
+}
+
+// FindRecipeWithTx lists the ingredients of a product within an existing DB transaction
+// Mevcut bir veritabanı işlemi içinde ürünün reçetesini listeler
+func (r *inventoryRepository) FindRecipeWithTx(tx *gorm.DB, productID uint) ([]models.RecipeItem, error) {
+	var items []models.RecipeItem
+	if err := tx.Preload("StockItem").Where("product_id = ?", productID).Order("id asc").Find(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// ReplaceRecipe swaps the whole recipe of a product
+// Bir ürünün tüm reçetesini değiştirir
+func (r *inventoryRepository) ReplaceRecipe(productID uint, items []models.RecipeItem) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// Hard delete so the unique (product, stock item) index can be reused
+		// Benzersiz (ürün, stok) indeksinin tekrar kullanılabilmesi için kalıcı sil
+		if err := tx.Unscoped().Where("product_id = ?", productID).Delete(&models.RecipeItem{}).Error; err != nil {
+			return err
+		}
+		if len(items) == 0 {
+			return nil
+		}
+		return tx.Omit("StockItem").Create(&items).Error
+	})
+}
+
+// CreateMovementWithTx applies the movement to the stock level and records it
+// Hareketi stok seviyesine uygular ve kaydeder
+func (r *inventoryRepository) CreateMovementWithTx(tx *gorm.DB, movement *models.StockMovement) error {
+	if err := tx.Model(&models.StockItem{}).Where("id = ?", movement.StockItemID).
+		UpdateColumn("quantity", gorm.Expr("quantity + ?", movement.Quantity)).Error; err != nil {
+		return err
+	}
+
+	var item models.StockItem
+	if err := tx.First(&item, movement.StockItemID).Error; err != nil {
+		return err
+	}
+	movement.BalanceAfter = item.Quantity
+
+	return tx.Omit("StockItem").Create(movement).Error
+}
+
+// FindMovements lists movements in the date range, optionally for a single stock item, newest first
+// Tarih aralığındaki hareketleri (isteğe bağlı tek stok kalemi için) yeniden eskiye listeler
+func (r *inventoryRepository) FindMovements(stockItemID *uint, start, end time.Time) ([]models.StockMovement, error) {
+	var movements []models.StockMovement
+	query := r.db.Preload("StockItem").Where("created_at >= ? AND created_at < ?", start, end)
+	if stockItemID != nil {
+		query = query.Where("stock_item_id = ?", *stockItemID)
+	}
+	if err := query.Order("created_at desc").Find(&movements).Error; err != nil {
+		return nil, err
+	}
+	return movements, nil
+}
+
+// SumItemMovementsWithTx sums booked SALE/RETURN deltas of an order item per stock item
+// Bir sipariş kaleminin SALE/RETURN hareketlerini stok kalemi bazında toplar
+func (r *inventoryRepository) SumItemMovementsWithTx(tx *gorm.DB, orderItemID uint) (map[uint]float64, error) {
+	var rows []struct {
+		StockItemID uint
+		Total       float64
+	}
+	err := tx.Model(&models.StockMovement{}).
+		Select("stock_item_id, COALESCE(sum(quantity), 0) as total").
+		Where("order_item_id = ?", orderItemID).
+		Group("stock_item_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	totals := make(map[uint]float64, len(rows))
+	for _, row := range rows {
+		totals[row.StockItemID] = row.Total
+	}
+	return totals, nil
+}
+
+// PendingDemand sums recipe quantities of items on OPEN orders per stock item
+// AÇIK siparişlerdeki kalemlerin reçete miktarlarını stok kalemi bazında toplar
+func (r *inventoryRepository) PendingDemand() (map[uint]float64, error) {
+	var rows []struct {
+		StockItemID uint
+		Total       float64
+	}
+	err := r.db.Model(&models.OrderItem{}).
+		Select("recipe_items.stock_item_id as stock_item_id, COALESCE(sum(order_items.quantity * recipe_items.quantity), 0) as total").
+		Joins("JOIN orders ON orders.id = order_items.order_id AND orders.deleted_at IS NULL").
+		Joins("JOIN recipe_items ON recipe_items.product_id = order_items.product_id AND recipe_items.deleted_at IS NULL").
+		Where("orders.status = ?", "OPEN").
+		Group("recipe_items.stock_item_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	demand := make(map[uint]float64, len(rows))
+	for _, row := range rows {
+		demand[row.StockItemID] = row.Total
+	}
+	return demand, nil
+}
+
+// WithTransaction runs a function within a database transaction
+func (r *inventoryRepository) WithTransaction(fn func(tx *gorm.DB) error) error {
+	return r.db.Transaction(fn)
+}
@@ -0,0 +1,92 @@
+package gorm_repo
+
+import (
+	"simple-pos/internal/models"
+	"simple-pos/internal/repositories"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type invoiceRepository struct {
+	db *gorm.DB
+}
+
+// NewInvoiceRepository creates a new instance of InvoiceRepository
+// Yeni bir InvoiceRepository örneği oluşturur
+func NewInvoiceRepository(db *gorm.DB) repositories.InvoiceRepository {
+	return &invoiceRepository{db: db}
+}
+
+// NextSequenceWithTx bumps the counter of the year and returns the new value, starting from 1
+// Yılın sayacını artırır ve yeni değeri döndürür, 1'den başlar
+func (r *invoiceRepository) NextSequenceWithTx(tx *gorm.DB, year int) (int, error) {
+	seq := models.InvoiceSequence{Year: year}
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&seq).Error; err != nil {
+		return 0, err
+	}
+	if err := tx.Model(&models.InvoiceSequence{}).Where("year = ?", year).
+		UpdateColumn("last_number", gorm.Expr("last_number + 1")).Error; err != nil {
+		return 0, err
+	}
+	if err := tx.Where("year = ?", year).First(&seq).Error; err != nil {
+		return 0, err
+	}
+	return seq.LastNumber, nil
+}
+
+func (r *invoiceRepository) CreateWithTx(tx *gorm.DB, invoice *models.Invoice) error {
+	return tx.Create(invoice).Error
+}
+
+func (r *invoiceRepository) Update(invoice *models.Invoice) error {
+	return r.db.Save(invoice).Error
+}
+
+func (r *invoiceRepository) FindByID(id uint) (*models.Invoice, error) {
+	var invoice models.Invoice
+	if err := r.db.First(&invoice, id).Error; err != nil {
+		return nil, err
+	}
+	return &invoice, nil
+}
+
+func (r *invoiceRepository) FindByOrderID(orderID uint) (*models.Invoice, error) {
+	var invoice models.Invoice
+	if err := r.db.Where("order_id = ?", orderID).First(&invoice).Error; err != nil {
+		return nil, err
+	}
+	return &invoice, nil
+}
+
+// Find lists invoices matching the filter, newest first
+// Filtreye uyan faturaları yeniden eskiye listeler
+func (r *invoiceRepository) Find(filter models.InvoiceFilter) ([]models.Invoice, error) {
+	query := r.db.Model(&models.Invoice{}).Omit("xml")
+	if filter.Year != 0 {
+		query = query.Where("year = ?", filter.Year)
+	}
+	if filter.OrderID != 0 {
+		query = query.Where("order_id = ?", filter.OrderID)
+	}
+	if filter.TaxID != "" {
+		query = query.Where("customer_tax_id = ?", filter.TaxID)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+
+	var invoices []models.Invoice
+	if err := query.Order("id desc").Find(&invoices).Error; err != nil {
+		return nil, err
+	}
+	return invoices, nil
+}
+
+// WithTransaction runs a function within a database transaction
+func (r *invoiceRepository) WithTransaction(fn func(tx *gorm.DB) error) error {
+	return r.db.Transaction(fn)
+}
@@ -0,0 +1,137 @@
+package gorm_repo
+
+import (
+	"simple-pos/internal/models"
+	"simple-pos/internal/repositories"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type kitchenRepository struct {
+	db *gorm.DB
+}
+
+// NewKitchenRepository creates a new instance of KitchenRepository
+// Yeni bir KitchenRepository örneği oluşturur
+func NewKitchenRepository(db *gorm.DB) repositories.KitchenRepository {
+	return &kitchenRepository{db: db}
+}
+
+// CreateStation creates a new kitchen station
+// Yeni bir mutfak istasyonu oluşturur
+func (r *kitchenRepository) CreateStation(station *models.KitchenStation) error {
+	return r.db.Create(station).Error
+}
+
+// FindAllStations lists kitchen stations
+// Mutfak istasyonlarını listeler
+func (r *kitchenRepository) FindAllStations() ([]models.KitchenStation, error) {
+	var stations []models.KitchenStation
+	if err := r.db.Order("sort_order asc").Find(&stations).Error; err != nil {
+		return nil, err
+	}
+	return stations, nil
+}
+
+// FindStationByID finds a kitchen station
+// ID ile mutfak istasyonunu bulur
+func (r *kitchenRepository) FindStationByID(id uint) (*models.KitchenStation, error) {
+	var station models.KitchenStation
+	if err := r.db.First(&station, id).Error; err != nil {
+		return nil, err
+	}
+	return &station, nil
+}
+
+// UpdateStation updates a kitchen station
+// Mutfak istasyonunu günceller
+func (r *kitchenRepository) UpdateStation(station *models.KitchenStation) error {
+	return r.db.Save(station).Error
+}
+
+// DeleteStation deletes a kitchen station and unroutes its categories
+// Mutfak istasyonunu siler ve kategorilerin yönlendirmesini kaldırır
+func (r *kitchenRepository) DeleteStation(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Category{}).Where("station_id = ?", id).Update("station_id", nil).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.KitchenStation{}, id).Error
+	})
+}
+
+// CreateTicket creates a kitchen ticket
+// Mutfak ticket'ı oluşturur
+func (r *kitchenRepository) CreateTicket(ticket *models.KitchenTicket) error {
+	return r.db.Create(ticket).Error
+}
+
+// FindTicketByID finds a kitchen ticket
+// ID ile mutfak ticket'ını bulur
+func (r *kitchenRepository) FindTicketByID(id uint) (*models.KitchenTicket, error) {
+	var ticket models.KitchenTicket
+	if err := r.db.First(&ticket, id).Error; err != nil {
+		return nil, err
+	}
+	return &ticket, nil
+}
+
+// FindTickets lists tickets filtered by station and statuses, oldest first
+// Ticketları istasyon ve durumlara göre filtreler, eskiden yeniye sıralar
+func (r *kitchenRepository) FindTickets(stationID *uint, statuses []string) ([]models.KitchenTicket, error) {
+	var tickets []models.KitchenTicket
+	query := r.db.Model(&models.KitchenTicket{})
+	if stationID != nil {
+		query = query.Where("station_id = ?", *stationID)
+	}
+	if len(statuses) > 0 {
+		query = query.Where("status IN ?", statuses)
+	}
+	if err := query.Order("created_at asc").Find(&tickets).Error; err != nil {
+		return nil, err
+	}
+	return tickets, nil
+}
+
+// FindTicketsByItem lists tickets of an order item
+// Bir sipariş kaleminin ticketlarını listeler
+func (r *kitchenRepository) FindTicketsByItem(itemID uint) ([]models.KitchenTicket, error) {
+	var tickets []models.KitchenTicket
+	if err := r.db.Where("order_item_id = ?", itemID).Order("created_at asc").Find(&tickets).Error; err != nil {
+		return nil, err
+	}
+	return tickets, nil
+}
+
+// FindTicketsByOrder lists tickets of an order
+// Bir siparişin ticketlarını listeler
+func (r *kitchenRepository) FindTicketsByOrder(orderID uint) ([]models.KitchenTicket, error) {
+	var tickets []models.KitchenTicket
+	if err := r.db.Where("order_id = ?", orderID).Order("created_at asc").Find(&tickets).Error; err != nil {
+		return nil, err
+	}
+	return tickets, nil
+}
+
+// FindTicketsBetween lists tickets created within the range
+// Belirtilen aralıkta oluşturulan ticketları listeler
+func (r *kitchenRepository) FindTicketsBetween(start, end time.Time) ([]models.KitchenTicket, error) {
+	var tickets []models.KitchenTicket
+	if err := r.db.Where("created_at >= ? AND created_at < ?", start, end).Find(&tickets).Error; err != nil {
+		return nil, err
+	}
+	return tickets, nil
+}
+
+// UpdateTicket updates a kitchen ticket
+// Mutfak ticket'ını günceller
+func (r *kitchenRepository) UpdateTicket(ticket *models.KitchenTicket) error {
+	return r.db.Save(ticket).Error
+}
+
+// UpdateItemStatus stores the aggregated kitchen status on the order item (hooks skipped)
+// Toplu mutfak durumunu sipariş kalemine yazar (hook'lar atlanır)
+func (r *kitchenRepository) UpdateItemStatus(itemID uint, status string) error {
+	return r.db.Model(&models.OrderItem{}).Where("id = ?", itemID).UpdateColumn("kitchen_status", status).Error
+}
@@ -0,0 +1,169 @@
+package gorm_repo
+
+import (
+	"simple-pos/internal/models"
+	"simple-pos/internal/repositories"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type loyaltyRepository struct {
+	db *gorm.DB
+}
+
+// NewLoyaltyRepository creates a new instance of LoyaltyRepository
+// Yeni bir LoyaltyRepository örneği oluşturur
+func NewLoyaltyRepository(db *gorm.DB) repositories.LoyaltyRepository {
+	return &loyaltyRepository{db: db}
+}
+
+func (r *loyaltyRepository) CreateRule(rule *models.LoyaltyRule) error {
+	return r.db.Omit("Category").Create(rule).Error
+}
+
+func (r *loyaltyRepository) UpdateRule(rule *models.LoyaltyRule) error {
+	return r.db.Omit("Category").Save(rule).Error
+}
+
+// DeleteRule removes the rule for good so the category can get a new one
+// Kuralı kalıcı olarak siler, böylece kategoriye yeni kural tanımlanabilir
+func (r *loyaltyRepository) DeleteRule(id uint) error {
+	return r.db.Unscoped().Delete(&models.LoyaltyRule{}, id).Error
+}
+
+func (r *loyaltyRepository) FindRuleByID(id uint) (*models.LoyaltyRule, error) {
+	var rule models.LoyaltyRule
+	if err := r.db.Preload("Category").First(&rule, id).Error; err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+func (r *loyaltyRepository) FindRules() ([]models.LoyaltyRule, error) {
+	var rules []models.LoyaltyRule
+	if err := r.db.Preload("Category").Order("category_id asc").Find(&rules).Error; err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+func (r *loyaltyRepository) FindActiveRulesWithTx(tx *gorm.DB) (map[uint]models.LoyaltyRule, error) {
+	var rules []models.LoyaltyRule
+	if err := tx.Where("is_active = ?", true).Find(&rules).Error; err != nil {
+		return nil, err
+	}
+	byCategory := make(map[uint]models.LoyaltyRule, len(rules))
+	for _, rule := range rules {
+		byCategory[rule.CategoryID] = rule
+	}
+	return byCategory, nil
+}
+
+func (r *loyaltyRepository) ProductCategoriesWithTx(tx *gorm.DB, productIDs []uint) (map[uint]uint, error) {
+	categories := make(map[uint]uint, len(productIDs))
+	if len(productIDs) == 0 {
+		return categories, nil
+	}
+
+	var rows []struct {
+		ID         uint
+		CategoryID uint
+	}
+	if err := tx.Unscoped().Model(&models.Product{}).Select("id, category_id").Where("id IN ?", productIDs).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		categories[row.ID] = row.CategoryID
+	}
+	return categories, nil
+}
+
+func (r *loyaltyRepository) FindStampCards(customerID uint) ([]models.LoyaltyStampCard, error) {
+	var cards []models.LoyaltyStampCard
+	if err := r.db.Where("customer_id = ?", customerID).Order("category_id asc").Find(&cards).Error; err != nil {
+		return nil, err
+	}
+	return cards, nil
+}
+
+func (r *loyaltyRepository) FindStampCardWithTx(tx *gorm.DB, customerID, categoryID uint) (*models.LoyaltyStampCard, error) {
+	card := models.LoyaltyStampCard{CustomerID: customerID, CategoryID: categoryID}
+	if err := tx.Where("customer_id = ? AND category_id = ?", customerID, categoryID).FirstOrInit(&card).Error; err != nil {
+		return nil, err
+	}
+	return &card, nil
+}
+
+// AddStampsWithTx creates the card on first use, then moves its stamps by delta
+// Kartı ilk kullanımda oluşturur, ardından damgalarını delta kadar değiştirir
+func (r *loyaltyRepository) AddStampsWithTx(tx *gorm.DB, customerID, categoryID uint, delta int) error {
+	card := models.LoyaltyStampCard{CustomerID: customerID, CategoryID: categoryID}
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&card).Error; err != nil {
+		return err
+	}
+	return tx.Model(&models.LoyaltyStampCard{}).
+		Where("customer_id = ? AND category_id = ?", customerID, categoryID).
+		Updates(map[string]interface{}{"stamps": gorm.Expr("stamps + ?", delta)}).Error
+}
+
+func (r *loyaltyRepository) AddPointsWithTx(tx *gorm.DB, customerID uint, delta int64) error {
+	return tx.Model(&models.Customer{}).Where("id = ?", customerID).
+		UpdateColumn("loyalty_points", gorm.Expr("loyalty_points + ?", delta)).Error
+}
+
+func (r *loyaltyRepository) CreateEntryWithTx(tx *gorm.DB, entry *models.LoyaltyEntry) error {
+	return tx.Create(entry).Error
+}
+
+// FindOrderEntriesWithTx lists everything an order earned and spent, oldest first
+// Siparişin kazandığı ve harcadığı her şeyi eskiden yeniye listeler
+func (r *loyaltyRepository) FindOrderEntriesWithTx(tx *gorm.DB, orderID uint) ([]models.LoyaltyEntry, error) {
+	var entries []models.LoyaltyEntry
+	if err := tx.Where("order_id = ?", orderID).Order("id asc").Find(&entries).Error; err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (r *loyaltyRepository) FindEntries(customerID uint, limit int) ([]models.LoyaltyEntry, error) {
+	query := r.db.Where("customer_id = ?", customerID).Order("id desc")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	var entries []models.LoyaltyEntry
+	if err := query.Find(&entries).Error; err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (r *loyaltyRepository) SaveRedemptionWithTx(tx *gorm.DB, redemption *models.LoyaltyRedemption) error {
+	if err := tx.Where("order_id = ?", redemption.OrderID).Delete(&models.LoyaltyRedemption{}).Error; err != nil {
+		return err
+	}
+	return tx.Create(redemption).Error
+}
+
+// FindRedemptionWithTx returns the pending redemption of an order, nil when there is none
+// Siparişin bekleyen kullanımını döndürür, yoksa nil
+func (r *loyaltyRepository) FindRedemptionWithTx(tx *gorm.DB, orderID uint) (*models.LoyaltyRedemption, error) {
+	var redemptions []models.LoyaltyRedemption
+	if err := tx.Where("order_id = ?", orderID).Limit(1).Find(&redemptions).Error; err != nil {
+		return nil, err
+	}
+	if len(redemptions) == 0 {
+		return nil, nil
+	}
+	return &redemptions[0], nil
+}
+
+func (r *loyaltyRepository) DeleteRedemptionWithTx(tx *gorm.DB, orderID uint) error {
+	return tx.Where("order_id = ?", orderID).Delete(&models.LoyaltyRedemption{}).Error
+}
+
+// WithTransaction runs a function within a database transaction
+func (r *loyaltyRepository) WithTransaction(fn func(tx *gorm.DB) error) error {
+	return r.db.Transaction(fn)
+}
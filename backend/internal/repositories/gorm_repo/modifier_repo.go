@@ -0,0 +1,108 @@
+package gorm_repo
+
+import (
+	"simple-pos/internal/models"
+	"simple-pos/internal/repositories"
+
+	"gorm.io/gorm"
+)
+
+type modifierRepository struct {
+	db *gorm.DB
+}
+
+// NewModifierRepository creates a new instance of ModifierRepository
+// Yeni bir ModifierRepository örneği oluşturur
+func NewModifierRepository(db *gorm.DB) repositories.ModifierRepository {
+	return &modifierRepository{db: db}
+}
+
+// CreateGroup creates a new modifier group
+// Yeni bir opsiyon grubu oluşturur
+func (r *modifierRepository) CreateGroup(group *models.ModifierGroup) error {
+	return r.db.Create(group).Error
+}
+
+// FindAllGroups lists every modifier group with its options
+// Tüm opsiyon gruplarını seçenekleriyle listeler
+func (r *modifierRepository) FindAllGroups() ([]models.ModifierGroup, error) {
+	var groups []models.ModifierGroup
+	if err := r.db.Preload("Options", func(db *gorm.DB) *gorm.DB {
+		return db.Order("sort_order asc")
+	}).Order("sort_order asc").Find(&groups).Error; err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
+
+// FindGroupByID finds a modifier group with its options
+// ID ile opsiyon grubunu seçenekleriyle bulur
+func (r *modifierRepository) FindGroupByID(id uint) (*models.ModifierGroup, error) {
+	var group models.ModifierGroup
+	if err := r.db.Preload("Options", func(db *gorm.DB) *gorm.DB {
+		return db.Order("sort_order asc")
+	}).First(&group, id).Error; err != nil {
+		return nil, err
+	}
+	return &group, nil
+}
+
+// FindGroupsForProduct returns groups attached to the product or to its category
+// Ürüne veya kategorisine bağlı grupları döndürür
+func (r *modifierRepository) FindGroupsForProduct(productID, categoryID uint) ([]models.ModifierGroup, error) {
+	var groups []models.ModifierGroup
+	if err := r.db.Preload("Options", func(db *gorm.DB) *gorm.DB {
+		return db.Order("sort_order asc")
+	}).
+		Where("product_id = ? OR category_id = ?", productID, categoryID).
+		Order("sort_order asc").
+		Find(&groups).Error; err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
+
+// UpdateGroup updates a modifier group
+// Opsiyon grubunu günceller
+func (r *modifierRepository) UpdateGroup(group *models.ModifierGroup) error {
+	return r.db.Omit("Options").Save(group).Error
+}
+
+// DeleteGroup deletes a modifier group together with its options
+// Opsiyon grubunu seçenekleriyle birlikte siler
+func (r *modifierRepository) DeleteGroup(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("group_id = ?", id).Delete(&models.ModifierOption{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.ModifierGroup{}, id).Error
+	})
+}
+
+// CreateOption creates a new modifier option
+// Yeni bir opsiyon seçeneği oluşturur
+func (r *modifierRepository) CreateOption(option *models.ModifierOption) error {
+	return r.db.Create(option).Error
+}
+
+// FindOptionByID finds a modifier option
+// ID ile opsiyon seçeneğini bulur
+func (r *modifierRepository) FindOptionByID(id uint) (*models.ModifierOption, error) {
+	var option models.ModifierOption
+	if err := r.db.First(&option, id).Error; err != nil {
+		return nil, err
+	}
+	return &option, nil
+}
+
+// UpdateOption updates a modifier option
+// Opsiyon seçeneğini günceller
+func (r *modifierRepository) UpdateOption(option *models.ModifierOption) error {
+	return r.db.Save(option).Error
+}
+
+// DeleteOption deletes a modifier option
+// Opsiyon seçeneğini siler
+func (r *modifierRepository) DeleteOption(id uint) error {
+	return r.db.Delete(&models.ModifierOption{}, id).Error
+}
@@ -18,7 +18,7 @@ func NewOrderRepository(db *gorm.DB) repositories.OrderRepository {
 
 func (r *orderRepository) GetOrderWithDetails(orderID uint) (*models.Order, error) {
 	var order models.Order
-	err := r.db.Preload("Items").Preload("Items.Product").First(&order, orderID).Error
+	err := r.db.Preload("Waiter").Preload("Items").Preload("Items.Modifiers").Preload("Items.Tickets").Preload("Payments").Preload("Promotions").First(&order, orderID).Error
 	if err != nil {
 		return nil, err
 	}
@@ -28,7 +28,7 @@ func (r *orderRepository) GetOrderWithDetails(orderID uint) (*models.Order, erro
 func (r *orderRepository) HasActiveOrders() (bool, error) {
 	var count int64
 	err := r.db.Model(&models.Order{}).
-		Where("status NOT IN ('COMPLETED', 'CANCELLED')").
+		Where("status NOT IN ('COMPLETED', 'REFUNDED', 'CANCELLED')").
 		Count(&count).Error
 	if err != nil {
 		return false, err
@@ -53,7 +53,7 @@ func (r *orderRepository) FindAll(startDate, endDate time.Time) ([]models.Order,
 
 func (r *orderRepository) FindByID(id uint) (*models.Order, error) {
 	var order models.Order
-	if err := r.db.Preload("Items").Preload("Waiter").First(&order, id).Error; err != nil {
+	if err := r.db.Preload("Items").Preload("Items.Modifiers").Preload("Items.Tickets").Preload("Waiter").Preload("Payments").Preload("Promotions").First(&order, id).Error; err != nil {
 		return nil, err
 	}
 	return &order, nil
@@ -61,7 +61,7 @@ func (r *orderRepository) FindByID(id uint) (*models.Order, error) {
 
 func (r *orderRepository) FindByTableID(tableID uint, status string) ([]models.Order, error) {
 	var orders []models.Order
-	query := r.db.Preload("Items").Preload("Waiter").Where("table_id = ?", tableID)
+	query := r.db.Preload("Items").Preload("Items.Modifiers").Preload("Waiter").Where("table_id = ?", tableID)
 	if status != "" {
 		query = query.Where("status = ?", status)
 	}
@@ -96,10 +96,16 @@ func (r *orderRepository) FindByWorkPeriodIDs(periodIDs []uint) ([]models.Order,
 	return orders, nil
 }
 
-// Update an order
-// Siparişi günceller
+// Update an order, its promotion lines are only written by the totals recalculation
+// Siparişi günceller, promosyon satırları yalnızca toplam hesaplamasıyla yazılır
 func (r *orderRepository) Update(order *models.Order) error {
-	return r.db.Save(order).Error
+	return r.db.Omit("Promotions").Save(order).Error
+}
+
+// UpdateWithTx updates an order within an existing DB transaction
+// Mevcut bir veritabanı işlemi içinde siparişi günceller
+func (r *orderRepository) UpdateWithTx(tx *gorm.DB, order *models.Order) error {
+	return tx.Omit("Promotions").Save(order).Error
 }
 
 // Delete an order
@@ -108,6 +114,12 @@ func (r *orderRepository) Delete(id uint) error {
 	return r.db.Delete(&models.Order{}, id).Error
 }
 
+// DeleteWithTx deletes an order within an existing DB transaction
+// Mevcut bir veritabanı işlemi içinde siparişi siler
+func (r *orderRepository) DeleteWithTx(tx *gorm.DB, id uint) error {
+	return tx.Delete(&models.Order{}, id).Error
+}
+
 // AddItem adds an item to order
 // Siparişe ürün ekler
 func (r *orderRepository) AddItem(item *models.OrderItem) error {
@@ -129,6 +141,12 @@ func (r *orderRepository) DeleteItem(item *models.OrderItem) error {
 	return r.db.Delete(item).Error
 }
 
+// DeleteItemWithTx removes an order item within an existing DB transaction
+// Mevcut bir veritabanı işlemi içinde sipariş kalemini siler
+func (r *orderRepository) DeleteItemWithTx(tx *gorm.DB, item *models.OrderItem) error {
+	return tx.Delete(item).Error
+}
+
 // FindItem finds an order item
 // Sipariş kalemini bulur
 func (r *orderRepository) FindItem(itemID uint) (*models.OrderItem, error) {
@@ -143,3 +161,31 @@ func (r *orderRepository) FindItem(itemID uint) (*models.OrderItem, error) {
 func (r *orderRepository) WithTransaction(fn func(tx *gorm.DB) error) error {
 	return r.db.Transaction(fn)
 }
+
+// RecalculateTotals recomputes order totals and per item KDV snapshots
+// Sipariş toplamlarını ve kalem bazlı KDV snapshot'larını yeniden hesaplar
+func (r *orderRepository) RecalculateTotals(orderID uint) error {
+	return models.RecalculateOrderTotals(r.db, orderID)
+}
+
+// SumTaxByRate aggregates KDV of completed orders per rate for the given work periods
+// Verilen çalışma dönemlerindeki tamamlanan siparişlerin KDV'sini orana göre toplar
+func (r *orderRepository) SumTaxByRate(periodIDs []uint) ([]models.TaxRateTotal, error) {
+	totals := []models.TaxRateTotal{}
+	if len(periodIDs) == 0 {
+		return totals, nil
+	}
+
+	err := r.db.Model(&models.OrderItem{}).
+		Select("order_items.tax_rate as tax_rate, COALESCE(sum(order_items.net_amount), 0) as net_amount, "+
+			"COALESCE(sum(order_items.tax_amount), 0) as tax_amount, COALESCE(sum(order_items.net_amount + order_items.tax_amount), 0) as gross_amount").
+		Joins("JOIN orders ON orders.id = order_items.order_id AND orders.deleted_at IS NULL").
+		Where("orders.status IN ? AND orders.work_period_id IN ?", []string{"COMPLETED", "REFUNDED"}, periodIDs).
+		Group("order_items.tax_rate").
+		Order("order_items.tax_rate asc").
+		Scan(&totals).Error
+	if err != nil {
+		return nil, err
+	}
+	return totals, nil
+}
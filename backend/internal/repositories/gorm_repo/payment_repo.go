@@ -0,0 +1,75 @@
+package gorm_repo
+
+import (
+	"simple-pos/internal/models"
+	"simple-pos/internal/repositories"
+
+	"gorm.io/gorm"
+)
+
+type paymentRepository struct {
+	db *gorm.DB
+}
+
+// NewPaymentRepository creates a new instance of PaymentRepository
+// Yeni bir PaymentRepository örneği oluşturur
+func NewPaymentRepository(db *gorm.DB) repositories.PaymentRepository {
+	return &paymentRepository{db: db}
+}
+
+// CreateWithTx creates a payment within an existing DB transaction
+// Mevcut bir veritabanı işlemi içinde bir ödeme oluşturur
+func (r *paymentRepository) CreateWithTx(tx *gorm.DB, payment *models.Payment) error {
+	return tx.Create(payment).Error
+}
+
+// Update a payment
+// Ödemeyi günceller
+func (r *paymentRepository) Update(payment *models.Payment) error {
+	return r.db.Save(payment).Error
+}
+
+// UpdateWithTx updates a payment within an existing DB transaction
+// Mevcut bir veritabanı işlemi içinde ödemeyi günceller
+func (r *paymentRepository) UpdateWithTx(tx *gorm.DB, payment *models.Payment) error {
+	return tx.Save(payment).Error
+}
+
+// FindByOrderID lists payments of an order, oldest first
+// Bir siparişin ödemelerini eskiden yeniye listeler
+func (r *paymentRepository) FindByOrderID(orderID uint) ([]models.Payment, error) {
+	var payments []models.Payment
+	if err := r.db.Where("order_id = ?", orderID).Order("created_at asc").Find(&payments).Error; err != nil {
+		return nil, err
+	}
+	return payments, nil
+}
+
+// SumByMethod returns payment totals grouped by method for the given work periods
+// Verilen çalışma dönemleri için ödeme yöntemine göre toplamları döndürür
+func (r *paymentRepository) SumByMethod(periodIDs []uint) (map[string]int64, error) {
+	totals := make(map[string]int64)
+	if len(periodIDs) == 0 {
+		return totals, nil
+	}
+
+	type methodTotal struct {
+		Method string
+		Total  int64
+	}
+	var rows []methodTotal
+
+	if err := r.db.Model(&models.Payment{}).
+		Select("payments.method as method, COALESCE(sum(payments.amount), 0) as total").
+		Joins("JOIN orders ON orders.id = payments.order_id AND orders.deleted_at IS NULL").
+		Where("payments.work_period_id IN ? AND orders.status <> ?", periodIDs, "CANCELLED").
+		Group("payments.method").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		totals[row.Method] = row.Total
+	}
+	return totals, nil
+}
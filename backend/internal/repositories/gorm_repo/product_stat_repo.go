@@ -0,0 +1,129 @@
+package gorm_repo
+
+import (
+	"fmt"
+	"simple-pos/internal/models"
+	"simple-pos/internal/repositories"
+
+	"gorm.io/gorm"
+)
+
+type productStatRepository struct {
+	db *gorm.DB
+}
+
+// NewProductStatRepository creates a new instance of ProductStatRepository
+// Yeni bir ProductStatRepository örneği oluşturur
+func NewProductStatRepository(db *gorm.DB) repositories.ProductStatRepository {
+	return &productStatRepository{db: db}
+}
+
+// RebuildForPeriodWithTx replaces the stats of the period with fresh totals from its sold order items
+// Dönemin istatistiklerini satılan sipariş kalemlerinden hesaplanan güncel toplamlarla değiştirir
+func (r *productStatRepository) RebuildForPeriodWithTx(tx *gorm.DB, period *models.WorkPeriod) (int, error) {
+	var stats []models.ProductSalesStat
+	err := tx.Model(&models.OrderItem{}).
+		Select("order_items.product_id as product_id, MAX(order_items.product_name) as product_name, "+
+			"COALESCE(products.category_id, 0) as category_id, COALESCE(MAX(categories.name), '') as category_name, "+
+			"COALESCE(sum(order_items.quantity), 0) as quantity_sold, "+
+			// Items from before the tax breakdown have no net/tax amounts, fall back to the discounted subtotal
+			"COALESCE(sum(CASE WHEN order_items.net_amount + order_items.tax_amount > 0 THEN order_items.net_amount + order_items.tax_amount "+
+			"ELSE order_items.subtotal - order_items.promotion_amount - order_items.discount_amount END), 0) as total_revenue, "+
+			"COALESCE(sum(order_items.refunded_quantity), 0) as refunded_quantity, "+
+			"COALESCE(sum((SELECT sum(refund_items.amount) FROM refund_items WHERE refund_items.order_item_id = order_items.id AND refund_items.deleted_at IS NULL)), 0) as refunded_amount").
+		Joins("JOIN orders ON orders.id = order_items.order_id AND orders.deleted_at IS NULL").
+		Joins("LEFT JOIN products ON products.id = order_items.product_id").
+		Joins("LEFT JOIN categories ON categories.id = products.category_id").
+		Where("orders.status IN ? AND orders.work_period_id = ?", []string{"COMPLETED", "REFUNDED"}, period.ID).
+		Group("order_items.product_id, products.category_id").
+		Scan(&stats).Error
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Where("work_period_id = ?", period.ID).Delete(&models.ProductSalesStat{}).Error; err != nil {
+		return 0, err
+	}
+	if len(stats) == 0 {
+		return 0, nil
+	}
+
+	reportDate := period.StartTime.Format("2006-01-02")
+	for i := range stats {
+		stats[i].WorkPeriodID = period.ID
+		stats[i].ReportDate = reportDate
+	}
+	if err := tx.CreateInBatches(stats, 100).Error; err != nil {
+		return 0, err
+	}
+	return len(stats), nil
+}
+
+// RankProducts lists the products on the menu by their sales in the date range, unsold products included
+// Menüdeki ürünleri tarih aralığındaki satışlarına göre sıralar, hiç satılmayanlar dahil
+func (r *productStatRepository) RankProducts(startDate, endDate, rank, metric string, limit int) ([]models.ProductSalesSummary, error) {
+	column := "net_quantity"
+	if metric == models.ProductMetricRevenue {
+		column = "net_revenue"
+	}
+	direction := "desc"
+	if rank == models.ProductRankBottom {
+		direction = "asc"
+	}
+
+	totals := r.db.Model(&models.ProductSalesStat{}).
+		Select("product_id, sum(quantity_sold) as quantity_sold, sum(total_revenue) as total_revenue, "+
+			"sum(refunded_quantity) as refunded_quantity, sum(refunded_amount) as refunded_amount").
+		Where("report_date >= ? AND report_date < ?", startDate, endDate).
+		Group("product_id")
+
+	var summaries []models.ProductSalesSummary
+	err := r.db.Model(&models.Product{}).
+		Select("products.id as product_id, products.name as product_name, products.category_id as category_id, "+
+			"COALESCE(categories.name, '') as category_name, "+
+			"COALESCE(s.quantity_sold, 0) as quantity_sold, COALESCE(s.total_revenue, 0) as total_revenue, "+
+			"COALESCE(s.refunded_quantity, 0) as refunded_quantity, COALESCE(s.refunded_amount, 0) as refunded_amount, "+
+			"COALESCE(s.quantity_sold - s.refunded_quantity, 0) as net_quantity, COALESCE(s.total_revenue - s.refunded_amount, 0) as net_revenue").
+		Joins("LEFT JOIN categories ON categories.id = products.category_id").
+		Joins("LEFT JOIN (?) AS s ON s.product_id = products.id", totals).
+		Order(fmt.Sprintf("%s %s, products.name asc", column, direction)).
+		Limit(limit).
+		Scan(&summaries).Error
+	if err != nil {
+		return nil, err
+	}
+	return summaries, nil
+}
+
+// CategoryMix sums the sales in the date range per category, best selling first
+// Tarih aralığındaki satışları kategori bazında toplar, en çok satan önce
+func (r *productStatRepository) CategoryMix(startDate, endDate string) ([]models.CategorySalesMix, error) {
+	var mix []models.CategorySalesMix
+	err := r.db.Model(&models.ProductSalesStat{}).
+		Select("category_id, MAX(category_name) as category_name, "+
+			"sum(quantity_sold - refunded_quantity) as quantity_sold, sum(total_revenue - refunded_amount) as net_revenue").
+		Where("report_date >= ? AND report_date < ?", startDate, endDate).
+		Group("category_id").
+		Order("net_revenue desc").
+		Scan(&mix).Error
+	if err != nil {
+		return nil, err
+	}
+	return mix, nil
+}
+
+// ProductTrend sums the sales of a product per day in the date range, days without sales are left out
+// Bir ürünün satışlarını tarih aralığında gün bazında toplar, satış olmayan günler yer almaz
+func (r *productStatRepository) ProductTrend(productID uint, startDate, endDate string) ([]models.ProductTrendPoint, error) {
+	var points []models.ProductTrendPoint
+	err := r.db.Model(&models.ProductSalesStat{}).
+		Select("report_date, sum(quantity_sold - refunded_quantity) as quantity_sold, sum(total_revenue - refunded_amount) as net_revenue").
+		Where("product_id = ? AND report_date >= ? AND report_date < ?", productID, startDate, endDate).
+		Group("report_date").
+		Order("report_date asc").
+		Scan(&points).Error
+	if err != nil {
+		return nil, err
+	}
+	return points, nil
+}
@@ -0,0 +1,75 @@
+package gorm_repo
+
+import (
+	"simple-pos/internal/models"
+	"simple-pos/internal/repositories"
+
+	"gorm.io/gorm"
+)
+
+type promotionRepository struct {
+	db *gorm.DB
+}
+
+// NewPromotionRepository creates a new instance of PromotionRepository
+// Yeni bir PromotionRepository örneği oluşturur
+func NewPromotionRepository(db *gorm.DB) repositories.PromotionRepository {
+	return &promotionRepository{db: db}
+}
+
+func (r *promotionRepository) Create(promotion *models.Promotion) error {
+	return r.db.Create(promotion).Error
+}
+
+// Update saves the promotion and replaces its combo items
+// Promosyonu kaydeder ve menü kalemlerini değiştirir
+func (r *promotionRepository) Update(promotion *models.Promotion) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("ComboItems").Save(promotion).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("promotion_id = ?", promotion.ID).Delete(&models.PromotionComboItem{}).Error; err != nil {
+			return err
+		}
+		for i := range promotion.ComboItems {
+			promotion.ComboItems[i].ID = 0
+			promotion.ComboItems[i].PromotionID = promotion.ID
+		}
+		if len(promotion.ComboItems) > 0 {
+			return tx.Create(&promotion.ComboItems).Error
+		}
+		return nil
+	})
+}
+
+// Delete removes a promotion together with its combo items, orders keep their applied lines
+// Promosyonu menü kalemleriyle birlikte siler, siparişler uygulanmış satırlarını korur
+func (r *promotionRepository) Delete(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("promotion_id = ?", id).Delete(&models.PromotionComboItem{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Promotion{}, id).Error
+	})
+}
+
+func (r *promotionRepository) FindByID(id uint) (*models.Promotion, error) {
+	var promotion models.Promotion
+	if err := r.db.Preload("ComboItems").First(&promotion, id).Error; err != nil {
+		return nil, err
+	}
+	return &promotion, nil
+}
+
+func (r *promotionRepository) FindAll(includeInactive bool) ([]models.Promotion, error) {
+	query := r.db.Preload("ComboItems")
+	if !includeInactive {
+		query = query.Where("is_active = ?", true)
+	}
+
+	var promotions []models.Promotion
+	if err := query.Order("id asc").Find(&promotions).Error; err != nil {
+		return nil, err
+	}
+	return promotions, nil
+}
@@ -0,0 +1,35 @@
+package gorm_repo
+
+import (
+	"simple-pos/internal/models"
+	"simple-pos/internal/repositories"
+
+	"gorm.io/gorm"
+)
+
+type refundRepository struct {
+	db *gorm.DB
+}
+
+func NewRefundRepository(db *gorm.DB) repositories.RefundRepository {
+	return &refundRepository{db: db}
+}
+
+// CreateWithTx stores the refund and its items within an existing DB transaction
+// İadeyi ve kalemlerini mevcut bir veritabanı işlemi içinde kaydeder
+func (r *refundRepository) CreateWithTx(tx *gorm.DB, refund *models.Refund) error {
+	return tx.Create(refund).Error
+}
+
+// FindByOrderID returns the refunds of an order with their items, oldest first
+// Siparişin iadelerini kalemleriyle birlikte eskiden yeniye döndürür
+func (r *refundRepository) FindByOrderID(orderID uint) ([]models.Refund, error) {
+	var refunds []models.Refund
+	err := r.db.Preload("Items").Where("order_id = ?", orderID).Order("created_at asc").Find(&refunds).Error
+	return refunds, err
+}
+
+// WithTransaction runs a function within a database transaction
+func (r *refundRepository) WithTransaction(fn func(tx *gorm.DB) error) error {
+	return r.db.Transaction(fn)
+}
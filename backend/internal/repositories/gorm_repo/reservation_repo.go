@@ -0,0 +1,99 @@
+package gorm_repo
+
+import (
+	"simple-pos/internal/models"
+	"simple-pos/internal/repositories"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type reservationRepository struct {
+	db *gorm.DB
+}
+
+// NewReservationRepository creates a new instance of ReservationRepository
+// Yeni bir ReservationRepository örneği oluşturur
+func NewReservationRepository(db *gorm.DB) repositories.ReservationRepository {
+	return &reservationRepository{db: db}
+}
+
+// Save creates or updates the reservation and replaces its tables
+// Rezervasyonu oluşturur veya günceller ve masalarını değiştirir
+func (r *reservationRepository) Save(reservation *models.Reservation) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		tables := reservation.Tables
+		if err := tx.Omit("Tables").Save(reservation).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(reservation).Association("Tables").Replace(tables); err != nil {
+			return err
+		}
+		reservation.Tables = tables
+		return nil
+	})
+}
+
+// FindByID finds a reservation with its tables
+// ID ile rezervasyonu masalarıyla birlikte bulur
+func (r *reservationRepository) FindByID(id uint) (*models.Reservation, error) {
+	var reservation models.Reservation
+	if err := r.db.Preload("Tables").First(&reservation, id).Error; err != nil {
+		return nil, err
+	}
+	return &reservation, nil
+}
+
+// Find lists reservations matching the filter, earliest slot first
+// Filtreye uyan rezervasyonları en erken saatten başlayarak listeler
+func (r *reservationRepository) Find(filter models.ReservationFilter) ([]models.Reservation, error) {
+	query := r.db.Preload("Tables")
+	if !filter.Start.IsZero() {
+		query = query.Where("end_time > ?", filter.Start)
+	}
+	if !filter.End.IsZero() {
+		query = query.Where("start_time < ?", filter.End)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.TableID != nil {
+		query = query.Where("id IN (?)", r.db.Table("reservation_tables").Select("reservation_id").Where("table_id = ?", *filter.TableID))
+	}
+
+	var reservations []models.Reservation
+	if err := query.Order("start_time asc").Find(&reservations).Error; err != nil {
+		return nil, err
+	}
+	return reservations, nil
+}
+
+// FindConflicts returns booked reservations of the tables overlapping the slot, excluding one reservation
+// Masaların aralıkla çakışan aktif rezervasyonlarını döndürür, bir rezervasyon hariç tutulur
+func (r *reservationRepository) FindConflicts(tableIDs []uint, start, end time.Time, excludeID uint) ([]models.Reservation, error) {
+	var reservations []models.Reservation
+	err := r.db.Preload("Tables").
+		Where("id IN (?)", r.db.Table("reservation_tables").Select("reservation_id").Where("table_id IN ?", tableIDs)).
+		Where("status = ? AND id <> ?", models.ReservationStatusBooked, excludeID).
+		Where("start_time < ? AND end_time > ?", end, start).
+		Order("start_time asc").
+		Find(&reservations).Error
+	if err != nil {
+		return nil, err
+	}
+	return reservations, nil
+}
+
+// FindBookedStartingBefore lists booked reservations whose slot starts before the given time
+// Aralığı verilen zamandan önce başlayan aktif rezervasyonları listeler
+func (r *reservationRepository) FindBookedStartingBefore(t time.Time) ([]models.Reservation, error) {
+	var reservations []models.Reservation
+	err := r.db.Preload("Tables").
+		Where("status = ? AND start_time < ?", models.ReservationStatusBooked, t).
+		Order("start_time asc").
+		Find(&reservations).Error
+	if err != nil {
+		return nil, err
+	}
+	return reservations, nil
+}
@@ -0,0 +1,60 @@
+package gorm_repo
+
+import (
+	"simple-pos/internal/models"
+	"simple-pos/internal/repositories"
+
+	"gorm.io/gorm"
+)
+
+type roleRepository struct {
+	db *gorm.DB
+}
+
+func NewRoleRepository(db *gorm.DB) repositories.RoleRepository {
+	return &roleRepository{db: db}
+}
+
+func (r *roleRepository) Create(role *models.Role) error {
+	return r.db.Create(role).Error
+}
+
+func (r *roleRepository) FindAll() ([]models.Role, error) {
+	var roles []models.Role
+	if err := r.db.Order("id asc").Find(&roles).Error; err != nil {
+		return nil, err
+	}
+	return roles, nil
+}
+
+func (r *roleRepository) FindByID(id uint) (*models.Role, error) {
+	var role models.Role
+	if err := r.db.First(&role, id).Error; err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+func (r *roleRepository) FindByName(name string) (*models.Role, error) {
+	var role models.Role
+	if err := r.db.Where("name = ?", name).First(&role).Error; err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+func (r *roleRepository) Update(role *models.Role) error {
+	return r.db.Save(role).Error
+}
+
+func (r *roleRepository) Delete(id uint) error {
+	return r.db.Delete(&models.Role{}, id).Error
+}
+
+// CountUsers returns how many users are assigned the role
+// Role atanmış kullanıcı sayısını döndürür
+func (r *roleRepository) CountUsers(name string) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.User{}).Where("role = ?", name).Count(&count).Error
+	return count, err
+}
@@ -0,0 +1,79 @@
+package gorm_repo
+
+import (
+	"simple-pos/internal/models"
+	"simple-pos/internal/repositories"
+
+	"gorm.io/gorm"
+)
+
+type sectionRepository struct {
+	db *gorm.DB
+}
+
+// NewSectionRepository creates a new instance of SectionRepository
+// Yeni bir SectionRepository örneği oluşturur
+func NewSectionRepository(db *gorm.DB) repositories.SectionRepository {
+	return &sectionRepository{db: db}
+}
+
+// Create creates a new section
+// Yeni bir bölüm oluşturur
+func (r *sectionRepository) Create(section *models.Section) error {
+	return r.db.Create(section).Error
+}
+
+// FindAll lists sections in floor plan order
+// Bölümleri kat planı sırasıyla listeler
+func (r *sectionRepository) FindAll() ([]models.Section, error) {
+	var sections []models.Section
+	if err := r.db.Order("sort_order asc, name asc").Find(&sections).Error; err != nil {
+		return nil, err
+	}
+	return sections, nil
+}
+
+// FindByID finds a section
+// ID ile bölümü bulur
+func (r *sectionRepository) FindByID(id uint) (*models.Section, error) {
+	var section models.Section
+	if err := r.db.First(&section, id).Error; err != nil {
+		return nil, err
+	}
+	return &section, nil
+}
+
+// FindByName finds a section by its name
+// Adıyla bölümü bulur
+func (r *sectionRepository) FindByName(name string) (*models.Section, error) {
+	var section models.Section
+	if err := r.db.Where("name = ?", name).First(&section).Error; err != nil {
+		return nil, err
+	}
+	return &section, nil
+}
+
+// Update saves the section and renames the section of its tables
+// Bölümü kaydeder ve masalarının bölüm adını günceller
+func (r *sectionRepository) Update(section *models.Section) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(section).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.Table{}).Where("section_id = ?", section.ID).Update("section", section.Name).Error
+	})
+}
+
+// Delete deletes a section
+// Bölümü siler
+func (r *sectionRepository) Delete(id uint) error {
+	return r.db.Delete(&models.Section{}, id).Error
+}
+
+// CountTables returns how many tables are placed in the section
+// Bölüme yerleştirilmiş masa sayısını döndürür
+func (r *sectionRepository) CountTables(id uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Table{}).Where("section_id = ?", id).Count(&count).Error
+	return count, err
+}
@@ -28,8 +28,16 @@ func (r *tableRepository) Create(table *models.Table) error {
 			// Soft-deleted -> Restore and Update
 			// Silinmiş -> Geri yükle ve Güncelle
 			existingTable.DeletedAt = gorm.DeletedAt{} // Restore / Geri yükle
-			existingTable.Section = table.Section      // Update section / Bölümü güncelle
-			existingTable.Status = "available"         // Reset status / Durumu sıfırla
+			existingTable.SectionID = table.SectionID  // Update section / Bölümü güncelle
+			existingTable.Section = table.Section
+			existingTable.Status = "available" // Reset status / Durumu sıfırla
+
+			// Take the layout of the new table / Yeni masanın yerleşimini al
+			existingTable.PosX = table.PosX
+			existingTable.PosY = table.PosY
+			existingTable.Shape = table.Shape
+			existingTable.Seats = table.Seats
+			existingTable.Rotation = table.Rotation
 
 			if saveErr := r.db.Save(&existingTable).Error; saveErr != nil {
 				return saveErr
@@ -94,3 +102,25 @@ func (r *tableRepository) Update(table *models.Table) error {
 func (r *tableRepository) Delete(id uint) error {
 	return r.db.Delete(&models.Table{}, id).Error
 }
+
+// SaveLayout stores the section and layout fields of several tables in one transaction
+// Birden çok masanın bölüm ve yerleşim alanlarını tek işlemde kaydeder
+func (r *tableRepository) SaveLayout(tables []models.Table) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for _, table := range tables {
+			err := tx.Model(&models.Table{}).Where("id = ?", table.ID).Updates(map[string]interface{}{
+				"section_id": table.SectionID,
+				"section":    table.Section,
+				"pos_x":      table.PosX,
+				"pos_y":      table.PosY,
+				"shape":      table.Shape,
+				"seats":      table.Seats,
+				"rotation":   table.Rotation,
+			}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -83,11 +83,24 @@ func (r *transactionRepository) Update(transaction *models.Transaction) error {
 	return r.db.Save(transaction).Error
 }
 
+func (r *transactionRepository) UpdateWithTx(tx *gorm.DB, transaction *models.Transaction) error {
+	return tx.Save(transaction).Error
+}
+
 func (r *transactionRepository) Delete(id uint) error {
 	// Soft delete
 	return r.db.Delete(&models.Transaction{}, id).Error
 }
 
+func (r *transactionRepository) DeleteWithTx(tx *gorm.DB, id uint) error {
+	return tx.Delete(&models.Transaction{}, id).Error
+}
+
+// WithTransaction runs a function within a database transaction
+func (r *transactionRepository) WithTransaction(fn func(tx *gorm.DB) error) error {
+	return r.db.Transaction(fn)
+}
+
 func (r *transactionRepository) FindByID(id uint) (*models.Transaction, error) {
 	var transaction models.Transaction
 	err := r.db.First(&transaction, id).Error
@@ -104,3 +117,59 @@ func (r *transactionRepository) FindByOrderID(orderID uint) (*models.Transaction
 	}
 	return &transaction, nil
 }
+
+// SumRefundsByMethod returns the refunded amounts of the given work periods per payment method.
+// REFUND transactions are stored negative, the totals are returned positive.
+// Verilen çalışma dönemlerinde iade edilen tutarları ödeme yöntemine göre döndürür.
+// REFUND işlemleri negatif saklanır, toplamlar pozitif döner.
+func (r *transactionRepository) SumRefundsByMethod(periodIDs []uint) (map[string]int64, error) {
+	totals := make(map[string]int64)
+	if len(periodIDs) == 0 {
+		return totals, nil
+	}
+
+	type methodTotal struct {
+		Method string
+		Total  int64
+	}
+	var rows []methodTotal
+	if err := r.db.Model(&models.Transaction{}).
+		Select("payment_method as method, COALESCE(-sum(amount), 0) as total").
+		Where("type = ? AND work_period_id IN ?", models.TransactionTypeRefund, periodIDs).
+		Group("payment_method").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		totals[row.Method] = row.Total
+	}
+	return totals, nil
+}
+
+// SumAccountPaymentsByMethod returns the tab payments taken in the given work periods per payment method
+// Verilen çalışma dönemlerinde alınan veresiye ödemelerini ödeme yöntemine göre döndürür
+func (r *transactionRepository) SumAccountPaymentsByMethod(periodIDs []uint) (map[string]int64, error) {
+	totals := make(map[string]int64)
+	if len(periodIDs) == 0 {
+		return totals, nil
+	}
+
+	type methodTotal struct {
+		Method string
+		Total  int64
+	}
+	var rows []methodTotal
+	if err := r.db.Model(&models.Transaction{}).
+		Select("payment_method as method, COALESCE(sum(amount), 0) as total").
+		Where("type = ? AND category = ? AND work_period_id IN ?", "INCOME", models.TransactionCategoryAccountPayment, periodIDs).
+		Group("payment_method").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		totals[row.Method] = row.Total
+	}
+	return totals, nil
+}
@@ -47,6 +47,15 @@ func (r *userRepository) Update(user *models.User) error {
 	return r.db.Save(user).Error
 }
 
+func (r *userRepository) UpdateWithTx(tx *gorm.DB, user *models.User) error {
+	return tx.Save(user).Error
+}
+
+// WithTransaction runs a function within a database transaction
+func (r *userRepository) WithTransaction(fn func(tx *gorm.DB) error) error {
+	return r.db.Transaction(fn)
+}
+
 func (r *userRepository) Delete(id uint) error {
 	return r.db.Delete(&models.User{}, id).Error
 }
@@ -51,6 +51,12 @@ func (r *workPeriodRepository) Update(period *models.WorkPeriod) error {
 	return r.db.Save(period).Error
 }
 
+// UpdateWithTx updates a work period within an existing DB transaction
+// Mevcut bir veritabanı işlemi içinde work period günceller
+func (r *workPeriodRepository) UpdateWithTx(tx *gorm.DB, period *models.WorkPeriod) error {
+	return tx.Save(period).Error
+}
+
 func (r *workPeriodRepository) GetPeriodsByDate(date time.Time) ([]models.WorkPeriod, error) {
 	startOfDay := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
 	endOfDay := startOfDay.Add(24 * time.Hour)
@@ -83,3 +89,45 @@ func (r *workPeriodRepository) FindByID(id uint) (*models.WorkPeriod, error) {
 	}
 	return &period, nil
 }
+
+// CreateCashMovementWithTx records a drawer movement within an existing DB transaction
+// Mevcut bir veritabanı işlemi içinde kasa hareketi kaydeder
+func (r *workPeriodRepository) CreateCashMovementWithTx(tx *gorm.DB, movement *models.CashMovement) error {
+	return tx.Create(movement).Error
+}
+
+// FindCashMovements returns the drawer movements of a work period, oldest first
+// Çalışma döneminin kasa hareketlerini eskiden yeniye döndürür
+func (r *workPeriodRepository) FindCashMovements(periodID uint) ([]models.CashMovement, error) {
+	var movements []models.CashMovement
+	err := r.db.Where("work_period_id = ?", periodID).Order("created_at asc").Find(&movements).Error
+	return movements, err
+}
+
+// SumCashMovements returns the drawer movement totals of a work period grouped by type
+// Çalışma döneminin kasa hareketi toplamlarını türe göre döndürür
+func (r *workPeriodRepository) SumCashMovements(periodID uint) (map[string]int64, error) {
+	type typeTotal struct {
+		Type  string
+		Total int64
+	}
+	var rows []typeTotal
+	if err := r.db.Model(&models.CashMovement{}).
+		Select("type, COALESCE(sum(amount), 0) as total").
+		Where("work_period_id = ?", periodID).
+		Group("type").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	totals := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		totals[row.Type] = row.Total
+	}
+	return totals, nil
+}
+
+// WithTransaction runs a function within a database transaction
+func (r *workPeriodRepository) WithTransaction(fn func(tx *gorm.DB) error) error {
+	return r.db.Transaction(fn)
+}
@@ -28,6 +28,24 @@ type CategoryRepository interface {
 	Delete(id uint) error
 }
 
+// ModifierRepository defines the interface for product modifier data access
+// Ürün opsiyonu veri erişimi için arayüzü tanımlar
+type ModifierRepository interface {
+	CreateGroup(group *models.ModifierGroup) error
+	FindAllGroups() ([]models.ModifierGroup, error)
+	FindGroupByID(id uint) (*models.ModifierGroup, error)
+	// FindGroupsForProduct returns groups attached to the product or to its category
+	// Ürüne veya kategorisine bağlı grupları döndürür
+	FindGroupsForProduct(productID, categoryID uint) ([]models.ModifierGroup, error)
+	UpdateGroup(group *models.ModifierGroup) error
+	DeleteGroup(id uint) error
+
+	CreateOption(option *models.ModifierOption) error
+	FindOptionByID(id uint) (*models.ModifierOption, error)
+	UpdateOption(option *models.ModifierOption) error
+	DeleteOption(id uint) error
+}
+
 // UserRepository defines the interface for user data access
 // Kullanıcı veri erişimi için arayüzü tanımlar
 type UserRepository interface {
@@ -36,7 +54,26 @@ type UserRepository interface {
 	FindByUsername(username string) (*models.User, error)
 	FindAll() ([]models.User, error)
 	Update(user *models.User) error
+	// UpdateWithTx updates a user within an existing DB transaction
+	// Mevcut bir veritabanı işlemi içinde kullanıcıyı günceller
+	UpdateWithTx(tx *gorm.DB, user *models.User) error
+	Delete(id uint) error
+
+	WithTransaction(fn func(tx *gorm.DB) error) error
+}
+
+// RoleRepository defines the interface for role data access
+// Rol veri erişimi için arayüzü tanımlar
+type RoleRepository interface {
+	Create(role *models.Role) error
+	FindAll() ([]models.Role, error)
+	FindByID(id uint) (*models.Role, error)
+	FindByName(name string) (*models.Role, error)
+	Update(role *models.Role) error
 	Delete(id uint) error
+	// CountUsers returns how many users are assigned the role
+	// Role atanmış kullanıcı sayısını döndürür
+	CountUsers(name string) (int64, error)
 }
 
 // OrderRepository defines the interface for order data access
@@ -51,14 +88,21 @@ type OrderRepository interface {
 	GetOrderWithDetails(orderID uint) (*models.Order, error)
 	HasActiveOrders() (bool, error)
 	Update(order *models.Order) error
+	UpdateWithTx(tx *gorm.DB, order *models.Order) error
 	Delete(id uint) error
+	DeleteWithTx(tx *gorm.DB, id uint) error
 
 	// Item Management
 	AddItem(item *models.OrderItem) error
 	UpdateItem(item *models.OrderItem) error
 	DeleteItem(item *models.OrderItem) error
+	DeleteItemWithTx(tx *gorm.DB, item *models.OrderItem) error
 	FindItem(itemID uint) (*models.OrderItem, error)
 
+	// Totals & Tax
+	RecalculateTotals(orderID uint) error
+	SumTaxByRate(periodIDs []uint) ([]models.TaxRateTotal, error)
+
 	// WithTransaction runs a function within a database transaction
 	// Bir veritabanı işlemi içinde bir fonksiyon çalıştırır
 	WithTransaction(fn func(tx *gorm.DB) error) error
@@ -76,9 +120,56 @@ type TransactionRepository interface {
 	FindAllByWorkPeriodID(periodID uint, txType string) ([]models.Transaction, error)
 	FindAllByWorkPeriodIDs(periodIDs []uint, txType string) ([]models.Transaction, error)
 	Update(transaction *models.Transaction) error
+	UpdateWithTx(tx *gorm.DB, transaction *models.Transaction) error
 	Delete(id uint) error
+	DeleteWithTx(tx *gorm.DB, id uint) error
 	FindByID(id uint) (*models.Transaction, error)
 	FindByOrderID(orderID uint) (*models.Transaction, error)
+	// SumRefundsByMethod returns the refunded amounts (positive) of the given work periods per payment method
+	// Verilen çalışma dönemlerinde iade edilen tutarları (pozitif) ödeme yöntemine göre döndürür
+	SumRefundsByMethod(periodIDs []uint) (map[string]int64, error)
+	// SumAccountPaymentsByMethod returns what customers paid off their tabs in the given work periods per payment method
+	// Verilen çalışma dönemlerinde müşterilerin veresiye ödemelerini ödeme yöntemine göre döndürür
+	SumAccountPaymentsByMethod(periodIDs []uint) (map[string]int64, error)
+
+	WithTransaction(fn func(tx *gorm.DB) error) error
+}
+
+// PaymentRepository defines the interface for order payment data access
+// Sipariş ödemesi veri erişimi için arayüzü tanımlar
+type PaymentRepository interface {
+	// CreateWithTx creates a payment within an existing DB transaction
+	// Mevcut bir veritabanı işlemi içinde bir ödeme oluşturur
+	CreateWithTx(tx *gorm.DB, payment *models.Payment) error
+	Update(payment *models.Payment) error
+	UpdateWithTx(tx *gorm.DB, payment *models.Payment) error
+	FindByOrderID(orderID uint) ([]models.Payment, error)
+	// SumByMethod returns payment totals grouped by method for the given work periods (cancelled orders excluded)
+	// Verilen çalışma dönemleri için ödeme yöntemine göre toplamları döndürür (iptal edilen siparişler hariç)
+	SumByMethod(periodIDs []uint) (map[string]int64, error)
+}
+
+// KitchenRepository defines the interface for kitchen station and ticket data access
+// Mutfak istasyonu ve ticket veri erişimi için arayüzü tanımlar
+type KitchenRepository interface {
+	CreateStation(station *models.KitchenStation) error
+	FindAllStations() ([]models.KitchenStation, error)
+	FindStationByID(id uint) (*models.KitchenStation, error)
+	UpdateStation(station *models.KitchenStation) error
+	DeleteStation(id uint) error
+
+	CreateTicket(ticket *models.KitchenTicket) error
+	FindTicketByID(id uint) (*models.KitchenTicket, error)
+	// FindTickets lists tickets filtered by station (nil = all) and statuses, oldest first
+	// Ticketları istasyon (nil = tümü) ve durumlara göre filtreler, eskiden yeniye sıralar
+	FindTickets(stationID *uint, statuses []string) ([]models.KitchenTicket, error)
+	FindTicketsByItem(itemID uint) ([]models.KitchenTicket, error)
+	FindTicketsByOrder(orderID uint) ([]models.KitchenTicket, error)
+	FindTicketsBetween(start, end time.Time) ([]models.KitchenTicket, error)
+	UpdateTicket(ticket *models.KitchenTicket) error
+	// UpdateItemStatus stores the aggregated kitchen status on the order item
+	// Toplu mutfak durumunu sipariş kalemine yazar
+	UpdateItemStatus(itemID uint, status string) error
 }
 
 // WorkPeriodRepository defines the interface for work period data access
@@ -87,9 +178,16 @@ type WorkPeriodRepository interface {
 	Create(period *models.WorkPeriod) error
 	FindActivePeriod() (*models.WorkPeriod, error)
 	Update(period *models.WorkPeriod) error
+	UpdateWithTx(tx *gorm.DB, period *models.WorkPeriod) error
 	GetPeriodsByDate(date time.Time) ([]models.WorkPeriod, error)
 	GetPeriodsBetweenDates(start, end time.Time) ([]models.WorkPeriod, error)
 	FindByID(id uint) (*models.WorkPeriod, error)
+
+	CreateCashMovementWithTx(tx *gorm.DB, movement *models.CashMovement) error
+	FindCashMovements(periodID uint) ([]models.CashMovement, error)
+	SumCashMovements(periodID uint) (map[string]int64, error)
+
+	WithTransaction(fn func(tx *gorm.DB) error) error
 }
 
 // TableRepository defines the interface for table data access
@@ -100,4 +198,221 @@ type TableRepository interface {
 	FindByID(id uint) (*models.Table, error)
 	Update(table *models.Table) error
 	Delete(id uint) error
+	// SaveLayout stores the section and layout fields of several tables in one transaction
+	// Birden çok masanın bölüm ve yerleşim alanlarını tek işlemde kaydeder
+	SaveLayout(tables []models.Table) error
+}
+
+// SectionRepository defines the interface for floor plan section data access
+// Kat planı bölümü veri erişimi için arayüzü tanımlar
+type SectionRepository interface {
+	Create(section *models.Section) error
+	FindAll() ([]models.Section, error)
+	FindByID(id uint) (*models.Section, error)
+	FindByName(name string) (*models.Section, error)
+	// Update saves the section and renames the section of its tables
+	// Bölümü kaydeder ve masalarının bölüm adını günceller
+	Update(section *models.Section) error
+	Delete(id uint) error
+	CountTables(id uint) (int64, error)
+}
+
+type InventoryRepository interface {
+	CreateStockItem(item *models.StockItem) error
+	FindAllStockItems() ([]models.StockItem, error)
+	FindStockItemByID(id uint) (*models.StockItem, error)
+	UpdateStockItem(item *models.StockItem) error
+	DeleteStockItem(id uint) error
+	FindLowStockItems() ([]models.StockItem, error)
+
+	// Recipes
+	FindRecipe(productID uint) ([]models.RecipeItem, error)
+	FindRecipeWithTx(tx *gorm.DB, productID uint) ([]models.RecipeItem, error)
+	ReplaceRecipe(productID uint, items []models.RecipeItem) error
+
+	// Movements
+	// CreateMovementWithTx applies the signed delta to the stock item and stores the movement with its resulting balance
+	// İşaretli miktarı stoğa uygular ve hareketi sonuç bakiyesiyle kaydeder
+	CreateMovementWithTx(tx *gorm.DB, movement *models.StockMovement) error
+	FindMovements(stockItemID *uint, start, end time.Time) ([]models.StockMovement, error)
+	// SumItemMovementsWithTx returns the net stock delta already booked for an order item, per stock item
+	// Bir sipariş kalemi için kaydedilmiş net stok değişimini stok kalemi bazında döndürür
+	SumItemMovementsWithTx(tx *gorm.DB, orderItemID uint) (map[uint]float64, error)
+	// PendingDemand returns stock needed by OPEN orders that has not been depleted yet, per stock item
+	// AÇIK siparişlerin henüz düşülmemiş stok ihtiyacını stok kalemi bazında döndürür
+	PendingDemand() (map[uint]float64, error)
+
+	WithTransaction(fn func(tx *gorm.DB) error) error
+}
+
+// ReservationRepository defines the interface for reservation data access
+// Rezervasyon veri erişimi için arayüzü tanımlar
+type ReservationRepository interface {
+	// Save creates or updates the reservation and replaces its tables
+	// Rezervasyonu oluşturur veya günceller ve masalarını değiştirir
+	Save(reservation *models.Reservation) error
+	FindByID(id uint) (*models.Reservation, error)
+	// Find lists reservations matching the filter, earliest slot first
+	// Filtreye uyan rezervasyonları en erken saatten başlayarak listeler
+	Find(filter models.ReservationFilter) ([]models.Reservation, error)
+	// FindConflicts returns booked reservations of the tables overlapping the slot, excluding one reservation
+	// Masaların aralıkla çakışan aktif rezervasyonlarını döndürür, bir rezervasyon hariç tutulur
+	FindConflicts(tableIDs []uint, start, end time.Time, excludeID uint) ([]models.Reservation, error)
+	// FindBookedStartingBefore lists booked reservations whose slot starts before the given time
+	// Aralığı verilen zamandan önce başlayan aktif rezervasyonları listeler
+	FindBookedStartingBefore(t time.Time) ([]models.Reservation, error)
+}
+
+// ProductStatRepository defines the interface for product sales statistics
+// Ürün satış istatistikleri için arayüzü tanımlar
+type ProductStatRepository interface {
+	// RebuildForPeriodWithTx replaces the stats of the period with fresh totals from its sold order items
+	// Dönemin istatistiklerini satılan sipariş kalemlerinden hesaplanan güncel toplamlarla değiştirir
+	RebuildForPeriodWithTx(tx *gorm.DB, period *models.WorkPeriod) (int, error)
+	// RankProducts lists the products on the menu by their sales in the date range (YYYY-MM-DD, end exclusive)
+	// Menüdeki ürünleri tarih aralığındaki satışlarına göre sıralar (YYYY-MM-DD, bitiş hariç)
+	RankProducts(startDate, endDate, rank, metric string, limit int) ([]models.ProductSalesSummary, error)
+	CategoryMix(startDate, endDate string) ([]models.CategorySalesMix, error)
+	ProductTrend(productID uint, startDate, endDate string) ([]models.ProductTrendPoint, error)
+}
+
+// AuditRepository defines the interface for the append-only audit log
+// Yalnızca eklenebilir denetim kaydı için arayüzü tanımlar
+type AuditRepository interface {
+	// CreateWithTx appends an entry within the DB transaction of the audited change
+	// Kaydı, denetlenen değişikliğin veritabanı işlemi içinde ekler
+	CreateWithTx(tx *gorm.DB, entry *models.AuditLog) error
+	// Find lists entries matching the filter, newest first
+	// Filtreye uyan kayıtları yeniden eskiye listeler
+	Find(filter models.AuditFilter) ([]models.AuditLog, error)
+}
+
+// RefundRepository defines the interface for refund data access
+// İade veri erişimi için arayüzü tanımlar
+type RefundRepository interface {
+	// CreateWithTx stores the refund with its items within an existing DB transaction
+	// İadeyi kalemleriyle birlikte mevcut bir veritabanı işlemi içinde kaydeder
+	CreateWithTx(tx *gorm.DB, refund *models.Refund) error
+	FindByOrderID(orderID uint) ([]models.Refund, error)
+
+	WithTransaction(fn func(tx *gorm.DB) error) error
+}
+
+// InvoiceRepository defines the interface for e-Arşiv invoice data access
+// e-Arşiv fatura veri erişimi için arayüzü tanımlar
+type InvoiceRepository interface {
+	// NextSequenceWithTx reserves the next invoice number of the year within the issuing transaction
+	// Yılın bir sonraki fatura numarasını kesim işlemi içinde ayırır
+	NextSequenceWithTx(tx *gorm.DB, year int) (int, error)
+	CreateWithTx(tx *gorm.DB, invoice *models.Invoice) error
+	Update(invoice *models.Invoice) error
+	FindByID(id uint) (*models.Invoice, error)
+	FindByOrderID(orderID uint) (*models.Invoice, error)
+	// Find lists invoices matching the filter, newest first
+	// Filtreye uyan faturaları yeniden eskiye listeler
+	Find(filter models.InvoiceFilter) ([]models.Invoice, error)
+
+	WithTransaction(fn func(tx *gorm.DB) error) error
+}
+
+// CustomerRepository defines the interface for customer account data access
+// Müşteri hesabı veri erişimi için arayüzü tanımlar
+type CustomerRepository interface {
+	Create(customer *models.Customer) error
+	Update(customer *models.Customer) error
+	Delete(id uint) error
+	FindByID(id uint) (*models.Customer, error)
+	FindByPhone(phone string) (*models.Customer, error)
+	// Find lists customers matching the filter by name
+	// Filtreye uyan müşterileri ada göre listeler
+	Find(filter models.CustomerFilter) ([]models.Customer, error)
+
+	// FindByIDWithTx loads a customer within the DB transaction that posts to its ledger
+	// Müşteriyi hesabına kayıt atan veritabanı işlemi içinde yükler
+	FindByIDWithTx(tx *gorm.DB, id uint) (*models.Customer, error)
+	// CreateEntryWithTx moves the customer balance by the entry amount and appends the entry with the resulting balance
+	// Müşteri bakiyesini hareket tutarı kadar değiştirir ve hareketi oluşan bakiyeyle ekler
+	CreateEntryWithTx(tx *gorm.DB, entry *models.CustomerLedgerEntry) error
+	// FindEntries lists the ledger entries of a customer created in [start, end), oldest first
+	// Müşterinin [start, end) aralığında oluşan hesap hareketlerini eskiden yeniye listeler
+	FindEntries(customerID uint, start, end time.Time) ([]models.CustomerLedgerEntry, error)
+	// BalanceAt returns the balance of a customer just before the given time
+	// Müşterinin verilen zamandan hemen önceki bakiyesini döndürür
+	BalanceAt(customerID uint, t time.Time) (int64, error)
+
+	WithTransaction(fn func(tx *gorm.DB) error) error
+}
+
+// LoyaltyRepository defines the interface for loyalty rules, balances and history
+// Sadakat kuralları, bakiyeleri ve geçmişi için arayüzü tanımlar
+type LoyaltyRepository interface {
+	CreateRule(rule *models.LoyaltyRule) error
+	UpdateRule(rule *models.LoyaltyRule) error
+	DeleteRule(id uint) error
+	FindRuleByID(id uint) (*models.LoyaltyRule, error)
+	FindRules() ([]models.LoyaltyRule, error)
+	// FindActiveRulesWithTx returns the active rules keyed by category
+	// Aktif kuralları kategoriye göre döndürür
+	FindActiveRulesWithTx(tx *gorm.DB) (map[uint]models.LoyaltyRule, error)
+	// ProductCategoriesWithTx maps products, deleted ones included, to their categories
+	// Ürünleri, silinmişler dahil, kategorilerine eşler
+	ProductCategoriesWithTx(tx *gorm.DB, productIDs []uint) (map[uint]uint, error)
+
+	FindStampCards(customerID uint) ([]models.LoyaltyStampCard, error)
+	FindStampCardWithTx(tx *gorm.DB, customerID, categoryID uint) (*models.LoyaltyStampCard, error)
+	// AddStampsWithTx moves a stamp card by delta, creating it on first use
+	// Damga kartını delta kadar değiştirir, ilk kullanımda oluşturur
+	AddStampsWithTx(tx *gorm.DB, customerID, categoryID uint, delta int) error
+	// AddPointsWithTx moves the loyalty points of a customer by delta
+	// Müşterinin sadakat puanını delta kadar değiştirir
+	AddPointsWithTx(tx *gorm.DB, customerID uint, delta int64) error
+	CreateEntryWithTx(tx *gorm.DB, entry *models.LoyaltyEntry) error
+	// FindEntries lists the latest history of a customer, newest first
+	// Müşterinin son geçmişini yeniden eskiye listeler
+	FindEntries(customerID uint, limit int) ([]models.LoyaltyEntry, error)
+	// FindOrderEntriesWithTx lists the history entries of an order, oldest first
+	// Siparişin geçmiş kayıtlarını eskiden yeniye listeler
+	FindOrderEntriesWithTx(tx *gorm.DB, orderID uint) ([]models.LoyaltyEntry, error)
+
+	// SaveRedemptionWithTx stores the pending redemption of an order, replacing an earlier one
+	// Siparişin bekleyen kullanımını kaydeder, öncekinin yerine geçer
+	SaveRedemptionWithTx(tx *gorm.DB, redemption *models.LoyaltyRedemption) error
+	FindRedemptionWithTx(tx *gorm.DB, orderID uint) (*models.LoyaltyRedemption, error)
+	DeleteRedemptionWithTx(tx *gorm.DB, orderID uint) error
+
+	WithTransaction(fn func(tx *gorm.DB) error) error
+}
+
+// PromotionRepository defines the interface for promotion rules
+// Promosyon kuralları için arayüzü tanımlar
+type PromotionRepository interface {
+	Create(promotion *models.Promotion) error
+	// Update saves the promotion and replaces its combo items
+	// Promosyonu kaydeder ve menü kalemlerini değiştirir
+	Update(promotion *models.Promotion) error
+	Delete(id uint) error
+	FindByID(id uint) (*models.Promotion, error)
+	FindAll(includeInactive bool) ([]models.Promotion, error)
+}
+
+// CouponRepository defines the interface for coupons and their redemptions
+// Kuponlar ve kullanımları için arayüzü tanımlar
+type CouponRepository interface {
+	Create(coupon *models.Coupon) error
+	Update(coupon *models.Coupon) error
+	Delete(id uint) error
+	FindByID(id uint) (*models.Coupon, error)
+	FindByCode(code string) (*models.Coupon, error)
+	FindAll(includeInactive bool) ([]models.Coupon, error)
+
+	FindByIDWithTx(tx *gorm.DB, id uint) (*models.Coupon, error)
+	// CountCustomerRedemptionsWithTx counts the redemptions of a coupon by a customer
+	// Bir kuponun bir müşteri tarafından kullanım sayısını verir
+	CountCustomerRedemptionsWithTx(tx *gorm.DB, couponID, customerID uint) (int64, error)
+	// CreateRedemptionWithTx records the redemption and counts it on the coupon
+	// Kullanımı kaydeder ve kuponda sayar
+	CreateRedemptionWithTx(tx *gorm.DB, redemption *models.CouponRedemption) error
+	// DeleteRedemptionWithTx removes the redemption of an order and takes it off the coupon's count
+	// Siparişin kullanımını siler ve kuponun sayısından düşer
+	DeleteRedemptionWithTx(tx *gorm.DB, orderID uint) error
 }
@@ -3,6 +3,9 @@ package routes
 import (
 	"simple-pos/internal/handlers"
 	"simple-pos/internal/middleware"
+	"simple-pos/internal/models"
+	"simple-pos/internal/platform/einvoice"
+	"simple-pos/internal/platform/events"
 	"simple-pos/internal/repositories/gorm_repo"
 	"simple-pos/internal/services"
 	"simple-pos/pkg/config"
@@ -24,18 +27,87 @@ func RegisterRoutes(app *fiber.App, db *gorm.DB, cfg *config.Config) {
 	orderRepo := gorm_repo.NewOrderRepository(db)
 	workPeriodRepo := gorm_repo.NewWorkPeriodRepository(db)
 	tableRepo := gorm_repo.NewTableRepository(db)
+	sectionRepo := gorm_repo.NewSectionRepository(db)
+	productStatRepo := gorm_repo.NewProductStatRepository(db)
+	paymentRepo := gorm_repo.NewPaymentRepository(db)
+	modifierRepo := gorm_repo.NewModifierRepository(db)
+	kitchenRepo := gorm_repo.NewKitchenRepository(db)
+	inventoryRepo := gorm_repo.NewInventoryRepository(db)
+	auditRepo := gorm_repo.NewAuditRepository(db)
+	roleRepo := gorm_repo.NewRoleRepository(db)
+	refundRepo := gorm_repo.NewRefundRepository(db)
+	reservationRepo := gorm_repo.NewReservationRepository(db)
+	invoiceRepo := gorm_repo.NewInvoiceRepository(db)
+	customerRepo := gorm_repo.NewCustomerRepository(db)
+	loyaltyRepo := gorm_repo.NewLoyaltyRepository(db)
+	promotionRepo := gorm_repo.NewPromotionRepository(db)
+	couponRepo := gorm_repo.NewCouponRepository(db)
+
+	// Real-time event bus shared by publishing services and the stream handler
+	// Yayın yapan servisler ve akış handlerı tarafından paylaşılan olay yolu
+	eventBus := events.NewBus()
 
 	// 5. Initialize Services
-	authService := services.NewAuthService(userRepo)
+	auditService := services.NewAuditService(auditRepo)
+	printService := services.NewPrintService(orderRepo, tableRepo, kitchenRepo, services.PrintSettings{
+		ReceiptPrinter: cfg.ReceiptPrinter,
+		KitchenPrinter: cfg.KitchenPrinter,
+		Width:          cfg.PrinterWidth,
+		Header:         cfg.ReceiptHeader,
+	})
+	inventoryService := services.NewInventoryService(inventoryRepo, productRepo, eventBus, cfg.BlockOnInsufficientStock)
+	roleService := services.NewRoleService(roleRepo)
+	overrideService := services.NewOverrideService(userRepo, roleService, auditService, cfg.OverrideDiscountPercent)
+	authService := services.NewAuthService(userRepo, roleService)
 	categoryService := services.NewCategoryService(categoryRepo)
 	productService := services.NewProductService(productRepo)
-	transactionService := services.NewTransactionService(transactionRepo, workPeriodRepo)
-	orderService := services.NewOrderService(orderRepo, transactionRepo, workPeriodRepo, productRepo, tableRepo)
-	analyticsService := services.NewAnalyticsService(db, transactionRepo, workPeriodRepo)
-	userService := services.NewUserService(userRepo)
-	managementService := services.NewManagementService(workPeriodRepo, orderRepo, db)
-	tableService := services.NewTableService(tableRepo)
+	transactionService := services.NewTransactionService(transactionRepo, workPeriodRepo, auditService)
+	customerService := services.NewCustomerService(customerRepo, transactionRepo, workPeriodRepo, auditService)
+	orderService := services.NewOrderService(orderRepo, transactionRepo, workPeriodRepo, productRepo, tableRepo, paymentRepo, modifierRepo, kitchenRepo, eventBus, inventoryService, auditService, printService, overrideService, customerService, couponRepo)
+	refundService := services.NewRefundService(refundRepo, orderRepo, transactionRepo, workPeriodRepo, inventoryService, auditService, eventBus, overrideService, customerService)
+	loyaltyService := services.NewLoyaltyService(loyaltyRepo, customerRepo, categoryRepo, orderRepo, orderService, int64(cfg.LoyaltyPointValue))
+	analyticsService := services.NewAnalyticsService(db, transactionRepo, workPeriodRepo, paymentRepo, orderRepo, productStatRepo)
+	userService := services.NewUserService(userRepo, roleRepo, auditService)
+	invoiceService := services.NewInvoiceService(invoiceRepo, orderRepo, auditService, services.InvoiceSettings{
+		Prefix:     cfg.InvoicePrefix,
+		Integrator: cfg.InvoiceIntegrator,
+		Seller: einvoice.Party{
+			TaxID:     cfg.SellerTaxID,
+			Name:      cfg.SellerName,
+			TaxOffice: cfg.SellerTaxOffice,
+			Street:    cfg.SellerAddress,
+			District:  cfg.SellerDistrict,
+			City:      cfg.SellerCity,
+			Country:   "Türkiye",
+			Email:     cfg.SellerEmail,
+		},
+	})
+	exportService := services.NewExportService(orderService, transactionService, analyticsService, workPeriodRepo, productStatRepo, cfg.ReportFontPath)
+	managementService := services.NewManagementService(workPeriodRepo, orderRepo, paymentRepo, transactionRepo, productStatRepo, db, eventBus, auditService)
+	tableService := services.NewTableService(tableRepo, sectionRepo, eventBus)
 	uploadService := services.NewUploadService()
+	modifierService := services.NewModifierService(modifierRepo, productRepo)
+	promotionService := services.NewPromotionService(promotionRepo, productRepo, categoryRepo)
+	couponService := services.NewCouponService(couponRepo)
+	kitchenService := services.NewKitchenService(kitchenRepo)
+	reservationService := services.NewReservationService(reservationRepo, tableRepo, orderService, eventBus, services.ReservationSettings{
+		Duration: time.Duration(cfg.ReservationMinutes) * time.Minute,
+		Hold:     time.Duration(cfg.ReservationHoldMinutes) * time.Minute,
+		NoShow:   time.Duration(cfg.ReservationNoShowMinutes) * time.Minute,
+	})
+
+	// Settle loyalty points and stamps whenever an order is closed, take them back when it is refunded
+	// Her sipariş kapandığında sadakat puanlarını ve damgalarını işle, iade edildiğinde geri al
+	orderService.OnClose(loyaltyService.SettleOrderWithTx)
+	refundService.OnRefund(loyaltyService.ReverseRefundWithTx)
+
+	// A fully refunded order no longer counts against its coupon's limits
+	// Tamamen iade edilen sipariş kuponunun limitlerinden sayılmaz
+	refundService.OnRefund(couponService.ReleaseRefundWithTx)
+
+	// Keep table statuses in line with upcoming reservations
+	// Masa durumlarını yaklaşan rezervasyonlarla uyumlu tut
+	reservationService.StartSync(time.Minute)
 
 	// 6. Initialize Handlers
 	authHandler := handlers.NewAuthHandler(authService, workPeriodRepo)
@@ -44,10 +116,25 @@ func RegisterRoutes(app *fiber.App, db *gorm.DB, cfg *config.Config) {
 	transactionHandler := handlers.NewTransactionHandler(transactionService)
 	orderHandler := handlers.NewOrderHandler(orderService)
 	analyticsHandler := handlers.NewAnalyticsHandler(analyticsService)
+	exportHandler := handlers.NewExportHandler(exportService)
+	invoiceHandler := handlers.NewInvoiceHandler(invoiceService)
+	customerHandler := handlers.NewCustomerHandler(customerService)
+	loyaltyHandler := handlers.NewLoyaltyHandler(loyaltyService)
 	userHandler := handlers.NewUserHandler(userService)
 	managementHandler := handlers.NewManagementHandler(managementService)
 	tableHandler := handlers.NewTableHandler(tableService)
 	uploadHandler := handlers.NewUploadHandler(uploadService)
+	modifierHandler := handlers.NewModifierHandler(modifierService)
+	promotionHandler := handlers.NewPromotionHandler(promotionService)
+	couponHandler := handlers.NewCouponHandler(couponService)
+	kitchenHandler := handlers.NewKitchenHandler(kitchenService)
+	eventHandler := handlers.NewEventHandler(eventBus)
+	inventoryHandler := handlers.NewInventoryHandler(inventoryService)
+	auditHandler := handlers.NewAuditHandler(auditService)
+	printHandler := handlers.NewPrintHandler(printService)
+	refundHandler := handlers.NewRefundHandler(refundService)
+	roleHandler := handlers.NewRoleHandler(roleService)
+	reservationHandler := handlers.NewReservationHandler(reservationService)
 
 	// 7. Route Groups
 	api := app.Group("/api/v1") // /api/v1
@@ -61,15 +148,40 @@ func RegisterRoutes(app *fiber.App, db *gorm.DB, cfg *config.Config) {
 	app.Post("/auth/login", authHandler.Login)
 	api.Get("/categories", categoryHandler.GetAll)
 	api.Get("/products", productHandler.GetAll)
+	api.Get("/products/:id/modifiers", modifierHandler.GetProductModifiers)
 
-	// Protected Routes (Waiter + Admin)
-	protected := api.Group("/", middleware.Protected())
+	// Real-time Events (SSE, token via header or access_token query)
+	api.Get("/events", middleware.ProtectedStream(authService), eventHandler.Stream)
+
+	// Protected Routes (every signed in role)
+	protected := api.Group("/", middleware.Protected(authService))
+
+	// Permission Guards (admins pass every guard)
+	// Yetki kontrolleri (adminler tüm kontrollerden geçer)
+	canDiscount := middleware.RequirePermission(models.PermissionApplyDiscount)
+	canVoid := middleware.RequirePermission(models.PermissionVoidItem)
+	canRefund := middleware.RequirePermission(models.PermissionRefundOrder)
+	canCloseDay := middleware.RequirePermission(models.PermissionCloseDay)
+	canManageDrawer := middleware.RequirePermission(models.PermissionManageCashDrawer)
+	canViewReports := middleware.RequirePermission(models.PermissionViewReports)
+	canManageMenu := middleware.RequirePermission(models.PermissionManageMenu)
+	canManageInventory := middleware.RequirePermission(models.PermissionManageInventory)
+	canManageTables := middleware.RequirePermission(models.PermissionManageTables)
+	canManageReservations := middleware.RequirePermission(models.PermissionManageReservations)
+	canManageKitchen := middleware.RequirePermission(models.PermissionManageKitchen)
+	canManageExpenses := middleware.RequirePermission(models.PermissionManageExpenses)
+	canIssueInvoice := middleware.RequirePermission(models.PermissionIssueInvoice)
+	canManageCustomers := middleware.RequirePermission(models.PermissionManageCustomers)
+	canManageUsers := middleware.RequirePermission(models.PermissionManageUsers)
+	canManageRoles := middleware.RequirePermission(models.PermissionManageRoles)
+	canViewAuditLog := middleware.RequirePermission(models.PermissionViewAuditLog)
 
 	// Auth Persistence
 	protected.Get("/auth/me", authHandler.Me)
 
 	// Tables (Read-Only Public/Protected) - Waiters need to see tables.
 	protected.Get("/tables", tableHandler.ListTables)
+	protected.Get("/sections", tableHandler.ListSections)
 
 	// System Status (Shared)
 	protected.Get("/management/status", managementHandler.GetSystemStatus)
@@ -77,53 +189,183 @@ func RegisterRoutes(app *fiber.App, db *gorm.DB, cfg *config.Config) {
 	// Orders
 	protected.Post("/orders", orderHandler.Create)
 	protected.Post("/orders/:id/close", orderHandler.Close)
+	protected.Post("/orders/:id/payments", orderHandler.AddPayment)
+	protected.Get("/orders/:id/payments", orderHandler.GetPayments)
 	protected.Post("/orders/:id/items", orderHandler.AddItem)
 	protected.Put("/orders/:id/items/:itemId", orderHandler.UpdateItem)
-	protected.Delete("/orders/:id/items/:itemId", orderHandler.RemoveItem)
-	protected.Delete("/orders/:id", orderHandler.Cancel)
-	protected.Post("/orders/:id/discount", orderHandler.ApplyDiscount) // Discount for Waiters/Admins
+	protected.Delete("/orders/:id/items/:itemId", canVoid, orderHandler.RemoveItem)
+	protected.Put("/orders/:id/note", orderHandler.UpdateNote)
+	protected.Delete("/orders/:id", canVoid, orderHandler.Cancel)
+	protected.Post("/orders/:id/discount", canDiscount, orderHandler.ApplyDiscount)
+	protected.Post("/orders/:id/coupon", orderHandler.ApplyCoupon)
+	protected.Delete("/orders/:id/coupon", orderHandler.RemoveCoupon)
+	protected.Put("/orders/:id/customer", loyaltyHandler.AttachCustomer)
+	protected.Post("/orders/:id/loyalty/redeem", canDiscount, loyaltyHandler.Redeem)
+	protected.Post("/orders/:id/transfer", orderHandler.Transfer)
+	protected.Post("/orders/:id/move-items", orderHandler.MoveItems)
+	protected.Post("/tables/:id/merge", orderHandler.MergeTables)
 	protected.Get("/orders/:id", orderHandler.GetOrder)
+	protected.Get("/orders/:id/receipt", printHandler.GetReceipt)
+	protected.Post("/orders/:id/receipt/print", printHandler.PrintReceipt)
 	protected.Get("/orders", orderHandler.GetOrders)
 	protected.Get("/orders/table/:id", orderHandler.GetOrdersByTable)
-	// Admin Routes
-	admin := protected.Group("/", middleware.RequireRole("admin"))
 
-	// Expense Management (Admin Only)
-	admin.Post("/transactions/expense", transactionHandler.AddExpense)
-	admin.Get("/transactions/expense", transactionHandler.ListExpenses)
-	admin.Put("/transactions/expense/:id", transactionHandler.UpdateExpense)
-	admin.Delete("/transactions/expense/:id", transactionHandler.DeleteExpense)
+	// Refunds and Voids of completed orders
+	protected.Get("/orders/:id/refunds", canRefund, refundHandler.ListRefunds)
+	protected.Post("/orders/:id/refunds", canRefund, refundHandler.RefundOrder)
+
+	// Reservations
+	protected.Get("/reservations", reservationHandler.ListReservations)
+	protected.Get("/reservations/:id", reservationHandler.GetReservation)
+	protected.Post("/reservations", canManageReservations, reservationHandler.CreateReservation)
+	protected.Put("/reservations/:id", canManageReservations, reservationHandler.UpdateReservation)
+	protected.Delete("/reservations/:id", canManageReservations, reservationHandler.CancelReservation)
+	protected.Post("/reservations/:id/no-show", canManageReservations, reservationHandler.MarkNoShow)
+	protected.Post("/reservations/:id/seat", canManageReservations, reservationHandler.SeatReservation)
+
+	// Customer Accounts (everyone can pick a customer when closing on account)
+	protected.Get("/customers", customerHandler.ListCustomers)
+	protected.Get("/customers/:id", customerHandler.GetCustomer)
+	protected.Post("/customers", canManageCustomers, customerHandler.CreateCustomer)
+	protected.Put("/customers/:id", canManageCustomers, customerHandler.UpdateCustomer)
+	protected.Delete("/customers/:id", canManageCustomers, customerHandler.DeleteCustomer)
+	protected.Post("/customers/:id/payments", canManageCustomers, customerHandler.RecordPayment)
+	protected.Get("/customers/:id/statement", canManageCustomers, customerHandler.GetStatement)
+	protected.Get("/customers/:id/loyalty", loyaltyHandler.GetBalance)
+
+	// Loyalty Rules
+	protected.Get("/loyalty/rules", loyaltyHandler.ListRules)
+	protected.Post("/loyalty/rules", canManageCustomers, loyaltyHandler.CreateRule)
+	protected.Put("/loyalty/rules/:id", canManageCustomers, loyaltyHandler.UpdateRule)
+	protected.Delete("/loyalty/rules/:id", canManageCustomers, loyaltyHandler.DeleteRule)
+
+	// Low stock alerts (Kitchen + Waiters)
+	protected.Get("/inventory/alerts", inventoryHandler.GetLowStock)
+
+	// Kitchen Display (Kitchen screen + Waiters)
+	protected.Get("/kitchen/stations", kitchenHandler.ListStations)
+	protected.Get("/kitchen/tickets", kitchenHandler.ListTickets)
+	protected.Post("/kitchen/tickets/:id/bump", kitchenHandler.BumpTicket)
+	protected.Put("/kitchen/tickets/:id/status", kitchenHandler.SetTicketStatus)
+	protected.Post("/kitchen/tickets/:id/print", printHandler.PrintKitchenTicket)
+
+	// Expense Management
+	protected.Post("/transactions/expense", canManageExpenses, transactionHandler.AddExpense)
+	protected.Get("/transactions/expense", canManageExpenses, transactionHandler.ListExpenses)
+	protected.Put("/transactions/expense/:id", canManageExpenses, transactionHandler.UpdateExpense)
+	protected.Delete("/transactions/expense/:id", canManageExpenses, transactionHandler.DeleteExpense)
 
 	// User Management
-	admin.Post("/users", userHandler.Create)
-	admin.Get("/users", userHandler.GetUsers)
-	admin.Get("/users/:id", userHandler.GetUserByID)
-	admin.Put("/users/:id", userHandler.UpdateUser)
-	admin.Put("/users/:id/pin", userHandler.ChangePin)
-	admin.Delete("/users/:id", userHandler.DeleteUser)
-
-	// Menu Management (Admin)
-	admin.Post("/categories", categoryHandler.Create)
-	admin.Put("/categories/:id", categoryHandler.Update)
-	admin.Delete("/categories/:id", categoryHandler.Delete)
-	admin.Post("/products", productHandler.Create)
-	admin.Put("/products/:id", productHandler.Update)
-	admin.Delete("/products/:id", productHandler.Delete)
-
-	// Upload Management (Admin)
-	admin.Post("/uploads/product-image", uploadHandler.UploadProductImage)
-
-	// Table Management (Admin)
-	admin.Post("/tables", tableHandler.CreateTable)
-	admin.Put("/tables/:id", tableHandler.UpdateTable)
-	admin.Delete("/tables/:id", tableHandler.DeleteTable)
-
-	// Management Routes (Admin)
-	management := admin.Group("/management", middleware.RateLimiter(5, time.Minute))
+	protected.Post("/users", canManageUsers, userHandler.Create)
+	protected.Get("/users", canManageUsers, userHandler.GetUsers)
+	protected.Get("/users/:id", canManageUsers, userHandler.GetUserByID)
+	protected.Put("/users/:id", canManageUsers, userHandler.UpdateUser)
+	protected.Put("/users/:id/pin", canManageUsers, userHandler.ChangePin)
+	protected.Delete("/users/:id", canManageUsers, userHandler.DeleteUser)
+
+	// Role Management
+	protected.Get("/permissions", canManageRoles, roleHandler.ListPermissions)
+	protected.Get("/roles", canManageRoles, roleHandler.ListRoles)
+	protected.Post("/roles", canManageRoles, roleHandler.CreateRole)
+	protected.Put("/roles/:id", canManageRoles, roleHandler.UpdateRole)
+	protected.Delete("/roles/:id", canManageRoles, roleHandler.DeleteRole)
+
+	// Menu Management
+	protected.Post("/categories", canManageMenu, categoryHandler.Create)
+	protected.Put("/categories/:id", canManageMenu, categoryHandler.Update)
+	protected.Delete("/categories/:id", canManageMenu, categoryHandler.Delete)
+	protected.Post("/products", canManageMenu, productHandler.Create)
+	protected.Put("/products/:id", canManageMenu, productHandler.Update)
+	protected.Delete("/products/:id", canManageMenu, productHandler.Delete)
+	protected.Post("/uploads/product-image", canManageMenu, uploadHandler.UploadProductImage)
+
+	// Modifier Management
+	protected.Get("/modifier-groups", canManageMenu, modifierHandler.ListGroups)
+	protected.Post("/modifier-groups", canManageMenu, modifierHandler.CreateGroup)
+	protected.Put("/modifier-groups/:id", canManageMenu, modifierHandler.UpdateGroup)
+	protected.Delete("/modifier-groups/:id", canManageMenu, modifierHandler.DeleteGroup)
+	protected.Post("/modifier-groups/:id/options", canManageMenu, modifierHandler.AddOption)
+	protected.Put("/modifier-options/:id", canManageMenu, modifierHandler.UpdateOption)
+	protected.Delete("/modifier-options/:id", canManageMenu, modifierHandler.DeleteOption)
+
+	// Promotions (read by everyone so the POS can show running offers)
+	protected.Get("/promotions", promotionHandler.ListPromotions)
+	protected.Get("/promotions/:id", promotionHandler.GetPromotion)
+	protected.Post("/promotions", canManageMenu, promotionHandler.CreatePromotion)
+	protected.Put("/promotions/:id", canManageMenu, promotionHandler.UpdatePromotion)
+	protected.Delete("/promotions/:id", canManageMenu, promotionHandler.DeletePromotion)
+
+	// Coupons (waiters only enter codes on orders)
+	protected.Get("/coupons", canManageMenu, couponHandler.ListCoupons)
+	protected.Get("/coupons/:id", canManageMenu, couponHandler.GetCoupon)
+	protected.Post("/coupons", canManageMenu, couponHandler.CreateCoupon)
+	protected.Put("/coupons/:id", canManageMenu, couponHandler.UpdateCoupon)
+	protected.Delete("/coupons/:id", canManageMenu, couponHandler.DeleteCoupon)
+
+	// Inventory Management
+	protected.Get("/inventory/items", canManageInventory, inventoryHandler.ListStockItems)
+	protected.Post("/inventory/items", canManageInventory, inventoryHandler.CreateStockItem)
+	protected.Put("/inventory/items/:id", canManageInventory, inventoryHandler.UpdateStockItem)
+	protected.Delete("/inventory/items/:id", canManageInventory, inventoryHandler.DeleteStockItem)
+	protected.Post("/inventory/items/:id/movements", canManageInventory, inventoryHandler.RecordMovement)
+	protected.Get("/inventory/movements", canManageInventory, inventoryHandler.ListMovements)
+	protected.Get("/products/:id/recipe", canManageInventory, inventoryHandler.GetRecipe)
+	protected.Put("/products/:id/recipe", canManageInventory, inventoryHandler.SetRecipe)
+
+	// Kitchen Station Management
+	protected.Post("/kitchen/stations", canManageKitchen, kitchenHandler.CreateStation)
+	protected.Put("/kitchen/stations/:id", canManageKitchen, kitchenHandler.UpdateStation)
+	protected.Delete("/kitchen/stations/:id", canManageKitchen, kitchenHandler.DeleteStation)
+	protected.Get("/kitchen/stats", canManageKitchen, kitchenHandler.GetStationStats)
+
+	// Table Management
+	protected.Post("/tables", canManageTables, tableHandler.CreateTable)
+	protected.Put("/tables/layout", canManageTables, tableHandler.SaveLayout)
+	protected.Put("/tables/:id", canManageTables, tableHandler.UpdateTable)
+	protected.Delete("/tables/:id", canManageTables, tableHandler.DeleteTable)
+	protected.Post("/sections", canManageTables, tableHandler.CreateSection)
+	protected.Put("/sections/:id", canManageTables, tableHandler.UpdateSection)
+	protected.Delete("/sections/:id", canManageTables, tableHandler.DeleteSection)
+
+	// Management Routes (Day open/close)
+	management := protected.Group("/management", canCloseDay, middleware.RateLimiter(5, time.Minute))
 	management.Post("/start-day", managementHandler.StartDay)
 	management.Post("/end-day", managementHandler.EndDay)
+	management.Post("/product-stats/rebuild", managementHandler.RebuildProductStats)
+
+	// Cash Drawer
+	protected.Get("/cash-drawer/movements", canManageDrawer, managementHandler.ListCashMovements)
+	protected.Post("/cash-drawer/movements", canManageDrawer, managementHandler.RecordCashMovement)
+
+	// Analytics Routes
+	protected.Get("/analytics/daily", canViewReports, analyticsHandler.GetDailyReport)
+	protected.Get("/analytics/history", canViewReports, analyticsHandler.GetReportHistory)
+	protected.Get("/analytics/range", canViewReports, analyticsHandler.GetRangeReport)
+	protected.Get("/analytics/modifiers", canViewReports, analyticsHandler.GetModifierUsage)
+	protected.Get("/analytics/products", canViewReports, analyticsHandler.GetProductRanking)
+	protected.Get("/analytics/products/:id/trend", canViewReports, analyticsHandler.GetProductTrend)
+	protected.Get("/analytics/categories", canViewReports, analyticsHandler.GetCategoryMix)
+	protected.Get("/analytics/heatmap", canViewReports, analyticsHandler.GetSalesHeatmap)
+	protected.Get("/analytics/waiters", canViewReports, analyticsHandler.GetWaiterPerformance)
+	protected.Get("/analytics/promotions", canViewReports, analyticsHandler.GetPromotionReport)
+	protected.Get("/analytics/coupons", canViewReports, analyticsHandler.GetCouponReport)
+
+	// E-Arşiv Invoices
+	protected.Post("/orders/:id/invoice", canIssueInvoice, invoiceHandler.IssueInvoice)
+	protected.Get("/orders/:id/invoice", canIssueInvoice, invoiceHandler.GetOrderInvoice)
+	protected.Get("/invoices", canIssueInvoice, invoiceHandler.ListInvoices)
+	protected.Get("/invoices/:id", canIssueInvoice, invoiceHandler.GetInvoice)
+	protected.Get("/invoices/:id/xml", canIssueInvoice, invoiceHandler.DownloadXML)
+	protected.Post("/invoices/:id/send", canIssueInvoice, invoiceHandler.SendInvoice)
+
+	// Exports (CSV/XLSX lists and the PDF Z-report)
+	// Dışa aktarımlar (CSV/XLSX listeler ve PDF Z raporu)
+	protected.Get("/exports/periods", canViewReports, exportHandler.ExportPeriods)
+	protected.Get("/exports/orders", canViewReports, exportHandler.ExportOrders)
+	protected.Get("/exports/expenses", canViewReports, exportHandler.ExportExpenses)
+	protected.Get("/exports/product-sales", canViewReports, exportHandler.ExportProductSales)
+	protected.Get("/exports/z-report", canViewReports, exportHandler.ZReport)
 
-	// Analytics Routes (Admin)
-	admin.Get("/analytics/daily", analyticsHandler.GetDailyReport)
-	admin.Get("/analytics/history", analyticsHandler.GetReportHistory)
+	// Audit Log
+	protected.Get("/audit-logs", canViewAuditLog, auditHandler.ListLogs)
 }
@@ -11,6 +11,13 @@ import (
 
 // Seed initializes the database with default data if it's empty
 func Seed(db *gorm.DB, cfg *config.Config) {
+	// 0. Built-in roles, also added to existing databases
+	// Yerleşik roller, mevcut veritabanlarına da eklenir
+	seedRoles(db)
+	// Tables of older databases get a section record for their section name
+	// Eski veritabanlarındaki masalar bölüm adları için bir bölüm kaydı alır
+	seedSections(db)
+
 	// 1. Check if Generic User exists
 	var count int64
 	db.Model(&models.User{}).Count(&count)
@@ -35,7 +42,7 @@ func Seed(db *gorm.DB, cfg *config.Config) {
 	admin := models.User{
 		Name:     cfg.SeedAdminName,
 		PinCode:  string(hashedPin),
-		Role:     "admin",
+		Role:     models.RoleAdmin,
 		IsActive: true,
 	}
 
@@ -47,3 +54,44 @@ func Seed(db *gorm.DB, cfg *config.Config) {
 	log.Printf("Admin user '%s' created successfully.", admin.Name)
 
 }
+
+// seedRoles creates the default roles that do not exist yet, edited roles are left alone
+// Henüz olmayan varsayılan rolleri oluşturur, düzenlenmiş rollere dokunulmaz
+func seedRoles(db *gorm.DB) {
+	for _, role := range models.DefaultRoles {
+		var count int64
+		db.Model(&models.Role{}).Where("name = ?", role.Name).Count(&count)
+		if count > 0 {
+			continue
+		}
+		role := role
+		if err := db.Create(&role).Error; err != nil {
+			log.Printf("Failed to create role '%s': %v", role.Name, err)
+		}
+	}
+}
+
+// seedSections links tables without a section record to the section named by their section string
+// Bölüm kaydı olmayan masaları bölüm adlarındaki bölüme bağlar
+func seedSections(db *gorm.DB) {
+	var tables []models.Table
+	if err := db.Where("section_id IS NULL").Find(&tables).Error; err != nil {
+		log.Printf("Failed to load tables without section: %v", err)
+		return
+	}
+	for _, table := range tables {
+		name := table.Section
+		if name == "" {
+			name = "salon"
+		}
+		var section models.Section
+		if err := db.Where(models.Section{Name: name}).FirstOrCreate(&section).Error; err != nil {
+			log.Printf("Failed to create section '%s': %v", name, err)
+			continue
+		}
+		db.Model(&models.Table{}).Where("id = ?", table.ID).Updates(map[string]interface{}{
+			"section_id": section.ID,
+			"section":    section.Name,
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"log"
 
 	"simple-pos/internal/middleware"
+	"simple-pos/internal/models"
 	"simple-pos/internal/platform/database"
 	"simple-pos/internal/routes"
 	"simple-pos/internal/seeder"
@@ -26,6 +27,10 @@ func New(cfg *config.Config) *fiber.App {
 	// 3. Migrate Database
 	database.Migrate()
 
+	// Tax settings used by order total calculation
+	// Sipariş toplam hesaplamasında kullanılan vergi ayarları
+	models.ConfigureTax(cfg.PricesIncludeTax, cfg.DefaultTaxRate)
+
 	// 4. Seed Database
 	seeder.Seed(database.DB, cfg)
 
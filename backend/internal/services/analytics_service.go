@@ -2,8 +2,10 @@ package services
 
 import (
 	"errors"
+	"math"
 	"simple-pos/internal/models"
 	"simple-pos/internal/repositories"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -15,25 +17,613 @@ type AnalyticsService struct {
 	db              *gorm.DB
 	transactionRepo repositories.TransactionRepository
 	workPeriodRepo  repositories.WorkPeriodRepository
+	paymentRepo     repositories.PaymentRepository
+	orderRepo       repositories.OrderRepository
+	productStatRepo repositories.ProductStatRepository
 }
 
-func NewAnalyticsService(db *gorm.DB, txRepo repositories.TransactionRepository, wpRepo repositories.WorkPeriodRepository) *AnalyticsService {
+func NewAnalyticsService(db *gorm.DB, txRepo repositories.TransactionRepository, wpRepo repositories.WorkPeriodRepository, paymentRepo repositories.PaymentRepository, orderRepo repositories.OrderRepository, productStatRepo repositories.ProductStatRepository) *AnalyticsService {
 	return &AnalyticsService{
 		db:              db,
 		transactionRepo: txRepo,
 		workPeriodRepo:  wpRepo,
+		paymentRepo:     paymentRepo,
+		orderRepo:       orderRepo,
+		productStatRepo: productStatRepo,
 	}
 }
 
-// GetReportHistory fetches past work periods (history)
-// Geçmiş çalışma dönemlerini getirir
-func (s *AnalyticsService) GetReportHistory() ([]models.WorkPeriod, error) {
+// ModifierUsageStat represents how often a modifier option was sold
+// Bir opsiyon seçeneğinin ne sıklıkla satıldığını temsil eder
+type ModifierUsageStat struct {
+	OptionID   uint   `json:"option_id"`
+	GroupName  string `json:"group_name"`
+	OptionName string `json:"option_name"`
+	Quantity   int64  `json:"quantity"` // Units of items sold with this option
+	Revenue    int64  `json:"revenue"`  // Sum of price deltas
+}
+
+// GetModifierUsage counts modifier options on completed orders within the date range
+// Tarih aralığındaki tamamlanan siparişlerde opsiyon kullanımını sayar
+func (s *AnalyticsService) GetModifierUsage(startDate, endDate time.Time) ([]ModifierUsageStat, error) {
+	var stats []ModifierUsageStat
+	err := s.db.Model(&models.OrderItemModifier{}).
+		Select("order_item_modifiers.option_id as option_id, order_item_modifiers.group_name as group_name, order_item_modifiers.option_name as option_name, "+
+			"COALESCE(sum(order_items.quantity), 0) as quantity, COALESCE(sum(order_items.quantity * order_item_modifiers.price_delta), 0) as revenue").
+		Joins("JOIN order_items ON order_items.id = order_item_modifiers.order_item_id AND order_items.deleted_at IS NULL").
+		Joins("JOIN orders ON orders.id = order_items.order_id AND orders.deleted_at IS NULL").
+		Where("orders.status = ? AND orders.created_at >= ? AND orders.created_at < ?", "COMPLETED", startDate, endDate).
+		Group("order_item_modifiers.option_id, order_item_modifiers.group_name, order_item_modifiers.option_name").
+		Order("quantity desc").
+		Scan(&stats).Error
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// SalesBucket is the sales of one hour-of-day, day-of-week or both
+// Bir saat dilimi, haftanın günü veya ikisinin birlikte satışı
+type SalesBucket struct {
+	DayOfWeek     *int  `json:"day_of_week,omitempty"` // 0 = Sunday
+	Hour          *int  `json:"hour,omitempty"`        // 0-23, local time
+	Orders        int64 `json:"orders"`
+	Sales         int64 `json:"sales"`
+	AverageTicket int64 `json:"average_ticket"`
+}
+
+// SalesHeatmap is the sales of a date range by hour, by weekday and by both
+// Tarih aralığındaki satışların saate, haftanın gününe ve ikisine göre dağılımı
+type SalesHeatmap struct {
+	ByHour    []SalesBucket `json:"by_hour"`    // 24 buckets
+	ByWeekday []SalesBucket `json:"by_weekday"` // 7 buckets, Sunday first
+	Cells     []SalesBucket `json:"cells"`      // 7 x 24 buckets, weekday major
+}
+
+// WaiterPerformance is what a waiter sold and gave away within a date range
+// Bir garsonun tarih aralığında sattıkları ve yaptığı indirim/iptaller
+type WaiterPerformance struct {
+	WaiterID       uint   `json:"waiter_id"`
+	WaiterName     string `json:"waiter_name"`
+	Orders         int64  `json:"orders"`
+	Revenue        int64  `json:"revenue"` // Gross, before refunds
+	Refunds        int64  `json:"refunds"`
+	NetRevenue     int64  `json:"net_revenue"`
+	AverageTicket  int64  `json:"average_ticket"`
+	Discounts      int64  `json:"discounts"`       // Orders with a discount
+	DiscountAmount int64  `json:"discount_amount"` // Total discount given
+	Cancellations  int64  `json:"cancellations"`   // Cancelled orders
+}
+
+// GetSalesHeatmap buckets the completed orders of the date range by the local hour and weekday they were opened
+// Tarih aralığındaki tamamlanan siparişleri açıldıkları yerel saate ve haftanın gününe göre gruplar
+func (s *AnalyticsService) GetSalesHeatmap(startDate, endDate time.Time) (*SalesHeatmap, error) {
+	var orders []models.Order
+	err := s.db.Select("created_at, total_amount").
+		Where("status IN ? AND created_at >= ? AND created_at < ?", []string{"COMPLETED", "REFUNDED"}, startDate, endDate).
+		Find(&orders).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var cells [7][24]SalesBucket
+	for _, o := range orders {
+		t := o.CreatedAt.In(startDate.Location())
+		cell := &cells[t.Weekday()][t.Hour()]
+		cell.Orders++
+		cell.Sales += o.TotalAmount
+	}
+
+	heatmap := &SalesHeatmap{
+		ByHour:    make([]SalesBucket, 24),
+		ByWeekday: make([]SalesBucket, 7),
+		Cells:     make([]SalesBucket, 0, 7*24),
+	}
+	for day := 0; day < 7; day++ {
+		for hour := 0; hour < 24; hour++ {
+			cell := cells[day][hour]
+			heatmap.ByHour[hour].Orders += cell.Orders
+			heatmap.ByHour[hour].Sales += cell.Sales
+			heatmap.ByWeekday[day].Orders += cell.Orders
+			heatmap.ByWeekday[day].Sales += cell.Sales
+
+			d, h := day, hour
+			cell.DayOfWeek, cell.Hour = &d, &h
+			cell.AverageTicket = averageOf(cell.Sales, cell.Orders)
+			heatmap.Cells = append(heatmap.Cells, cell)
+		}
+	}
+	for hour := range heatmap.ByHour {
+		h := hour
+		heatmap.ByHour[hour].Hour = &h
+		heatmap.ByHour[hour].AverageTicket = averageOf(heatmap.ByHour[hour].Sales, heatmap.ByHour[hour].Orders)
+	}
+	for day := range heatmap.ByWeekday {
+		d := day
+		heatmap.ByWeekday[day].DayOfWeek = &d
+		heatmap.ByWeekday[day].AverageTicket = averageOf(heatmap.ByWeekday[day].Sales, heatmap.ByWeekday[day].Orders)
+	}
+	return heatmap, nil
+}
+
+// GetWaiterPerformance sums the orders opened in the date range per waiter, highest net revenue first.
+// Cancellations are orders deleted while still open.
+// Tarih aralığında açılan siparişleri garson bazında toplar, en yüksek net ciro önce.
+// İptaller açıkken silinen siparişlerdir.
+func (s *AnalyticsService) GetWaiterPerformance(startDate, endDate time.Time) ([]WaiterPerformance, error) {
+	var sales []WaiterPerformance
+	err := s.db.Model(&models.Order{}).
+		Select("waiter_id, count(*) as orders, COALESCE(sum(total_amount), 0) as revenue, COALESCE(sum(refunded_amount), 0) as refunds, "+
+			"COALESCE(sum(CASE WHEN discount_amount > 0 THEN 1 ELSE 0 END), 0) as discounts, COALESCE(sum(discount_amount), 0) as discount_amount").
+		Where("waiter_id IS NOT NULL AND status IN ? AND created_at >= ? AND created_at < ?", []string{"COMPLETED", "REFUNDED"}, startDate, endDate).
+		Group("waiter_id").
+		Scan(&sales).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var cancellations []struct {
+		WaiterID uint
+		Count    int64
+	}
+	err = s.db.Unscoped().Model(&models.Order{}).
+		Select("waiter_id, count(*) as count").
+		Where("waiter_id IS NOT NULL AND deleted_at IS NOT NULL AND created_at >= ? AND created_at < ?", startDate, endDate).
+		Group("waiter_id").
+		Scan(&cancellations).Error
+	if err != nil {
+		return nil, err
+	}
+
+	cancelled := make(map[uint]int64, len(cancellations))
+	for _, c := range cancellations {
+		cancelled[c.WaiterID] = c.Count
+	}
+	for i := range sales {
+		sales[i].Cancellations = cancelled[sales[i].WaiterID]
+		delete(cancelled, sales[i].WaiterID)
+	}
+	// Waiters who only cancelled in the range
+	// Aralıkta yalnızca iptal yapan garsonlar
+	for waiterID, count := range cancelled {
+		sales = append(sales, WaiterPerformance{WaiterID: waiterID, Cancellations: count})
+	}
+
+	ids := make([]uint, 0, len(sales))
+	for _, p := range sales {
+		ids = append(ids, p.WaiterID)
+	}
+	var users []models.User
+	if len(ids) > 0 {
+		if err := s.db.Unscoped().Select("id, name").Where("id IN ?", ids).Find(&users).Error; err != nil {
+			return nil, err
+		}
+	}
+	names := make(map[uint]string, len(users))
+	for _, u := range users {
+		names[u.ID] = u.Name
+	}
+
+	for i := range sales {
+		p := &sales[i]
+		p.WaiterName = names[p.WaiterID]
+		p.NetRevenue = p.Revenue - p.Refunds
+		p.AverageTicket = averageOf(p.Revenue, p.Orders)
+	}
+	sort.Slice(sales, func(i, j int) bool {
+		if sales[i].NetRevenue != sales[j].NetRevenue {
+			return sales[i].NetRevenue > sales[j].NetRevenue
+		}
+		return sales[i].WaiterID < sales[j].WaiterID
+	})
+	return sales, nil
+}
+
+// averageOf divides total by count rounding to the nearest kuruş, zero when there is nothing to divide
+// Toplamı adede böler ve en yakın kuruşa yuvarlar, bölünecek bir şey yoksa sıfırdır
+func averageOf(total, count int64) int64 {
+	if count == 0 {
+		return 0
+	}
+	return int64(math.Round(float64(total) / float64(count)))
+}
+
+// PromotionStat is what a single promotion gave away within a date range
+// Tek bir promosyonun tarih aralığında sağladığı indirim
+type PromotionStat struct {
+	PromotionID   uint   `json:"promotion_id"`
+	PromotionName string `json:"promotion_name"`
+	Type          string `json:"type"`
+	Orders        int64  `json:"orders"`       // Orders the promotion was applied to
+	Applications  int64  `json:"applications"` // Bundles, groups or units
+	Amount        int64  `json:"amount"`
+}
+
+// PromotionReport lists automatic promotions next to the manual discounts of the same orders
+// Otomatik promosyonları aynı siparişlerin manuel indirimleriyle yan yana listeler
+type PromotionReport struct {
+	Promotions      []PromotionStat `json:"promotions"`
+	PromotionAmount int64           `json:"promotion_amount"`
+	DiscountOrders  int64           `json:"discount_orders"` // Orders with a manual discount
+	DiscountAmount  int64           `json:"discount_amount"` // Manual discounts given
+}
+
+// GetPromotionReport sums the promotions and manual discounts of the completed orders opened in the date range
+// Tarih aralığında açılan tamamlanmış siparişlerin promosyon ve manuel indirimlerini toplar
+func (s *AnalyticsService) GetPromotionReport(startDate, endDate time.Time) (*PromotionReport, error) {
+	report := &PromotionReport{Promotions: []PromotionStat{}}
+	err := s.db.Model(&models.OrderPromotion{}).
+		Select("order_promotions.promotion_id as promotion_id, MAX(order_promotions.promotion_name) as promotion_name, "+
+			"MAX(order_promotions.type) as type, count(DISTINCT order_promotions.order_id) as orders, "+
+			"COALESCE(sum(order_promotions.applications), 0) as applications, COALESCE(sum(order_promotions.amount), 0) as amount").
+		Joins("JOIN orders ON orders.id = order_promotions.order_id AND orders.deleted_at IS NULL").
+		Where("orders.status IN ? AND orders.created_at >= ? AND orders.created_at < ?", []string{"COMPLETED", "REFUNDED"}, startDate, endDate).
+		Group("order_promotions.promotion_id").
+		Order("amount desc").
+		Scan(&report.Promotions).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range report.Promotions {
+		report.PromotionAmount += p.Amount
+	}
+
+	var discounts struct {
+		Orders int64
+		Amount int64
+	}
+	err = s.db.Model(&models.Order{}).
+		Select("COALESCE(sum(CASE WHEN discount_amount > 0 THEN 1 ELSE 0 END), 0) as orders, COALESCE(sum(discount_amount), 0) as amount").
+		Where("status IN ? AND created_at >= ? AND created_at < ?", []string{"COMPLETED", "REFUNDED"}, startDate, endDate).
+		Scan(&discounts).Error
+	if err != nil {
+		return nil, err
+	}
+	report.DiscountOrders = discounts.Orders
+	report.DiscountAmount = discounts.Amount
+	return report, nil
+}
+
+// GetCouponReport lists the coupon redemptions of the date range per coupon and one by one, with their cost
+// Tarih aralığındaki kupon kullanımlarını kupon bazında ve tek tek, maliyetleriyle listeler
+func (s *AnalyticsService) GetCouponReport(startDate, endDate time.Time) (*models.CouponReport, error) {
+	report := &models.CouponReport{Coupons: []models.CouponStat{}}
+	err := s.db.Model(&models.CouponRedemption{}).
+		Select("coupon_id, MAX(coupon_code) as coupon_code, count(*) as redemptions, "+
+			"count(DISTINCT customer_id) as customers, COALESCE(sum(amount), 0) as amount").
+		Where("created_at >= ? AND created_at < ?", startDate, endDate).
+		Group("coupon_id").
+		Order("amount desc").
+		Scan(&report.Coupons).Error
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.db.Where("created_at >= ? AND created_at < ?", startDate, endDate).
+		Order("created_at asc, id asc").
+		Find(&report.Redemptions).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, c := range report.Coupons {
+		report.TotalCount += c.Redemptions
+		report.TotalAmount += c.Amount
+	}
+	return report, nil
+}
+
+// GetProductRanking lists the top or bottom sellers of the menu within the date range.
+// Stats come from closed work periods; the active period is not included.
+// Tarih aralığında menünün en çok veya en az satan ürünlerini listeler.
+// İstatistikler kapanmış dönemlerden gelir; aktif dönem dahil değildir.
+func (s *AnalyticsService) GetProductRanking(startDate, endDate time.Time, rank, metric string, limit int) ([]models.ProductSalesSummary, error) {
+	if rank != models.ProductRankTop && rank != models.ProductRankBottom {
+		return nil, errors.New("rank must be top or bottom")
+	}
+	if metric != models.ProductMetricQuantity && metric != models.ProductMetricRevenue {
+		return nil, errors.New("metric must be quantity or revenue")
+	}
+	if limit <= 0 || limit > 100 {
+		return nil, errors.New("limit must be between 1 and 100")
+	}
+	return s.productStatRepo.RankProducts(statDate(startDate), statDate(endDate), rank, metric, limit)
+}
+
+// GetCategoryMix returns the share of each category in the units and net revenue of the date range
+// Her kategorinin tarih aralığındaki satış adedi ve net cirodaki payını döndürür
+func (s *AnalyticsService) GetCategoryMix(startDate, endDate time.Time) ([]models.CategorySalesMix, error) {
+	mix, err := s.productStatRepo.CategoryMix(statDate(startDate), statDate(endDate))
+	if err != nil {
+		return nil, err
+	}
+
+	var quantity, revenue int64
+	for _, m := range mix {
+		quantity += m.QuantitySold
+		revenue += m.NetRevenue
+	}
+	for i := range mix {
+		mix[i].QuantityShare = percentOf(mix[i].QuantitySold, quantity)
+		mix[i].RevenueShare = percentOf(mix[i].NetRevenue, revenue)
+	}
+	return mix, nil
+}
+
+// GetProductTrend returns the daily sales of a product in the date range, days without sales as zero
+// Bir ürünün tarih aralığındaki günlük satışlarını döndürür, satış olmayan günler sıfırdır
+func (s *AnalyticsService) GetProductTrend(productID uint, startDate, endDate time.Time) ([]models.ProductTrendPoint, error) {
+	points, err := s.productStatRepo.ProductTrend(productID, statDate(startDate), statDate(endDate))
+	if err != nil {
+		return nil, err
+	}
+
+	byDate := make(map[string]models.ProductTrendPoint, len(points))
+	for _, p := range points {
+		byDate[p.ReportDate] = p
+	}
+	trend := []models.ProductTrendPoint{}
+	for day := startDate; day.Before(endDate); day = day.AddDate(0, 0, 1) {
+		date := statDate(day)
+		point, ok := byDate[date]
+		if !ok {
+			point = models.ProductTrendPoint{ReportDate: date}
+		}
+		trend = append(trend, point)
+	}
+	return trend, nil
+}
+
+// statDate formats a time as the report date key of the stats
+// Zamanı istatistiklerin rapor tarihi anahtarı olarak biçimlendirir
+func statDate(t time.Time) string {
+	return t.Format("2006-01-02")
+}
+
+// percentOf returns part as a percentage of total rounded to one decimal
+// Parçanın toplamdaki yüzdesini bir ondalığa yuvarlanmış olarak döndürür
+func percentOf(part, total int64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return math.Round(float64(part)*1000/float64(total)) / 10
+}
+
+// GetReportHistory fetches a page of past work periods (history), newest first, with the number of closed periods
+// Geçmiş çalışma dönemlerinden bir sayfayı yeniden eskiye getirir, kapanmış dönem sayısıyla birlikte
+func (s *AnalyticsService) GetReportHistory(page, limit int) ([]models.WorkPeriod, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+
+	var total int64
+	if err := s.db.Model(&models.WorkPeriod{}).Where("is_active = ?", false).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
 	var periods []models.WorkPeriod
 	// Fetch closed periods, newest first
-	if err := s.db.Where("is_active = ?", false).Order("end_time desc").Limit(50).Find(&periods).Error; err != nil {
+	err := s.db.Where("is_active = ?", false).
+		Order("end_time desc").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&periods).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return periods, total, nil
+}
+
+// Range report groupings
+// Aralık raporu gruplamaları
+const (
+	ReportGroupDay   = "day"
+	ReportGroupWeek  = "week" // Weeks start on Monday
+	ReportGroupMonth = "month"
+)
+
+// Range report comparisons
+// Aralık raporu karşılaştırmaları
+const (
+	ReportCompareNone     = "none"
+	ReportComparePrevious = "previous"  // Range of the same length right before
+	ReportCompareLastYear = "last_year" // Same dates one year earlier
+)
+
+// RangeReport aggregates the work periods started within a date range, per bucket and in total
+// Tarih aralığında başlayan çalışma dönemlerini grup bazında ve toplamda birleştirir
+type RangeReport struct {
+	StartDate  string               `json:"start_date"`
+	EndDate    string               `json:"end_date"` // Inclusive
+	GroupBy    string               `json:"group_by"`
+	Totals     models.DailyReport   `json:"totals"`
+	Buckets    []models.DailyReport `json:"buckets"` // ReportDate is the first day of the bucket
+	Comparison *RangeComparison     `json:"comparison,omitempty"`
+}
+
+// RangeComparison holds the totals of the compared range and the change of the key metrics
+// Karşılaştırılan aralığın toplamlarını ve temel ölçütlerdeki değişimi tutar
+type RangeComparison struct {
+	Mode      string                 `json:"mode"`
+	StartDate string                 `json:"start_date"`
+	EndDate   string                 `json:"end_date"` // Inclusive
+	Totals    models.DailyReport     `json:"totals"`
+	Deltas    map[string]MetricDelta `json:"deltas"`
+}
+
+// MetricDelta is the change of a metric against the compared range
+// Bir ölçütün karşılaştırılan aralığa göre değişimi
+type MetricDelta struct {
+	Current       int64    `json:"current"`
+	Previous      int64    `json:"previous"`
+	Change        int64    `json:"change"`
+	ChangePercent *float64 `json:"change_percent"` // nil when the previous value is zero
+}
+
+// GetRangeReport aggregates the work periods started between startDate and endDate (exclusive),
+// grouped by day, week or month, optionally compared with an earlier range
+// startDate ile endDate (hariç) arasında başlayan çalışma dönemlerini gün, hafta veya aya göre
+// gruplayarak birleştirir, isteğe bağlı olarak önceki bir aralıkla karşılaştırır
+func (s *AnalyticsService) GetRangeReport(startDate, endDate time.Time, groupBy, compare string) (*RangeReport, error) {
+	switch groupBy {
+	case ReportGroupDay:
+		if endDate.Sub(startDate) > 366*24*time.Hour {
+			return nil, errors.New("daily grouping is limited to one year, group by week or month")
+		}
+	case ReportGroupWeek, ReportGroupMonth:
+		if endDate.Sub(startDate) > 3*366*24*time.Hour {
+			return nil, errors.New("date range cannot exceed three years")
+		}
+	default:
+		return nil, errors.New("group_by must be day, week or month")
+	}
+
+	report := &RangeReport{
+		StartDate: startDate.Format("2006-01-02"),
+		EndDate:   endDate.AddDate(0, 0, -1).Format("2006-01-02"),
+		GroupBy:   groupBy,
+		Buckets:   []models.DailyReport{},
+	}
+
+	periods, err := s.periodsBetween(startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	// Bucket the periods by the day they started
+	// Dönemleri başladıkları güne göre grupla
+	byBucket := make(map[string][]uint)
+	var allIDs []uint
+	for _, p := range periods {
+		key := bucketStart(p.StartTime.In(startDate.Location()), groupBy).Format("2006-01-02")
+		byBucket[key] = append(byBucket[key], p.ID)
+		allIDs = append(allIDs, p.ID)
+	}
+	for day := bucketStart(startDate, groupBy); day.Before(endDate); day = nextBucket(day, groupBy) {
+		bucket := models.DailyReport{ReportDate: day.Format("2006-01-02"), UpdatedAt: time.Now()}
+		if ids := byBucket[bucket.ReportDate]; len(ids) > 0 {
+			if err := s.aggregatePeriods(&bucket, ids); err != nil {
+				return nil, err
+			}
+		}
+		report.Buckets = append(report.Buckets, bucket)
+	}
+
+	if report.Totals, err = s.rangeTotals(report.StartDate+" - "+report.EndDate, allIDs); err != nil {
+		return nil, err
+	}
+
+	var compareStart, compareEnd time.Time
+	switch compare {
+	case "", ReportCompareNone:
+		return report, nil
+	case ReportComparePrevious:
+		days := int(math.Round(endDate.Sub(startDate).Hours() / 24))
+		compareStart, compareEnd = startDate.AddDate(0, 0, -days), startDate
+	case ReportCompareLastYear:
+		compareStart, compareEnd = startDate.AddDate(-1, 0, 0), endDate.AddDate(-1, 0, 0)
+	default:
+		return nil, errors.New("compare must be none, previous or last_year")
+	}
+
+	previousPeriods, err := s.periodsBetween(compareStart, compareEnd)
+	if err != nil {
 		return nil, err
 	}
-	return periods, nil
+	var previousIDs []uint
+	for _, p := range previousPeriods {
+		previousIDs = append(previousIDs, p.ID)
+	}
+
+	comparison := &RangeComparison{
+		Mode:      compare,
+		StartDate: compareStart.Format("2006-01-02"),
+		EndDate:   compareEnd.AddDate(0, 0, -1).Format("2006-01-02"),
+	}
+	if comparison.Totals, err = s.rangeTotals(comparison.StartDate+" - "+comparison.EndDate, previousIDs); err != nil {
+		return nil, err
+	}
+	current, previous := report.Totals, comparison.Totals
+	comparison.Deltas = map[string]MetricDelta{
+		"total_orders":   metricDelta(int64(current.TotalOrders), int64(previous.TotalOrders)),
+		"total_sales":    metricDelta(current.TotalSales, previous.TotalSales),
+		"total_refunds":  metricDelta(current.TotalRefunds, previous.TotalRefunds),
+		"net_sales":      metricDelta(current.NetSales, previous.NetSales),
+		"total_expenses": metricDelta(current.TotalExpenses, previous.TotalExpenses),
+		"net_profit":     metricDelta(current.NetProfit, previous.NetProfit),
+		"total_tax":      metricDelta(current.TotalTax, previous.TotalTax),
+		"average_ticket": metricDelta(averageOf(current.TotalSales, int64(current.TotalOrders)), averageOf(previous.TotalSales, int64(previous.TotalOrders))),
+	}
+	report.Comparison = comparison
+	return report, nil
+}
+
+// periodsBetween returns the work periods started within [start, end)
+// [start, end) aralığında başlayan çalışma dönemlerini döndürür
+func (s *AnalyticsService) periodsBetween(start, end time.Time) ([]models.WorkPeriod, error) {
+	periods, err := s.workPeriodRepo.GetPeriodsBetweenDates(start, end)
+	if err != nil {
+		return nil, err
+	}
+	inRange := periods[:0]
+	for _, p := range periods {
+		if p.StartTime.Before(end) {
+			inRange = append(inRange, p)
+		}
+	}
+	return inRange, nil
+}
+
+// rangeTotals aggregates the periods into a single report labelled with the range
+// Dönemleri aralık etiketli tek bir raporda birleştirir
+func (s *AnalyticsService) rangeTotals(label string, periodIDs []uint) (models.DailyReport, error) {
+	totals := models.DailyReport{ReportDate: label, UpdatedAt: time.Now()}
+	if len(periodIDs) == 0 {
+		return totals, nil
+	}
+	err := s.aggregatePeriods(&totals, periodIDs)
+	return totals, err
+}
+
+// bucketStart returns the first day of the day, week (Monday) or month containing t
+// t'yi içeren günün, haftanın (pazartesi) veya ayın ilk gününü döndürür
+func bucketStart(t time.Time, groupBy string) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	switch groupBy {
+	case ReportGroupWeek:
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	case ReportGroupMonth:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+	default:
+		return day
+	}
+}
+
+// nextBucket returns the first day of the bucket after the one starting at t
+// t'de başlayan gruptan sonraki grubun ilk gününü döndürür
+func nextBucket(t time.Time, groupBy string) time.Time {
+	switch groupBy {
+	case ReportGroupWeek:
+		return t.AddDate(0, 0, 7)
+	case ReportGroupMonth:
+		return t.AddDate(0, 1, 0)
+	default:
+		return t.AddDate(0, 0, 1)
+	}
+}
+
+// metricDelta compares a metric with its value in the compared range
+// Bir ölçütü karşılaştırılan aralıktaki değeriyle karşılaştırır
+func metricDelta(current, previous int64) MetricDelta {
+	delta := MetricDelta{Current: current, Previous: previous, Change: current - previous}
+	if previous != 0 {
+		percent := math.Round(float64(delta.Change)*1000/math.Abs(float64(previous))) / 10
+		delta.ChangePercent = &percent
+	}
+	return delta
 }
 
 // GetDailyReport generates a report for the given date
@@ -51,23 +641,31 @@ func (s *AnalyticsService) GetDailyReport(dateStr string, scope string) (*models
 		}
 
 		// Calculate payment stats (not stored on WorkPeriod)
-		var cashSales float64
-		s.db.Model(&models.Order{}).Where("work_period_id = ? AND payment_method = ?", period.ID, "CASH").Select("COALESCE(sum(total_amount), 0)").Scan(&cashSales)
-
-		var posSales float64
-		s.db.Model(&models.Order{}).Where("work_period_id = ? AND payment_method = ?", period.ID, "CREDIT_CARD").Select("COALESCE(sum(total_amount), 0)").Scan(&posSales)
+		paymentTotals, err := s.paymentRepo.SumByMethod([]uint{period.ID})
+		if err != nil {
+			return nil, err
+		}
 
 		// Return report derived strictly from WorkPeriod stats (plus calculated payment split)
-		return &models.DailyReport{
+		report := &models.DailyReport{
 			ReportDate:    period.StartTime.Format("2006-01-02 15:04"), // Precise time for display
 			TotalOrders:   period.TotalOrders,
 			TotalSales:    period.TotalSales,
+			TotalRefunds:  period.TotalRefunds,
+			NetSales:      period.TotalSales - period.TotalRefunds,
 			TotalExpenses: period.TotalExpenses,
 			NetProfit:     period.NetProfit,
-			CashSales:     int64(cashSales),
-			PosSales:      int64(posSales),
+			CashSales:     paymentTotals[models.PaymentMethodCash],
+			PosSales:      paymentTotals[models.PaymentMethodCreditCard],
 			UpdatedAt:     time.Now(),
-		}, nil
+		}
+		if err := fillTaxBreakdown(s.orderRepo, report, []uint{period.ID}); err != nil {
+			return nil, err
+		}
+		if report.CashDrawer, err = buildCashDrawer(s.workPeriodRepo, s.paymentRepo, s.transactionRepo, period); err != nil {
+			return nil, err
+		}
+		return report, nil
 	}
 
 	// Handle Active Period request
@@ -90,25 +688,34 @@ func (s *AnalyticsService) GetDailyReport(dateStr string, scope string) (*models
 		var totalSales float64
 		s.db.Model(&models.Order{}).Where("work_period_id = ?", period.ID).Select("COALESCE(sum(total_amount), 0)").Scan(&totalSales)
 
-		var cashSales float64
-		s.db.Model(&models.Order{}).Where("work_period_id = ? AND payment_method = ?", period.ID, "CASH").Select("COALESCE(sum(total_amount), 0)").Scan(&cashSales)
-
-		var posSales float64
-		s.db.Model(&models.Order{}).Where("work_period_id = ? AND payment_method = ?", period.ID, "CREDIT_CARD").Select("COALESCE(sum(total_amount), 0)").Scan(&posSales)
+		paymentTotals, err := s.paymentRepo.SumByMethod([]uint{period.ID})
+		if err != nil {
+			return nil, err
+		}
 
 		var totalExpenses float64
 		s.db.Model(&models.Transaction{}).Where("work_period_id = ? AND type = ?", period.ID, "EXPENSE").Select("COALESCE(sum(amount), 0)").Scan(&totalExpenses)
 
-		return &models.DailyReport{
+		report := &models.DailyReport{
 			ReportDate:    period.StartTime.Format("2006-01-02 15:04"),
 			TotalOrders:   int(totalOrders),
 			TotalSales:    int64(totalSales),
 			TotalExpenses: int64(totalExpenses),
 			NetProfit:     int64(totalSales - totalExpenses),
-			CashSales:     int64(cashSales),
-			PosSales:      int64(posSales),
+			CashSales:     paymentTotals[models.PaymentMethodCash],
+			PosSales:      paymentTotals[models.PaymentMethodCreditCard],
 			UpdatedAt:     time.Now(),
-		}, nil
+		}
+		if err := fillRefunds(s.transactionRepo, report, []uint{period.ID}); err != nil {
+			return nil, err
+		}
+		if err := fillTaxBreakdown(s.orderRepo, report, []uint{period.ID}); err != nil {
+			return nil, err
+		}
+		if report.CashDrawer, err = buildCashDrawer(s.workPeriodRepo, s.paymentRepo, s.transactionRepo, period); err != nil {
+			return nil, err
+		}
+		return report, nil
 	}
 
 	parsedDate, err := time.Parse("2006-01-02", dateStr)
@@ -152,39 +759,56 @@ func (s *AnalyticsService) GetDailyReport(dateStr string, scope string) (*models
 		return &report, nil
 	}
 
-	// 1. Total Completed Orders Count
-	// Toplam Tamamlanan Sipariş Sayısı
+	if err := s.aggregatePeriods(&report, periodIDs); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// aggregatePeriods fills the report with the sales, payments, expenses, refunds and KDV of the work periods
+// Raporu çalışma dönemlerinin satış, ödeme, gider, iade ve KDV toplamlarıyla doldurur
+func (s *AnalyticsService) aggregatePeriods(report *models.DailyReport, periodIDs []uint) error {
+	// 1. Total Completed Orders Count (refunded orders were sales too)
+	// Toplam Tamamlanan Sipariş Sayısı (iade edilen siparişler de satıştır)
 	var totalOrders int64
 	s.db.Model(&models.Order{}).
-		Where("status = ? AND work_period_id IN ?", "COMPLETED", periodIDs).
+		Where("status IN ? AND work_period_id IN ?", []string{"COMPLETED", "REFUNDED"}, periodIDs).
 		Count(&totalOrders)
 	report.TotalOrders = int(totalOrders)
 
-	// 2. Total Sales & Breakdown (Cash/POS)
-	// Toplam Satış ve Dağılım
-	type PaymentStat struct {
-		Method string
-		Total  int64
-	}
-	var paymentStats []PaymentStat
+	// 2. Gross Sales
+	// Brüt Satış
+	s.db.Model(&models.Order{}).
+		Where("status IN ? AND work_period_id IN ?", []string{"COMPLETED", "REFUNDED"}, periodIDs).
+		Select("COALESCE(sum(total_amount), 0)").
+		Scan(&report.TotalSales)
 
+	// Promotions, coupons and manual discounts, reported apart
+	// Promosyonlar, kuponlar ve manuel indirimler, ayrı raporlanır
+	var reductions struct {
+		Promotions int64
+		Coupons    int64
+		Discounts  int64
+	}
 	s.db.Model(&models.Order{}).
-		Select("payment_method as method, sum(total_amount) as total").
-		Where("status = ? AND work_period_id IN ?", "COMPLETED", periodIDs).
-		Group("payment_method").
-		Scan(&paymentStats)
+		Where("status IN ? AND work_period_id IN ?", []string{"COMPLETED", "REFUNDED"}, periodIDs).
+		Select("COALESCE(sum(promotion_amount), 0) as promotions, COALESCE(sum(coupon_amount), 0) as coupons, " +
+			"COALESCE(sum(discount_amount), 0) as discounts").
+		Scan(&reductions)
+	report.TotalPromotions = reductions.Promotions
+	report.TotalCoupons = reductions.Coupons
+	report.TotalDiscounts = reductions.Discounts
 
-	for _, stat := range paymentStats {
-		report.TotalSales += stat.Total
-		switch stat.Method {
-		case "CASH":
-			report.CashSales += stat.Total
-		case "CREDIT_CARD":
-			report.PosSales += stat.Total
-		}
+	// 3. Cash/POS Breakdown (from payments, split bills included)
+	// Nakit/POS Dağılımı (ödemelerden, bölünmüş hesaplar dahil)
+	paymentTotals, err := s.paymentRepo.SumByMethod(periodIDs)
+	if err != nil {
+		return err
 	}
+	report.CashSales = paymentTotals[models.PaymentMethodCash]
+	report.PosSales = paymentTotals[models.PaymentMethodCreditCard]
 
-	// 3. Total Expenses
+	// 4. Total Expenses
 	// Toplam Giderler
 	var totalExpenses int64
 	s.db.Model(&models.Transaction{}).
@@ -193,9 +817,48 @@ func (s *AnalyticsService) GetDailyReport(dateStr string, scope string) (*models
 		Scan(&totalExpenses)
 	report.TotalExpenses = totalExpenses
 
-	// 4. Net Profit
-	// Net Kar
-	report.NetProfit = report.TotalSales - report.TotalExpenses
+	// 5. Refunds, Net Sales and Net Profit
+	// İadeler, Net Satış ve Net Kar
+	if err := fillRefunds(s.transactionRepo, report, periodIDs); err != nil {
+		return err
+	}
+
+	// 6. KDV per rate
+	// Orana göre KDV
+	if err := fillTaxBreakdown(s.orderRepo, report, periodIDs); err != nil {
+		return err
+	}
 
-	return &report, nil
+	return nil
+}
+
+// fillRefunds attaches the refunds given in the periods and derives net sales and net profit from them
+// Dönemlerde verilen iadeleri rapora ekler, net satış ve net karı bunlardan hesaplar
+func fillRefunds(txRepo repositories.TransactionRepository, report *models.DailyReport, periodIDs []uint) error {
+	refunds, err := txRepo.SumRefundsByMethod(periodIDs)
+	if err != nil {
+		return err
+	}
+	report.TotalRefunds = 0
+	for _, amount := range refunds {
+		report.TotalRefunds += amount
+	}
+	report.NetSales = report.TotalSales - report.TotalRefunds
+	report.NetProfit = report.NetSales - report.TotalExpenses
+	return nil
+}
+
+// fillTaxBreakdown attaches the per rate KDV totals of the given periods to the report
+// Verilen dönemlerin oran bazlı KDV toplamlarını rapora ekler
+func fillTaxBreakdown(orderRepo repositories.OrderRepository, report *models.DailyReport, periodIDs []uint) error {
+	totals, err := orderRepo.SumTaxByRate(periodIDs)
+	if err != nil {
+		return err
+	}
+	report.TaxBreakdown = totals
+	report.TotalTax = 0
+	for _, t := range totals {
+		report.TotalTax += t.TaxAmount
+	}
+	return nil
 }
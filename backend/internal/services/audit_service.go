@@ -0,0 +1,64 @@
+package services
+
+import (
+	"encoding/json"
+	"simple-pos/internal/models"
+	"simple-pos/internal/repositories"
+
+	"gorm.io/gorm"
+)
+
+// maxAuditResults caps a single audit log query
+// Tek bir denetim kaydı sorgusunu sınırlar
+const maxAuditResults = 500
+
+type AuditService struct {
+	repo repositories.AuditRepository
+}
+
+func NewAuditService(repo repositories.AuditRepository) *AuditService {
+	return &AuditService{repo: repo}
+}
+
+// Record appends an audit entry inside the transaction of the audited change,
+// so the entry and the change are committed or rolled back together.
+// Denetim kaydını denetlenen değişikliğin işlemi içinde ekler,
+// böylece kayıt ve değişiklik birlikte onaylanır veya geri alınır.
+func (s *AuditService) Record(tx *gorm.DB, userID uint, action, entityType string, entityID uint, before, after interface{}, reason string) error {
+	beforeJSON, err := auditSnapshot(before)
+	if err != nil {
+		return err
+	}
+	afterJSON, err := auditSnapshot(after)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.CreateWithTx(tx, &models.AuditLog{
+		UserID:     userID,
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Before:     beforeJSON,
+		After:      afterJSON,
+		Reason:     reason,
+	})
+}
+
+// List returns audit entries matching the filter, newest first
+// Filtreye uyan denetim kayıtlarını yeniden eskiye döndürür
+func (s *AuditService) List(filter models.AuditFilter) ([]models.AuditLog, error) {
+	if filter.Limit <= 0 || filter.Limit > maxAuditResults {
+		filter.Limit = maxAuditResults
+	}
+	return s.repo.Find(filter)
+}
+
+// auditSnapshot marshals a snapshot, nil stays empty
+// Snapshot'ı JSON'a çevirir, nil boş kalır
+func auditSnapshot(v interface{}) (json.RawMessage, error) {
+	if v == nil {
+		return nil, nil
+	}
+	return json.Marshal(v)
+}
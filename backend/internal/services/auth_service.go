@@ -12,11 +12,13 @@ import (
 
 type AuthService struct {
 	userRepo repositories.UserRepository
+	roles    *RoleService
 }
 
-func NewAuthService(userRepo repositories.UserRepository) *AuthService {
+func NewAuthService(userRepo repositories.UserRepository, roles *RoleService) *AuthService {
 	return &AuthService{
 		userRepo: userRepo,
+		roles:    roles,
 	}
 }
 
@@ -41,8 +43,14 @@ func (s *AuthService) Login(username string, password string) (user *models.User
 		return nil, "", errors.New("invalid credentials")
 	}
 
-	// 3. Generate JWT
-	token, err = utils.GenerateToken(user.ID, user.Role)
+	// 3. Generate JWT carrying the permissions for the UI, requests resolve them from the role
+	// Arayüz için yetkileri taşıyan JWT üret, istekler yetkileri rolden çözer
+	permissions, err := s.roles.Permissions(user.Role)
+	if err != nil {
+		logger.Warn("Login failed: Unknown role", logger.String("username", username), logger.String("role", user.Role))
+		return nil, "", errors.New("invalid credentials")
+	}
+	token, err = utils.GenerateToken(user.ID, user.Role, permissions)
 	if err != nil {
 		logger.Warn("Login failed: Token generation failed", logger.String("username", username))
 		return nil, "", errors.New("token generation failed")
@@ -55,3 +63,28 @@ func (s *AuthService) Login(username string, password string) (user *models.User
 func (s *AuthService) GetUser(id uint) (*models.User, error) {
 	return s.userRepo.FindByID(id)
 }
+
+// GetPermissions returns the current effective permissions of a user
+// Kullanıcının güncel geçerli yetkilerini döndürür
+func (s *AuthService) GetPermissions(user *models.User) ([]string, error) {
+	return s.roles.Permissions(user.Role)
+}
+
+// Access returns the current role and permissions of a signed in user.
+// Deleted or deactivated users lose access right away, their tokens stop working.
+// Oturum açmış kullanıcının güncel rolünü ve yetkilerini döndürür.
+// Silinen veya pasifleştirilen kullanıcılar erişimi hemen kaybeder, tokenları çalışmaz.
+func (s *AuthService) Access(userID uint) (string, []string, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return "", nil, errors.New("user not found")
+	}
+	if !user.IsActive {
+		return "", nil, errors.New("account is disabled")
+	}
+	permissions, err := s.roles.Permissions(user.Role)
+	if err != nil {
+		permissions = []string{}
+	}
+	return user.Role, permissions, nil
+}
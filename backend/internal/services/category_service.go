@@ -15,13 +15,15 @@ func NewCategoryService(repo repositories.CategoryRepository) *CategoryService {
 
 // CreateCategory creates a new category
 // Yeni bir kategori oluşturur
-func (s *CategoryService) CreateCategory(name, icon, color string, sortOrder int) (*models.Category, error) {
+func (s *CategoryService) CreateCategory(name, icon, color string, sortOrder int, stationID *uint, taxRate *int) (*models.Category, error) {
 	category := &models.Category{
 		Name:      name,
 		Icon:      icon,
 		Color:     color,
 		SortOrder: sortOrder,
 		IsActive:  true,
+		StationID: stationID,
+		TaxRate:   taxRate,
 	}
 	if err := s.repo.Create(category); err != nil {
 		return nil, err
@@ -37,7 +39,7 @@ func (s *CategoryService) GetCategories() ([]models.Category, error) {
 
 // UpdateCategory updates an existing category
 // Mevcut bir kategoriyi günceller
-func (s *CategoryService) UpdateCategory(id uint, name, icon, color string, sortOrder int) (*models.Category, error) {
+func (s *CategoryService) UpdateCategory(id uint, name, icon, color string, sortOrder int, stationID *uint, taxRate *int) (*models.Category, error) {
 	category, err := s.repo.FindByID(id)
 	if err != nil {
 		return nil, err
@@ -47,6 +49,8 @@ func (s *CategoryService) UpdateCategory(id uint, name, icon, color string, sort
 	category.Icon = icon
 	category.Color = color
 	category.SortOrder = sortOrder
+	category.StationID = stationID
+	category.TaxRate = taxRate
 
 	if err := s.repo.Update(category); err != nil {
 		return nil, err
@@ -0,0 +1,156 @@
+package services
+
+import (
+	"errors"
+	"simple-pos/internal/models"
+	"simple-pos/internal/repositories"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Coupon errors
+// Kupon hataları
+var (
+	ErrCouponNotFound         = errors.New("coupon not found")
+	ErrCouponInactive         = errors.New("coupon is not active")
+	ErrCouponExpired          = errors.New("coupon has expired")
+	ErrCouponUsedUp           = errors.New("coupon has reached its usage limit")
+	ErrCouponMinSpend         = errors.New("order does not reach the coupon's minimum spend")
+	ErrCouponCustomerRequired = errors.New("coupon is limited per customer, link a customer to the order first")
+	ErrCouponCustomerLimit    = errors.New("customer has reached the coupon's limit")
+	ErrCouponCodeTaken        = errors.New("coupon code already exists")
+)
+
+// CouponInput describes a coupon
+// Bir kuponu tanımlar
+type CouponInput struct {
+	Code             string
+	Description      string
+	DiscountType     string
+	Value            int64
+	MinSpend         int64
+	UsageLimit       int
+	PerCustomerLimit int
+	ExpiresAt        *time.Time
+	IsActive         bool
+}
+
+type CouponService struct {
+	repo repositories.CouponRepository
+}
+
+func NewCouponService(repo repositories.CouponRepository) *CouponService {
+	return &CouponService{repo: repo}
+}
+
+// ListCoupons returns the coupons, newest first
+// Kuponları en yeniden başlayarak döndürür
+func (s *CouponService) ListCoupons(includeInactive bool) ([]models.Coupon, error) {
+	return s.repo.FindAll(includeInactive)
+}
+
+// GetCoupon returns a coupon with its usage count
+// Kuponu kullanım sayısıyla döndürür
+func (s *CouponService) GetCoupon(id uint) (*models.Coupon, error) {
+	coupon, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, ErrCouponNotFound
+	}
+	return coupon, nil
+}
+
+// CreateCoupon issues a new coupon code
+// Yeni kupon kodu oluşturur
+func (s *CouponService) CreateCoupon(input CouponInput) (*models.Coupon, error) {
+	coupon := &models.Coupon{}
+	if err := applyCoupon(coupon, input); err != nil {
+		return nil, err
+	}
+	if _, err := s.repo.FindByCode(coupon.Code); err == nil {
+		return nil, ErrCouponCodeTaken
+	}
+	if err := s.repo.Create(coupon); err != nil {
+		return nil, ErrCouponCodeTaken
+	}
+	return coupon, nil
+}
+
+// UpdateCoupon changes a coupon, redemptions made so far are kept
+// Kuponu değiştirir, şimdiye kadarki kullanımlar korunur
+func (s *CouponService) UpdateCoupon(id uint, input CouponInput) (*models.Coupon, error) {
+	coupon, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, ErrCouponNotFound
+	}
+	if err := applyCoupon(coupon, input); err != nil {
+		return nil, err
+	}
+	if existing, err := s.repo.FindByCode(coupon.Code); err == nil && existing.ID != coupon.ID {
+		return nil, ErrCouponCodeTaken
+	}
+	if err := s.repo.Update(coupon); err != nil {
+		return nil, ErrCouponCodeTaken
+	}
+	return coupon, nil
+}
+
+// DeleteCoupon withdraws a coupon, its redemptions stay in the reports
+// Kuponu geri çeker, kullanımları raporlarda kalır
+func (s *CouponService) DeleteCoupon(id uint) error {
+	if _, err := s.repo.FindByID(id); err != nil {
+		return ErrCouponNotFound
+	}
+	return s.repo.Delete(id)
+}
+
+// ReleaseRefundWithTx is the refund hook of coupons: a fully refunded order gives its coupon use back
+// Kuponların iade hook'u: tamamen iade edilen sipariş kupon kullanımını geri verir
+func (s *CouponService) ReleaseRefundWithTx(tx *gorm.DB, order *models.Order, refund *models.Refund, userID uint) error {
+	if order.Status != "REFUNDED" {
+		return nil
+	}
+	return s.repo.DeleteRedemptionWithTx(tx, order.ID)
+}
+
+// applyCoupon validates the input and copies it onto the coupon
+// Girdiyi doğrular ve kupona kopyalar
+func applyCoupon(coupon *models.Coupon, input CouponInput) error {
+	code := normalizeCouponCode(input.Code)
+	if code == "" {
+		return errors.New("coupon code is required")
+	}
+	switch input.DiscountType {
+	case "AMOUNT":
+		if input.Value <= 0 {
+			return errors.New("coupon value must be positive")
+		}
+	case "PERCENTAGE":
+		if input.Value <= 0 || input.Value > 100 {
+			return errors.New("percentage must be between 1 and 100")
+		}
+	default:
+		return errors.New("invalid coupon type")
+	}
+	if input.MinSpend < 0 || input.UsageLimit < 0 || input.PerCustomerLimit < 0 {
+		return errors.New("coupon limits cannot be negative")
+	}
+
+	coupon.Code = code
+	coupon.Description = strings.TrimSpace(input.Description)
+	coupon.DiscountType = input.DiscountType
+	coupon.Value = input.Value
+	coupon.MinSpend = input.MinSpend
+	coupon.UsageLimit = input.UsageLimit
+	coupon.PerCustomerLimit = input.PerCustomerLimit
+	coupon.ExpiresAt = input.ExpiresAt
+	coupon.IsActive = input.IsActive
+	return nil
+}
+
+// normalizeCouponCode makes codes case insensitive, waiters type them by hand
+// Kodları büyük/küçük harf duyarsız yapar, garsonlar elle yazar
+func normalizeCouponCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
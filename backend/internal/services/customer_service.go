@@ -0,0 +1,329 @@
+package services
+
+import (
+	"errors"
+	"simple-pos/internal/models"
+	"simple-pos/internal/repositories"
+	"simple-pos/pkg/logger"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Customer account errors
+// Müşteri hesabı hataları
+var (
+	ErrCustomerNotFound      = errors.New("customer not found")
+	ErrCustomerInactive      = errors.New("customer account is inactive")
+	ErrCustomerRequired      = errors.New("customer is required for on account payments")
+	ErrCreditLimitExceeded   = errors.New("charge exceeds the customer's credit limit")
+	ErrCustomerHasBalance    = errors.New("customer still has an open balance")
+	ErrPaymentExceedsBalance = errors.New("payment exceeds the customer's balance")
+	ErrCustomerPhoneTaken    = errors.New("phone number belongs to another customer")
+)
+
+// CustomerInput describes the editable details of a customer account
+// Müşteri hesabının düzenlenebilir bilgilerini tanımlar
+type CustomerInput struct {
+	Name        string
+	Phone       string
+	Email       string
+	Note        string
+	CreditLimit int64 // 0 = no limit
+	IsActive    bool
+}
+
+type CustomerService struct {
+	repo            repositories.CustomerRepository
+	transactionRepo repositories.TransactionRepository
+	workPeriodRepo  repositories.WorkPeriodRepository
+	audit           *AuditService
+}
+
+func NewCustomerService(repo repositories.CustomerRepository, txRepo repositories.TransactionRepository, wpRepo repositories.WorkPeriodRepository, audit *AuditService) *CustomerService {
+	return &CustomerService{
+		repo:            repo,
+		transactionRepo: txRepo,
+		workPeriodRepo:  wpRepo,
+		audit:           audit,
+	}
+}
+
+// ListCustomers returns customers matching the filter
+// Filtreye uyan müşterileri döndürür
+func (s *CustomerService) ListCustomers(filter models.CustomerFilter) ([]models.Customer, error) {
+	filter.Search = strings.TrimSpace(filter.Search)
+	return s.repo.Find(filter)
+}
+
+// GetCustomer returns a customer with its current balance
+// Müşteriyi güncel bakiyesiyle döndürür
+func (s *CustomerService) GetCustomer(id uint) (*models.Customer, error) {
+	customer, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, ErrCustomerNotFound
+	}
+	return customer, nil
+}
+
+// CreateCustomer opens a new customer account with a zero balance
+// Sıfır bakiyeli yeni bir müşteri hesabı açar
+func (s *CustomerService) CreateCustomer(input CustomerInput) (*models.Customer, error) {
+	customer := &models.Customer{}
+	if err := applyCustomer(customer, input); err != nil {
+		return nil, err
+	}
+	if err := s.checkPhone(customer); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Create(customer); err != nil {
+		return nil, err
+	}
+	return customer, nil
+}
+
+// UpdateCustomer changes the details of a customer, credit limit changes are audited
+// Müşteri bilgilerini değiştirir, kredi limiti değişiklikleri denetlenir
+func (s *CustomerService) UpdateCustomer(id uint, input CustomerInput, userID uint) (*models.Customer, error) {
+	customer, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, ErrCustomerNotFound
+	}
+
+	before := customer.CreditLimit
+	if err := applyCustomer(customer, input); err != nil {
+		return nil, err
+	}
+	if err := s.checkPhone(customer); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(customer); err != nil {
+		return nil, err
+	}
+
+	if customer.CreditLimit != before {
+		err := s.repo.WithTransaction(func(tx *gorm.DB) error {
+			return s.audit.Record(tx, userID, models.AuditActionCustomerLimit, models.AuditEntityCustomer, customer.ID,
+				map[string]interface{}{"credit_limit": before},
+				map[string]interface{}{"credit_limit": customer.CreditLimit}, "")
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return customer, nil
+}
+
+// DeleteCustomer removes a customer account once its balance is settled
+// Bakiyesi kapanmış müşteri hesabını siler
+func (s *CustomerService) DeleteCustomer(id uint) error {
+	customer, err := s.repo.FindByID(id)
+	if err != nil {
+		return ErrCustomerNotFound
+	}
+	if customer.Balance != 0 {
+		return ErrCustomerHasBalance
+	}
+	return s.repo.Delete(id)
+}
+
+// ChargeOrderWithTx posts the unpaid amount of an order to the customer's tab within the closing transaction.
+// The charge is refused when it would take the balance over the credit limit.
+// Siparişin ödenmemiş tutarını kapanış işlemi içinde müşterinin hesabına borç yazar.
+// Bakiye kredi limitini aşacaksa borç reddedilir.
+func (s *CustomerService) ChargeOrderWithTx(tx *gorm.DB, customerID uint, order *models.Order, amount int64, userID uint) (*models.CustomerLedgerEntry, error) {
+	customer, err := s.repo.FindByIDWithTx(tx, customerID)
+	if err != nil {
+		return nil, ErrCustomerNotFound
+	}
+	if !customer.IsActive {
+		return nil, ErrCustomerInactive
+	}
+	if customer.CreditLimit > 0 && customer.Balance+amount > customer.CreditLimit {
+		return nil, ErrCreditLimitExceeded
+	}
+
+	entry := &models.CustomerLedgerEntry{
+		CustomerID:   customer.ID,
+		Type:         models.LedgerEntryCharge,
+		Amount:       amount,
+		OrderID:      &order.ID,
+		OrderNumber:  order.OrderNumber,
+		WorkPeriodID: order.WorkPeriodID,
+		CreatedBy:    userID,
+	}
+	if err := s.repo.CreateEntryWithTx(tx, entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// CreditRefundWithTx takes a refund of an order closed on account off the customer's tab
+// Hesaba yazılmış bir siparişin iadesini müşterinin borcundan düşer
+func (s *CustomerService) CreditRefundWithTx(tx *gorm.DB, customerID uint, order *models.Order, refund *models.Refund, amount int64, userID uint) error {
+	customer, err := s.repo.FindByIDWithTx(tx, customerID)
+	if err != nil {
+		return ErrCustomerNotFound
+	}
+
+	return s.repo.CreateEntryWithTx(tx, &models.CustomerLedgerEntry{
+		CustomerID:   customer.ID,
+		Type:         models.LedgerEntryRefund,
+		Amount:       -amount,
+		OrderID:      &order.ID,
+		OrderNumber:  order.OrderNumber,
+		RefundID:     &refund.ID,
+		WorkPeriodID: refund.WorkPeriodID,
+		CreatedBy:    userID,
+	})
+}
+
+// RecordPayment takes a payment against the customer's balance into the till of the running day (ACID)
+// Müşterinin bakiyesine karşı alınan ödemeyi açık günün kasasına kaydeder (ACID)
+func (s *CustomerService) RecordPayment(customerID uint, amount int64, method, note string, userID uint) (*models.CustomerLedgerEntry, *models.Customer, error) {
+	if amount <= 0 {
+		return nil, nil, errors.New("payment amount must be positive")
+	}
+	if method != models.PaymentMethodCash && method != models.PaymentMethodCreditCard {
+		return nil, nil, errors.New("invalid payment method")
+	}
+
+	period, err := s.workPeriodRepo.FindActivePeriod()
+	if err != nil {
+		return nil, nil, err
+	}
+	if period == nil {
+		return nil, nil, errors.New("no active work period found")
+	}
+
+	var entry *models.CustomerLedgerEntry
+	var customer *models.Customer
+	err = s.repo.WithTransaction(func(tx *gorm.DB) error {
+		c, err := s.repo.FindByIDWithTx(tx, customerID)
+		if err != nil {
+			return ErrCustomerNotFound
+		}
+		customer = c
+		if amount > customer.Balance {
+			return ErrPaymentExceedsBalance
+		}
+
+		transaction := &models.Transaction{
+			Type:            "INCOME",
+			Category:        models.TransactionCategoryAccountPayment,
+			PaymentMethod:   method,
+			Amount:          amount,
+			Description:     "Account payment - " + customer.Name,
+			WorkPeriodID:    period.ID,
+			CreatedBy:       userID,
+			TransactionDate: time.Now(),
+		}
+		if err := s.transactionRepo.CreateWithTx(tx, transaction); err != nil {
+			return err
+		}
+
+		before := map[string]interface{}{"balance": customer.Balance}
+		entry = &models.CustomerLedgerEntry{
+			CustomerID:    customer.ID,
+			Type:          models.LedgerEntryPayment,
+			Amount:        -amount,
+			PaymentMethod: method,
+			TransactionID: &transaction.ID,
+			WorkPeriodID:  period.ID,
+			Note:          note,
+			CreatedBy:     userID,
+		}
+		if err := s.repo.CreateEntryWithTx(tx, entry); err != nil {
+			return err
+		}
+		customer.Balance = entry.Balance
+
+		after := map[string]interface{}{"balance": customer.Balance, "entry": entry}
+		return s.audit.Record(tx, userID, models.AuditActionCustomerPayment, models.AuditEntityCustomer, customer.ID, before, after, note)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	logger.Info("Customer payment recorded",
+		logger.Int("customer_id", int(customer.ID)),
+		logger.Int("amount", int(amount)),
+		logger.String("method", method),
+		logger.Int("balance", int(customer.Balance)),
+	)
+
+	return entry, customer, nil
+}
+
+// GetStatement lists the charges, payments and refunds of a customer in [start, end) with the running balances
+// Müşterinin [start, end) aralığındaki borç, ödeme ve iadelerini bakiyeleriyle listeler
+func (s *CustomerService) GetStatement(customerID uint, start, end time.Time) (*models.CustomerStatement, error) {
+	customer, err := s.repo.FindByID(customerID)
+	if err != nil {
+		return nil, ErrCustomerNotFound
+	}
+
+	opening, err := s.repo.BalanceAt(customerID, start)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := s.repo.FindEntries(customerID, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	statement := &models.CustomerStatement{
+		Customer:       *customer,
+		StartDate:      start,
+		EndDate:        end,
+		OpeningBalance: opening,
+		ClosingBalance: opening,
+		Entries:        entries,
+	}
+	for _, e := range entries {
+		switch e.Type {
+		case models.LedgerEntryCharge:
+			statement.TotalCharges += e.Amount
+		case models.LedgerEntryPayment:
+			statement.TotalPayments -= e.Amount
+		case models.LedgerEntryRefund:
+			statement.TotalRefunds -= e.Amount
+		}
+		statement.ClosingBalance += e.Amount
+	}
+	return statement, nil
+}
+
+// checkPhone keeps phone numbers unique, loyalty finds the customer by phone
+// Telefon numaralarını tekil tutar, sadakat müşteriyi telefonla bulur
+func (s *CustomerService) checkPhone(customer *models.Customer) error {
+	if customer.Phone == "" {
+		return nil
+	}
+	existing, err := s.repo.FindByPhone(customer.Phone)
+	if err == nil && existing.ID != customer.ID {
+		return ErrCustomerPhoneTaken
+	}
+	return nil
+}
+
+// applyCustomer validates the input and copies it onto the customer
+// Girdiyi doğrular ve müşteriye kopyalar
+func applyCustomer(customer *models.Customer, input CustomerInput) error {
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return errors.New("customer name is required")
+	}
+	if input.CreditLimit < 0 {
+		return errors.New("credit limit cannot be negative")
+	}
+
+	customer.Name = name
+	customer.Phone = strings.TrimSpace(input.Phone)
+	customer.Email = strings.TrimSpace(input.Email)
+	customer.Note = input.Note
+	customer.CreditLimit = input.CreditLimit
+	customer.IsActive = input.IsActive
+	return nil
+}
@@ -0,0 +1,249 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"simple-pos/internal/models"
+	"simple-pos/internal/platform/export"
+	"simple-pos/internal/repositories"
+	"strconv"
+	"time"
+)
+
+// Export file formats
+// Dışa aktarım dosya biçimleri
+const (
+	ExportFormatCSV  = "csv"
+	ExportFormatXLSX = "xlsx"
+)
+
+// ExportService turns reports, orders, expenses and product sales into files for the accountant
+// Raporları, siparişleri, giderleri ve ürün satışlarını muhasebeci için dosyalara dönüştürür
+type ExportService struct {
+	orderService       *OrderService
+	transactionService *TransactionService
+	analyticsService   *AnalyticsService
+	workPeriodRepo     repositories.WorkPeriodRepository
+	productStatRepo    repositories.ProductStatRepository
+	fontPath           string // TrueType font of the PDF reports
+}
+
+func NewExportService(orderService *OrderService, transactionService *TransactionService, analyticsService *AnalyticsService, wpRepo repositories.WorkPeriodRepository, productStatRepo repositories.ProductStatRepository, fontPath string) *ExportService {
+	return &ExportService{
+		orderService:       orderService,
+		transactionService: transactionService,
+		analyticsService:   analyticsService,
+		workPeriodRepo:     wpRepo,
+		productStatRepo:    productStatRepo,
+		fontPath:           fontPath,
+	}
+}
+
+// WriteSheet writes the sheet in the requested format
+// Sayfayı istenen biçimde yazar
+func (s *ExportService) WriteSheet(w io.Writer, sheet *export.Sheet, format string) error {
+	switch format {
+	case ExportFormatCSV:
+		return export.WriteCSV(w, sheet)
+	case ExportFormatXLSX:
+		return export.WriteXLSX(w, sheet)
+	default:
+		return errors.New("format must be csv or xlsx")
+	}
+}
+
+// PeriodsSheet lists the closed work periods started within the date range with their totals and drawer count
+// Tarih aralığında başlayan kapanmış çalışma dönemlerini toplamları ve kasa sayımıyla listeler
+func (s *ExportService) PeriodsSheet(startDate, endDate time.Time) (*export.Sheet, error) {
+	periods, err := s.analyticsService.periodsBetween(startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	sheet := export.NewSheet("Dönemler",
+		"Dönem", "Başlangıç", "Bitiş", "Sipariş", "Brüt Satış", "İade", "Net Satış",
+		"Gider", "Net Kâr", "Açılış Kasası", "Beklenen Nakit", "Sayılan Nakit", "Kasa Farkı")
+	for _, p := range periods {
+		if p.IsActive {
+			continue
+		}
+		sheet.AddRow(
+			int(p.ID), p.StartTime, p.EndTime, p.TotalOrders,
+			export.Money(p.TotalSales), export.Money(p.TotalRefunds), export.Money(p.TotalSales-p.TotalRefunds),
+			export.Money(p.TotalExpenses), export.Money(p.NetProfit),
+			export.Money(p.OpeningFloat), export.Money(p.ExpectedCash), optionalMoney(p.CountedCash), optionalMoney(p.CashVariance),
+		)
+	}
+	return sheet, nil
+}
+
+// OrdersSheet lists the same orders as GET /orders, one row per order
+// GET /orders ile aynı siparişleri sipariş başına bir satır olarak listeler
+func (s *ExportService) OrdersSheet(startDate, endDate time.Time, scope string) (*export.Sheet, error) {
+	orders, err := s.orderService.GetOrders(startDate, endDate, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	sheet := export.NewSheet("Siparişler",
+		"Sipariş No", "Tarih", "Masa", "Garson", "Durum", "Ara Toplam", "Promosyon", "Kupon", "İndirim",
+		"KDV", "Toplam", "Ödenen", "İade", "Ödeme Yöntemi")
+	for _, o := range orders {
+		waiter := ""
+		if o.Waiter != nil {
+			waiter = o.Waiter.Name
+		}
+		sheet.AddRow(
+			o.OrderNumber, o.CreatedAt, o.TableName, waiter, o.Status,
+			export.Money(o.Subtotal), export.Money(o.PromotionAmount), export.Money(o.CouponAmount), export.Money(o.DiscountAmount), export.Money(o.TaxAmount),
+			export.Money(o.TotalAmount), export.Money(o.PaidAmount), export.Money(o.RefundedAmount),
+			o.PaymentMethod,
+		)
+	}
+	return sheet, nil
+}
+
+// ExpensesSheet lists the same expenses as GET /transactions/expense
+// GET /transactions/expense ile aynı giderleri listeler
+func (s *ExportService) ExpensesSheet(startDate, endDate time.Time, scope string) (*export.Sheet, error) {
+	expenses, err := s.transactionService.ListExpenses(startDate, endDate, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	sheet := export.NewSheet("Giderler", "Tarih", "Kategori", "Açıklama", "Ödeme Yöntemi", "Tutar", "Dönem")
+	for _, t := range expenses {
+		date := t.TransactionDate
+		if date.IsZero() {
+			date = t.CreatedAt
+		}
+		sheet.AddRow(date, t.Category, t.Description, t.PaymentMethod, export.Money(t.Amount), int(t.WorkPeriodID))
+	}
+	return sheet, nil
+}
+
+// ProductSalesSheet lists every product on the menu with its sales in the date range, best selling first.
+// Stats come from closed work periods like the product analytics.
+// Menüdeki her ürünü tarih aralığındaki satışlarıyla listeler, en çok satan önce.
+// İstatistikler ürün analizlerinde olduğu gibi kapanmış dönemlerden gelir.
+func (s *ExportService) ProductSalesSheet(startDate, endDate time.Time) (*export.Sheet, error) {
+	products, err := s.productStatRepo.RankProducts(statDate(startDate), statDate(endDate), models.ProductRankTop, models.ProductMetricRevenue, -1)
+	if err != nil {
+		return nil, err
+	}
+
+	sheet := export.NewSheet("Ürün Satışları",
+		"Ürün", "Kategori", "Satılan Adet", "Ciro", "İade Adet", "İade Tutarı", "Net Adet", "Net Ciro")
+	for _, p := range products {
+		sheet.AddRow(
+			p.ProductName, p.CategoryName, p.QuantitySold, export.Money(p.TotalRevenue),
+			p.RefundedQuantity, export.Money(p.RefundedAmount), p.NetQuantity, export.Money(p.NetRevenue),
+		)
+	}
+	return sheet, nil
+}
+
+// WriteZReport writes the printable end-of-day report of a closed work period as PDF.
+// Without a period the most recently closed one is used. The totals are the ones EndDay stored on the period.
+// Kapanmış bir çalışma döneminin yazdırılabilir gün sonu raporunu PDF olarak yazar.
+// Dönem verilmezse en son kapanan kullanılır. Toplamlar EndDay'in döneme kaydettikleridir.
+func (s *ExportService) WriteZReport(w io.Writer, periodID uint) error {
+	period, err := s.closedPeriod(periodID)
+	if err != nil {
+		return err
+	}
+	report, err := s.analyticsService.GetDailyReport("period_"+strconv.FormatUint(uint64(period.ID), 10), "")
+	if err != nil {
+		return err
+	}
+
+	pdf := export.NewPDF(s.fontPath).
+		Title("Z Raporu").
+		Subtitle(fmt.Sprintf("Dönem #%d", period.ID)).
+		Subtitle(fmt.Sprintf("%s - %s", period.StartTime.Local().Format("02.01.2006 15:04"), period.EndTime.Local().Format("02.01.2006 15:04")))
+
+	pdf.Section("Satışlar").
+		Row("Sipariş Sayısı", strconv.Itoa(report.TotalOrders)).
+		Row("Brüt Satış", export.FormatTL(report.TotalSales)).
+		Row("Promosyonlar", export.FormatTL(report.TotalPromotions)).
+		Row("Kuponlar", export.FormatTL(report.TotalCoupons)).
+		Row("Manuel İndirimler", export.FormatTL(report.TotalDiscounts)).
+		Row("İadeler", export.FormatTL(report.TotalRefunds)).
+		TotalRow("Net Satış", export.FormatTL(report.NetSales)).
+		Row("Giderler", export.FormatTL(report.TotalExpenses)).
+		TotalRow("Net Kâr", export.FormatTL(report.NetProfit))
+
+	pdf.Section("Ödemeler").
+		Row("Nakit", export.FormatTL(report.CashSales)).
+		Row("Kredi Kartı", export.FormatTL(report.PosSales))
+
+	if len(report.TaxBreakdown) > 0 {
+		rows := make([][]string, 0, len(report.TaxBreakdown)+1)
+		var net, gross int64
+		for _, t := range report.TaxBreakdown {
+			rows = append(rows, []string{
+				fmt.Sprintf("%%%d", t.TaxRate), export.FormatTL(t.NetAmount), export.FormatTL(t.TaxAmount), export.FormatTL(t.GrossAmount),
+			})
+			net += t.NetAmount
+			gross += t.GrossAmount
+		}
+		rows = append(rows, []string{"Toplam", export.FormatTL(net), export.FormatTL(report.TotalTax), export.FormatTL(gross)})
+		pdf.Section("KDV Dökümü").Table([]string{"Oran", "Matrah", "KDV", "Toplam"}, rows)
+	}
+
+	if drawer := report.CashDrawer; drawer != nil {
+		pdf.Section("Kasa").
+			Row("Açılış Kasası", export.FormatTL(drawer.OpeningFloat)).
+			Row("Nakit Satış", export.FormatTL(drawer.CashSales)).
+			Row("Veresiye Tahsilatı", export.FormatTL(drawer.CashAccount)).
+			Row("Kasaya Giriş", export.FormatTL(drawer.CashIn)).
+			Row("Kasadan Alınan", export.FormatTL(drawer.CashDrops)).
+			Row("Ödemeler", export.FormatTL(drawer.Payouts)).
+			Row("Nakit Giderler", export.FormatTL(drawer.CashExpenses)).
+			Row("Nakit İadeler", export.FormatTL(drawer.CashRefunds)).
+			TotalRow("Beklenen Nakit", export.FormatTL(drawer.ExpectedCash))
+		if drawer.CountedCash != nil {
+			pdf.Row("Sayılan Nakit", export.FormatTL(*drawer.CountedCash))
+		}
+		if drawer.Variance != nil {
+			pdf.TotalRow("Kasa Farkı", export.FormatTL(*drawer.Variance))
+		}
+	}
+
+	pdf.Note("Oluşturulma: " + time.Now().Format("02.01.2006 15:04"))
+	return pdf.Write(w)
+}
+
+// closedPeriod finds the closed work period by ID, or the most recently closed one when ID is 0
+// Kapanmış çalışma dönemini ID ile bulur, ID 0 ise en son kapananı döndürür
+func (s *ExportService) closedPeriod(periodID uint) (*models.WorkPeriod, error) {
+	if periodID == 0 {
+		periods, _, err := s.analyticsService.GetReportHistory(1, 1)
+		if err != nil {
+			return nil, err
+		}
+		if len(periods) == 0 {
+			return nil, errors.New("no closed work period found")
+		}
+		return &periods[0], nil
+	}
+
+	period, err := s.workPeriodRepo.FindByID(periodID)
+	if err != nil {
+		return nil, errors.New("work period not found")
+	}
+	if period.IsActive || period.EndTime == nil {
+		return nil, errors.New("work period is still open, close the day first")
+	}
+	return period, nil
+}
+
+// optionalMoney leaves the cell empty when the amount is missing
+// Tutar yoksa hücreyi boş bırakır
+func optionalMoney(amount *int64) interface{} {
+	if amount == nil {
+		return nil
+	}
+	return export.Money(*amount)
+}
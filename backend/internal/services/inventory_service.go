@@ -0,0 +1,391 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"simple-pos/internal/models"
+	"simple-pos/internal/platform/events"
+	"simple-pos/internal/repositories"
+	"simple-pos/pkg/logger"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrInsufficientStock is returned when a product cannot be made from the stock on hand
+// Eldeki stokla ürün hazırlanamadığında döner
+var ErrInsufficientStock = errors.New("insufficient stock")
+
+// stockEpsilon absorbs float rounding when comparing stock quantities
+// Stok miktarlarını karşılaştırırken ondalık yuvarlama farkını tolere eder
+const stockEpsilon = 1e-9
+
+type InventoryService struct {
+	repo            repositories.InventoryRepository
+	productRepo     repositories.ProductRepository
+	bus             *events.Bus
+	blockOnShortage bool
+}
+
+func NewInventoryService(repo repositories.InventoryRepository, productRepo repositories.ProductRepository, bus *events.Bus, blockOnShortage bool) *InventoryService {
+	return &InventoryService{
+		repo:            repo,
+		productRepo:     productRepo,
+		bus:             bus,
+		blockOnShortage: blockOnShortage,
+	}
+}
+
+// RecipeInput is a single ingredient line of a recipe
+// Reçetenin tek bir malzeme satırı
+type RecipeInput struct {
+	StockItemID uint
+	Quantity    float64
+}
+
+// CreateStockItem creates a stock item, booking the opening quantity as a stock-in movement
+// Stok kalemi oluşturur, açılış miktarını stok girişi olarak kaydeder
+func (s *InventoryService) CreateStockItem(name, unit string, lowStockThreshold, initialQuantity float64, userID uint) (*models.StockItem, error) {
+	if lowStockThreshold < 0 || initialQuantity < 0 {
+		return nil, errors.New("quantities cannot be negative")
+	}
+	if unit == "" {
+		unit = models.StockUnitPiece
+	}
+
+	item := &models.StockItem{
+		Name:              name,
+		Unit:              unit,
+		LowStockThreshold: lowStockThreshold,
+		IsActive:          true,
+	}
+	if err := s.repo.CreateStockItem(item); err != nil {
+		return nil, err
+	}
+
+	if initialQuantity > 0 {
+		if _, err := s.RecordMovement(item.ID, models.StockMovementIn, initialQuantity, "Opening stock", userID); err != nil {
+			return nil, err
+		}
+		return s.repo.FindStockItemByID(item.ID)
+	}
+	return item, nil
+}
+
+// ListStockItems returns all stock items
+// Tüm stok kalemlerini döndürür
+func (s *InventoryService) ListStockItems() ([]models.StockItem, error) {
+	return s.repo.FindAllStockItems()
+}
+
+// UpdateStockItem updates stock item details; quantity changes go through movements
+// Stok kalemi detaylarını günceller; miktar değişiklikleri hareketlerle yapılır
+func (s *InventoryService) UpdateStockItem(id uint, name, unit string, lowStockThreshold float64, isActive bool) (*models.StockItem, error) {
+	item, err := s.repo.FindStockItemByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if lowStockThreshold < 0 {
+		return nil, errors.New("low stock threshold cannot be negative")
+	}
+
+	item.Name = name
+	if unit != "" {
+		item.Unit = unit
+	}
+	item.LowStockThreshold = lowStockThreshold
+	item.IsActive = isActive
+
+	if err := s.repo.UpdateStockItem(item); err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+// DeleteStockItem deletes a stock item
+// Stok kalemini siler
+func (s *InventoryService) DeleteStockItem(id uint) error {
+	return s.repo.DeleteStockItem(id)
+}
+
+// GetLowStock returns items at or below their alert threshold
+// Uyarı eşiğindeki veya altındaki kalemleri döndürür
+func (s *InventoryService) GetLowStock() ([]models.StockItem, error) {
+	return s.repo.FindLowStockItems()
+}
+
+// GetRecipe returns the recipe of a product
+// Ürünün reçetesini döndürür
+func (s *InventoryService) GetRecipe(productID uint) ([]models.RecipeItem, error) {
+	if _, err := s.productRepo.FindByID(productID); err != nil {
+		return nil, errors.New("product not found")
+	}
+	return s.repo.FindRecipe(productID)
+}
+
+// SetRecipe replaces the recipe of a product (an empty list removes stock tracking for it)
+// Ürünün reçetesini değiştirir (boş liste ürünün stok takibini kaldırır)
+func (s *InventoryService) SetRecipe(productID uint, inputs []RecipeInput) ([]models.RecipeItem, error) {
+	if _, err := s.productRepo.FindByID(productID); err != nil {
+		return nil, errors.New("product not found")
+	}
+
+	seen := make(map[uint]bool, len(inputs))
+	items := make([]models.RecipeItem, 0, len(inputs))
+	for _, in := range inputs {
+		if in.Quantity <= 0 {
+			return nil, errors.New("recipe quantity must be positive")
+		}
+		if seen[in.StockItemID] {
+			return nil, errors.New("stock item listed more than once")
+		}
+		seen[in.StockItemID] = true
+
+		if _, err := s.repo.FindStockItemByID(in.StockItemID); err != nil {
+			return nil, fmt.Errorf("stock item %d not found", in.StockItemID)
+		}
+		items = append(items, models.RecipeItem{
+			ProductID:   productID,
+			StockItemID: in.StockItemID,
+			Quantity:    in.Quantity,
+		})
+	}
+
+	if err := s.repo.ReplaceRecipe(productID, items); err != nil {
+		return nil, err
+	}
+	return s.repo.FindRecipe(productID)
+}
+
+// RecordMovement books a manual stock-in, waste or adjustment
+// IN and WASTE take a positive quantity; ADJUSTMENT takes a signed correction.
+// Manuel stok girişi, fire veya düzeltme kaydeder
+// IN ve WASTE pozitif miktar alır; ADJUSTMENT işaretli düzeltme alır.
+func (s *InventoryService) RecordMovement(stockItemID uint, movementType string, quantity float64, note string, userID uint) (*models.StockMovement, error) {
+	var delta float64
+	switch movementType {
+	case models.StockMovementIn:
+		if quantity <= 0 {
+			return nil, errors.New("stock-in quantity must be positive")
+		}
+		delta = quantity
+	case models.StockMovementWaste:
+		if quantity <= 0 {
+			return nil, errors.New("waste quantity must be positive")
+		}
+		delta = -quantity
+	case models.StockMovementAdjustment:
+		if quantity == 0 {
+			return nil, errors.New("adjustment cannot be zero")
+		}
+		delta = quantity
+	default:
+		return nil, errors.New("invalid movement type")
+	}
+
+	if _, err := s.repo.FindStockItemByID(stockItemID); err != nil {
+		return nil, errors.New("stock item not found")
+	}
+
+	movement := &models.StockMovement{
+		StockItemID: stockItemID,
+		Type:        movementType,
+		Quantity:    delta,
+		Note:        note,
+		CreatedBy:   userID,
+	}
+	err := s.repo.WithTransaction(func(tx *gorm.DB) error {
+		return s.repo.CreateMovementWithTx(tx, movement)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.NotifyLowStock([]models.StockMovement{*movement})
+	return movement, nil
+}
+
+// ListMovements returns stock movements in the date range
+// Tarih aralığındaki stok hareketlerini döndürür
+func (s *InventoryService) ListMovements(stockItemID *uint, start, end time.Time) ([]models.StockMovement, error) {
+	return s.repo.FindMovements(stockItemID, start, end)
+}
+
+// CheckAvailability verifies that quantity more units of the product can be made, counting stock
+// already promised to OPEN orders. It is a no-op unless blocking on shortage is enabled.
+// Üründen quantity adet daha hazırlanabileceğini AÇIK siparişlere ayrılan stoğu da hesaba katarak doğrular.
+// Stok yetersizliğinde engelleme kapalıysa hiçbir şey yapmaz.
+func (s *InventoryService) CheckAvailability(productID uint, quantity int) error {
+	if !s.blockOnShortage || quantity <= 0 {
+		return nil
+	}
+
+	recipe, err := s.repo.FindRecipe(productID)
+	if err != nil {
+		return err
+	}
+	if len(recipe) == 0 {
+		return nil
+	}
+
+	demand, err := s.repo.PendingDemand()
+	if err != nil {
+		return err
+	}
+
+	for _, ri := range recipe {
+		if !ri.StockItem.IsActive {
+			continue
+		}
+		available := ri.StockItem.Quantity - demand[ri.StockItemID]
+		needed := ri.Quantity * float64(quantity)
+		if needed > available+stockEpsilon {
+			return fmt.Errorf("%w: %s (needed %g %s, available %g)",
+				ErrInsufficientStock, ri.StockItem.Name, needed, ri.StockItem.Unit, math.Max(available, 0))
+		}
+	}
+	return nil
+}
+
+// SyncItemStock reconciles the stock booked for an order item with the given quantity (0 restores everything)
+// Sipariş kalemi için düşülen stoğu verilen adetle eşitler (0 tamamını iade eder)
+func (s *InventoryService) SyncItemStock(item *models.OrderItem, quantity int, userID uint) error {
+	var movements []models.StockMovement
+	err := s.repo.WithTransaction(func(tx *gorm.DB) error {
+		var err error
+		movements, err = s.SyncItemStockWithTx(tx, item, quantity, userID)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	s.NotifyLowStock(movements)
+	return nil
+}
+
+// SyncItemStockWithTx depletes or restores stock so that the item's net SALE/RETURN movements match
+// recipe quantity x units. It is idempotent and safe to call repeatedly. Returns the created movements.
+// Kalemin net SALE/RETURN hareketlerini reçete miktarı x adet ile eşitlemek için stok düşer veya iade eder.
+// Tekrar çağrılması güvenlidir. Oluşturulan hareketleri döndürür.
+func (s *InventoryService) SyncItemStockWithTx(tx *gorm.DB, item *models.OrderItem, quantity int, userID uint) ([]models.StockMovement, error) {
+	booked, err := s.repo.SumItemMovementsWithTx(tx, item.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	target := make(map[uint]float64)
+	if quantity > 0 {
+		recipe, err := s.repo.FindRecipeWithTx(tx, item.ProductID)
+		if err != nil {
+			return nil, err
+		}
+		for _, ri := range recipe {
+			target[ri.StockItemID] = -ri.Quantity * float64(quantity)
+		}
+	}
+
+	// Stock items booked earlier but no longer in the recipe are restored too
+	// Daha önce düşülüp artık reçetede olmayan stoklar da iade edilir
+	for stockItemID := range booked {
+		if _, ok := target[stockItemID]; !ok {
+			target[stockItemID] = 0
+		}
+	}
+
+	var created []models.StockMovement
+	for stockItemID, want := range target {
+		delta := want - booked[stockItemID]
+		if math.Abs(delta) < stockEpsilon {
+			continue
+		}
+
+		movementType := models.StockMovementSale
+		if delta > 0 {
+			movementType = models.StockMovementReturn
+		}
+
+		movement := models.StockMovement{
+			StockItemID: stockItemID,
+			Type:        movementType,
+			Quantity:    delta,
+			OrderID:     &item.OrderID,
+			OrderItemID: &item.ID,
+			Note:        item.ProductName,
+			CreatedBy:   userID,
+		}
+		if err := s.repo.CreateMovementWithTx(tx, &movement); err != nil {
+			return nil, err
+		}
+		created = append(created, movement)
+	}
+
+	return created, nil
+}
+
+// ReturnItemStockWithTx puts recipe quantity x units of the item back into stock,
+// never more than the item still holds. Returns the created movements.
+// Kalemin reçete miktarı x adet kadar stoğunu iade eder, kalemin hâlâ tuttuğundan fazlasını iade etmez.
+// Oluşturulan hareketleri döndürür.
+func (s *InventoryService) ReturnItemStockWithTx(tx *gorm.DB, item *models.OrderItem, units int, userID uint) ([]models.StockMovement, error) {
+	if units <= 0 {
+		return nil, nil
+	}
+
+	booked, err := s.repo.SumItemMovementsWithTx(tx, item.ID)
+	if err != nil {
+		return nil, err
+	}
+	recipe, err := s.repo.FindRecipeWithTx(tx, item.ProductID)
+	if err != nil {
+		return nil, err
+	}
+
+	var created []models.StockMovement
+	for _, ri := range recipe {
+		delta := math.Min(ri.Quantity*float64(units), -booked[ri.StockItemID])
+		if delta < stockEpsilon {
+			continue
+		}
+
+		movement := models.StockMovement{
+			StockItemID: ri.StockItemID,
+			Type:        models.StockMovementReturn,
+			Quantity:    delta,
+			OrderID:     &item.OrderID,
+			OrderItemID: &item.ID,
+			Note:        item.ProductName,
+			CreatedBy:   userID,
+		}
+		if err := s.repo.CreateMovementWithTx(tx, &movement); err != nil {
+			return nil, err
+		}
+		created = append(created, movement)
+	}
+
+	return created, nil
+}
+
+// NotifyLowStock publishes an alert for every stock item these movements pushed to or below its threshold
+// Bu hareketlerin eşiğe veya altına düşürdüğü her stok kalemi için uyarı yayınlar
+func (s *InventoryService) NotifyLowStock(movements []models.StockMovement) {
+	for _, m := range movements {
+		if m.Quantity >= 0 {
+			continue
+		}
+		item, err := s.repo.FindStockItemByID(m.StockItemID)
+		if err != nil {
+			logger.Error("Failed to load stock item for alert", logger.Err(err))
+			continue
+		}
+		before := m.BalanceAfter - m.Quantity
+		if item.IsLow() && before > item.LowStockThreshold {
+			logger.Warn("Stock is running low",
+				logger.String("stock_item", item.Name),
+				logger.String("quantity", fmt.Sprintf("%g %s", m.BalanceAfter, item.Unit)),
+			)
+			s.bus.Publish(events.StockLow, item)
+		}
+	}
+}
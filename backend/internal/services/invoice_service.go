@@ -0,0 +1,338 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"simple-pos/internal/models"
+	"simple-pos/internal/platform/einvoice"
+	"simple-pos/internal/repositories"
+	"simple-pos/pkg/logger"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Invoicing errors
+// Faturalama hataları
+var (
+	ErrInvoiceNotFound      = errors.New("invoice not found")
+	ErrInvoiceOrderNotFound = errors.New("order not found")
+	ErrOrderNotInvoiceable  = errors.New("only completed orders without refunds can be invoiced")
+	ErrOrderAlreadyInvoiced = errors.New("order is already invoiced")
+	ErrInvoiceAlreadySent   = errors.New("invoice is already sent")
+	ErrSellerNotConfigured  = errors.New("seller tax info is not configured")
+)
+
+// InvoiceSettings holds the seller and numbering configuration read at startup
+// Açılışta okunan satıcı ve numaralandırma yapılandırmasını tutar
+type InvoiceSettings struct {
+	Prefix     string         // 3 character series of the invoice numbers
+	Seller     einvoice.Party // Our own tax info
+	Integrator string         // Address of the integrator, e.g. file://./invoices (empty = keep invoices unsent)
+}
+
+// InvoiceCustomer is the tax info of the buyer
+// Alıcının vergi bilgileri
+type InvoiceCustomer struct {
+	TaxID     string // VKN or TCKN
+	Name      string // Company title or full name
+	TaxOffice string // Required for companies
+	Address   string
+	District  string
+	City      string
+	Country   string
+	Email     string
+}
+
+type InvoiceService struct {
+	repo          repositories.InvoiceRepository
+	orderRepo     repositories.OrderRepository
+	audit         *AuditService
+	settings      InvoiceSettings
+	integrator    einvoice.Integrator
+	integratorErr error // Why no integrator is available
+}
+
+func NewInvoiceService(repo repositories.InvoiceRepository, orderRepo repositories.OrderRepository, audit *AuditService, settings InvoiceSettings) *InvoiceService {
+	s := &InvoiceService{
+		repo:      repo,
+		orderRepo: orderRepo,
+		audit:     audit,
+		settings:  settings,
+	}
+	s.integrator, s.integratorErr = einvoice.ParseIntegrator(settings.Integrator)
+	if s.integratorErr != nil && !errors.Is(s.integratorErr, einvoice.ErrNoIntegrator) {
+		logger.Warn("E-invoice integrator disabled", logger.Err(s.integratorErr))
+	}
+	return s
+}
+
+// IssueInvoice numbers and stores an e-Arşiv invoice for a completed order, then hands it to the integrator.
+// A failed delivery does not undo the invoice, it stays numbered and can be sent again.
+// Tamamlanmış bir sipariş için e-Arşiv faturası numaralandırır ve kaydeder, ardından entegratöre iletir.
+// Başarısız gönderim faturayı geri almaz, fatura numaralı kalır ve yeniden gönderilebilir.
+func (s *InvoiceService) IssueInvoice(orderID uint, customer InvoiceCustomer, userID uint) (*models.Invoice, error) {
+	if err := s.checkSeller(); err != nil {
+		return nil, err
+	}
+	buyer, err := buyerParty(customer)
+	if err != nil {
+		return nil, err
+	}
+
+	order, err := s.orderRepo.GetOrderWithDetails(orderID)
+	if err != nil {
+		return nil, ErrInvoiceOrderNotFound
+	}
+	if order.Status != "COMPLETED" || order.RefundedAmount > 0 {
+		return nil, ErrOrderNotInvoiceable
+	}
+	if _, err := s.repo.FindByOrderID(orderID); err == nil {
+		return nil, ErrOrderAlreadyInvoiced
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	now := time.Now()
+	doc := &einvoice.Document{
+		UUID:     uuid.New().String(),
+		IssuedAt: now,
+		Profile:  einvoice.ProfileEArchive,
+		Type:     einvoice.TypeSale,
+		Currency: einvoice.CurrencyTRY,
+		Notes:    []string{"Sipariş No: " + order.OrderNumber},
+		Supplier: s.settings.Seller,
+		Customer: buyer,
+	}
+	doc.Lines, doc.Taxes = invoiceLines(order)
+	lineExtension, discount, tax, payable := doc.Totals()
+
+	invoice := &models.Invoice{
+		UUID:              doc.UUID,
+		Year:              now.Year(),
+		OrderID:           order.ID,
+		Profile:           doc.Profile,
+		InvoiceType:       doc.Type,
+		IssuedAt:          now,
+		CustomerTaxID:     buyer.TaxID,
+		CustomerName:      buyer.Name,
+		CustomerTaxOffice: buyer.TaxOffice,
+		CustomerAddress:   buyer.Street,
+		CustomerDistrict:  buyer.District,
+		CustomerCity:      buyer.City,
+		CustomerCountry:   buyer.Country,
+		CustomerEmail:     buyer.Email,
+		NetAmount:         lineExtension,
+		DiscountAmount:    discount,
+		TaxAmount:         tax,
+		PayableAmount:     payable,
+		Currency:          doc.Currency,
+		Status:            models.InvoiceStatusIssued,
+		CreatedBy:         userID,
+	}
+
+	// The number is taken in the same transaction as the invoice so the series has no gaps
+	// Numara, serinin boşluksuz kalması için faturayla aynı işlemde alınır
+	err = s.repo.WithTransaction(func(tx *gorm.DB) error {
+		seq, err := s.repo.NextSequenceWithTx(tx, invoice.Year)
+		if err != nil {
+			return err
+		}
+		invoice.Sequence = seq
+		invoice.InvoiceNumber = fmt.Sprintf("%s%d%09d", s.settings.Prefix, invoice.Year, seq)
+		doc.Number = invoice.InvoiceNumber
+
+		xml, err := einvoice.MarshalUBL(doc)
+		if err != nil {
+			return err
+		}
+		invoice.XML = string(xml)
+
+		if err := s.repo.CreateWithTx(tx, invoice); err != nil {
+			return err
+		}
+		return s.audit.Record(tx, userID, models.AuditActionInvoiceIssue, models.AuditEntityInvoice, invoice.ID, nil, invoice, "")
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The outcome of the delivery is recorded on the invoice
+	// Gönderimin sonucu faturaya kaydedilir
+	if s.integrator != nil {
+		_ = s.deliver(invoice)
+	}
+	return invoice, nil
+}
+
+// SendInvoice hands an unsent or failed invoice to the integrator again
+// Gönderilmemiş veya başarısız bir faturayı entegratöre yeniden iletir
+func (s *InvoiceService) SendInvoice(id uint) (*models.Invoice, error) {
+	invoice, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, ErrInvoiceNotFound
+	}
+	if invoice.Status == models.InvoiceStatusSent {
+		return nil, ErrInvoiceAlreadySent
+	}
+	if s.integrator == nil {
+		return nil, s.integratorErr
+	}
+	if err := s.deliver(invoice); err != nil {
+		return invoice, err
+	}
+	return invoice, nil
+}
+
+// GetInvoice returns an invoice with its UBL XML
+// Faturayı UBL XML'i ile birlikte döndürür
+func (s *InvoiceService) GetInvoice(id uint) (*models.Invoice, error) {
+	invoice, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, ErrInvoiceNotFound
+	}
+	return invoice, nil
+}
+
+// GetOrderInvoice returns the invoice of an order
+// Siparişin faturasını döndürür
+func (s *InvoiceService) GetOrderInvoice(orderID uint) (*models.Invoice, error) {
+	invoice, err := s.repo.FindByOrderID(orderID)
+	if err != nil {
+		return nil, ErrInvoiceNotFound
+	}
+	return invoice, nil
+}
+
+// ListInvoices lists invoices matching the filter, newest first
+// Filtreye uyan faturaları yeniden eskiye listeler
+func (s *InvoiceService) ListInvoices(filter models.InvoiceFilter) ([]models.Invoice, error) {
+	if filter.Limit <= 0 || filter.Limit > 500 {
+		filter.Limit = 100
+	}
+	return s.repo.Find(filter)
+}
+
+// deliver sends the invoice to the integrator and records the outcome on it
+// Faturayı entegratöre gönderir ve sonucu faturaya kaydeder
+func (s *InvoiceService) deliver(invoice *models.Invoice) error {
+	reference, sendErr := s.integrator.Send(invoice.InvoiceNumber, invoice.UUID, []byte(invoice.XML))
+	if sendErr != nil {
+		logger.Error("Failed to send invoice", logger.String("invoice", invoice.InvoiceNumber), logger.Err(sendErr))
+		invoice.Status = models.InvoiceStatusFailed
+		invoice.ErrorMessage = sendErr.Error()
+	} else {
+		now := time.Now()
+		invoice.Status = models.InvoiceStatusSent
+		invoice.Reference = reference
+		invoice.ErrorMessage = ""
+		invoice.SentAt = &now
+	}
+	if err := s.repo.Update(invoice); err != nil {
+		return err
+	}
+	return sendErr
+}
+
+// checkSeller makes sure our own tax info and the number series are configured
+// Kendi vergi bilgilerimizin ve numara serisinin yapılandırıldığından emin olur
+func (s *InvoiceService) checkSeller() error {
+	seller := s.settings.Seller
+	if seller.TaxID == "" || seller.Name == "" || seller.TaxOffice == "" || seller.City == "" {
+		return ErrSellerNotConfigured
+	}
+	if err := einvoice.ValidateTaxID(seller.TaxID); err != nil {
+		return fmt.Errorf("seller %w", err)
+	}
+	if len(s.settings.Prefix) != 3 {
+		return errors.New("invoice prefix must be 3 characters")
+	}
+	return nil
+}
+
+// buyerParty validates the customer tax info, companies need their tax office
+// Müşteri vergi bilgilerini doğrular, şirketler vergi dairesi vermelidir
+func buyerParty(c InvoiceCustomer) (einvoice.Party, error) {
+	party := einvoice.Party{
+		TaxID:     strings.TrimSpace(c.TaxID),
+		Name:      strings.TrimSpace(c.Name),
+		TaxOffice: strings.TrimSpace(c.TaxOffice),
+		Street:    strings.TrimSpace(c.Address),
+		District:  strings.TrimSpace(c.District),
+		City:      strings.TrimSpace(c.City),
+		Country:   strings.TrimSpace(c.Country),
+		Email:     strings.TrimSpace(c.Email),
+	}
+	if err := einvoice.ValidateTaxID(party.TaxID); err != nil {
+		return party, err
+	}
+	if party.Name == "" {
+		return party, errors.New("customer name is required")
+	}
+	if party.Scheme() == einvoice.SchemeVKN && party.TaxOffice == "" {
+		return party, errors.New("tax office is required for a VKN")
+	}
+	if party.City == "" {
+		return party, errors.New("customer city is required")
+	}
+	if party.District == "" {
+		party.District = party.City
+	}
+	if party.Country == "" {
+		party.Country = "Türkiye"
+	}
+	return party, nil
+}
+
+// invoiceLines turns the order items into invoice lines without KDV and sums the KDV per rate.
+// The order discount is already spread over the items, so each line carries its share.
+// Sipariş kalemlerini KDV hariç fatura satırlarına çevirir ve KDV'yi oran bazında toplar.
+// Sipariş indirimi kalemlere zaten dağıtılmıştır, her satır kendi payını taşır.
+func invoiceLines(order *models.Order) ([]einvoice.Line, []einvoice.TaxSubtotal) {
+	lines := make([]einvoice.Line, 0, len(order.Items))
+	byRate := map[int]*einvoice.TaxSubtotal{}
+	for _, item := range order.Items {
+		name := item.ProductName
+		if len(item.Modifiers) > 0 {
+			options := make([]string, 0, len(item.Modifiers))
+			for _, m := range item.Modifiers {
+				options = append(options, m.OptionName)
+			}
+			name = fmt.Sprintf("%s (%s)", name, strings.Join(options, ", "))
+		}
+
+		gross := item.NetSubtotal(order.TaxMode)
+		discount := gross - item.NetAmount
+		if discount < 0 {
+			discount = 0
+			gross = item.NetAmount
+		}
+		lines = append(lines, einvoice.Line{
+			Name:        name,
+			Quantity:    item.Quantity,
+			GrossAmount: gross,
+			Discount:    discount,
+			NetAmount:   item.NetAmount,
+			TaxRate:     item.TaxRate,
+			TaxAmount:   item.TaxAmount,
+		})
+
+		t, ok := byRate[item.TaxRate]
+		if !ok {
+			t = &einvoice.TaxSubtotal{TaxRate: item.TaxRate}
+			byRate[item.TaxRate] = t
+		}
+		t.TaxableAmount += item.NetAmount
+		t.TaxAmount += item.TaxAmount
+	}
+
+	taxes := make([]einvoice.TaxSubtotal, 0, len(byRate))
+	for _, t := range byRate {
+		taxes = append(taxes, *t)
+	}
+	sort.Slice(taxes, func(i, j int) bool { return taxes[i].TaxRate < taxes[j].TaxRate })
+	return lines, taxes
+}
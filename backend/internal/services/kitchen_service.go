@@ -0,0 +1,270 @@
+package services
+
+import (
+	"errors"
+	"simple-pos/internal/models"
+	"simple-pos/internal/repositories"
+	"time"
+)
+
+type KitchenService struct {
+	repo repositories.KitchenRepository
+}
+
+func NewKitchenService(repo repositories.KitchenRepository) *KitchenService {
+	return &KitchenService{repo: repo}
+}
+
+// activeKitchenStatuses are the states shown on the kitchen screen by default
+// Mutfak ekranında varsayılan olarak gösterilen durumlar
+var activeKitchenStatuses = []string{
+	models.KitchenStatusQueued,
+	models.KitchenStatusInPreparation,
+	models.KitchenStatusReady,
+}
+
+// kitchenStatusRank orders the ticket lifecycle, lower is less advanced
+// Ticket yaşam döngüsünü sıralar, düşük değer daha geride demektir
+var kitchenStatusRank = map[string]int{
+	models.KitchenStatusQueued:        0,
+	models.KitchenStatusInPreparation: 1,
+	models.KitchenStatusReady:         2,
+	models.KitchenStatusServed:        3,
+}
+
+// StationStat holds ticket timing figures of a station
+// Bir istasyonun ticket süre istatistikleri
+type StationStat struct {
+	StationID       *uint   `json:"station_id"`
+	StationName     string  `json:"station_name"`
+	Tickets         int     `json:"tickets"`
+	Served          int     `json:"served"`
+	AvgWaitSeconds  float64 `json:"avg_wait_seconds"`  // Queued -> in preparation
+	AvgPrepSeconds  float64 `json:"avg_prep_seconds"`  // In preparation -> ready
+	AvgTotalSeconds float64 `json:"avg_total_seconds"` // Queued -> ready
+}
+
+// CreateStation creates a kitchen station
+// Mutfak istasyonu oluşturur
+func (s *KitchenService) CreateStation(name string, sortOrder int, printer string) (*models.KitchenStation, error) {
+	if err := validatePrinter(printer); err != nil {
+		return nil, err
+	}
+
+	station := &models.KitchenStation{
+		Name:      name,
+		IsActive:  true,
+		SortOrder: sortOrder,
+		Printer:   printer,
+	}
+	if err := s.repo.CreateStation(station); err != nil {
+		return nil, err
+	}
+	return station, nil
+}
+
+// ListStations returns all kitchen stations
+// Tüm mutfak istasyonlarını döndürür
+func (s *KitchenService) ListStations() ([]models.KitchenStation, error) {
+	return s.repo.FindAllStations()
+}
+
+// UpdateStation updates a kitchen station
+// Mutfak istasyonunu günceller
+func (s *KitchenService) UpdateStation(id uint, name string, isActive bool, sortOrder int, printer string) (*models.KitchenStation, error) {
+	if err := validatePrinter(printer); err != nil {
+		return nil, err
+	}
+
+	station, err := s.repo.FindStationByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	station.Name = name
+	station.IsActive = isActive
+	station.SortOrder = sortOrder
+	station.Printer = printer
+
+	if err := s.repo.UpdateStation(station); err != nil {
+		return nil, err
+	}
+	return station, nil
+}
+
+// DeleteStation deletes a kitchen station
+// Mutfak istasyonunu siler
+func (s *KitchenService) DeleteStation(id uint) error {
+	return s.repo.DeleteStation(id)
+}
+
+// ListTickets returns tickets for the kitchen screen (active ones unless a status is given)
+// Mutfak ekranı için ticketları döndürür (durum verilmezse aktif olanlar)
+func (s *KitchenService) ListTickets(stationID *uint, status string) ([]models.KitchenTicket, error) {
+	statuses := activeKitchenStatuses
+	if status != "" {
+		statuses = []string{status}
+	}
+	return s.repo.FindTickets(stationID, statuses)
+}
+
+// BumpTicket advances a ticket to its next state
+// Ticket'ı bir sonraki duruma ilerletir
+func (s *KitchenService) BumpTicket(id uint) (*models.KitchenTicket, error) {
+	ticket, err := s.repo.FindTicketByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	next := models.NextKitchenStatus(ticket.Status)
+	if next == "" {
+		return nil, errors.New("ticket cannot be advanced")
+	}
+
+	if err := s.applyStatus(ticket, next); err != nil {
+		return nil, err
+	}
+	return ticket, nil
+}
+
+// SetTicketStatus moves a ticket to an explicit state (e.g. recall a ready ticket)
+// Ticket'ı belirli bir duruma taşır (örn. hazır ticket'ı geri çağırma)
+func (s *KitchenService) SetTicketStatus(id uint, status string) (*models.KitchenTicket, error) {
+	if _, ok := kitchenStatusRank[status]; !ok {
+		return nil, errors.New("invalid kitchen status")
+	}
+
+	ticket, err := s.repo.FindTicketByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if ticket.Status == models.KitchenStatusCancelled {
+		return nil, errors.New("ticket is cancelled")
+	}
+
+	if err := s.applyStatus(ticket, status); err != nil {
+		return nil, err
+	}
+	return ticket, nil
+}
+
+// GetStationStats computes per station timing statistics for tickets created in the range
+// Aralıkta oluşturulan ticketlar için istasyon bazlı süre istatistiklerini hesaplar
+func (s *KitchenService) GetStationStats(start, end time.Time) ([]StationStat, error) {
+	tickets, err := s.repo.FindTicketsBetween(start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	type accumulator struct {
+		stat                    StationStat
+		waitSum, prepSum, total float64
+		waitN, prepN, totalN    int
+	}
+
+	var order []string
+	byStation := make(map[string]*accumulator)
+	for _, t := range tickets {
+		if t.Status == models.KitchenStatusCancelled {
+			continue
+		}
+
+		key := t.StationName
+		acc, ok := byStation[key]
+		if !ok {
+			acc = &accumulator{stat: StationStat{StationID: t.StationID, StationName: t.StationName}}
+			byStation[key] = acc
+			order = append(order, key)
+		}
+
+		acc.stat.Tickets++
+		if t.Status == models.KitchenStatusServed {
+			acc.stat.Served++
+		}
+		if t.StartedAt != nil {
+			acc.waitSum += t.StartedAt.Sub(t.CreatedAt).Seconds()
+			acc.waitN++
+		}
+		if t.StartedAt != nil && t.ReadyAt != nil {
+			acc.prepSum += t.ReadyAt.Sub(*t.StartedAt).Seconds()
+			acc.prepN++
+		}
+		if t.ReadyAt != nil {
+			acc.total += t.ReadyAt.Sub(t.CreatedAt).Seconds()
+			acc.totalN++
+		}
+	}
+
+	stats := make([]StationStat, 0, len(order))
+	for _, key := range order {
+		acc := byStation[key]
+		if acc.waitN > 0 {
+			acc.stat.AvgWaitSeconds = acc.waitSum / float64(acc.waitN)
+		}
+		if acc.prepN > 0 {
+			acc.stat.AvgPrepSeconds = acc.prepSum / float64(acc.prepN)
+		}
+		if acc.totalN > 0 {
+			acc.stat.AvgTotalSeconds = acc.total / float64(acc.totalN)
+		}
+		stats = append(stats, acc.stat)
+	}
+	return stats, nil
+}
+
+// applyStatus sets the status with its timestamp and refreshes the order item status
+// Durumu zaman damgasıyla ayarlar ve sipariş kaleminin durumunu yeniler
+func (s *KitchenService) applyStatus(ticket *models.KitchenTicket, status string) error {
+	now := time.Now()
+	ticket.Status = status
+	switch status {
+	case models.KitchenStatusQueued:
+		ticket.StartedAt, ticket.ReadyAt, ticket.ServedAt = nil, nil, nil
+	case models.KitchenStatusInPreparation:
+		if ticket.StartedAt == nil {
+			ticket.StartedAt = &now
+		}
+		ticket.ReadyAt, ticket.ServedAt = nil, nil
+	case models.KitchenStatusReady:
+		if ticket.StartedAt == nil {
+			ticket.StartedAt = &now
+		}
+		ticket.ReadyAt = &now
+		ticket.ServedAt = nil
+	case models.KitchenStatusServed:
+		if ticket.ReadyAt == nil {
+			ticket.ReadyAt = &now
+		}
+		ticket.ServedAt = &now
+	}
+
+	if err := s.repo.UpdateTicket(ticket); err != nil {
+		return err
+	}
+	return refreshItemKitchenStatus(s.repo, ticket.OrderItemID)
+}
+
+// refreshItemKitchenStatus stores the least advanced active ticket state on the order item
+// Sipariş kalemine en geride olan aktif ticket durumunu yazar
+func refreshItemKitchenStatus(repo repositories.KitchenRepository, itemID uint) error {
+	tickets, err := repo.FindTicketsByItem(itemID)
+	if err != nil {
+		return err
+	}
+
+	status := ""
+	for _, t := range tickets {
+		rank, ok := kitchenStatusRank[t.Status]
+		if !ok {
+			continue // cancelled
+		}
+		if status == "" || rank < kitchenStatusRank[status] {
+			status = t.Status
+		}
+	}
+	if status == "" && len(tickets) > 0 {
+		status = models.KitchenStatusCancelled
+	}
+
+	return repo.UpdateItemStatus(itemID, status)
+}
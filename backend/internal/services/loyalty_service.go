@@ -0,0 +1,661 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"simple-pos/internal/models"
+	"simple-pos/internal/repositories"
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// Loyalty errors
+// Sadakat hataları
+var (
+	ErrLoyaltyRuleNotFound = errors.New("loyalty rule not found")
+	ErrOrderHasNoCustomer  = errors.New("order has no customer")
+	ErrOrderHasDiscount    = errors.New("order already has a discount")
+	ErrNotEnoughPoints     = errors.New("not enough loyalty points")
+	ErrNotEnoughStamps     = errors.New("not enough stamps for a reward")
+)
+
+// LoyaltyRuleInput describes the earn and burn rule of a category
+// Bir kategorinin kazanma ve harcama kuralını tanımlar
+type LoyaltyRuleInput struct {
+	CategoryID      uint
+	PointsPerLira   int64
+	RedeemPoints    bool
+	StampsPerItem   int
+	StampsForReward int
+	IsActive        bool
+}
+
+// RedeemInput spends either points or one stamp card reward on an open order
+// Açık bir siparişte puan veya bir damga kartı ödülü harcar
+type RedeemInput struct {
+	Points     int64 // Points to spend
+	CategoryID *uint // Stamp card to redeem for one free unit of the category
+	UserID     uint
+	Override   *OverrideInput // Manager approval when the discount is above the threshold
+}
+
+type LoyaltyService struct {
+	repo         repositories.LoyaltyRepository
+	customerRepo repositories.CustomerRepository
+	categoryRepo repositories.CategoryRepository
+	orderRepo    repositories.OrderRepository
+	orders       *OrderService
+	pointValue   int64 // Kuruş per point
+}
+
+func NewLoyaltyService(repo repositories.LoyaltyRepository, customerRepo repositories.CustomerRepository, categoryRepo repositories.CategoryRepository, orderRepo repositories.OrderRepository, orders *OrderService, pointValue int64) *LoyaltyService {
+	if pointValue <= 0 {
+		pointValue = 1
+	}
+	return &LoyaltyService{
+		repo:         repo,
+		customerRepo: customerRepo,
+		categoryRepo: categoryRepo,
+		orderRepo:    orderRepo,
+		orders:       orders,
+		pointValue:   pointValue,
+	}
+}
+
+// ListRules returns the loyalty rules of every category
+// Tüm kategorilerin sadakat kurallarını döndürür
+func (s *LoyaltyService) ListRules() ([]models.LoyaltyRule, error) {
+	return s.repo.FindRules()
+}
+
+// CreateRule adds the loyalty rule of a category, each category has at most one
+// Bir kategorinin sadakat kuralını ekler, her kategorinin en fazla bir kuralı olur
+func (s *LoyaltyService) CreateRule(input LoyaltyRuleInput) (*models.LoyaltyRule, error) {
+	rule := &models.LoyaltyRule{}
+	if err := s.applyRule(rule, input); err != nil {
+		return nil, err
+	}
+	if err := s.repo.CreateRule(rule); err != nil {
+		return nil, errors.New("category already has a loyalty rule")
+	}
+	return s.repo.FindRuleByID(rule.ID)
+}
+
+// UpdateRule changes a loyalty rule, balances collected so far are kept
+// Sadakat kuralını değiştirir, şimdiye kadar toplanan bakiyeler korunur
+func (s *LoyaltyService) UpdateRule(id uint, input LoyaltyRuleInput) (*models.LoyaltyRule, error) {
+	rule, err := s.repo.FindRuleByID(id)
+	if err != nil {
+		return nil, ErrLoyaltyRuleNotFound
+	}
+	if err := s.applyRule(rule, input); err != nil {
+		return nil, err
+	}
+	if err := s.repo.UpdateRule(rule); err != nil {
+		return nil, errors.New("category already has a loyalty rule")
+	}
+	return s.repo.FindRuleByID(rule.ID)
+}
+
+// DeleteRule stops a category from earning and burning loyalty
+// Bir kategorinin sadakat kazanmasını ve harcamasını durdurur
+func (s *LoyaltyService) DeleteRule(id uint) error {
+	if _, err := s.repo.FindRuleByID(id); err != nil {
+		return ErrLoyaltyRuleNotFound
+	}
+	return s.repo.DeleteRule(id)
+}
+
+// AttachCustomer links an open order to the customer with the given phone number, an empty phone unlinks it
+// Açık siparişi verilen telefon numarasına sahip müşteriye bağlar, boş telefon bağlantıyı kaldırır
+func (s *LoyaltyService) AttachCustomer(orderID uint, phone string) (*models.Order, error) {
+	phone = strings.TrimSpace(phone)
+	if phone == "" {
+		return s.orders.SetOrderCustomer(orderID, nil)
+	}
+
+	customer, err := s.customerRepo.FindByPhone(phone)
+	if err != nil {
+		return nil, ErrCustomerNotFound
+	}
+	return s.orders.SetOrderCustomer(orderID, &customer.ID)
+}
+
+// GetBalance returns the points, stamp cards and latest history of a customer
+// Müşterinin puanlarını, damga kartlarını ve son geçmişini döndürür
+func (s *LoyaltyService) GetBalance(customerID uint, limit int) (*models.LoyaltyBalance, error) {
+	customer, err := s.customerRepo.FindByID(customerID)
+	if err != nil {
+		return nil, ErrCustomerNotFound
+	}
+	if limit <= 0 {
+		limit = 50
+	}
+
+	cards, err := s.repo.FindStampCards(customerID)
+	if err != nil {
+		return nil, err
+	}
+	rules, err := s.repo.FindRules()
+	if err != nil {
+		return nil, err
+	}
+	rulesByCategory := make(map[uint]models.LoyaltyRule, len(rules))
+	for _, rule := range rules {
+		rulesByCategory[rule.CategoryID] = rule
+	}
+
+	history, err := s.repo.FindEntries(customerID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	balance := &models.LoyaltyBalance{
+		CustomerID:  customer.ID,
+		Points:      customer.LoyaltyPoints,
+		PointsValue: customer.LoyaltyPoints * s.pointValue,
+		StampCards:  []models.LoyaltyStampBalance{},
+		History:     history,
+	}
+	for _, card := range cards {
+		stamps := models.LoyaltyStampBalance{CategoryID: card.CategoryID, Stamps: card.Stamps}
+		if rule, ok := rulesByCategory[card.CategoryID]; ok {
+			if rule.Category != nil {
+				stamps.CategoryName = rule.Category.Name
+			}
+			stamps.StampsForReward = rule.StampsForReward
+			if rule.StampsForReward > 0 {
+				stamps.RewardsAvailable = card.Stamps / rule.StampsForReward
+			}
+		}
+		balance.StampCards = append(balance.StampCards, stamps)
+	}
+	return balance, nil
+}
+
+// Redeem turns points or a stamp card reward into an AMOUNT discount, saved with the redemption in one transaction.
+// The balance is spent when the order is closed, so dropping the discount gives it back.
+// Puanları veya damga kartı ödülünü TUTAR indirimine çevirir, indirim ve kullanım tek işlemde kaydedilir.
+// Bakiye sipariş kapanınca harcanır, indirim kaldırılırsa geri kazanılmış olur.
+func (s *LoyaltyService) Redeem(orderID uint, input RedeemInput) (*models.Order, error) {
+	if (input.Points > 0) == (input.CategoryID != nil) {
+		return nil, errors.New("redeem either points or a stamp card")
+	}
+	if input.Points < 0 {
+		return nil, errors.New("points must be positive")
+	}
+
+	order, err := s.orderRepo.FindByID(orderID)
+	if err != nil {
+		return nil, errors.New("order not found")
+	}
+	if order.Status != "OPEN" {
+		return nil, errors.New("cannot redeem on a closed order")
+	}
+	if order.CustomerID == nil {
+		return nil, ErrOrderHasNoCustomer
+	}
+
+	redemption := &models.LoyaltyRedemption{
+		OrderID:    order.ID,
+		CustomerID: *order.CustomerID,
+	}
+
+	err = s.repo.WithTransaction(func(tx *gorm.DB) error {
+		pending, err := s.repo.FindRedemptionWithTx(tx, order.ID)
+		if err != nil {
+			return err
+		}
+		if pending == nil && order.DiscountType != "" && order.DiscountType != "NONE" {
+			return ErrOrderHasDiscount
+		}
+
+		customer, err := s.customerRepo.FindByIDWithTx(tx, *order.CustomerID)
+		if err != nil {
+			return ErrCustomerNotFound
+		}
+		rules, err := s.repo.FindActiveRulesWithTx(tx)
+		if err != nil {
+			return err
+		}
+		categories, err := s.repo.ProductCategoriesWithTx(tx, orderProductIDs(order.Items))
+		if err != nil {
+			return err
+		}
+
+		if input.Points > 0 {
+			if customer.LoyaltyPoints < input.Points {
+				return ErrNotEnoughPoints
+			}
+			var redeemable int64
+			for _, item := range order.Items {
+				if rule, ok := rules[categories[item.ProductID]]; ok && rule.RedeemPoints {
+					redeemable += item.Subtotal - item.PromotionAmount
+				}
+			}
+			if redeemable == 0 {
+				return errors.New("no item on the order can be paid with points")
+			}
+			redemption.Points = input.Points
+			redemption.Amount = input.Points * s.pointValue
+			if redemption.Amount > redeemable {
+				return errors.New("points exceed the amount that can be paid with points")
+			}
+			return s.saveRedemption(tx, order, redemption, fmt.Sprintf("Loyalty: %d points", input.Points), input)
+		}
+
+		rule, ok := rules[*input.CategoryID]
+		if !ok || rule.StampsForReward <= 0 {
+			return errors.New("category has no stamp card")
+		}
+		card, err := s.repo.FindStampCardWithTx(tx, customer.ID, *input.CategoryID)
+		if err != nil {
+			return err
+		}
+		if card.Stamps < rule.StampsForReward {
+			return ErrNotEnoughStamps
+		}
+
+		// The reward covers one unit, the cheapest of the category on the order after promotions
+		// Ödül bir adedi karşılar, siparişteki kategorinin promosyonlar sonrası en ucuzunu
+		var free *models.OrderItem
+		var freePrice int64
+		for i := range order.Items {
+			item := &order.Items[i]
+			if categories[item.ProductID] != *input.CategoryID || item.Quantity <= 0 {
+				continue
+			}
+			price := (item.Subtotal - item.PromotionAmount) / int64(item.Quantity)
+			if free == nil || price < freePrice {
+				free, freePrice = item, price
+			}
+		}
+		if free == nil {
+			return errors.New("no item of the stamp card's category on the order")
+		}
+		if freePrice <= 0 {
+			return errors.New("the stamp card's item is already free")
+		}
+		redemption.CategoryID = input.CategoryID
+		redemption.Stamps = rule.StampsForReward
+		redemption.Amount = freePrice
+		return s.saveRedemption(tx, order, redemption, "Loyalty: free "+free.ProductName, input)
+	})
+	if err != nil {
+		s.orders.overrides.RecordFailure(err)
+		return nil, err
+	}
+
+	s.orders.publishDiscount(order)
+	return order, nil
+}
+
+// saveRedemption applies the discount of a redemption and stores it as pending
+// Kullanımın indirimini uygular ve bekleyen olarak saklar
+func (s *LoyaltyService) saveRedemption(tx *gorm.DB, order *models.Order, redemption *models.LoyaltyRedemption, reason string, input RedeemInput) error {
+	if err := s.orders.ApplyDiscountWithTx(tx, order, "AMOUNT", redemption.Amount, reason, input.UserID, input.Override); err != nil {
+		return err
+	}
+	return s.repo.SaveRedemptionWithTx(tx, redemption)
+}
+
+// SettleOrderWithTx is the close hook of loyalty: it spends a pending redemption whose discount
+// is still on the order, then credits points and stamps for what the customer bought.
+// Sadakatin kapanış hook'u: indirimi siparişte duran bekleyen kullanımı harcar,
+// ardından müşterinin satın aldıkları için puan ve damga ekler.
+func (s *LoyaltyService) SettleOrderWithTx(tx *gorm.DB, order *models.Order, items []models.OrderItem, userID uint) error {
+	redemption, err := s.repo.FindRedemptionWithTx(tx, order.ID)
+	if err != nil {
+		return err
+	}
+	if redemption != nil {
+		if err := s.repo.DeleteRedemptionWithTx(tx, order.ID); err != nil {
+			return err
+		}
+	}
+	if order.CustomerID == nil {
+		return nil
+	}
+
+	customer, err := s.customerRepo.FindByIDWithTx(tx, *order.CustomerID)
+	if err != nil {
+		return ErrCustomerNotFound
+	}
+
+	if redemption != nil && redemption.CustomerID == customer.ID &&
+		order.DiscountType == "AMOUNT" && order.DiscountValue == redemption.Amount {
+		if err := s.spend(tx, customer, order, redemption, userID); err != nil {
+			return err
+		}
+	}
+	return s.earn(tx, customer, order, items, userID)
+}
+
+// spend takes a redemption off the customer's points or stamp card
+// Kullanımı müşterinin puanlarından veya damga kartından düşer
+func (s *LoyaltyService) spend(tx *gorm.DB, customer *models.Customer, order *models.Order, redemption *models.LoyaltyRedemption, userID uint) error {
+	if redemption.Points > 0 {
+		if customer.LoyaltyPoints < redemption.Points {
+			return ErrNotEnoughPoints
+		}
+		if err := s.repo.AddPointsWithTx(tx, customer.ID, -redemption.Points); err != nil {
+			return err
+		}
+	}
+	if redemption.CategoryID != nil {
+		card, err := s.repo.FindStampCardWithTx(tx, customer.ID, *redemption.CategoryID)
+		if err != nil {
+			return err
+		}
+		if card.Stamps < redemption.Stamps {
+			return ErrNotEnoughStamps
+		}
+		if err := s.repo.AddStampsWithTx(tx, customer.ID, *redemption.CategoryID, -redemption.Stamps); err != nil {
+			return err
+		}
+	}
+
+	return s.repo.CreateEntryWithTx(tx, &models.LoyaltyEntry{
+		CustomerID: customer.ID,
+		OrderID:    &order.ID,
+		Type:       models.LoyaltyEntryRedeem,
+		Points:     -redemption.Points,
+		CategoryID: redemption.CategoryID,
+		Stamps:     -redemption.Stamps,
+		Amount:     redemption.Amount,
+		CreatedBy:  userID,
+	})
+}
+
+// ReverseRefundWithTx is the refund hook of loyalty: it takes back what the refunded share earned
+// with negative EARN entries, and gives back the points or stamps spent once the order is fully refunded.
+// Balances may drop below zero when the earned points were spent in the meantime.
+// Sadakatin iade hook'u: iade edilen payın kazandırdıklarını negatif EARN kayıtlarıyla geri alır,
+// sipariş tamamen iade edildiğinde harcanan puan veya damgaları geri verir.
+// Kazanılan puanlar bu arada harcandıysa bakiyeler sıfırın altına inebilir.
+func (s *LoyaltyService) ReverseRefundWithTx(tx *gorm.DB, order *models.Order, refund *models.Refund, userID uint) error {
+	if order.CustomerID == nil {
+		return nil
+	}
+	entries, err := s.repo.FindOrderEntriesWithTx(tx, order.ID)
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+
+	// What the order still holds after earlier refunds, and what it spent
+	// Siparişin önceki iadelerden sonra elinde kalanlar ve harcadıkları
+	var earnedPoints, spentPoints, spentPointsAmount int64
+	earnedStamps := make(map[uint]int)
+	spentStamps := make(map[uint]int)
+	spentStampsAmount := make(map[uint]int64)
+	for _, entry := range entries {
+		switch entry.Type {
+		case models.LoyaltyEntryEarn:
+			earnedPoints += entry.Points
+			if entry.CategoryID != nil {
+				earnedStamps[*entry.CategoryID] += entry.Stamps
+			}
+		case models.LoyaltyEntryRedeem:
+			if entry.CategoryID != nil {
+				spentStamps[*entry.CategoryID] -= entry.Stamps
+				spentStampsAmount[*entry.CategoryID] += entry.Amount
+			} else {
+				spentPoints -= entry.Points
+				spentPointsAmount += entry.Amount
+			}
+		}
+	}
+
+	fullRefund := order.Status == "REFUNDED"
+	points := earnedPoints
+	stamps := earnedStamps
+	if !fullRefund {
+		if points, stamps, err = s.refundedShare(tx, order, refund); err != nil {
+			return err
+		}
+	}
+
+	if points > earnedPoints {
+		points = earnedPoints
+	}
+	if points > 0 {
+		if err := s.repo.AddPointsWithTx(tx, *order.CustomerID, -points); err != nil {
+			return err
+		}
+		if err := s.repo.CreateEntryWithTx(tx, &models.LoyaltyEntry{
+			CustomerID: *order.CustomerID,
+			OrderID:    &order.ID,
+			Type:       models.LoyaltyEntryEarn,
+			Points:     -points,
+			CreatedBy:  userID,
+		}); err != nil {
+			return err
+		}
+	}
+	for _, categoryID := range sortedCategories(stamps) {
+		categoryID := categoryID
+		n := stamps[categoryID]
+		if n > earnedStamps[categoryID] {
+			n = earnedStamps[categoryID]
+		}
+		if n <= 0 {
+			continue
+		}
+		if err := s.repo.AddStampsWithTx(tx, *order.CustomerID, categoryID, -n); err != nil {
+			return err
+		}
+		if err := s.repo.CreateEntryWithTx(tx, &models.LoyaltyEntry{
+			CustomerID: *order.CustomerID,
+			OrderID:    &order.ID,
+			Type:       models.LoyaltyEntryEarn,
+			CategoryID: &categoryID,
+			Stamps:     -n,
+			CreatedBy:  userID,
+		}); err != nil {
+			return err
+		}
+	}
+	if !fullRefund {
+		return nil
+	}
+
+	// A full refund also gives back what the order spent, as positive REDEEM entries
+	// Tam iade siparişin harcadıklarını da pozitif REDEEM kayıtlarıyla geri verir
+	if spentPoints > 0 {
+		if err := s.repo.AddPointsWithTx(tx, *order.CustomerID, spentPoints); err != nil {
+			return err
+		}
+		if err := s.repo.CreateEntryWithTx(tx, &models.LoyaltyEntry{
+			CustomerID: *order.CustomerID,
+			OrderID:    &order.ID,
+			Type:       models.LoyaltyEntryRedeem,
+			Points:     spentPoints,
+			Amount:     -spentPointsAmount,
+			CreatedBy:  userID,
+		}); err != nil {
+			return err
+		}
+	}
+	for _, categoryID := range sortedCategories(spentStamps) {
+		categoryID := categoryID
+		n := spentStamps[categoryID]
+		if n <= 0 {
+			continue
+		}
+		if err := s.repo.AddStampsWithTx(tx, *order.CustomerID, categoryID, n); err != nil {
+			return err
+		}
+		if err := s.repo.CreateEntryWithTx(tx, &models.LoyaltyEntry{
+			CustomerID: *order.CustomerID,
+			OrderID:    &order.ID,
+			Type:       models.LoyaltyEntryRedeem,
+			CategoryID: &categoryID,
+			Stamps:     n,
+			Amount:     -spentStampsAmount[categoryID],
+			CreatedBy:  userID,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// refundedShare works out the points and stamps the refunded units earned, with the rules earn uses
+// İade edilen adetlerin kazandırdığı puan ve damgaları earn'ün kullandığı kurallarla hesaplar
+func (s *LoyaltyService) refundedShare(tx *gorm.DB, order *models.Order, refund *models.Refund) (int64, map[uint]int, error) {
+	stamps := make(map[uint]int)
+	rules, err := s.repo.FindActiveRulesWithTx(tx)
+	if err != nil || len(rules) == 0 {
+		return 0, stamps, err
+	}
+	categories, err := s.repo.ProductCategoriesWithTx(tx, orderProductIDs(order.Items))
+	if err != nil {
+		return 0, stamps, err
+	}
+	products := make(map[uint]uint, len(order.Items))
+	for _, item := range order.Items {
+		products[item.ID] = item.ProductID
+	}
+
+	paid := make(map[uint]int64)
+	for _, ri := range refund.Items {
+		categoryID := categories[products[ri.OrderItemID]]
+		paid[categoryID] += ri.Amount
+		if rule, ok := rules[categoryID]; ok && rule.StampsPerItem > 0 && rule.StampsForReward > 0 {
+			stamps[categoryID] += ri.Quantity * rule.StampsPerItem
+		}
+	}
+
+	var points int64
+	for categoryID, amount := range paid {
+		if rule, ok := rules[categoryID]; ok {
+			points += amount * rule.PointsPerLira / 100
+		}
+	}
+	return points, stamps, nil
+}
+
+// earn credits points on what was paid per category and stamps per unit sold
+// Kategori bazında ödenen tutar üzerinden puan, satılan adet başına damga ekler
+func (s *LoyaltyService) earn(tx *gorm.DB, customer *models.Customer, order *models.Order, items []models.OrderItem, userID uint) error {
+	rules, err := s.repo.FindActiveRulesWithTx(tx)
+	if err != nil {
+		return err
+	}
+	if len(rules) == 0 {
+		return nil
+	}
+	categories, err := s.repo.ProductCategoriesWithTx(tx, orderProductIDs(items))
+	if err != nil {
+		return err
+	}
+
+	paid := make(map[uint]int64)
+	units := make(map[uint]int)
+	for _, item := range items {
+		categoryID := categories[item.ProductID]
+		paid[categoryID] += item.PayableAmount()
+		units[categoryID] += item.Quantity
+	}
+
+	var points int64
+	var stampCategories []uint
+	for categoryID, amount := range paid {
+		rule, ok := rules[categoryID]
+		if !ok {
+			continue
+		}
+		points += amount * rule.PointsPerLira / 100
+		if rule.StampsPerItem > 0 && rule.StampsForReward > 0 {
+			stampCategories = append(stampCategories, categoryID)
+		}
+	}
+	sort.Slice(stampCategories, func(i, j int) bool { return stampCategories[i] < stampCategories[j] })
+
+	if points > 0 {
+		if err := s.repo.AddPointsWithTx(tx, customer.ID, points); err != nil {
+			return err
+		}
+		if err := s.repo.CreateEntryWithTx(tx, &models.LoyaltyEntry{
+			CustomerID: customer.ID,
+			OrderID:    &order.ID,
+			Type:       models.LoyaltyEntryEarn,
+			Points:     points,
+			CreatedBy:  userID,
+		}); err != nil {
+			return err
+		}
+	}
+
+	for _, categoryID := range stampCategories {
+		categoryID := categoryID
+		stamps := units[categoryID] * rules[categoryID].StampsPerItem
+		if err := s.repo.AddStampsWithTx(tx, customer.ID, categoryID, stamps); err != nil {
+			return err
+		}
+		if err := s.repo.CreateEntryWithTx(tx, &models.LoyaltyEntry{
+			CustomerID: customer.ID,
+			OrderID:    &order.ID,
+			Type:       models.LoyaltyEntryEarn,
+			CategoryID: &categoryID,
+			Stamps:     stamps,
+			CreatedBy:  userID,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyRule validates the input and copies it onto the rule
+// Girdiyi doğrular ve kurala kopyalar
+func (s *LoyaltyService) applyRule(rule *models.LoyaltyRule, input LoyaltyRuleInput) error {
+	if _, err := s.categoryRepo.FindByID(input.CategoryID); err != nil {
+		return errors.New("category not found")
+	}
+	if input.PointsPerLira < 0 || input.StampsPerItem < 0 || input.StampsForReward < 0 {
+		return errors.New("loyalty values cannot be negative")
+	}
+	if input.StampsPerItem > 0 && input.StampsForReward == 0 {
+		return errors.New("stamp cards need the number of stamps for a reward")
+	}
+
+	rule.CategoryID = input.CategoryID
+	rule.Category = nil
+	rule.PointsPerLira = input.PointsPerLira
+	rule.RedeemPoints = input.RedeemPoints
+	rule.StampsPerItem = input.StampsPerItem
+	rule.StampsForReward = input.StampsForReward
+	rule.IsActive = input.IsActive
+	return nil
+}
+
+// sortedCategories returns the categories of a stamp map in a stable order
+// Damga haritasının kategorilerini sabit bir sırayla döndürür
+func sortedCategories(stamps map[uint]int) []uint {
+	ids := make([]uint, 0, len(stamps))
+	for id := range stamps {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+// orderProductIDs returns the distinct products of the items
+// Kalemlerin tekil ürünlerini döndürür
+func orderProductIDs(items []models.OrderItem) []uint {
+	seen := make(map[uint]bool, len(items))
+	ids := make([]uint, 0, len(items))
+	for _, item := range items {
+		if !seen[item.ProductID] {
+			seen[item.ProductID] = true
+			ids = append(ids, item.ProductID)
+		}
+	}
+	return ids
+}
@@ -3,6 +3,7 @@ package services
 import (
 	"errors"
 	"simple-pos/internal/models"
+	"simple-pos/internal/platform/events"
 	"simple-pos/internal/repositories"
 	"simple-pos/pkg/logger"
 	"time"
@@ -11,21 +12,36 @@ import (
 )
 
 type ManagementService struct {
-	workPeriodRepo repositories.WorkPeriodRepository
-	orderRepo      repositories.OrderRepository
-	db             *gorm.DB
+	workPeriodRepo  repositories.WorkPeriodRepository
+	orderRepo       repositories.OrderRepository
+	paymentRepo     repositories.PaymentRepository
+	transactionRepo repositories.TransactionRepository
+	productStatRepo repositories.ProductStatRepository
+	db              *gorm.DB
+	bus             *events.Bus
+	audit           *AuditService
 }
 
-func NewManagementService(wpRepo repositories.WorkPeriodRepository, orderRepo repositories.OrderRepository, db *gorm.DB) *ManagementService {
+func NewManagementService(wpRepo repositories.WorkPeriodRepository, orderRepo repositories.OrderRepository, paymentRepo repositories.PaymentRepository, txRepo repositories.TransactionRepository, productStatRepo repositories.ProductStatRepository, db *gorm.DB, bus *events.Bus, audit *AuditService) *ManagementService {
 	return &ManagementService{
-		workPeriodRepo: wpRepo,
-		orderRepo:      orderRepo,
-		db:             db,
+		workPeriodRepo:  wpRepo,
+		orderRepo:       orderRepo,
+		paymentRepo:     paymentRepo,
+		transactionRepo: txRepo,
+		productStatRepo: productStatRepo,
+		db:              db,
+		bus:             bus,
+		audit:           audit,
 	}
 }
 
-// StartDay starts a new work period
-func (s *ManagementService) StartDay(userID uint) error {
+// StartDay starts a new work period with the cash counted into the drawer as opening float
+// Kasaya sayılan nakdi açılış bakiyesi olarak alıp yeni bir çalışma dönemi başlatır
+func (s *ManagementService) StartDay(userID uint, openingFloat int64) error {
+	if openingFloat < 0 {
+		return errors.New("opening float cannot be negative")
+	}
+
 	existing, err := s.workPeriodRepo.FindActivePeriod()
 	if err != nil {
 		return err
@@ -35,8 +51,9 @@ func (s *ManagementService) StartDay(userID uint) error {
 	}
 
 	period := &models.WorkPeriod{
-		StartTime: time.Now(),
-		IsActive:  true,
+		StartTime:    time.Now(),
+		IsActive:     true,
+		OpeningFloat: openingFloat,
 	}
 
 	if err := s.workPeriodRepo.Create(period); err != nil {
@@ -45,11 +62,19 @@ func (s *ManagementService) StartDay(userID uint) error {
 	}
 
 	logger.Info("Work period started", logger.Int("user_id", int(userID)))
+	s.bus.Publish(events.DayStarted, period)
 	return nil
 }
 
-// EndDay closes the current work period and generates a report
-func (s *ManagementService) EndDay(userID uint) (*models.DailyReport, error) {
+// EndDay closes the current work period and generates a report.
+// countedCash is the blind count of the drawer; when given, the over/short variance is stored on the period.
+// Mevcut çalışma dönemini kapatır ve rapor oluşturur.
+// countedCash kasanın kör sayımıdır; verilirse fazla/eksik farkı döneme kaydedilir.
+func (s *ManagementService) EndDay(userID uint, countedCash *int64) (*models.DailyReport, error) {
+	if countedCash != nil && *countedCash < 0 {
+		return nil, errors.New("counted cash cannot be negative")
+	}
+
 	period, err := s.workPeriodRepo.FindActivePeriod()
 	if err != nil {
 		return nil, err
@@ -82,34 +107,57 @@ func (s *ManagementService) EndDay(userID uint) (*models.DailyReport, error) {
 		Select("COALESCE(sum(total_amount), 0)").
 		Scan(&totalSales)
 
-	var cashSales float64
-	s.db.Model(&models.Order{}).
-		Where("work_period_id = ? AND payment_method = ?", period.ID, "CASH").
-		Select("COALESCE(sum(total_amount), 0)").
-		Scan(&cashSales)
-
-	var posSales float64
-	s.db.Model(&models.Order{}).
-		Where("work_period_id = ? AND payment_method = ?", period.ID, "CREDIT_CARD").
-		Select("COALESCE(sum(total_amount), 0)").
-		Scan(&posSales)
-
 	var totalExpenses float64
 	s.db.Model(&models.Transaction{}).
 		Where("work_period_id = ? AND type = ?", period.ID, "EXPENSE").
 		Select("COALESCE(sum(amount), 0)").
 		Scan(&totalExpenses)
 
+	refunds, err := s.transactionRepo.SumRefundsByMethod([]uint{period.ID})
+	if err != nil {
+		return nil, err
+	}
+	var totalRefunds int64
+	for _, amount := range refunds {
+		totalRefunds += amount
+	}
+
+	drawer, err := buildCashDrawer(s.workPeriodRepo, s.paymentRepo, s.transactionRepo, period)
+	if err != nil {
+		return nil, err
+	}
+
 	// 2. Close Work Period with Stats
+	before := *period
 	period.IsActive = false
 	period.EndTime = &now
 	period.ClosedBy = userID
 	period.TotalOrders = int(totalOrders)
 	period.TotalSales = int64(totalSales)
+	period.TotalRefunds = totalRefunds
 	period.TotalExpenses = int64(totalExpenses)
-	period.NetProfit = int64(totalSales - totalExpenses)
+	period.NetProfit = int64(totalSales) - totalRefunds - int64(totalExpenses)
+	period.ExpectedCash = drawer.ExpectedCash
+	if countedCash != nil {
+		variance := *countedCash - drawer.ExpectedCash
+		period.CountedCash = countedCash
+		period.CashVariance = &variance
+	}
+	drawer.CountedCash = period.CountedCash
+	drawer.Variance = period.CashVariance
 
-	if err := s.workPeriodRepo.Update(period); err != nil {
+	err = s.workPeriodRepo.WithTransaction(func(tx *gorm.DB) error {
+		if err := s.workPeriodRepo.UpdateWithTx(tx, period); err != nil {
+			return err
+		}
+		// Per product sales of the period for the menu analytics
+		// Menü analizleri için dönemin ürün bazlı satışları
+		if _, err := s.productStatRepo.RebuildForPeriodWithTx(tx, period); err != nil {
+			return err
+		}
+		return s.audit.Record(tx, userID, models.AuditActionDayClose, models.AuditEntityWorkPeriod, period.ID, before, period, "")
+	})
+	if err != nil {
 		logger.Error("Failed to close work period", logger.Err(err))
 		return nil, err
 	}
@@ -147,9 +195,35 @@ func (s *ManagementService) EndDay(userID uint) (*models.DailyReport, error) {
 	report.TotalOrders = int(drTotalOrders)
 	report.TotalSales = int64(drTotalSales)
 	report.TotalExpenses = int64(drTotalExpenses)
-	report.NetProfit = int64(drTotalSales - drTotalExpenses)
-	report.CashSales = 0 // Keeping simplified
-	report.PosSales = 0
+
+	// Cash/POS split comes from payments of all periods started that day
+	// Nakit/POS dağılımı o gün başlayan tüm dönemlerin ödemelerinden gelir
+	dayPeriods, err := s.workPeriodRepo.GetPeriodsByDate(period.StartTime)
+	if err != nil {
+		return nil, err
+	}
+	var dayPeriodIDs []uint
+	for _, p := range dayPeriods {
+		dayPeriodIDs = append(dayPeriodIDs, p.ID)
+	}
+	paymentTotals, err := s.paymentRepo.SumByMethod(dayPeriodIDs)
+	if err != nil {
+		return nil, err
+	}
+	report.CashSales = paymentTotals[models.PaymentMethodCash]
+	report.PosSales = paymentTotals[models.PaymentMethodCreditCard]
+
+	// Refunds given during the day's periods, net sales and net profit
+	// Günün dönemlerinde verilen iadeler, net satış ve net kar
+	if err := fillRefunds(s.transactionRepo, &report, dayPeriodIDs); err != nil {
+		return nil, err
+	}
+
+	// KDV per rate for the accountant
+	// Muhasebe için orana göre KDV
+	if err := fillTaxBreakdown(s.orderRepo, &report, dayPeriodIDs); err != nil {
+		return nil, err
+	}
 
 	report.UpdatedAt = now
 	if err := s.db.Save(&report).Error; err != nil {
@@ -157,11 +231,164 @@ func (s *ManagementService) EndDay(userID uint) (*models.DailyReport, error) {
 		return nil, err
 	}
 
+	// The drawer belongs to the closed period, not the whole day
+	// Kasa mutabakatı tüm güne değil kapanan döneme aittir
+	report.CashDrawer = drawer
+
 	logger.Info("Work period ended", logger.Int("period_id", int(period.ID)))
+
+	// Everyone learns the shop closed; only report viewers receive the financial summary
+	// Herkes dükkanın kapandığını öğrenir; finansal özet yalnızca rapor görebilenlere gider
+	s.bus.PublishExcept(events.DayEnded, map[string]interface{}{
+		"work_period_id": period.ID,
+		"end_time":       period.EndTime,
+	}, models.PermissionViewReports)
+	s.bus.PublishTo(events.DayEnded, map[string]interface{}{
+		"work_period_id": period.ID,
+		"end_time":       period.EndTime,
+		"report":         report,
+	}, models.PermissionViewReports)
 	return &report, nil
 }
 
+// RebuildProductStats recalculates the product sales stats of the closed periods started within the range,
+// e.g. after refunds given on a later day or for periods closed before the stats existed.
+// Returns the number of periods and stat rows written.
+// Aralıkta başlayan kapanmış dönemlerin ürün satış istatistiklerini yeniden hesaplar,
+// örn. sonraki bir gün verilen iadelerden sonra veya istatistiklerden önce kapanmış dönemler için.
+// Yazılan dönem ve istatistik satırı sayısını döndürür.
+func (s *ManagementService) RebuildProductStats(start, end time.Time) (int, int, error) {
+	periods, err := s.workPeriodRepo.GetPeriodsBetweenDates(start, end)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	rebuilt, rows := 0, 0
+	for i := range periods {
+		period := &periods[i]
+		if period.IsActive || !period.StartTime.Before(end) {
+			continue
+		}
+		err := s.workPeriodRepo.WithTransaction(func(tx *gorm.DB) error {
+			n, err := s.productStatRepo.RebuildForPeriodWithTx(tx, period)
+			rows += n
+			return err
+		})
+		if err != nil {
+			logger.Error("Failed to rebuild product stats", logger.Int("period_id", int(period.ID)), logger.Err(err))
+			return rebuilt, rows, err
+		}
+		rebuilt++
+	}
+
+	logger.Info("Product stats rebuilt", logger.Int("periods", rebuilt), logger.Int("rows", rows))
+	return rebuilt, rows, nil
+}
+
 // GetActivePeriod returns the current active work period or nil if none
 func (s *ManagementService) GetActivePeriod() (*models.WorkPeriod, error) {
 	return s.workPeriodRepo.FindActivePeriod()
 }
+
+// RecordCashMovement records cash added to or taken out of the drawer of the active period
+// Aktif dönemin kasasına eklenen veya kasadan alınan nakdi kaydeder
+func (s *ManagementService) RecordCashMovement(userID uint, movementType string, amount int64, reason string) (*models.CashMovement, error) {
+	switch movementType {
+	case models.CashMovementIn, models.CashMovementDrop, models.CashMovementPayout:
+	default:
+		return nil, errors.New("invalid cash movement type")
+	}
+	if amount <= 0 {
+		return nil, errors.New("amount must be positive")
+	}
+
+	period, err := s.workPeriodRepo.FindActivePeriod()
+	if err != nil {
+		return nil, err
+	}
+	if period == nil {
+		return nil, errors.New("no active work period found")
+	}
+
+	movement := &models.CashMovement{
+		WorkPeriodID: period.ID,
+		Type:         movementType,
+		Amount:       amount,
+		Reason:       reason,
+		CreatedBy:    userID,
+	}
+	err = s.workPeriodRepo.WithTransaction(func(tx *gorm.DB) error {
+		if err := s.workPeriodRepo.CreateCashMovementWithTx(tx, movement); err != nil {
+			return err
+		}
+		return s.audit.Record(tx, userID, models.AuditActionCashMovement, models.AuditEntityCashMovement, movement.ID, nil, movement, reason)
+	})
+	if err != nil {
+		logger.Error("Failed to record cash movement", logger.Err(err))
+		return nil, err
+	}
+	return movement, nil
+}
+
+// ListCashMovements returns the drawer movements of the active period
+// Aktif dönemin kasa hareketlerini döndürür
+func (s *ManagementService) ListCashMovements() ([]models.CashMovement, error) {
+	period, err := s.workPeriodRepo.FindActivePeriod()
+	if err != nil {
+		return nil, err
+	}
+	if period == nil {
+		return []models.CashMovement{}, nil
+	}
+	return s.workPeriodRepo.FindCashMovements(period.ID)
+}
+
+// buildCashDrawer works out the cash expected in the drawer of a period.
+// Closed periods keep the expected amount and count stored when they were closed.
+// Dönemin kasasında olması gereken nakdi hesaplar.
+// Kapanmış dönemler kapanışta kaydedilen beklenen tutarı ve sayımı korur.
+func buildCashDrawer(wpRepo repositories.WorkPeriodRepository, paymentRepo repositories.PaymentRepository, txRepo repositories.TransactionRepository, period *models.WorkPeriod) (*models.CashDrawer, error) {
+	paymentTotals, err := paymentRepo.SumByMethod([]uint{period.ID})
+	if err != nil {
+		return nil, err
+	}
+	movementTotals, err := wpRepo.SumCashMovements(period.ID)
+	if err != nil {
+		return nil, err
+	}
+	expenses, err := txRepo.FindAllByWorkPeriodID(period.ID, "EXPENSE")
+	if err != nil {
+		return nil, err
+	}
+
+	drawer := &models.CashDrawer{
+		OpeningFloat: period.OpeningFloat,
+		CashSales:    paymentTotals[models.PaymentMethodCash],
+		CashIn:       movementTotals[models.CashMovementIn],
+		CashDrops:    movementTotals[models.CashMovementDrop],
+		Payouts:      movementTotals[models.CashMovementPayout],
+	}
+	refunds, err := txRepo.SumRefundsByMethod([]uint{period.ID})
+	if err != nil {
+		return nil, err
+	}
+	drawer.CashRefunds = refunds[models.PaymentMethodCash]
+	accountPayments, err := txRepo.SumAccountPaymentsByMethod([]uint{period.ID})
+	if err != nil {
+		return nil, err
+	}
+	drawer.CashAccount = accountPayments[models.PaymentMethodCash]
+	for _, e := range expenses {
+		if e.PaymentMethod == models.PaymentMethodCash {
+			drawer.CashExpenses += e.Amount
+		}
+	}
+	drawer.ExpectedCash = drawer.OpeningFloat + drawer.CashSales + drawer.CashAccount + drawer.CashIn - drawer.CashDrops - drawer.Payouts - drawer.CashExpenses - drawer.CashRefunds
+
+	if !period.IsActive {
+		drawer.ExpectedCash = period.ExpectedCash
+		drawer.CountedCash = period.CountedCash
+		drawer.Variance = period.CashVariance
+	}
+	return drawer, nil
+}
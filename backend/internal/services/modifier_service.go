@@ -0,0 +1,148 @@
+package services
+
+import (
+	"errors"
+	"simple-pos/internal/models"
+	"simple-pos/internal/repositories"
+)
+
+type ModifierService struct {
+	repo        repositories.ModifierRepository
+	productRepo repositories.ProductRepository
+}
+
+func NewModifierService(repo repositories.ModifierRepository, productRepo repositories.ProductRepository) *ModifierService {
+	return &ModifierService{
+		repo:        repo,
+		productRepo: productRepo,
+	}
+}
+
+// CreateGroup creates a modifier group attached to either a product or a category
+// Bir ürüne veya kategoriye bağlı opsiyon grubu oluşturur
+func (s *ModifierService) CreateGroup(name string, productID, categoryID *uint, isRequired bool, minSelect, maxSelect, sortOrder int) (*models.ModifierGroup, error) {
+	group := &models.ModifierGroup{
+		Name:       name,
+		ProductID:  productID,
+		CategoryID: categoryID,
+		IsRequired: isRequired,
+		MinSelect:  minSelect,
+		MaxSelect:  maxSelect,
+		SortOrder:  sortOrder,
+	}
+	if err := validateModifierGroup(group); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.CreateGroup(group); err != nil {
+		return nil, err
+	}
+	return group, nil
+}
+
+// ListGroups returns all modifier groups with their options
+// Tüm opsiyon gruplarını seçenekleriyle döndürür
+func (s *ModifierService) ListGroups() ([]models.ModifierGroup, error) {
+	return s.repo.FindAllGroups()
+}
+
+// GetProductModifiers returns the groups that apply to a product (own + category)
+// Bir ürüne uygulanan grupları döndürür (kendi + kategori)
+func (s *ModifierService) GetProductModifiers(productID uint) ([]models.ModifierGroup, error) {
+	product, err := s.productRepo.FindByID(productID)
+	if err != nil {
+		return nil, errors.New("product not found")
+	}
+	return s.repo.FindGroupsForProduct(product.ID, product.CategoryID)
+}
+
+// UpdateGroup updates a modifier group
+// Opsiyon grubunu günceller
+func (s *ModifierService) UpdateGroup(id uint, name string, productID, categoryID *uint, isRequired bool, minSelect, maxSelect, sortOrder int) (*models.ModifierGroup, error) {
+	group, err := s.repo.FindGroupByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	group.Name = name
+	group.ProductID = productID
+	group.CategoryID = categoryID
+	group.IsRequired = isRequired
+	group.MinSelect = minSelect
+	group.MaxSelect = maxSelect
+	group.SortOrder = sortOrder
+	if err := validateModifierGroup(group); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpdateGroup(group); err != nil {
+		return nil, err
+	}
+	return group, nil
+}
+
+// DeleteGroup deletes a modifier group and its options
+// Opsiyon grubunu ve seçeneklerini siler
+func (s *ModifierService) DeleteGroup(id uint) error {
+	return s.repo.DeleteGroup(id)
+}
+
+// AddOption adds an option to a modifier group
+// Opsiyon grubuna seçenek ekler
+func (s *ModifierService) AddOption(groupID uint, name string, priceDelta int64, sortOrder int) (*models.ModifierOption, error) {
+	if _, err := s.repo.FindGroupByID(groupID); err != nil {
+		return nil, errors.New("modifier group not found")
+	}
+
+	option := &models.ModifierOption{
+		GroupID:     groupID,
+		Name:        name,
+		PriceDelta:  priceDelta,
+		IsAvailable: true,
+		SortOrder:   sortOrder,
+	}
+	if err := s.repo.CreateOption(option); err != nil {
+		return nil, err
+	}
+	return option, nil
+}
+
+// UpdateOption updates a modifier option
+// Opsiyon seçeneğini günceller
+func (s *ModifierService) UpdateOption(id uint, name string, priceDelta int64, isAvailable bool, sortOrder int) (*models.ModifierOption, error) {
+	option, err := s.repo.FindOptionByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	option.Name = name
+	option.PriceDelta = priceDelta
+	option.IsAvailable = isAvailable
+	option.SortOrder = sortOrder
+
+	if err := s.repo.UpdateOption(option); err != nil {
+		return nil, err
+	}
+	return option, nil
+}
+
+// DeleteOption deletes a modifier option
+// Opsiyon seçeneğini siler
+func (s *ModifierService) DeleteOption(id uint) error {
+	return s.repo.DeleteOption(id)
+}
+
+// validateModifierGroup checks attachment and selection bounds
+// Bağlantıyı ve seçim sınırlarını kontrol eder
+func validateModifierGroup(group *models.ModifierGroup) error {
+	if (group.ProductID == nil) == (group.CategoryID == nil) {
+		return errors.New("modifier group must be attached to either a product or a category")
+	}
+	if group.MinSelect < 0 || group.MaxSelect < 0 {
+		return errors.New("selection limits cannot be negative")
+	}
+	if group.MaxSelect > 0 && group.MinSelect > group.MaxSelect {
+		return errors.New("min selection cannot exceed max selection")
+	}
+	return nil
+}
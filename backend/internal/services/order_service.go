@@ -2,7 +2,9 @@ package services
 
 import (
 	"errors"
+	"fmt"
 	"simple-pos/internal/models"
+	"simple-pos/internal/platform/events"
 	"simple-pos/internal/repositories"
 	"simple-pos/pkg/logger"
 	"strconv"
@@ -12,34 +14,94 @@ import (
 	"gorm.io/gorm"
 )
 
+// ErrOrderCompleted is returned when a completed order would be edited; refunds reverse it instead
+// Tamamlanmış sipariş düzenlenmek istendiğinde döner; bunun yerine iade kullanılır
+var ErrOrderCompleted = errors.New("completed orders cannot be changed, refund items instead")
+
 type OrderService struct {
 	orderRepo       repositories.OrderRepository
 	transactionRepo repositories.TransactionRepository
 	workPeriodRepo  repositories.WorkPeriodRepository
 	productRepo     repositories.ProductRepository
 	tableRepo       repositories.TableRepository
+	paymentRepo     repositories.PaymentRepository
+	modifierRepo    repositories.ModifierRepository
+	kitchenRepo     repositories.KitchenRepository
+	bus             *events.Bus
+	inventory       *InventoryService
+	audit           *AuditService
+	printer         *PrintService
+	overrides       *OverrideService
+	customers       *CustomerService
+	couponRepo      repositories.CouponRepository
+
+	// closeHooks run inside the closing transaction of every order
+	// Her siparişin kapanış işlemi içinde çalışır
+	closeHooks []CloseHook
 }
 
-func NewOrderService(orderRepo repositories.OrderRepository, txRepo repositories.TransactionRepository, wpRepo repositories.WorkPeriodRepository, prodRepo repositories.ProductRepository, tableRepo repositories.TableRepository) *OrderService {
+// CloseHook runs inside the closing transaction once the order is completed, an error rolls the close back
+// Sipariş tamamlandıktan sonra kapanış işlemi içinde çalışır, hata kapanışı geri alır
+type CloseHook func(tx *gorm.DB, order *models.Order, items []models.OrderItem, userID uint) error
+
+func NewOrderService(orderRepo repositories.OrderRepository, txRepo repositories.TransactionRepository, wpRepo repositories.WorkPeriodRepository, prodRepo repositories.ProductRepository, tableRepo repositories.TableRepository, paymentRepo repositories.PaymentRepository, modifierRepo repositories.ModifierRepository, kitchenRepo repositories.KitchenRepository, bus *events.Bus, inventory *InventoryService, audit *AuditService, printer *PrintService, overrides *OverrideService, customers *CustomerService, couponRepo repositories.CouponRepository) *OrderService {
 	return &OrderService{
 		orderRepo:       orderRepo,
 		transactionRepo: txRepo,
 		workPeriodRepo:  wpRepo,
 		productRepo:     prodRepo,
 		tableRepo:       tableRepo,
+		paymentRepo:     paymentRepo,
+		modifierRepo:    modifierRepo,
+		kitchenRepo:     kitchenRepo,
+		bus:             bus,
+		inventory:       inventory,
+		audit:           audit,
+		printer:         printer,
+		overrides:       overrides,
+		customers:       customers,
+		couponRepo:      couponRepo,
 	}
 }
 
+// OnClose registers a hook that runs whenever an order is closed
+// Her sipariş kapandığında çalışacak bir hook kaydeder
+func (s *OrderService) OnClose(hook CloseHook) {
+	s.closeHooks = append(s.closeHooks, hook)
+}
+
+// PaymentItemInput selects an order item and how many of its units are being paid
+// Ödenen sipariş kalemini ve adedini belirtir
+type PaymentItemInput struct {
+	ItemID   uint
+	Quantity int
+}
+
+// PaymentInput describes a partial payment request
+// Kısmi ödeme isteğini tanımlar
+type PaymentInput struct {
+	Method     string
+	SplitType  string // FULL, AMOUNT, ITEMS, EQUAL
+	Amount     int64  // AMOUNT only
+	Items      []PaymentItemInput
+	SplitCount int // EQUAL only: number of guests sharing the bill
+	Shares     int // EQUAL only: how many shares this payment covers (default 1)
+	UserID     uint
+}
+
 // AddOrderItem adds an item to an existing OPEN order
 // Mevcut AÇIK bir siparişe ürün ekler
-func (s *OrderService) AddOrderItem(orderID uint, productID uint, quantity int, note string) (*models.OrderItem, error) {
+func (s *OrderService) AddOrderItem(orderID uint, productID uint, quantity int, note string, optionIDs []uint) (*models.OrderItem, error) {
 	// 1. Validate Order
 	order, err := s.orderRepo.FindByID(orderID)
 	if err != nil {
 		return nil, err
 	}
-	if order.Status != "OPEN" && order.Status != "COMPLETED" {
-		return nil, errors.New("cannot modify cancelled order")
+	if order.Status == "COMPLETED" {
+		return nil, ErrOrderCompleted
+	}
+	if order.Status != "OPEN" {
+		return nil, errors.New("cannot modify closed order")
 	}
 
 	// 2. Fetch Product (for Price snapshot)
@@ -48,27 +110,59 @@ func (s *OrderService) AddOrderItem(orderID uint, productID uint, quantity int,
 		return nil, errors.New("product not found")
 	}
 
-	// 3. Create Item
+	// 3. Validate & Snapshot Modifiers
+	// Opsiyonları doğrula ve snapshot al
+	modifiers, err := s.resolveModifiers(product, optionIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	// 4. Make sure the kitchen has enough stock (when blocking is enabled)
+	// Mutfakta yeterli stok olduğundan emin ol (engelleme açıksa)
+	if err := s.inventory.CheckAvailability(productID, quantity); err != nil {
+		return nil, err
+	}
+
+	// 5. Create Item
 	item := &models.OrderItem{
 		OrderID:     orderID,
 		ProductID:   productID,
 		ProductName: product.Name,
 		Quantity:    quantity,
 		UnitPrice:   product.Price,
+		TaxRate:     product.EffectiveTaxRate(),
+		Snapshotted: true,
+		Modifiers:   modifiers,
+		Note:        strings.TrimSpace(note),
 		// Subtotal and TotalAmount will be handled by BeforeCreate/AfterSave hooks
 	}
 
-	// 4. Save Item
+	// 6. Save Item
 	// This triggers Hooks: BeforeCreate (Snapshots/Subtotal) -> Insert -> AfterSave (Recalculate Order Total)
 	if err := s.orderRepo.AddItem(item); err != nil {
 		return nil, err
 	}
 
-	if order.Status == "COMPLETED" {
-		if err := s.syncTransactionAmount(orderID); err != nil {
-			logger.Error("Failed to sync transaction amount", logger.Err(err))
-		}
+	// 7. Fire to Kitchen
+	// Mutfağa gönder
+	if err := s.fireItem(order, item, item.Quantity, modifierSummary(item.Modifiers)); err != nil {
+		logger.Error("Failed to create kitchen ticket", logger.Err(err))
+	}
+
+	// Tax snapshots and kitchen status are written with column updates, reload them for the caller
+	// Vergi snapshot'ları ve mutfak durumu kolon güncellemesiyle yazılır, çağırana güncel halini ver
+	saved, err := s.orderRepo.FindItem(item.ID)
+	if err != nil {
+		logger.Error("Failed to reload added item", logger.Int("item_id", int(item.ID)), logger.Err(err))
+		return nil, err
 	}
+	item.PromotionAmount = saved.PromotionAmount
+	item.DiscountAmount = saved.DiscountAmount
+	item.NetAmount = saved.NetAmount
+	item.TaxAmount = saved.TaxAmount
+	item.KitchenStatus = saved.KitchenStatus
+
+	s.publishOrderEvent(events.ItemAdded, order, map[string]interface{}{"item": item})
 
 	return item, nil
 }
@@ -81,8 +175,11 @@ func (s *OrderService) UpdateItemQuantity(orderID, itemID uint, quantity int) er
 	if err != nil {
 		return err
 	}
-	if order.Status != "OPEN" && order.Status != "COMPLETED" {
-		return errors.New("cannot modify cancelled order")
+	if order.Status == "COMPLETED" {
+		return ErrOrderCompleted
+	}
+	if order.Status != "OPEN" {
+		return errors.New("cannot modify closed order")
 	}
 
 	// 2. Fetch Item
@@ -93,10 +190,22 @@ func (s *OrderService) UpdateItemQuantity(orderID, itemID uint, quantity int) er
 	if item.OrderID != orderID {
 		return errors.New("item does not belong to this order")
 	}
+	if quantity < item.PaidQuantity {
+		return errors.New("cannot reduce quantity below already paid units")
+	}
+	if s.projectedTotal(order, item.ID, quantity) < order.PaidAmount {
+		return errors.New("order total cannot drop below the amount already paid")
+	}
+	if quantity > item.Quantity {
+		if err := s.inventory.CheckAvailability(item.ProductID, quantity-item.Quantity); err != nil {
+			return err
+		}
+	}
 
 	// 3. Update Logic
+	oldQuantity := item.Quantity
 	item.Quantity = quantity
-	item.Subtotal = int64(quantity) * item.UnitPrice // Manual update needed or rely on hooks if we used repository Update efficiently.
+	item.Subtotal = int64(quantity) * item.LinePrice() // Manual update needed or rely on hooks if we used repository Update efficiently.
 	// Hooks usually run on Save. But specific column update might skip. Best to set explicitly.
 	// Also need to trigger AfterSave for Order Total Recalc.
 
@@ -104,25 +213,111 @@ func (s *OrderService) UpdateItemQuantity(orderID, itemID uint, quantity int) er
 		return err
 	}
 
-	if order.Status == "COMPLETED" {
-		if err := s.syncTransactionAmount(orderID); err != nil {
-			logger.Error("Failed to sync transaction amount", logger.Err(err))
+	if err := s.adjustItemTickets(order, item, oldQuantity); err != nil {
+		logger.Error("Failed to adjust kitchen tickets", logger.Err(err))
+	}
+
+	s.publishOrderEvent(events.ItemUpdated, order, map[string]interface{}{"item": item})
+
+	return nil
+}
+
+// UpdateItemNote updates the kitchen note of an item in an OPEN order
+// AÇIK siparişteki bir ürünün mutfak notunu günceller
+func (s *OrderService) UpdateItemNote(orderID, itemID uint, note string) error {
+	order, err := s.orderRepo.FindByID(orderID)
+	if err != nil {
+		return err
+	}
+	if order.Status != "OPEN" {
+		return errors.New("cannot edit notes of a closed order")
+	}
+
+	item, err := s.orderRepo.FindItem(itemID)
+	if err != nil {
+		return err
+	}
+	if item.OrderID != orderID {
+		return errors.New("item does not belong to this order")
+	}
+
+	item.Note = strings.TrimSpace(note)
+	if err := s.orderRepo.UpdateItem(item); err != nil {
+		return err
+	}
+
+	// Carry the note to tickets the kitchen has not finished yet
+	// Notu mutfağın henüz bitirmediği ticketlara taşı
+	tickets, err := s.kitchenRepo.FindTicketsByItem(itemID)
+	if err != nil {
+		return err
+	}
+	for i := range tickets {
+		if tickets[i].Status == models.KitchenStatusQueued || tickets[i].Status == models.KitchenStatusInPreparation {
+			tickets[i].Note = item.Note
+			if err := s.kitchenRepo.UpdateTicket(&tickets[i]); err != nil {
+				return err
+			}
 		}
 	}
 
+	s.publishOrderEvent(events.ItemUpdated, order, map[string]interface{}{"item": item})
 	return nil
 }
 
+// UpdateOrderNote updates the order level note of an OPEN order
+// AÇIK siparişin genel notunu günceller
+func (s *OrderService) UpdateOrderNote(orderID uint, note string) (*models.Order, error) {
+	order, err := s.orderRepo.FindByID(orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status != "OPEN" {
+		return nil, errors.New("cannot edit notes of a closed order")
+	}
+
+	order.Note = strings.TrimSpace(note)
+	if err := s.orderRepo.Update(order); err != nil {
+		return nil, err
+	}
+
+	s.publishOrderEvent(events.OrderUpdated, order, map[string]interface{}{"note": order.Note})
+	return order, nil
+}
+
+// SetOrderCustomer links an OPEN order to a customer for loyalty and tabs, nil unlinks it
+// AÇIK siparişi sadakat ve veresiye için bir müşteriye bağlar, nil bağlantıyı kaldırır
+func (s *OrderService) SetOrderCustomer(orderID uint, customerID *uint) (*models.Order, error) {
+	order, err := s.orderRepo.FindByID(orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status != "OPEN" {
+		return nil, errors.New("cannot change the customer of a closed order")
+	}
+
+	order.CustomerID = customerID
+	if err := s.orderRepo.Update(order); err != nil {
+		return nil, err
+	}
+
+	s.publishOrderEvent(events.OrderUpdated, order, map[string]interface{}{"customer_id": order.CustomerID})
+	return order, nil
+}
+
 // RemoveOrderItem removes an item from OPEN order
 // AÇIK siparişten bir ürünü kaldırır
-func (s *OrderService) RemoveOrderItem(orderID, itemID uint) error {
+func (s *OrderService) RemoveOrderItem(orderID, itemID, userID uint) error {
 	// 1. Validate Order
 	order, err := s.orderRepo.FindByID(orderID)
 	if err != nil {
 		return err
 	}
-	if order.Status != "OPEN" && order.Status != "COMPLETED" {
-		return errors.New("cannot modify cancelled order")
+	if order.Status == "COMPLETED" {
+		return ErrOrderCompleted
+	}
+	if order.Status != "OPEN" {
+		return errors.New("cannot modify closed order")
 	}
 
 	// 2. Validate Item Ownership
@@ -133,26 +328,59 @@ func (s *OrderService) RemoveOrderItem(orderID, itemID uint) error {
 	if item.OrderID != orderID {
 		return errors.New("item does not belong to this order")
 	}
+	if item.PaidQuantity > 0 {
+		return errors.New("cannot remove an item that is already paid")
+	}
+	if s.projectedTotal(order, item.ID, 0) < order.PaidAmount {
+		return errors.New("order total cannot drop below the amount already paid")
+	}
 
-	// 3. Delete
+	// 3. Delete and audit in one transaction
 	// This triggers AfterDelete hook (we added it) -> Recalculate Order Total
 	// We pass the full item so the Hook knows the OrderID
-	if err := s.orderRepo.DeleteItem(item); err != nil {
-		return err
+	// Silme ve denetim tek işlemde yapılır
+	before := map[string]interface{}{
+		"item":         item,
+		"order_status": order.Status,
+		"total_amount": order.TotalAmount,
+		"paid_amount":  order.PaidAmount,
 	}
+	err = s.orderRepo.WithTransaction(func(tx *gorm.DB) error {
+		if err := s.orderRepo.DeleteItemWithTx(tx, item); err != nil {
+			return err
+		}
 
-	if order.Status == "COMPLETED" {
-		if err := s.syncTransactionAmount(orderID); err != nil {
-			logger.Error("Failed to sync transaction amount", logger.Err(err))
+		var updated models.Order
+		if err := tx.First(&updated, orderID).Error; err != nil {
+			return err
 		}
+		after := map[string]interface{}{
+			"order_status": updated.Status,
+			"total_amount": updated.TotalAmount,
+			"paid_amount":  updated.PaidAmount,
+		}
+		return s.audit.Record(tx, userID, models.AuditActionOrderItemRemove, models.AuditEntityOrderItem, item.ID, before, after, "")
+	})
+	if err != nil {
+		return err
+	}
+
+	tickets, err := s.kitchenRepo.FindTicketsByItem(itemID)
+	if err == nil {
+		err = s.cancelTickets(tickets)
+	}
+	if err != nil {
+		logger.Error("Failed to cancel kitchen tickets", logger.Err(err))
 	}
 
+	s.publishOrderEvent(events.ItemRemoved, order, map[string]interface{}{"item_id": itemID})
+
 	return nil
 }
 
 // CreateOrder initiates a new order
 // Yeni bir sipariş başlatır
-func (s *OrderService) CreateOrder(tableID *uint, waiterID uint, orderNumber string) (*models.Order, error) {
+func (s *OrderService) CreateOrder(tableID *uint, waiterID uint, orderNumber, note string) (*models.Order, error) {
 	// Check for active work period
 	// Aktif çalışma dönemini kontrol et
 	period, err := s.workPeriodRepo.FindActivePeriod()
@@ -169,6 +397,7 @@ func (s *OrderService) CreateOrder(tableID *uint, waiterID uint, orderNumber str
 		OrderNumber:  orderNumber,
 		Status:       "OPEN",
 		WorkPeriodID: period.ID,
+		Note:         strings.TrimSpace(note),
 	}
 
 	if err := s.orderRepo.Create(order); err != nil {
@@ -182,12 +411,16 @@ func (s *OrderService) CreateOrder(tableID *uint, waiterID uint, orderNumber str
 		if err == nil {
 			table.Status = "occupied"
 			table.CurrentOrderID = &order.ID
-			_ = s.tableRepo.Update(table) // Log error if needed, but don't fail order creation?
+			if err := s.tableRepo.Update(table); err == nil { // Log error if needed, but don't fail order creation?
+				s.bus.Publish(events.TableStatusChanged, table)
+			}
 		} else {
 			logger.Error("Failed to find table to update status", logger.Err(err))
 		}
 	}
 
+	s.bus.Publish(events.OrderCreated, order)
+
 	return order, nil
 }
 
@@ -249,86 +482,248 @@ func (s *OrderService) GetOrders(startDate, endDate time.Time, scope string) ([]
 	return s.orderRepo.FindByWorkPeriodIDs(ids)
 }
 
-// ApplyDiscount applies a discount to the order and saves it
-// Siparişe indirim uygular ve kaydeder
-func (s *OrderService) ApplyDiscount(orderID uint, discountType string, value int64, reason string) (*models.Order, error) {
+// ApplyDiscount applies a discount to the order and saves it.
+// Discounts above the override threshold need a manager approval.
+// Siparişe indirim uygular ve kaydeder.
+// Eşiği aşan indirimler yönetici onayı gerektirir.
+func (s *OrderService) ApplyDiscount(orderID uint, discountType string, value int64, reason string, userID uint, override *OverrideInput) (*models.Order, error) {
 	// 1. Get Order
 	order, err := s.orderRepo.FindByID(orderID)
 	if err != nil {
 		return nil, err
 	}
+
+	err = s.orderRepo.WithTransaction(func(tx *gorm.DB) error {
+		return s.ApplyDiscountWithTx(tx, order, discountType, value, reason, userID, override)
+	})
+	if err != nil {
+		s.overrides.RecordFailure(err)
+		return nil, err
+	}
+
+	s.publishDiscount(order)
+	return order, nil
+}
+
+// ApplyDiscountWithTx applies a discount to a loaded order within an existing DB transaction,
+// so callers can save their own records with it. The caller publishes the update once committed
+// and hands a failed transaction's error to OverrideService.RecordFailure.
+// Yüklenmiş siparişe mevcut bir veritabanı işlemi içinde indirim uygular,
+// böylece çağıran kendi kayıtlarını onunla birlikte saklayabilir. Güncellemeyi onaydan sonra çağıran yayınlar,
+// başarısız işlemin hatasını OverrideService.RecordFailure'a verir.
+func (s *OrderService) ApplyDiscountWithTx(tx *gorm.DB, order *models.Order, discountType string, value int64, reason string, userID uint, override *OverrideInput) error {
 	if order.Status != "OPEN" {
-		return nil, errors.New("cannot apply discount to closed order")
+		return errors.New("cannot apply discount to closed order")
 	}
 
 	// 2. Validate Inputs
 	if discountType != "AMOUNT" && discountType != "PERCENTAGE" && discountType != "NONE" {
-		return nil, errors.New("invalid discount type")
+		return errors.New("invalid discount type")
 	}
 	if value < 0 {
-		return nil, errors.New("discount value cannot be negative")
+		return errors.New("discount value cannot be negative")
 	}
 	if reason == "" && discountType != "NONE" {
-		return nil, errors.New("discount reason is required")
+		return errors.New("discount reason is required")
 	}
 
-	// 3. Update Order Fields
-	// The calculation logic is now in the Order.BeforeSave/AfterSave hooks (or in OrderItem hooks for Propagating)
-	// But since we are updating Order directly, we need to ensuring TotalAmount is recalculated.
-	// We added logic in OrderItem.AfterSave to update Order.TotalAmount.
-	// But here we are updating the Order itself. So we should recalculate here or add a BeforeSave hook on Order?
-	// The OrderItem.AfterSave hook recalculates everything.
-	// Let's manually trigger the recalculation logic here to be safe and explicit, or rely on a new method.
-	// For now, let's replicate the calculation logic or ensure the Order hooks handle it.
-	// We didn't add a BeforeSave hook on Order in models.go, only AfterSave on OrderItem.
-	// So we must manually recalculate here.
-
+	// 3. Update Order Fields and recalculate discount split and KDV
+	// Sipariş alanlarını güncelle, indirim dağılımını ve KDV'yi yeniden hesapla
+	before := discountSnapshot(order)
 	order.DiscountType = discountType
 	order.DiscountValue = value
 	order.DiscountReason = reason
 
-	// Subtotal should be correct already.
-	// // Recalculate DiscountAmount
-	switch order.DiscountType {
-	case "PERCENTAGE":
-		order.DiscountAmount = (order.Subtotal * order.DiscountValue) / 100
-	case "AMOUNT":
-		if order.DiscountValue > order.Subtotal {
-			order.DiscountAmount = order.Subtotal
-		} else {
-			order.DiscountAmount = order.DiscountValue
+	order.CalculateTotals(order.Items)
+	if order.TotalAmount < order.PaidAmount {
+		return errors.New("discount would drop total below the amount already paid")
+	}
+
+	// 4. Record who applied the discount and who approved it
+	// İndirimi uygulayanı ve onaylayanı kaydet
+	order.DiscountBy = &userID
+	order.DiscountApprovedBy = nil
+	if s.overrides.DiscountNeedsApproval(order) {
+		approverID, err := s.overrides.Check(userID, override)
+		if err != nil {
+			return err
 		}
-	default:
-		order.DiscountAmount = 0
+		order.DiscountApprovedBy = &approverID
 	}
-	// if order.DiscountType == "PERCENTAGE" {
-	// 	order.DiscountAmount = (order.Subtotal * order.DiscountValue) / 100
-	// } else if order.DiscountType == "AMOUNT" {
-	// 	if order.DiscountValue > order.Subtotal {
-	// 		order.DiscountAmount = order.Subtotal
-	// 	} else {
-	// 		order.DiscountAmount = order.DiscountValue
-	// 	}
-	// } else {
-	// 	order.DiscountAmount = 0
-	// }
 
-	order.TotalAmount = order.Subtotal - order.DiscountAmount + order.TaxAmount
+	if err := s.orderRepo.UpdateWithTx(tx, order); err != nil {
+		return err
+	}
+	// Persist the per item discount shares and tax snapshots
+	// Kalem bazlı indirim paylarını ve vergi snapshot'larını kaydet
+	if err := models.RecalculateOrderTotals(tx, order.ID); err != nil {
+		return err
+	}
+	return s.audit.Record(tx, userID, models.AuditActionOrderDiscount, models.AuditEntityOrder, order.ID, before, discountSnapshot(order), reason)
+}
 
-	if err := s.orderRepo.Update(order); err != nil {
+// publishDiscount announces the new discount and total of an order
+// Siparişin yeni indirimini ve toplamını duyurur
+func (s *OrderService) publishDiscount(order *models.Order) {
+	s.publishOrderEvent(events.OrderUpdated, order, map[string]interface{}{
+		"discount_amount": order.DiscountAmount,
+		"total_amount":    order.TotalAmount,
+	})
+}
+
+// ApplyCoupon puts a coupon code on an OPEN order, replacing the coupon already on it.
+// The coupon is checked again when the order is closed, that is when it counts as used.
+// AÇIK siparişe kupon kodu uygular, siparişteki kuponun yerine geçer.
+// Kupon sipariş kapanırken yeniden kontrol edilir, kullanılmış sayılması o zamandır.
+func (s *OrderService) ApplyCoupon(orderID uint, code string) (*models.Order, error) {
+	order, err := s.orderRepo.FindByID(orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status != "OPEN" {
+		return nil, errors.New("cannot apply coupon to closed order")
+	}
+
+	coupon, err := s.couponRepo.FindByCode(normalizeCouponCode(code))
+	if err != nil {
+		return nil, ErrCouponNotFound
+	}
+
+	order.CouponID = &coupon.ID
+	order.CouponCode = coupon.Code
+	order.CouponType = coupon.DiscountType
+	order.CouponValue = coupon.Value
+	order.CalculateTotals(order.Items)
+	if order.TotalAmount < order.PaidAmount {
+		return nil, errors.New("coupon would drop total below the amount already paid")
+	}
+
+	err = s.orderRepo.WithTransaction(func(tx *gorm.DB) error {
+		if err := s.checkCouponWithTx(tx, order, coupon); err != nil {
+			return err
+		}
+		if err := s.orderRepo.UpdateWithTx(tx, order); err != nil {
+			return err
+		}
+		return models.RecalculateOrderTotals(tx, order.ID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.publishOrderEvent(events.OrderUpdated, order, map[string]interface{}{
+		"coupon_amount": order.CouponAmount,
+		"total_amount":  order.TotalAmount,
+	})
+
+	return order, nil
+}
+
+// RemoveCoupon takes the coupon off an OPEN order
+// AÇIK siparişten kuponu kaldırır
+func (s *OrderService) RemoveCoupon(orderID uint) (*models.Order, error) {
+	order, err := s.orderRepo.FindByID(orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status != "OPEN" {
+		return nil, errors.New("cannot remove coupon from closed order")
+	}
+
+	order.CouponID = nil
+	order.CouponCode = ""
+	order.CouponType = ""
+	order.CouponValue = 0
+	order.CalculateTotals(order.Items)
+
+	err = s.orderRepo.WithTransaction(func(tx *gorm.DB) error {
+		if err := s.orderRepo.UpdateWithTx(tx, order); err != nil {
+			return err
+		}
+		return models.RecalculateOrderTotals(tx, order.ID)
+	})
+	if err != nil {
 		return nil, err
 	}
 
+	s.publishOrderEvent(events.OrderUpdated, order, map[string]interface{}{
+		"coupon_amount": order.CouponAmount,
+		"total_amount":  order.TotalAmount,
+	})
+
 	return order, nil
 }
 
-// CloseOrder completes payment and records revenue logic (ACID)
-// Siparişi tamamlar, ödemeyi alır ve geliri kaydeder (ACID)
-func (s *OrderService) CloseOrder(orderID uint, paymentMethod string) error {
+// checkCouponWithTx validates the coupon against its limits and the order
+// Kuponu limitlerine ve siparişe göre doğrular
+func (s *OrderService) checkCouponWithTx(tx *gorm.DB, order *models.Order, coupon *models.Coupon) error {
+	if !coupon.IsActive {
+		return ErrCouponInactive
+	}
+	if coupon.ExpiresAt != nil && !time.Now().Before(*coupon.ExpiresAt) {
+		return ErrCouponExpired
+	}
+	if coupon.UsageLimit > 0 && coupon.UsedCount >= coupon.UsageLimit {
+		return ErrCouponUsedUp
+	}
+	if coupon.MinSpend > 0 && order.Subtotal-order.PromotionAmount < coupon.MinSpend {
+		return ErrCouponMinSpend
+	}
+	if coupon.PerCustomerLimit > 0 {
+		if order.CustomerID == nil {
+			return ErrCouponCustomerRequired
+		}
+		used, err := s.couponRepo.CountCustomerRedemptionsWithTx(tx, coupon.ID, *order.CustomerID)
+		if err != nil {
+			return err
+		}
+		if used >= int64(coupon.PerCustomerLimit) {
+			return ErrCouponCustomerLimit
+		}
+	}
+	return nil
+}
+
+// redeemCouponWithTx checks the coupon of a closing order once more and records its redemption
+// Kapanan siparişin kuponunu bir kez daha kontrol eder ve kullanımını kaydeder
+func (s *OrderService) redeemCouponWithTx(tx *gorm.DB, order *models.Order, userID uint) error {
+	coupon, err := s.couponRepo.FindByIDWithTx(tx, *order.CouponID)
+	if err != nil {
+		return ErrCouponNotFound
+	}
+	if err := s.checkCouponWithTx(tx, order, coupon); err != nil {
+		return err
+	}
+	if order.CouponAmount <= 0 {
+		return nil
+	}
+
+	return s.couponRepo.CreateRedemptionWithTx(tx, &models.CouponRedemption{
+		CouponID:     coupon.ID,
+		CouponCode:   coupon.Code,
+		OrderID:      order.ID,
+		OrderNumber:  order.OrderNumber,
+		CustomerID:   order.CustomerID,
+		WorkPeriodID: order.WorkPeriodID,
+		Amount:       order.CouponAmount,
+		RedeemedBy:   userID,
+	})
+}
+
+// CloseOrder completes the order once it is fully paid (ACID)
+// If paymentMethod is given, any remaining balance is settled with it first.
+// ON_ACCOUNT charges the remaining balance to the customer's tab instead.
+// Siparişi tamamen ödendiğinde kapatır (ACID)
+// Ödeme yöntemi verilirse kalan bakiye önce bu yöntemle tahsil edilir.
+// ON_ACCOUNT kalan bakiyeyi bunun yerine müşterinin hesabına yazar.
+func (s *OrderService) CloseOrder(orderID uint, paymentMethod string, customerID *uint, userID uint) error {
+	var order models.Order
+	var freedTable *models.Table
+	var stockMovements []models.StockMovement
+
 	// Execute within a transaction
 	// İşlem içinde çalıştır
-	return s.orderRepo.WithTransaction(func(tx *gorm.DB) error {
-		var order models.Order
+	err := s.orderRepo.WithTransaction(func(tx *gorm.DB) error {
 		if err := tx.First(&order, orderID).Error; err != nil {
 			return errors.New("order not found")
 		}
@@ -336,6 +731,37 @@ func (s *OrderService) CloseOrder(orderID uint, paymentMethod string) error {
 		if order.Status == "COMPLETED" {
 			return errors.New("order is already completed")
 		}
+		if order.Status != "OPEN" {
+			return errors.New("cannot close cancelled order")
+		}
+		if customerID != nil {
+			order.CustomerID = customerID
+		}
+
+		// The coupon may have expired or run out since it was entered
+		// Kupon girildiğinden beri süresi dolmuş veya tükenmiş olabilir
+		if order.CouponID != nil {
+			if err := s.redeemCouponWithTx(tx, &order, userID); err != nil {
+				return err
+			}
+		}
+
+		// Settle remaining balance
+		// Kalan bakiyeyi tahsil et
+		if remaining := order.RemainingAmount(); remaining > 0 {
+			switch paymentMethod {
+			case "":
+				return errors.New("order has an unpaid balance")
+			case models.PaymentMethodOnAccount:
+				if err := s.chargeAccount(tx, &order, remaining, userID); err != nil {
+					return err
+				}
+			default:
+				if _, err := s.recordPayment(tx, &order, paymentMethod, models.PaymentSplitFull, remaining, userID); err != nil {
+					return err
+				}
+			}
+		}
 
 		// Snapshot TableName logic
 		if order.TableID != nil {
@@ -345,30 +771,50 @@ func (s *OrderService) CloseOrder(orderID uint, paymentMethod string) error {
 			}
 		}
 
+		// Resolve the order level payment method from recorded payments
+		// Sipariş düzeyindeki ödeme yöntemini kayıtlı ödemelerden belirle
+		var methods []string
+		if err := tx.Model(&models.Payment{}).Where("order_id = ?", order.ID).Distinct().Pluck("method", &methods).Error; err != nil {
+			return err
+		}
+		switch len(methods) {
+		case 0:
+			order.PaymentMethod = paymentMethod // Zero-total order
+		case 1:
+			order.PaymentMethod = methods[0]
+		default:
+			order.PaymentMethod = models.PaymentMethodMixed
+		}
+
 		// Update Order
 		now := time.Now()
 		order.Status = "COMPLETED"
-		order.PaymentMethod = paymentMethod
 		order.CompletedAt = &now
 
 		if err := tx.Save(&order).Error; err != nil {
 			return err
 		}
 
-		// Create Transaction Record
-		transaction := &models.Transaction{
-			Type:            "INCOME",
-			Category:        "Sales",
-			PaymentMethod:   paymentMethod,
-			Amount:          order.TotalAmount,
-			Description:     "Order #" + order.OrderNumber,
-			OrderID:         &order.ID,
-			WorkPeriodID:    order.WorkPeriodID,
-			TransactionDate: now,
+		// Deplete ingredients by recipe
+		// Reçeteye göre malzemeleri stoktan düş
+		var items []models.OrderItem
+		if err := tx.Where("order_id = ?", order.ID).Find(&items).Error; err != nil {
+			return err
+		}
+		for i := range items {
+			movements, err := s.inventory.SyncItemStockWithTx(tx, &items[i], items[i].Quantity, userID)
+			if err != nil {
+				return err
+			}
+			stockMovements = append(stockMovements, movements...)
 		}
 
-		if err := s.transactionRepo.CreateWithTx(tx, transaction); err != nil {
-			return err
+		// Let subscribers such as loyalty act on the completed order
+		// Sadakat gibi abonelerin tamamlanan sipariş üzerinde işlem yapmasına izin ver
+		for _, hook := range s.closeHooks {
+			if err := hook(tx, &order, items, userID); err != nil {
+				return err
+			}
 		}
 
 		// Update Table Status
@@ -381,80 +827,1024 @@ func (s *OrderService) CloseOrder(orderID uint, paymentMethod string) error {
 				if err := tx.First(&table, *order.TableID).Error; err == nil {
 					table.Status = "available"
 					table.CurrentOrderID = nil
-					tx.Save(&table)
+					if err := tx.Save(&table).Error; err == nil {
+						freedTable = &table
+					}
 				}
 			}
 		}
 
-		logger.Info("Order closed and transaction recorded",
+		logger.Info("Order closed",
 			logger.Int("order_id", int(order.ID)),
 			logger.Int("amount", int(order.TotalAmount)),
+			logger.String("payment_method", order.PaymentMethod),
 		)
 
 		return nil
 	})
-}
-
-// CancelOrder cancels (deletes) an OPEN order
-// AÇIK siparişi iptal eder (siler)
-func (s *OrderService) CancelOrder(orderID uint) error {
-	order, err := s.orderRepo.FindByID(orderID)
 	if err != nil {
 		return err
 	}
 
-	if order.Status != "OPEN" {
-		return errors.New("cannot cancel closed order")
-	}
-
-	// Delete
-	if err := s.orderRepo.Delete(orderID); err != nil {
-		return err
-	}
-
-	// Check if Table needs to be freed
-	if order.TableID != nil {
-		// Check remaining open orders
-		orders, err := s.orderRepo.FindByTableID(*order.TableID, "OPEN")
-		if err == nil && len(orders) == 0 {
-			table, err := s.tableRepo.FindByID(*order.TableID)
-			if err == nil {
-				table.Status = "available"
-				table.CurrentOrderID = nil
-				s.tableRepo.Update(table)
-			}
-		} else if err == nil && len(orders) > 0 {
-			// If we deleted the "CurrentOrderID" one, switch to another
-			table, err := s.tableRepo.FindByID(*order.TableID)
-			if err == nil && table.CurrentOrderID != nil && *table.CurrentOrderID == orderID {
-				table.CurrentOrderID = &orders[0].ID
-				s.tableRepo.Update(table)
-			}
-		}
+	// Publish only after commit so listeners never see rolled back state
+	// Dinleyiciler geri alınan durumu görmesin diye yalnızca commit sonrası yayınla
+	s.publishOrderEvent(events.OrderClosed, &order, map[string]interface{}{
+		"total_amount":   order.TotalAmount,
+		"payment_method": order.PaymentMethod,
+	})
+	if freedTable != nil {
+		s.bus.Publish(events.TableStatusChanged, freedTable)
 	}
+	s.inventory.NotifyLowStock(stockMovements)
 
 	return nil
 }
 
-// syncTransactionAmount updates the linked transaction amount when a closed order is modified
-func (s *OrderService) syncTransactionAmount(orderID uint) error {
-	// Re-fetch order to get updated total
-	order, err := s.orderRepo.FindByID(orderID)
-	if err != nil {
-		return err
-	}
-
-	// Find transaction
-	tx, err := s.transactionRepo.FindByOrderID(orderID)
-	if err != nil {
-		// Possibly no transaction if 0 amount or issue. Log but don't hard fail?
-		return err
-	}
+// AddPayment records a partial payment (by amount, by items or by equal shares) on an OPEN order
+// AÇIK bir siparişe kısmi ödeme kaydeder (tutar, ürün veya eşit pay bazında)
+func (s *OrderService) AddPayment(orderID uint, input PaymentInput) (*models.Payment, *models.Order, error) {
+	var payment *models.Payment
+	var order models.Order
 
-	if tx.Amount != order.TotalAmount {
-		tx.Amount = order.TotalAmount
-		return s.transactionRepo.Update(tx)
+	err := s.orderRepo.WithTransaction(func(tx *gorm.DB) error {
+		if err := tx.First(&order, orderID).Error; err != nil {
+			return errors.New("order not found")
+		}
+		if order.Status != "OPEN" {
+			return errors.New("can only take payments on open orders")
+		}
+
+		remaining := order.RemainingAmount()
+		if remaining <= 0 {
+			return errors.New("order is already fully paid")
+		}
+
+		var amount int64
+		switch input.SplitType {
+		case models.PaymentSplitFull, "":
+			input.SplitType = models.PaymentSplitFull
+			amount = remaining
+
+		case models.PaymentSplitAmount:
+			if input.Amount <= 0 {
+				return errors.New("payment amount must be positive")
+			}
+			if input.Amount > remaining {
+				return errors.New("payment amount exceeds remaining balance")
+			}
+			amount = input.Amount
+
+		case models.PaymentSplitItems:
+			itemAmount, err := s.payItems(tx, &order, input.Items)
+			if err != nil {
+				return err
+			}
+			amount = itemAmount
+
+		case models.PaymentSplitEqual:
+			if input.SplitCount < 2 {
+				return errors.New("split count must be at least 2")
+			}
+			shares := input.Shares
+			if shares <= 0 {
+				shares = 1
+			}
+			// The first share fixes the split, later shares must use the same count
+			// İlk pay bölüşümü belirler, sonraki paylar aynı sayıyı kullanmalıdır
+			if order.SplitCount != 0 && order.SplitCount != input.SplitCount {
+				return fmt.Errorf("order is already split into %d shares", order.SplitCount)
+			}
+			if order.SplitSharesPaid+shares > input.SplitCount {
+				return fmt.Errorf("only %d of %d shares left to pay", input.SplitCount-order.SplitSharesPaid, input.SplitCount)
+			}
+			amount = order.TotalAmount * int64(shares) / int64(input.SplitCount)
+			// The last share absorbs the rounding remainder
+			// Son pay yuvarlama farkını üstlenir
+			if amount > remaining || order.SplitSharesPaid+shares == input.SplitCount {
+				amount = remaining
+			}
+			order.SplitCount = input.SplitCount
+			order.SplitSharesPaid += shares
+			if err := tx.Model(&order).UpdateColumns(map[string]interface{}{
+				"split_count":       order.SplitCount,
+				"split_shares_paid": order.SplitSharesPaid,
+			}).Error; err != nil {
+				return err
+			}
+
+		default:
+			return errors.New("invalid split type")
+		}
+
+		if amount <= 0 {
+			return errors.New("nothing to pay")
+		}
+
+		p, err := s.recordPayment(tx, &order, input.Method, input.SplitType, amount, input.UserID)
+		if err != nil {
+			return err
+		}
+		payment = p
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	logger.Info("Payment recorded",
+		logger.Int("order_id", int(order.ID)),
+		logger.Int("amount", int(payment.Amount)),
+		logger.String("method", payment.Method),
+		logger.Int("remaining", int(order.RemainingAmount())),
+	)
+
+	s.publishOrderEvent(events.PaymentAdded, &order, map[string]interface{}{
+		"payment":          payment,
+		"paid_amount":      order.PaidAmount,
+		"remaining_amount": order.RemainingAmount(),
+	})
+
+	return payment, &order, nil
+}
+
+// GetPayments returns the order together with its recorded payments
+// Siparişi kayıtlı ödemeleriyle birlikte döndürür
+func (s *OrderService) GetPayments(orderID uint) (*models.Order, error) {
+	return s.orderRepo.FindByID(orderID)
+}
+
+// payItems marks the selected item units as paid and returns their share of the order total
+// Seçilen kalem adetlerini ödendi olarak işaretler ve sipariş toplamındaki paylarını döndürür
+func (s *OrderService) payItems(tx *gorm.DB, order *models.Order, selections []PaymentItemInput) (int64, error) {
+	if len(selections) == 0 {
+		return 0, errors.New("no items selected for payment")
+	}
+
+	var amount int64
+	for _, sel := range selections {
+		if sel.Quantity <= 0 {
+			return 0, errors.New("item quantity must be positive")
+		}
+
+		var item models.OrderItem
+		if err := tx.First(&item, sel.ItemID).Error; err != nil {
+			return 0, errors.New("order item not found")
+		}
+		if item.OrderID != order.ID {
+			return 0, errors.New("item does not belong to this order")
+		}
+		if sel.Quantity > item.Quantity-item.PaidQuantity {
+			return 0, errors.New("item quantity exceeds unpaid units")
+		}
+
+		// UpdateColumn skips hooks, order totals are unaffected
+		// UpdateColumn hook'ları atlar, sipariş toplamları etkilenmez
+		if err := tx.Model(&item).UpdateColumn("paid_quantity", item.PaidQuantity+sel.Quantity).Error; err != nil {
+			return 0, err
+		}
+		// The line already carries its discount share and KDV
+		// Satır indirim payını ve KDV'sini zaten taşır
+		amount += item.PayableAmount() * int64(sel.Quantity) / int64(item.Quantity)
+	}
+
+	// Once every unit is settled, collect the exact remainder
+	// Tüm adetler ödendiğinde kalan tutarı tam olarak tahsil et
+	var unpaid int64
+	if err := tx.Model(&models.OrderItem{}).
+		Where("order_id = ? AND paid_quantity < quantity", order.ID).
+		Count(&unpaid).Error; err != nil {
+		return 0, err
+	}
+	if unpaid == 0 || amount > order.RemainingAmount() {
+		amount = order.RemainingAmount()
+	}
+
+	return amount, nil
+}
+
+// recordPayment writes the payment with its INCOME transaction and bumps the paid amount of the order
+// Ödemeyi GELİR işlemiyle birlikte kaydeder ve siparişin ödenen tutarını artırır
+func (s *OrderService) recordPayment(tx *gorm.DB, order *models.Order, method, splitType string, amount int64, userID uint) (*models.Payment, error) {
+	if method != models.PaymentMethodCash && method != models.PaymentMethodCreditCard {
+		return nil, errors.New("invalid payment method")
+	}
+
+	now := time.Now()
+	transaction := &models.Transaction{
+		Type:            "INCOME",
+		Category:        "Sales",
+		PaymentMethod:   method,
+		Amount:          amount,
+		Description:     "Order #" + order.OrderNumber,
+		OrderID:         &order.ID,
+		WorkPeriodID:    order.WorkPeriodID,
+		CreatedBy:       userID,
+		TransactionDate: now,
+	}
+	if err := s.transactionRepo.CreateWithTx(tx, transaction); err != nil {
+		return nil, err
+	}
+
+	payment := &models.Payment{
+		OrderID:       order.ID,
+		WorkPeriodID:  order.WorkPeriodID,
+		Method:        method,
+		SplitType:     splitType,
+		Amount:        amount,
+		TransactionID: &transaction.ID,
+		CreatedBy:     userID,
+	}
+	if err := s.paymentRepo.CreateWithTx(tx, payment); err != nil {
+		return nil, err
+	}
+
+	order.PaidAmount += amount
+	if err := tx.Model(order).UpdateColumn("paid_amount", order.PaidAmount).Error; err != nil {
+		return nil, err
+	}
+
+	return payment, nil
+}
+
+// chargeAccount posts the amount to the customer's tab and records it as an ON_ACCOUNT payment.
+// No INCOME transaction is written, the money comes in later as a customer payment.
+// Tutarı müşterinin hesabına borç yazar ve ON_ACCOUNT ödemesi olarak kaydeder.
+// GELİR işlemi yazılmaz, para daha sonra müşteri ödemesi olarak gelir.
+func (s *OrderService) chargeAccount(tx *gorm.DB, order *models.Order, amount int64, userID uint) error {
+	if order.CustomerID == nil {
+		return ErrCustomerRequired
+	}
+	if _, err := s.customers.ChargeOrderWithTx(tx, *order.CustomerID, order, amount, userID); err != nil {
+		return err
+	}
+
+	payment := &models.Payment{
+		OrderID:      order.ID,
+		WorkPeriodID: order.WorkPeriodID,
+		Method:       models.PaymentMethodOnAccount,
+		SplitType:    models.PaymentSplitFull,
+		Amount:       amount,
+		CreatedBy:    userID,
+	}
+	if err := s.paymentRepo.CreateWithTx(tx, payment); err != nil {
+		return err
+	}
+
+	order.PaidAmount += amount
+	return tx.Model(order).UpdateColumn("paid_amount", order.PaidAmount).Error
+}
+
+// resolveModifiers validates the selected options against the product's groups and snapshots them
+// Seçilen opsiyonları ürünün gruplarına göre doğrular ve snapshot alır
+func (s *OrderService) resolveModifiers(product *models.Product, optionIDs []uint) ([]models.OrderItemModifier, error) {
+	groups, err := s.modifierRepo.FindGroupsForProduct(product.ID, product.CategoryID)
+	if err != nil {
+		return nil, err
+	}
+	if len(groups) == 0 {
+		if len(optionIDs) > 0 {
+			return nil, errors.New("product has no modifiers")
+		}
+		return nil, nil
+	}
+
+	type choice struct {
+		group  *models.ModifierGroup
+		option models.ModifierOption
+	}
+	available := make(map[uint]choice)
+	for i := range groups {
+		for _, opt := range groups[i].Options {
+			available[opt.ID] = choice{group: &groups[i], option: opt}
+		}
+	}
+
+	var modifiers []models.OrderItemModifier
+	selected := make(map[uint]int) // group ID -> selection count
+	seen := make(map[uint]bool)
+	for _, id := range optionIDs {
+		if seen[id] {
+			return nil, errors.New("duplicate modifier option")
+		}
+		seen[id] = true
+
+		c, ok := available[id]
+		if !ok {
+			return nil, errors.New("modifier option not available for this product")
+		}
+		if !c.option.IsAvailable {
+			return nil, fmt.Errorf("modifier option %q is unavailable", c.option.Name)
+		}
+		selected[c.group.ID]++
+
+		modifiers = append(modifiers, models.OrderItemModifier{
+			GroupID:    c.group.ID,
+			OptionID:   c.option.ID,
+			GroupName:  c.group.Name,
+			OptionName: c.option.Name,
+			PriceDelta: c.option.PriceDelta,
+		})
 	}
 
+	// Enforce selection bounds of each group
+	// Her grubun seçim sınırlarını uygula
+	for _, g := range groups {
+		minSelect := g.MinSelect
+		if g.IsRequired && minSelect < 1 {
+			minSelect = 1
+		}
+		count := selected[g.ID]
+		if count < minSelect {
+			return nil, fmt.Errorf("modifier group %q requires at least %d selection(s)", g.Name, minSelect)
+		}
+		if g.MaxSelect > 0 && count > g.MaxSelect {
+			return nil, fmt.Errorf("modifier group %q allows at most %d selection(s)", g.Name, g.MaxSelect)
+		}
+	}
+
+	return modifiers, nil
+}
+
+// fireItem sends units of an order item to the kitchen station of its category
+// Sipariş kaleminin adetlerini kategorisinin mutfak istasyonuna gönderir
+func (s *OrderService) fireItem(order *models.Order, item *models.OrderItem, quantity int, modifiers string) error {
+	ticket := &models.KitchenTicket{
+		OrderID:     order.ID,
+		OrderItemID: item.ID,
+		ProductName: item.ProductName,
+		Quantity:    quantity,
+		Modifiers:   modifiers,
+		Note:        item.Note,
+		Status:      models.KitchenStatusQueued,
+	}
+
+	// Route by category station
+	// Kategori istasyonuna göre yönlendir
+	if product, err := s.productRepo.FindByID(item.ProductID); err == nil && product.Category.StationID != nil {
+		if station, err := s.kitchenRepo.FindStationByID(*product.Category.StationID); err == nil && station.IsActive {
+			ticket.StationID = &station.ID
+			ticket.StationName = station.Name
+		}
+	}
+
+	if order.TableID != nil {
+		if table, err := s.tableRepo.FindByID(*order.TableID); err == nil {
+			ticket.TableName = table.Name
+		}
+	}
+
+	if err := s.kitchenRepo.CreateTicket(ticket); err != nil {
+		return err
+	}
+	s.printer.PrintKitchenTicketAsync(ticket)
+	return refreshItemKitchenStatus(s.kitchenRepo, item.ID)
+}
+
+// adjustItemTickets reconciles kitchen tickets after a quantity change.
+// Queued tickets absorb the change; extra units on started tickets are fired as a new ticket.
+// Adet değişikliğinden sonra mutfak ticketlarını uzlaştırır.
+// Bekleyen ticketlar değişikliği üstlenir; başlamış ticketlara eklenen adetler yeni ticket olarak gönderilir.
+func (s *OrderService) adjustItemTickets(order *models.Order, item *models.OrderItem, oldQuantity int) error {
+	delta := item.Quantity - oldQuantity
+	if delta == 0 {
+		return nil
+	}
+
+	tickets, err := s.kitchenRepo.FindTicketsByItem(item.ID)
+	if err != nil {
+		return err
+	}
+
+	modifiers := ""
+	if len(tickets) > 0 {
+		modifiers = tickets[len(tickets)-1].Modifiers
+	}
+
+	if delta > 0 {
+		for i := len(tickets) - 1; i >= 0; i-- {
+			if tickets[i].Status == models.KitchenStatusQueued {
+				tickets[i].Quantity += delta
+				return s.kitchenRepo.UpdateTicket(&tickets[i])
+			}
+		}
+		return s.fireItem(order, item, delta, modifiers)
+	}
+
+	// Reduce queued tickets, newest first; units already in preparation stay as they are
+	// Bekleyen ticketları yeniden eskiye azalt; hazırlanmakta olan adetler olduğu gibi kalır
+	reduce := -delta
+	for i := len(tickets) - 1; i >= 0 && reduce > 0; i-- {
+		t := &tickets[i]
+		if t.Status != models.KitchenStatusQueued {
+			continue
+		}
+		if t.Quantity > reduce {
+			t.Quantity -= reduce
+			reduce = 0
+		} else {
+			reduce -= t.Quantity
+			t.Status = models.KitchenStatusCancelled
+		}
+		if err := s.kitchenRepo.UpdateTicket(t); err != nil {
+			return err
+		}
+	}
+	if reduce > 0 {
+		logger.Warn("Quantity reduced below units already in preparation",
+			logger.Int("order_item_id", int(item.ID)),
+			logger.Int("units", reduce),
+		)
+	}
+	return refreshItemKitchenStatus(s.kitchenRepo, item.ID)
+}
+
+// cancelTickets cancels tickets that have not been served yet
+// Henüz servis edilmemiş ticketları iptal eder
+func (s *OrderService) cancelTickets(tickets []models.KitchenTicket) error {
+	for i := range tickets {
+		if tickets[i].Status == models.KitchenStatusServed || tickets[i].Status == models.KitchenStatusCancelled {
+			continue
+		}
+		tickets[i].Status = models.KitchenStatusCancelled
+		if err := s.kitchenRepo.UpdateTicket(&tickets[i]); err != nil {
+			return err
+		}
+		if err := refreshItemKitchenStatus(s.kitchenRepo, tickets[i].OrderItemID); err != nil {
+			return err
+		}
+	}
 	return nil
 }
+
+// modifierSummary joins the option names of an item for kitchen display
+// Mutfak ekranı için kalemin opsiyon adlarını birleştirir
+func modifierSummary(modifiers []models.OrderItemModifier) string {
+	names := make([]string, 0, len(modifiers))
+	for _, m := range modifiers {
+		names = append(names, m.OptionName)
+	}
+	return strings.Join(names, ", ")
+}
+
+// projectedTotal computes the order total as if the item had the given quantity (0 removes it)
+// Kalem verilen adede sahip olsaydı (0 kaldırır) sipariş toplamının ne olacağını hesaplar
+func (s *OrderService) projectedTotal(order *models.Order, itemID uint, quantity int) int64 {
+	projected := *order
+	items := make([]models.OrderItem, 0, len(order.Items))
+	for _, item := range order.Items {
+		if item.ID == itemID {
+			if quantity == 0 {
+				continue
+			}
+			item.Quantity = quantity
+			item.Subtotal = int64(quantity) * item.LinePrice()
+		}
+		items = append(items, item)
+	}
+	projected.CalculateTotals(items)
+	return projected.TotalAmount
+}
+
+// CancelOrder cancels (deletes) an OPEN order.
+// Orders that already have items need a manager approval.
+// AÇIK siparişi iptal eder (siler).
+// Kalemi olan siparişler yönetici onayı gerektirir.
+func (s *OrderService) CancelOrder(orderID, userID uint, override *OverrideInput) error {
+	order, err := s.orderRepo.FindByID(orderID)
+	if err != nil {
+		return err
+	}
+
+	if order.Status != "OPEN" {
+		return errors.New("cannot cancel closed order")
+	}
+	if order.PaidAmount > 0 {
+		return errors.New("cannot cancel order with recorded payments")
+	}
+
+	var approvedBy *uint
+	if len(order.Items) > 0 {
+		approverID, err := s.overrides.Approve(userID, override)
+		if err != nil {
+			return err
+		}
+		approvedBy = &approverID
+	}
+
+	// Stamp the actor and approver, then delete
+	// İşlemi yapanı ve onaylayanı işle, sonra sil
+	err = s.orderRepo.WithTransaction(func(tx *gorm.DB) error {
+		stamp := map[string]interface{}{"cancelled_by": userID, "cancel_approved_by": approvedBy}
+		if err := tx.Model(&models.Order{}).Where("id = ?", orderID).UpdateColumns(stamp).Error; err != nil {
+			return err
+		}
+		if err := s.orderRepo.DeleteWithTx(tx, orderID); err != nil {
+			return err
+		}
+		return s.audit.Record(tx, userID, models.AuditActionOrderCancel, models.AuditEntityOrder, orderID, order, stamp, "")
+	})
+	if err != nil {
+		return err
+	}
+
+	tickets, err := s.kitchenRepo.FindTicketsByOrder(orderID)
+	if err == nil {
+		err = s.cancelTickets(tickets)
+	}
+	if err != nil {
+		logger.Error("Failed to cancel kitchen tickets", logger.Err(err))
+	}
+
+	// Give back any stock booked for the items
+	// Kalemler için düşülmüş stoğu iade et
+	for i := range order.Items {
+		if err := s.inventory.SyncItemStock(&order.Items[i], 0, 0); err != nil {
+			logger.Error("Failed to restore stock", logger.Err(err))
+		}
+	}
+
+	// Check if Table needs to be freed
+	if order.TableID != nil {
+		// Check remaining open orders
+		orders, err := s.orderRepo.FindByTableID(*order.TableID, "OPEN")
+		if err == nil && len(orders) == 0 {
+			table, err := s.tableRepo.FindByID(*order.TableID)
+			if err == nil {
+				table.Status = "available"
+				table.CurrentOrderID = nil
+				if err := s.tableRepo.Update(table); err == nil {
+					s.bus.Publish(events.TableStatusChanged, table)
+				}
+			}
+		} else if err == nil && len(orders) > 0 {
+			// If we deleted the "CurrentOrderID" one, switch to another
+			table, err := s.tableRepo.FindByID(*order.TableID)
+			if err == nil && table.CurrentOrderID != nil && *table.CurrentOrderID == orderID {
+				table.CurrentOrderID = &orders[0].ID
+				if err := s.tableRepo.Update(table); err == nil {
+					s.bus.Publish(events.TableStatusChanged, table)
+				}
+			}
+		}
+	}
+
+	// The row is soft deleted; listeners see it as cancelled
+	// Kayıt soft delete edildi; dinleyiciler iptal edilmiş olarak görür
+	order.Status = "CANCELLED"
+	s.publishOrderEvent(events.OrderCancelled, order, nil)
+
+	return nil
+}
+
+// publishOrderEvent pushes an order scoped event carrying the order and table identifiers
+// Sipariş ve masa kimliklerini taşıyan sipariş olayını yayınlar
+func (s *OrderService) publishOrderEvent(eventType string, order *models.Order, data map[string]interface{}) {
+	if data == nil {
+		data = map[string]interface{}{}
+	}
+	data["order_id"] = order.ID
+	data["order_number"] = order.OrderNumber
+	data["table_id"] = order.TableID
+	data["status"] = order.Status
+	s.bus.Publish(eventType, data)
+}
+
+// discountSnapshot captures the discount related fields of an order for the audit log
+// Denetim kaydı için siparişin indirimle ilgili alanlarını yakalar
+func discountSnapshot(order *models.Order) map[string]interface{} {
+	return map[string]interface{}{
+		"discount_type":   order.DiscountType,
+		"discount_value":  order.DiscountValue,
+		"discount_reason": order.DiscountReason,
+		"discount_amount": order.DiscountAmount,
+		"discount_by":     order.DiscountBy,
+		"approved_by":     order.DiscountApprovedBy,
+		"total_amount":    order.TotalAmount,
+	}
+}
+
+// MoveItemInput selects an order item and how many of its units move to another order
+// Başka bir siparişe taşınacak kalemi ve adedini belirtir
+type MoveItemInput struct {
+	ItemID   uint
+	Quantity int
+}
+
+// TransferOrder moves an OPEN order to another table
+// AÇIK bir siparişi başka bir masaya taşır
+func (s *OrderService) TransferOrder(orderID, targetTableID, userID uint) (*models.Order, error) {
+	var order models.Order
+	var touched []*models.Table
+
+	err := s.orderRepo.WithTransaction(func(tx *gorm.DB) error {
+		if err := tx.First(&order, orderID).Error; err != nil {
+			return errors.New("order not found")
+		}
+		if order.Status != "OPEN" {
+			return errors.New("can only transfer open orders")
+		}
+		if order.TableID != nil && *order.TableID == targetTableID {
+			return errors.New("order is already on this table")
+		}
+
+		var target models.Table
+		if err := tx.First(&target, targetTableID).Error; err != nil {
+			return errors.New("target table not found")
+		}
+
+		sourceTableID := order.TableID
+		order.TableID = &target.ID
+		if err := tx.Model(&order).UpdateColumn("table_id", target.ID).Error; err != nil {
+			return err
+		}
+		if err := s.retargetTicketsTx(tx, order.ID, target.Name); err != nil {
+			return err
+		}
+
+		var err error
+		touched, err = s.refreshTablesTx(tx, sourceTableID, &target.ID)
+		if err != nil {
+			return err
+		}
+		return s.audit.Record(tx, userID, models.AuditActionOrderTransfer, models.AuditEntityOrder, order.ID,
+			map[string]interface{}{"table_id": sourceTableID}, map[string]interface{}{"table_id": target.ID}, "")
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("Order transferred",
+		logger.Int("order_id", int(order.ID)),
+		logger.Int("table_id", int(targetTableID)),
+		logger.Int("user_id", int(userID)),
+	)
+
+	s.publishOrderEvent(events.OrderUpdated, &order, map[string]interface{}{"transferred_by": userID})
+	for _, table := range touched {
+		s.bus.Publish(events.TableStatusChanged, table)
+	}
+
+	return s.orderRepo.FindByID(order.ID)
+}
+
+// MoveItems moves selected items (or partial quantities) from one OPEN order to another
+// Seçilen kalemleri (veya kısmi adetleri) bir AÇIK siparişten diğerine taşır
+func (s *OrderService) MoveItems(sourceOrderID, targetOrderID uint, selections []MoveItemInput, userID uint) (*models.Order, *models.Order, error) {
+	if len(selections) == 0 {
+		return nil, nil, errors.New("no items selected to move")
+	}
+	if sourceOrderID == targetOrderID {
+		return nil, nil, errors.New("source and target orders are the same")
+	}
+
+	var source, target models.Order
+	err := s.orderRepo.WithTransaction(func(tx *gorm.DB) error {
+		if err := tx.First(&source, sourceOrderID).Error; err != nil {
+			return errors.New("source order not found")
+		}
+		moved := make([]map[string]interface{}, 0, len(selections))
+		if err := tx.First(&target, targetOrderID).Error; err != nil {
+			return errors.New("target order not found")
+		}
+		if source.Status != "OPEN" || target.Status != "OPEN" {
+			return errors.New("items can only be moved between open orders")
+		}
+
+		for _, sel := range selections {
+			var item models.OrderItem
+			if err := tx.Preload("Modifiers").First(&item, sel.ItemID).Error; err != nil {
+				return errors.New("order item not found")
+			}
+			if item.OrderID != source.ID {
+				return errors.New("item does not belong to the source order")
+			}
+			moved = append(moved, map[string]interface{}{
+				"item_id":      item.ID,
+				"product_name": item.ProductName,
+				"quantity":     sel.Quantity,
+			})
+			if err := s.moveItemTx(tx, &item, &target, sel.Quantity); err != nil {
+				return err
+			}
+		}
+
+		// Paid units never move, but the source total must still cover what was paid
+		// Ödenmiş adetler taşınmaz, yine de kaynak toplamı ödenen tutarı karşılamalı
+		if err := tx.First(&source, source.ID).Error; err != nil {
+			return err
+		}
+		if source.TotalAmount < source.PaidAmount {
+			return errors.New("source order total cannot drop below the amount already paid")
+		}
+		if err := tx.First(&target, target.ID).Error; err != nil {
+			return err
+		}
+		return s.audit.Record(tx, userID, models.AuditActionOrderItemMove, models.AuditEntityOrder, source.ID,
+			map[string]interface{}{"order_id": source.ID, "items": moved},
+			map[string]interface{}{"order_id": target.ID}, "")
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	logger.Info("Order items moved",
+		logger.Int("source_order_id", int(source.ID)),
+		logger.Int("target_order_id", int(target.ID)),
+		logger.Int("user_id", int(userID)),
+	)
+
+	s.publishOrderEvent(events.OrderUpdated, &source, map[string]interface{}{"items_moved_to": target.ID})
+	s.publishOrderEvent(events.OrderUpdated, &target, map[string]interface{}{"items_moved_from": source.ID})
+
+	sourceOrder, err := s.orderRepo.FindByID(source.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	targetOrder, err := s.orderRepo.FindByID(target.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return sourceOrder, targetOrder, nil
+}
+
+// MergeTables folds every OPEN order of the source table into the target table's current order.
+// Source orders with a discount, coupon, customer or pending loyalty redemption are refused.
+// Kaynak masanın tüm AÇIK siparişlerini hedef masanın mevcut siparişinde birleştirir.
+// İndirimi, kuponu, müşterisi veya bekleyen sadakat kullanımı olan kaynak siparişler reddedilir.
+func (s *OrderService) MergeTables(sourceTableID, targetTableID, userID uint) (*models.Order, error) {
+	if sourceTableID == targetTableID {
+		return nil, errors.New("cannot merge a table into itself")
+	}
+
+	var target models.Order
+	var merged []models.Order
+	var touched []*models.Table
+
+	err := s.orderRepo.WithTransaction(func(tx *gorm.DB) error {
+		var sourceTable, targetTable models.Table
+		if err := tx.First(&sourceTable, sourceTableID).Error; err != nil {
+			return errors.New("source table not found")
+		}
+		if err := tx.First(&targetTable, targetTableID).Error; err != nil {
+			return errors.New("target table not found")
+		}
+
+		var sources []models.Order
+		if err := tx.Where("table_id = ? AND status = ?", sourceTableID, "OPEN").Order("id asc").Find(&sources).Error; err != nil {
+			return err
+		}
+		if len(sources) == 0 {
+			return errors.New("source table has no open orders")
+		}
+		for _, o := range sources {
+			if o.PaidAmount > 0 {
+				return errors.New("cannot merge an order with recorded payments, transfer it instead")
+			}
+		}
+
+		// Merge into the target's current order, or adopt the first source order when the target is empty
+		// Hedefin mevcut siparişinde birleştir; hedef boşsa ilk kaynak siparişi devral
+		var targetOrders []models.Order
+		if err := tx.Where("table_id = ? AND status = ?", targetTableID, "OPEN").Order("id asc").Find(&targetOrders).Error; err != nil {
+			return err
+		}
+		switch {
+		case targetTable.CurrentOrderID != nil && containsOrder(targetOrders, *targetTable.CurrentOrderID):
+			if err := tx.First(&target, *targetTable.CurrentOrderID).Error; err != nil {
+				return err
+			}
+		case len(targetOrders) > 0:
+			target = targetOrders[0]
+		default:
+			target = sources[0]
+			sources = sources[1:]
+			if err := tx.Model(&target).UpdateColumn("table_id", targetTableID).Error; err != nil {
+				return err
+			}
+			target.TableID = &targetTable.ID
+			if err := s.retargetTicketsTx(tx, target.ID, targetTable.Name); err != nil {
+				return err
+			}
+		}
+
+		for _, o := range sources {
+			if err := checkMergeableTx(tx, &o); err != nil {
+				return err
+			}
+		}
+
+		for _, o := range sources {
+			var items []models.OrderItem
+			if err := tx.Preload("Modifiers").Where("order_id = ?", o.ID).Find(&items).Error; err != nil {
+				return err
+			}
+			for i := range items {
+				if err := s.moveItemTx(tx, &items[i], &target, items[i].Quantity); err != nil {
+					return err
+				}
+			}
+			if target.Note == "" && o.Note != "" {
+				target.Note = o.Note
+				if err := tx.Model(&target).UpdateColumn("note", o.Note).Error; err != nil {
+					return err
+				}
+			}
+
+			// The emptied order is removed just like a cancellation
+			// Boşalan sipariş iptal edilmiş gibi kaldırılır
+			if err := tx.Delete(&models.Order{}, o.ID).Error; err != nil {
+				return err
+			}
+			merged = append(merged, o)
+		}
+
+		if err := tx.First(&target, target.ID).Error; err != nil {
+			return err
+		}
+
+		var err error
+		touched, err = s.refreshTablesTx(tx, &sourceTable.ID, &targetTable.ID)
+		if err != nil {
+			return err
+		}
+
+		mergedIDs := make([]uint, 0, len(merged))
+		for _, o := range merged {
+			mergedIDs = append(mergedIDs, o.ID)
+		}
+		return s.audit.Record(tx, userID, models.AuditActionTableMerge, models.AuditEntityTable, sourceTable.ID,
+			map[string]interface{}{"order_ids": mergedIDs},
+			map[string]interface{}{"table_id": targetTable.ID, "order_id": target.ID}, "")
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("Tables merged",
+		logger.Int("source_table_id", int(sourceTableID)),
+		logger.Int("target_table_id", int(targetTableID)),
+		logger.Int("order_id", int(target.ID)),
+		logger.Int("user_id", int(userID)),
+	)
+
+	for i := range merged {
+		merged[i].Status = "CANCELLED"
+		s.publishOrderEvent(events.OrderCancelled, &merged[i], map[string]interface{}{"merged_into": target.ID})
+	}
+	s.publishOrderEvent(events.OrderUpdated, &target, map[string]interface{}{"merged_by": userID})
+	for _, table := range touched {
+		s.bus.Publish(events.TableStatusChanged, table)
+	}
+
+	return s.orderRepo.FindByID(target.ID)
+}
+
+// checkMergeableTx refuses to fold away an order carrying anything its items alone cannot take along,
+// otherwise the merged bill would lose its discount, coupon or customer without a trace
+// Yalnızca kalemleriyle taşınamayacak bir şey taşıyan siparişin birleştirilmesini reddeder,
+// aksi halde birleşen hesap indirimini, kuponunu veya müşterisini iz bırakmadan kaybederdi
+func checkMergeableTx(tx *gorm.DB, order *models.Order) error {
+	if order.DiscountType != "" && order.DiscountType != "NONE" {
+		return fmt.Errorf("order #%s has a discount, remove it or transfer the order instead", order.OrderNumber)
+	}
+	if order.CouponID != nil {
+		return fmt.Errorf("order #%s has a coupon, remove it or transfer the order instead", order.OrderNumber)
+	}
+	if order.CustomerID != nil {
+		return fmt.Errorf("order #%s is linked to a customer, unlink it or transfer the order instead", order.OrderNumber)
+	}
+	var redemptions int64
+	if err := tx.Model(&models.LoyaltyRedemption{}).Where("order_id = ?", order.ID).Count(&redemptions).Error; err != nil {
+		return err
+	}
+	if redemptions > 0 {
+		return fmt.Errorf("order #%s has a pending loyalty redemption, transfer the order instead", order.OrderNumber)
+	}
+	return nil
+}
+
+// moveItemTx moves quantity units of an item to the target order, splitting the line when only part of it moves
+// Kalemin quantity adedini hedef siparişe taşır; yalnızca bir kısmı taşınıyorsa satırı böler
+func (s *OrderService) moveItemTx(tx *gorm.DB, item *models.OrderItem, target *models.Order, quantity int) error {
+	if quantity <= 0 {
+		return errors.New("item quantity must be positive")
+	}
+	if quantity > item.Quantity-item.PaidQuantity {
+		return errors.New("cannot move more than the unpaid units of an item")
+	}
+
+	sourceOrderID := item.OrderID
+
+	if quantity == item.Quantity {
+		// Whole line: re-parent the item and its kitchen tickets, modifiers follow by foreign key
+		// Tüm satır: kalemi ve mutfak ticketlarını taşı, opsiyonlar yabancı anahtarla takip eder
+		if err := tx.Model(item).UpdateColumn("order_id", target.ID).Error; err != nil {
+			return err
+		}
+		ticketUpdates := map[string]interface{}{"order_id": target.ID}
+		if target.TableID != nil {
+			var table models.Table
+			if err := tx.First(&table, *target.TableID).Error; err != nil {
+				return err
+			}
+			ticketUpdates["table_name"] = table.Name
+		}
+		if err := tx.Model(&models.KitchenTicket{}).Where("order_item_id = ?", item.ID).UpdateColumns(ticketUpdates).Error; err != nil {
+			return err
+		}
+		if err := models.RecalculateOrderTotals(tx, sourceOrderID); err != nil {
+			return err
+		}
+		return models.RecalculateOrderTotals(tx, target.ID)
+	}
+
+	// Partial: shrink the source line and open a copy on the target.
+	// Tickets stay with the source line; the copy inherits its kitchen status.
+	// Kısmi: kaynak satırı küçült ve hedefte bir kopya aç.
+	// Ticketlar kaynak satırda kalır; kopya mutfak durumunu devralır.
+	modifiers := make([]models.OrderItemModifier, 0, len(item.Modifiers))
+	for _, m := range item.Modifiers {
+		modifiers = append(modifiers, models.OrderItemModifier{
+			GroupID:    m.GroupID,
+			OptionID:   m.OptionID,
+			GroupName:  m.GroupName,
+			OptionName: m.OptionName,
+			PriceDelta: m.PriceDelta,
+		})
+	}
+	moved := &models.OrderItem{
+		OrderID:       target.ID,
+		ProductID:     item.ProductID,
+		ProductName:   item.ProductName,
+		Quantity:      quantity,
+		UnitPrice:     item.UnitPrice,
+		TaxRate:       item.TaxRate,
+		Snapshotted:   true,
+		Note:          item.Note,
+		KitchenStatus: item.KitchenStatus,
+		Modifiers:     modifiers,
+	}
+	if err := tx.Create(moved).Error; err != nil {
+		return err
+	}
+
+	item.Quantity -= quantity
+	item.Subtotal = int64(item.Quantity) * item.LinePrice()
+	return tx.Omit("Modifiers").Save(item).Error
+}
+
+// retargetTicketsTx updates the table snapshot of unfinished tickets of an order
+// Bir siparişin bitmemiş ticketlarının masa snapshot'ını günceller
+func (s *OrderService) retargetTicketsTx(tx *gorm.DB, orderID uint, tableName string) error {
+	return tx.Model(&models.KitchenTicket{}).
+		Where("order_id = ? AND status IN ?", orderID, activeKitchenStatuses).
+		UpdateColumn("table_name", tableName).Error
+}
+
+// refreshTablesTx recomputes status and current order of the given tables from their OPEN orders
+// Verilen masaların durumunu ve mevcut siparişini AÇIK siparişlerine göre yeniden hesaplar
+func (s *OrderService) refreshTablesTx(tx *gorm.DB, tableIDs ...*uint) ([]*models.Table, error) {
+	var tables []*models.Table
+	for _, id := range tableIDs {
+		if id == nil {
+			continue
+		}
+
+		var table models.Table
+		if err := tx.First(&table, *id).Error; err != nil {
+			return nil, err
+		}
+
+		var open []models.Order
+		if err := tx.Where("table_id = ? AND status = ?", table.ID, "OPEN").Order("id asc").Find(&open).Error; err != nil {
+			return nil, err
+		}
+
+		if len(open) == 0 {
+			table.Status = models.TableStatusAvailable
+			table.CurrentOrderID = nil
+		} else {
+			table.Status = models.TableStatusOccupied
+			if table.CurrentOrderID == nil || !containsOrder(open, *table.CurrentOrderID) {
+				table.CurrentOrderID = &open[0].ID
+			}
+		}
+
+		if err := tx.Model(&table).Select("status", "current_order_id").Updates(&table).Error; err != nil {
+			return nil, err
+		}
+		tables = append(tables, &table)
+	}
+	return tables, nil
+}
+
+// containsOrder reports whether the order id is in the list
+// Sipariş kimliğinin listede olup olmadığını belirtir
+func containsOrder(orders []models.Order, id uint) bool {
+	for _, o := range orders {
+		if o.ID == id {
+			return true
+		}
+	}
+	return false
+}
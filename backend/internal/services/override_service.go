@@ -0,0 +1,211 @@
+package services
+
+import (
+	"errors"
+	"simple-pos/internal/models"
+	"simple-pos/internal/repositories"
+	"simple-pos/pkg/logger"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// Override errors
+// Yönetici onayı hataları
+var (
+	ErrOverrideRequired = errors.New("manager approval required")
+	ErrOverrideInvalid  = errors.New("invalid manager approval")
+	ErrOverrideLocked   = errors.New("too many wrong PINs, approver is locked for a while")
+)
+
+// Wrong PINs an actor may enter for an approver before the pair is locked out, and for how long
+// Kullanıcının bir onaylayan için kilitlenmeden önce girebileceği yanlış PIN sayısı ve kilit süresi
+const (
+	maxPinFailures = 5
+	pinLockout     = 5 * time.Minute
+)
+
+// overrideKey is an actor asking an approver, wrong PINs lock only this pair
+// so one user cannot lock the approvers out for everyone else
+// Bir onaylayandan onay isteyen kullanıcıdır, yanlış PIN'ler yalnızca bu ikiliyi kilitler,
+// böylece tek bir kullanıcı onaylayanları herkese karşı kilitleyemez
+type overrideKey struct {
+	actorID    uint
+	approverID uint
+}
+
+// pinFailures counts consecutive wrong PINs of an actor for an approver
+// Kullanıcının bir onaylayan için art arda girdiği yanlış PIN'leri sayar
+type pinFailures struct {
+	count       int
+	lockedUntil time.Time
+}
+
+// overrideFailure is a wrong PIN waiting to be audited, it unwraps to ErrOverrideInvalid or ErrOverrideLocked
+// Denetim kaydına yazılmayı bekleyen yanlış PIN'dir, ErrOverrideInvalid veya ErrOverrideLocked'a açılır
+type overrideFailure struct {
+	overrideKey
+	attempts int
+	locked   bool
+}
+
+func (f *overrideFailure) Error() string { return f.Unwrap().Error() }
+
+func (f *overrideFailure) Unwrap() error {
+	if f.locked {
+		return ErrOverrideLocked
+	}
+	return ErrOverrideInvalid
+}
+
+// OverrideInput carries the credentials of the approving manager
+// Onaylayan yöneticinin kimlik bilgilerini taşır
+type OverrideInput struct {
+	ApproverID uint
+	Pin        string
+}
+
+type OverrideService struct {
+	userRepo        repositories.UserRepository
+	roles           *RoleService
+	audit           *AuditService
+	discountPercent int
+
+	mu       sync.Mutex
+	failures map[overrideKey]*pinFailures // Reset on a correct PIN
+}
+
+func NewOverrideService(userRepo repositories.UserRepository, roles *RoleService, audit *AuditService, discountPercent int) *OverrideService {
+	return &OverrideService{
+		userRepo:        userRepo,
+		roles:           roles,
+		audit:           audit,
+		discountPercent: discountPercent,
+		failures:        make(map[overrideKey]*pinFailures),
+	}
+}
+
+// Approve authorizes a restricted action outside a transaction and returns the approving user.
+// Wrong PINs are written to the audit log right away.
+// Kısıtlı bir işlemi işlem (transaction) dışında onaylar ve onaylayan kullanıcıyı döndürür.
+// Yanlış PIN'ler hemen denetim kaydına yazılır.
+func (s *OverrideService) Approve(actorID uint, override *OverrideInput) (uint, error) {
+	approverID, err := s.Check(actorID, override)
+	if err != nil {
+		s.RecordFailure(err)
+	}
+	return approverID, err
+}
+
+// Check authorizes a restricted action and returns the approving user.
+// Actors allowed to approve overrides approve their own actions, anyone else needs a second user's PIN.
+// After maxPinFailures wrong PINs in a row the actor is locked out of that approver for pinLockout.
+// A wrong PIN is not audited here, inside a transaction pass the error to RecordFailure once it rolled back.
+// Kısıtlı bir işlemi onaylar ve onaylayan kullanıcıyı döndürür.
+// Onay yetkisi olanlar kendi işlemlerini onaylar, diğerleri ikinci bir kullanıcının PIN'ine ihtiyaç duyar.
+// Art arda maxPinFailures yanlış PIN'den sonra kullanıcı o onaylayana pinLockout süresince kilitlenir.
+// Yanlış PIN burada kaydedilmez, işlem içinde hata geri alındıktan sonra RecordFailure'a verilir.
+func (s *OverrideService) Check(actorID uint, override *OverrideInput) (uint, error) {
+	actor, err := s.userRepo.FindByID(actorID)
+	if err == nil && s.canApprove(actor) {
+		return actor.ID, nil
+	}
+
+	if override == nil || override.ApproverID == 0 {
+		return 0, ErrOverrideRequired
+	}
+	if override.ApproverID == actorID {
+		return 0, ErrOverrideInvalid
+	}
+
+	approver, err := s.userRepo.FindByID(override.ApproverID)
+	if err != nil || !approver.IsActive || !s.canApprove(approver) {
+		logger.Warn("Override rejected: Approver not allowed", logger.Int("actor_id", int(actorID)), logger.Int("approver_id", int(override.ApproverID)))
+		return 0, ErrOverrideInvalid
+	}
+	key := overrideKey{actorID: actorID, approverID: approver.ID}
+	if s.locked(key) {
+		logger.Warn("Override rejected: Approver locked", logger.Int("actor_id", int(actorID)), logger.Int("approver_id", int(override.ApproverID)))
+		return 0, ErrOverrideLocked
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(approver.PinCode), []byte(override.Pin)); err != nil {
+		logger.Warn("Override rejected: Wrong PIN", logger.Int("actor_id", int(actorID)), logger.Int("approver_id", int(override.ApproverID)))
+		return 0, s.countFailure(key)
+	}
+
+	s.mu.Lock()
+	delete(s.failures, key)
+	s.mu.Unlock()
+	return approver.ID, nil
+}
+
+// RecordFailure writes a wrong PIN returned by Check to the audit log, other errors are ignored
+// Check'in döndürdüğü yanlış PIN'i denetim kaydına yazar, diğer hatalar yok sayılır
+func (s *OverrideService) RecordFailure(err error) {
+	var failure *overrideFailure
+	if !errors.As(err, &failure) {
+		return
+	}
+
+	after := map[string]interface{}{"attempts": failure.attempts, "locked": failure.locked}
+	err = s.userRepo.WithTransaction(func(tx *gorm.DB) error {
+		return s.audit.Record(tx, failure.actorID, models.AuditActionOverrideFailed, models.AuditEntityUser, failure.approverID, nil, after, "")
+	})
+	if err != nil {
+		logger.Error("Failed to audit wrong override PIN", logger.Int("approver_id", int(failure.approverID)), logger.Err(err))
+	}
+}
+
+// locked reports whether the actor is still locked out of the approver
+// Kullanıcının onaylayana hâlâ kilitli olup olmadığını belirtir
+func (s *OverrideService) locked(key overrideKey) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f, ok := s.failures[key]
+	return ok && time.Now().Before(f.lockedUntil)
+}
+
+// countFailure counts a wrong PIN and locks the pair on the last allowed one
+// Yanlış PIN'i sayar ve izin verilen sonuncusunda ikiliyi kilitler
+func (s *OverrideService) countFailure(key overrideKey) *overrideFailure {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f, ok := s.failures[key]
+	if !ok {
+		f = &pinFailures{}
+		s.failures[key] = f
+	}
+	f.count++
+	failure := &overrideFailure{overrideKey: key, attempts: f.count, locked: f.count >= maxPinFailures}
+	if failure.locked {
+		f.count = 0
+		f.lockedUntil = time.Now().Add(pinLockout)
+	}
+	return failure
+}
+
+// DiscountNeedsApproval reports whether the discount of the order exceeds the allowed share of its subtotal
+// Siparişin indiriminin ara toplamın izin verilen payını aşıp aşmadığını belirtir
+func (s *OverrideService) DiscountNeedsApproval(order *models.Order) bool {
+	if order.DiscountAmount <= 0 {
+		return false
+	}
+	return order.DiscountAmount*100 > order.DiscountBase()*int64(s.discountPercent)
+}
+
+// canApprove reports whether the user's role grants the override permission
+// Kullanıcının rolünün onay yetkisi verip vermediğini belirtir
+func (s *OverrideService) canApprove(user *models.User) bool {
+	permissions, err := s.roles.Permissions(user.Role)
+	if err != nil {
+		return false
+	}
+	for _, p := range permissions {
+		if p == models.PermissionApproveOverride {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,277 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"simple-pos/internal/models"
+	"simple-pos/internal/platform/printing"
+	"simple-pos/internal/repositories"
+	"simple-pos/pkg/logger"
+	"sort"
+	"strings"
+)
+
+// Printing errors
+// Yazdırma hataları
+var (
+	ErrInvalidPrinter  = errors.New("invalid printer address")
+	ErrReceiptNotFound = errors.New("order not found")
+	ErrTicketNotFound  = errors.New("ticket not found")
+)
+
+// PrintSettings holds the printer configuration read at startup
+// Açılışta okunan yazıcı yapılandırmasını tutar
+type PrintSettings struct {
+	ReceiptPrinter string // Address of the receipt printer
+	KitchenPrinter string // Fallback for stations without their own printer
+	Width          int    // Characters per line
+	Header         string // Business name on receipts
+}
+
+type PrintService struct {
+	orderRepo   repositories.OrderRepository
+	tableRepo   repositories.TableRepository
+	kitchenRepo repositories.KitchenRepository
+	settings    PrintSettings
+}
+
+func NewPrintService(orderRepo repositories.OrderRepository, tableRepo repositories.TableRepository, kitchenRepo repositories.KitchenRepository, settings PrintSettings) *PrintService {
+	return &PrintService{
+		orderRepo:   orderRepo,
+		tableRepo:   tableRepo,
+		kitchenRepo: kitchenRepo,
+		settings:    settings,
+	}
+}
+
+// paymentMethodLabels are the names printed for payment methods
+// Ödeme yöntemleri için yazdırılan adlar
+var paymentMethodLabels = map[string]string{
+	models.PaymentMethodCash:       "Nakit",
+	models.PaymentMethodCreditCard: "Kredi Kartı",
+	models.PaymentMethodOnAccount:  "Veresiye",
+}
+
+// RenderReceipt renders the receipt of an order as an ESC/POS stream
+// Siparişin fişini ESC/POS akışı olarak oluşturur
+func (s *PrintService) RenderReceipt(orderID uint) ([]byte, error) {
+	order, err := s.orderRepo.GetOrderWithDetails(orderID)
+	if err != nil {
+		return nil, ErrReceiptNotFound
+	}
+
+	doc := printing.NewDocument(s.settings.Width)
+	doc.Align(printing.AlignCenter).DoubleSize(true).Bold(true).Line(s.settings.Header).DoubleSize(false).Bold(false)
+	doc.Feed(1).Align(printing.AlignLeft)
+
+	doc.Columns("Sipariş", order.OrderNumber)
+	doc.Columns("Tarih", order.CreatedAt.Format("02.01.2006 15:04"))
+	if table := s.tableName(order); table != "" {
+		doc.Columns("Masa", table)
+	}
+	if order.Waiter != nil {
+		doc.Columns("Garson", order.Waiter.Name)
+	}
+	doc.Separator()
+
+	for _, item := range order.Items {
+		doc.Columns(fmt.Sprintf("%d x %s", item.Quantity, item.ProductName), formatMoney(item.Subtotal))
+		for _, m := range item.Modifiers {
+			doc.Line("  + " + m.OptionName)
+		}
+		if item.Quantity > 1 {
+			doc.Line("  @ " + formatMoney(item.LinePrice()))
+		}
+	}
+	doc.Separator()
+
+	doc.Columns("Ara Toplam", formatMoney(order.Subtotal))
+	for _, p := range order.Promotions {
+		doc.Columns(p.PromotionName, "-"+formatMoney(p.Amount))
+	}
+	if order.CouponAmount > 0 {
+		doc.Columns("Kupon "+order.CouponCode, "-"+formatMoney(order.CouponAmount))
+	}
+	if order.DiscountAmount > 0 {
+		label := "İndirim"
+		if order.DiscountType == "PERCENTAGE" {
+			label = fmt.Sprintf("İndirim %%%d", order.DiscountValue)
+		}
+		doc.Columns(label, "-"+formatMoney(order.DiscountAmount))
+	}
+	for _, t := range receiptTaxes(order.Items) {
+		doc.Columns(fmt.Sprintf("KDV %%%d", t.TaxRate), formatMoney(t.TaxAmount))
+	}
+	doc.Bold(true).DoubleSize(true).Columns("TOPLAM", formatMoney(order.TotalAmount)).DoubleSize(false).Bold(false)
+
+	if len(order.Payments) > 0 {
+		doc.Separator()
+		for _, p := range order.Payments {
+			label, ok := paymentMethodLabels[p.Method]
+			if !ok {
+				label = p.Method
+			}
+			doc.Columns(label, formatMoney(p.Amount))
+		}
+		if remaining := order.RemainingAmount(); remaining > 0 {
+			doc.Columns("Kalan", formatMoney(remaining))
+		}
+	}
+
+	doc.Feed(1).Align(printing.AlignCenter).Line("Teşekkür ederiz").Feed(3).Cut()
+	return doc.Bytes(), nil
+}
+
+// PrintReceipt sends the receipt of an order to the receipt printer
+// Siparişin fişini fiş yazıcısına gönderir
+func (s *PrintService) PrintReceipt(orderID uint) error {
+	data, err := s.RenderReceipt(orderID)
+	if err != nil {
+		return err
+	}
+	transport, err := printing.ParseTransport(s.settings.ReceiptPrinter)
+	if err != nil {
+		return err
+	}
+	return transport.Send(data)
+}
+
+// RenderKitchenTicket renders a kitchen ticket as an ESC/POS stream
+// Mutfak ticket'ını ESC/POS akışı olarak oluşturur
+func (s *PrintService) RenderKitchenTicket(ticket *models.KitchenTicket) []byte {
+	doc := printing.NewDocument(s.settings.Width)
+	doc.Align(printing.AlignCenter).Bold(true)
+	if ticket.StationName != "" {
+		doc.Line(ticket.StationName)
+	}
+	table := ticket.TableName
+	if table == "" {
+		table = "Paket"
+	}
+	doc.DoubleSize(true).Line(table).DoubleSize(false).Bold(false)
+	doc.Line(fmt.Sprintf("#%d  %s", ticket.OrderID, ticket.CreatedAt.Format("15:04")))
+	doc.Align(printing.AlignLeft).Separator()
+
+	doc.DoubleSize(true).Bold(true).Line(fmt.Sprintf("%d x %s", ticket.Quantity, ticket.ProductName)).Bold(false).DoubleSize(false)
+	if ticket.Modifiers != "" {
+		for _, m := range strings.Split(ticket.Modifiers, ",") {
+			doc.Line("  + " + strings.TrimSpace(m))
+		}
+	}
+	if ticket.Note != "" {
+		doc.Bold(true).Line("NOT: " + ticket.Note).Bold(false)
+	}
+
+	doc.Feed(3).Cut()
+	return doc.Bytes()
+}
+
+// PrintKitchenTicket (re)prints a kitchen ticket on its station printer
+// Mutfak ticket'ını istasyon yazıcısına (yeniden) yazdırır
+func (s *PrintService) PrintKitchenTicket(ticketID uint) error {
+	ticket, err := s.kitchenRepo.FindTicketByID(ticketID)
+	if err != nil {
+		return ErrTicketNotFound
+	}
+	transport, err := printing.ParseTransport(s.kitchenPrinter(ticket))
+	if err != nil {
+		return err
+	}
+	return transport.Send(s.RenderKitchenTicket(ticket))
+}
+
+// PrintKitchenTicketAsync prints a freshly fired ticket in the background.
+// Stations without a printer are skipped; a nil service is a no-op.
+// Yeni gönderilen ticket'ı arka planda yazdırır.
+// Yazıcısı olmayan istasyonlar atlanır; nil servis hiçbir şey yapmaz.
+func (s *PrintService) PrintKitchenTicketAsync(ticket *models.KitchenTicket) {
+	if s == nil {
+		return
+	}
+	address := s.kitchenPrinter(ticket)
+	if address == "" {
+		return
+	}
+
+	data := s.RenderKitchenTicket(ticket)
+	go func() {
+		transport, err := printing.ParseTransport(address)
+		if err == nil {
+			err = transport.Send(data)
+		}
+		if err != nil {
+			logger.Error("Failed to print kitchen ticket", logger.Int("ticket_id", int(ticket.ID)), logger.Err(err))
+		}
+	}()
+}
+
+// kitchenPrinter resolves the printer of the ticket's station, falling back to the kitchen default
+// Ticket'ın istasyon yazıcısını belirler, yoksa mutfak varsayılanına düşer
+func (s *PrintService) kitchenPrinter(ticket *models.KitchenTicket) string {
+	if ticket.StationID != nil {
+		if station, err := s.kitchenRepo.FindStationByID(*ticket.StationID); err == nil && station.Printer != "" {
+			return station.Printer
+		}
+	}
+	return s.settings.KitchenPrinter
+}
+
+// tableName returns the table snapshot of the order, or the live table name while it is open
+// Siparişin masa snapshot'ını, açıkken güncel masa adını döndürür
+func (s *PrintService) tableName(order *models.Order) string {
+	if order.TableName != "" {
+		return order.TableName
+	}
+	if order.TableID != nil {
+		if table, err := s.tableRepo.FindByID(*order.TableID); err == nil {
+			return table.Name
+		}
+	}
+	return ""
+}
+
+// receiptTaxes sums the KDV snapshots of the items per rate, lowest rate first
+// Kalemlerin KDV snapshot'larını orana göre toplar, düşük oran önce
+func receiptTaxes(items []models.OrderItem) []models.TaxRateTotal {
+	byRate := make(map[int]*models.TaxRateTotal)
+	for _, item := range items {
+		t, ok := byRate[item.TaxRate]
+		if !ok {
+			t = &models.TaxRateTotal{TaxRate: item.TaxRate}
+			byRate[item.TaxRate] = t
+		}
+		t.NetAmount += item.NetAmount
+		t.TaxAmount += item.TaxAmount
+		t.GrossAmount += item.NetAmount + item.TaxAmount
+	}
+
+	totals := make([]models.TaxRateTotal, 0, len(byRate))
+	for _, t := range byRate {
+		totals = append(totals, *t)
+	}
+	sort.Slice(totals, func(i, j int) bool { return totals[i].TaxRate < totals[j].TaxRate })
+	return totals
+}
+
+// formatMoney renders kuruş as lira with a decimal comma, e.g. 1250 -> "12,50"
+// Kuruşu ondalık virgüllü lira olarak yazar, örn. 1250 -> "12,50"
+func formatMoney(amount int64) string {
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	return fmt.Sprintf("%s%d,%02d", sign, amount/100, amount%100)
+}
+
+// validatePrinter checks that a non-empty printer address can be used
+// Boş olmayan yazıcı adresinin kullanılabilir olduğunu kontrol eder
+func validatePrinter(address string) error {
+	if address == "" {
+		return nil
+	}
+	if _, err := printing.ParseTransport(address); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidPrinter, err)
+	}
+	return nil
+}
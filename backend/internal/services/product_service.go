@@ -16,7 +16,7 @@ func NewProductService(repo repositories.ProductRepository) *ProductService {
 
 // CreateProduct adds a new product
 // Yeni bir ürün ekler
-func (s *ProductService) CreateProduct(name string, price int64, categoryID uint, description, imageURL string) (*models.Product, error) {
+func (s *ProductService) CreateProduct(name string, price int64, categoryID uint, description, imageURL string, taxRate *int) (*models.Product, error) {
 	if price < 0 {
 		return nil, errors.New("price cannot be negative")
 	}
@@ -28,6 +28,7 @@ func (s *ProductService) CreateProduct(name string, price int64, categoryID uint
 		Description: description,
 		ImageURL:    imageURL,
 		IsAvailable: true,
+		TaxRate:     taxRate,
 	}
 
 	if err := s.repo.Create(product); err != nil {
@@ -48,7 +49,7 @@ func (s *ProductService) GetProducts(categoryID *uint) ([]models.Product, error)
 
 // UpdateProduct updates product details
 // Ürün detaylarını günceller
-func (s *ProductService) UpdateProduct(id uint, name string, price int64, isAvailable bool, description, imageURL string, categoryID uint) (*models.Product, error) {
+func (s *ProductService) UpdateProduct(id uint, name string, price int64, isAvailable bool, description, imageURL string, categoryID uint, taxRate *int) (*models.Product, error) {
 	product, err := s.repo.FindByID(id)
 	if err != nil {
 		return nil, err
@@ -63,6 +64,7 @@ func (s *ProductService) UpdateProduct(id uint, name string, price int64, isAvai
 	product.IsAvailable = isAvailable
 	product.Description = description
 	product.ImageURL = imageURL
+	product.TaxRate = taxRate
 	if categoryID > 0 {
 		product.CategoryID = categoryID
 	}
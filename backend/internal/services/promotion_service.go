@@ -0,0 +1,219 @@
+package services
+
+import (
+	"errors"
+	"simple-pos/internal/models"
+	"simple-pos/internal/repositories"
+	"strings"
+	"time"
+)
+
+// ErrPromotionNotFound is returned for unknown promotions
+// Bilinmeyen promosyonlar için döndürülür
+var ErrPromotionNotFound = errors.New("promotion not found")
+
+// PromotionInput describes a promotion rule
+// Bir promosyon kuralını tanımlar
+type PromotionInput struct {
+	Name        string
+	Type        string
+	CategoryID  *uint
+	ProductID   *uint
+	Percent     int
+	BuyQuantity int
+	GetQuantity int
+	ComboPrice  int64
+	ComboItems  []models.PromotionComboItem
+	StartTime   string
+	EndTime     string
+	StartsAt    *time.Time
+	EndsAt      *time.Time
+	IsActive    bool
+}
+
+type PromotionService struct {
+	repo         repositories.PromotionRepository
+	productRepo  repositories.ProductRepository
+	categoryRepo repositories.CategoryRepository
+}
+
+func NewPromotionService(repo repositories.PromotionRepository, productRepo repositories.ProductRepository, categoryRepo repositories.CategoryRepository) *PromotionService {
+	return &PromotionService{
+		repo:         repo,
+		productRepo:  productRepo,
+		categoryRepo: categoryRepo,
+	}
+}
+
+// ListPromotions returns the promotions, only active ones unless asked otherwise
+// Promosyonları döndürür, aksi istenmedikçe yalnızca etkin olanları
+func (s *PromotionService) ListPromotions(includeInactive bool) ([]models.Promotion, error) {
+	return s.repo.FindAll(includeInactive)
+}
+
+// GetPromotion returns a promotion with its combo items
+// Promosyonu menü kalemleriyle döndürür
+func (s *PromotionService) GetPromotion(id uint) (*models.Promotion, error) {
+	promotion, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, ErrPromotionNotFound
+	}
+	return promotion, nil
+}
+
+// CreatePromotion adds a promotion, open orders pick it up with their next item change
+// Promosyon ekler, açık siparişler bir sonraki kalem değişikliğinde uygular
+func (s *PromotionService) CreatePromotion(input PromotionInput) (*models.Promotion, error) {
+	promotion := &models.Promotion{}
+	if err := s.applyPromotion(promotion, input); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Create(promotion); err != nil {
+		return nil, err
+	}
+	return promotion, nil
+}
+
+// UpdatePromotion changes a promotion, completed orders keep what they were sold with
+// Promosyonu değiştirir, tamamlanan siparişler satıldıkları haliyle kalır
+func (s *PromotionService) UpdatePromotion(id uint, input PromotionInput) (*models.Promotion, error) {
+	promotion, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, ErrPromotionNotFound
+	}
+	if err := s.applyPromotion(promotion, input); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(promotion); err != nil {
+		return nil, err
+	}
+	return promotion, nil
+}
+
+// DeletePromotion removes a promotion
+// Promosyonu siler
+func (s *PromotionService) DeletePromotion(id uint) error {
+	if _, err := s.repo.FindByID(id); err != nil {
+		return ErrPromotionNotFound
+	}
+	return s.repo.Delete(id)
+}
+
+// applyPromotion validates the input for its type and copies it onto the promotion
+// Girdiyi türüne göre doğrular ve promosyona kopyalar
+func (s *PromotionService) applyPromotion(promotion *models.Promotion, input PromotionInput) error {
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return errors.New("promotion name is required")
+	}
+	if (input.StartTime == "") != (input.EndTime == "") {
+		return errors.New("time window needs both start_time and end_time")
+	}
+	for _, clock := range []string{input.StartTime, input.EndTime} {
+		if _, err := time.Parse("15:04", clock); clock != "" && err != nil {
+			return errors.New("invalid time format (use HH:MM)")
+		}
+	}
+	if input.StartTime != "" && input.StartTime == input.EndTime {
+		return errors.New("start_time and end_time cannot be the same")
+	}
+	if input.StartsAt != nil && input.EndsAt != nil && !input.EndsAt.After(*input.StartsAt) {
+		return errors.New("ends_at must be after starts_at")
+	}
+
+	promotion.Name = name
+	promotion.Type = input.Type
+	promotion.CategoryID = nil
+	promotion.ProductID = nil
+	promotion.Percent = 0
+	promotion.BuyQuantity = 0
+	promotion.GetQuantity = 0
+	promotion.ComboPrice = 0
+	promotion.ComboItems = nil
+	promotion.StartTime = input.StartTime
+	promotion.EndTime = input.EndTime
+	promotion.StartsAt = input.StartsAt
+	promotion.EndsAt = input.EndsAt
+	promotion.IsActive = input.IsActive
+
+	switch input.Type {
+	case models.PromotionHappyHour, models.PromotionCategoryPercent:
+		if input.Type == models.PromotionHappyHour && input.StartTime == "" {
+			return errors.New("happy hour needs a time window")
+		}
+		if input.Type == models.PromotionCategoryPercent && input.CategoryID == nil {
+			return errors.New("category is required")
+		}
+		if input.Percent < 1 || input.Percent > 100 {
+			return errors.New("percent must be between 1 and 100")
+		}
+		promotion.Percent = input.Percent
+		return s.applyTarget(promotion, input)
+
+	case models.PromotionBuyXGetY:
+		if input.BuyQuantity < 1 || input.GetQuantity < 1 {
+			return errors.New("buy and get quantities must be at least 1")
+		}
+		percent := input.Percent
+		if percent == 0 {
+			percent = 100 // The Y units are free by default
+		}
+		if percent < 1 || percent > 100 {
+			return errors.New("percent must be between 1 and 100")
+		}
+		promotion.Percent = percent
+		promotion.BuyQuantity = input.BuyQuantity
+		promotion.GetQuantity = input.GetQuantity
+		return s.applyTarget(promotion, input)
+
+	case models.PromotionCombo:
+		if input.ComboPrice < 0 {
+			return errors.New("combo price cannot be negative")
+		}
+		if len(input.ComboItems) < 2 {
+			return errors.New("a combo needs at least two items")
+		}
+		quantities := make(map[uint]int, len(input.ComboItems))
+		var order []uint
+		for _, item := range input.ComboItems {
+			if item.Quantity < 1 {
+				return errors.New("combo item quantity must be at least 1")
+			}
+			if _, err := s.productRepo.FindByID(item.ProductID); err != nil {
+				return errors.New("combo product not found")
+			}
+			if _, ok := quantities[item.ProductID]; !ok {
+				order = append(order, item.ProductID)
+			}
+			quantities[item.ProductID] += item.Quantity
+		}
+		for _, productID := range order {
+			promotion.ComboItems = append(promotion.ComboItems, models.PromotionComboItem{ProductID: productID, Quantity: quantities[productID]})
+		}
+		promotion.ComboPrice = input.ComboPrice
+		return nil
+
+	default:
+		return errors.New("invalid promotion type")
+	}
+}
+
+// applyTarget sets the product or category a promotion covers, exactly one of them
+// Promosyonun kapsadığı ürünü veya kategoriyi belirler, yalnızca biri
+func (s *PromotionService) applyTarget(promotion *models.Promotion, input PromotionInput) error {
+	if (input.ProductID == nil) == (input.CategoryID == nil) {
+		return errors.New("promotion needs either a product or a category")
+	}
+	if input.ProductID != nil {
+		if _, err := s.productRepo.FindByID(*input.ProductID); err != nil {
+			return errors.New("product not found")
+		}
+		promotion.ProductID = input.ProductID
+		return nil
+	}
+	if _, err := s.categoryRepo.FindByID(*input.CategoryID); err != nil {
+		return errors.New("category not found")
+	}
+	promotion.CategoryID = input.CategoryID
+	return nil
+}
@@ -0,0 +1,409 @@
+package services
+
+import (
+	"errors"
+	"simple-pos/internal/models"
+	"simple-pos/internal/platform/events"
+	"simple-pos/internal/repositories"
+	"simple-pos/pkg/logger"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// refundMethodOrder is the order in which a void gives money back when the sale was paid with several methods
+// Birden fazla yöntemle ödenmiş satış iptal edilirken paranın iade sırası
+var refundMethodOrder = []string{models.PaymentMethodCash, models.PaymentMethodCreditCard, models.PaymentMethodOnAccount}
+
+// RefundItemInput selects units of an order item to refund
+// İade edilecek sipariş kalemi adetlerini seçer
+type RefundItemInput struct {
+	ItemID   uint
+	Quantity int
+}
+
+// RefundInput describes a refund of a completed order
+// Tamamlanmış bir siparişin iadesini tanımlar
+type RefundInput struct {
+	Type          string // ITEMS, VOID
+	Items         []RefundItemInput
+	ReasonCode    string
+	Note          string
+	PaymentMethod string // Optional, required for item refunds of orders paid with several methods
+	Restock       bool
+	UserID        uint           // User giving the refund
+	Override      *OverrideInput // Manager approval when the user cannot approve refunds alone
+}
+
+type RefundService struct {
+	refundRepo      repositories.RefundRepository
+	orderRepo       repositories.OrderRepository
+	transactionRepo repositories.TransactionRepository
+	workPeriodRepo  repositories.WorkPeriodRepository
+	inventory       *InventoryService
+	audit           *AuditService
+	bus             *events.Bus
+	overrides       *OverrideService
+	customers       *CustomerService
+
+	// refundHooks run inside the transaction of every refund
+	// Her iadenin işlemi içinde çalışır
+	refundHooks []RefundHook
+}
+
+// RefundHook runs inside the refund transaction once the order is updated, an error rolls the refund back.
+// The order status is REFUNDED when the refund gave back its last unit.
+// Sipariş güncellendikten sonra iade işlemi içinde çalışır, hata iadeyi geri alır.
+// İade son adedi de geri verdiyse sipariş durumu REFUNDED olur.
+type RefundHook func(tx *gorm.DB, order *models.Order, refund *models.Refund, userID uint) error
+
+func NewRefundService(refundRepo repositories.RefundRepository, orderRepo repositories.OrderRepository, txRepo repositories.TransactionRepository, wpRepo repositories.WorkPeriodRepository, inventory *InventoryService, audit *AuditService, bus *events.Bus, overrides *OverrideService, customers *CustomerService) *RefundService {
+	return &RefundService{
+		refundRepo:      refundRepo,
+		orderRepo:       orderRepo,
+		transactionRepo: txRepo,
+		workPeriodRepo:  wpRepo,
+		inventory:       inventory,
+		audit:           audit,
+		bus:             bus,
+		overrides:       overrides,
+		customers:       customers,
+	}
+}
+
+// OnRefund registers a hook that runs whenever an order is refunded, the counterpart of OnClose
+// Her sipariş iade edildiğinde çalışacak bir hook kaydeder, OnClose'un karşılığı
+func (s *RefundService) OnRefund(hook RefundHook) {
+	s.refundHooks = append(s.refundHooks, hook)
+}
+
+// RefundOrder gives back (part of) a completed order with negative REFUND transactions (ACID).
+// The original INCOME transactions and payments stay untouched.
+// Tamamlanmış siparişin (bir kısmını) negatif REFUND işlemleriyle iade eder (ACID).
+// Orijinal GELİR işlemleri ve ödemeler değiştirilmez.
+func (s *RefundService) RefundOrder(orderID uint, input RefundInput) (*models.Refund, error) {
+	if input.Type != models.RefundTypeItems && input.Type != models.RefundTypeVoid {
+		return nil, errors.New("invalid refund type")
+	}
+	if !validRefundReason(input.ReasonCode) {
+		return nil, errors.New("invalid refund reason code")
+	}
+	if input.Type == models.RefundTypeItems && len(input.Items) == 0 {
+		return nil, errors.New("no items selected for refund")
+	}
+	if input.PaymentMethod != "" && input.PaymentMethod != models.PaymentMethodCash && input.PaymentMethod != models.PaymentMethodCreditCard && input.PaymentMethod != models.PaymentMethodOnAccount {
+		return nil, errors.New("invalid payment method")
+	}
+
+	// Money leaves the till of the running day
+	// Para açık olan günün kasasından çıkar
+	period, err := s.workPeriodRepo.FindActivePeriod()
+	if err != nil {
+		return nil, err
+	}
+	if period == nil {
+		return nil, errors.New("no active work period found")
+	}
+
+	// Taking money back out of a completed order always needs a manager
+	// Tamamlanmış siparişten para iadesi her zaman yönetici gerektirir
+	approverID, err := s.overrides.Approve(input.UserID, input.Override)
+	if err != nil {
+		return nil, err
+	}
+
+	var order models.Order
+	refund := &models.Refund{
+		OrderID:      orderID,
+		WorkPeriodID: period.ID,
+		Type:         input.Type,
+		ReasonCode:   input.ReasonCode,
+		Note:         input.Note,
+		Restock:      input.Restock,
+		ApprovedBy:   approverID,
+		CreatedBy:    input.UserID,
+	}
+
+	err = s.refundRepo.WithTransaction(func(tx *gorm.DB) error {
+		if err := tx.Preload("Items").Preload("Payments").First(&order, orderID).Error; err != nil {
+			return errors.New("order not found")
+		}
+		if order.Status == "REFUNDED" {
+			return errors.New("order is already fully refunded")
+		}
+		if order.Status != "COMPLETED" {
+			return errors.New("only completed orders can be refunded")
+		}
+		before := map[string]interface{}{
+			"status":          order.Status,
+			"refunded_amount": order.RefundedAmount,
+		}
+
+		selections := input.Items
+		if input.Type == models.RefundTypeVoid {
+			selections = nil
+			for _, item := range order.Items {
+				if left := item.Quantity - item.RefundedQuantity; left > 0 {
+					selections = append(selections, RefundItemInput{ItemID: item.ID, Quantity: left})
+				}
+			}
+		}
+
+		if err := s.refundItems(tx, &order, refund, selections); err != nil {
+			return err
+		}
+
+		parts, err := s.allocateMethods(tx, &order, refund.Amount, input)
+		if err != nil {
+			return err
+		}
+		refund.PaymentMethod = models.PaymentMethodMixed
+		if len(parts) == 1 {
+			for method := range parts {
+				refund.PaymentMethod = method
+			}
+		}
+
+		if err := s.refundRepo.CreateWithTx(tx, refund); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		for _, method := range refundMethodOrder {
+			amount, ok := parts[method]
+			if !ok {
+				continue
+			}
+			transaction := &models.Transaction{
+				Type:            models.TransactionTypeRefund,
+				Category:        "Refund",
+				PaymentMethod:   method,
+				Amount:          -amount,
+				Description:     "Refund of order #" + order.OrderNumber,
+				OrderID:         &order.ID,
+				RefundID:        &refund.ID,
+				WorkPeriodID:    period.ID,
+				CreatedBy:       input.UserID,
+				TransactionDate: now,
+			}
+			if err := s.transactionRepo.CreateWithTx(tx, transaction); err != nil {
+				return err
+			}
+
+			// Sales charged to a tab are given back by lowering what the customer owes
+			// Hesaba yazılan satışlar müşterinin borcu düşürülerek iade edilir
+			if method == models.PaymentMethodOnAccount {
+				if order.CustomerID == nil {
+					return ErrCustomerRequired
+				}
+				if err := s.customers.CreditRefundWithTx(tx, *order.CustomerID, &order, refund, amount, input.UserID); err != nil {
+					return err
+				}
+			}
+		}
+
+		order.RefundedAmount += refund.Amount
+		updates := map[string]interface{}{"refunded_amount": order.RefundedAmount}
+		if fullyRefunded(order.Items) {
+			order.Status = "REFUNDED"
+			updates["status"] = order.Status
+		}
+		// UpdateColumns skips hooks, order totals stay as sold
+		// UpdateColumns hook'ları atlar, sipariş toplamları satıldığı gibi kalır
+		if err := tx.Model(&order).UpdateColumns(updates).Error; err != nil {
+			return err
+		}
+
+		for _, hook := range s.refundHooks {
+			if err := hook(tx, &order, refund, input.UserID); err != nil {
+				return err
+			}
+		}
+
+		// Only the units of this refund go back, earlier refunds without restock stay wasted
+		// Yalnızca bu iadenin adetleri stoğa döner, önceki stoksuz iadeler fire olarak kalır
+		if input.Restock {
+			for i := range order.Items {
+				item := &order.Items[i]
+				if _, err := s.inventory.ReturnItemStockWithTx(tx, item, refundedUnits(refund, item.ID), input.UserID); err != nil {
+					return err
+				}
+			}
+		}
+
+		after := map[string]interface{}{
+			"status":          order.Status,
+			"refunded_amount": order.RefundedAmount,
+			"refund":          refund,
+		}
+		return s.audit.Record(tx, input.UserID, models.AuditActionOrderRefund, models.AuditEntityOrder, order.ID, before, after, input.ReasonCode)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("Order refunded", logger.Int("order_id", int(order.ID)), logger.Int("amount", int(refund.Amount)))
+	s.bus.Publish(events.OrderRefunded, map[string]interface{}{
+		"order_id":        order.ID,
+		"order_number":    order.OrderNumber,
+		"table_id":        order.TableID,
+		"status":          order.Status,
+		"refund_id":       refund.ID,
+		"amount":          refund.Amount,
+		"refunded_amount": order.RefundedAmount,
+	})
+	return refund, nil
+}
+
+// ListRefunds returns the refunds of an order
+// Siparişin iadelerini döndürür
+func (s *RefundService) ListRefunds(orderID uint) ([]models.Refund, error) {
+	if _, err := s.orderRepo.FindByID(orderID); err != nil {
+		return nil, errors.New("order not found")
+	}
+	return s.refundRepo.FindByOrderID(orderID)
+}
+
+// refundItems marks the selected units as refunded and sets the refund amount from their share of the sale
+// Seçilen adetleri iade edildi olarak işaretler ve iade tutarını satıştaki paylarından belirler
+func (s *RefundService) refundItems(tx *gorm.DB, order *models.Order, refund *models.Refund, selections []RefundItemInput) error {
+	if len(selections) == 0 {
+		return errors.New("nothing left to refund")
+	}
+
+	for _, sel := range selections {
+		if sel.Quantity <= 0 {
+			return errors.New("item quantity must be positive")
+		}
+
+		var item *models.OrderItem
+		for i := range order.Items {
+			if order.Items[i].ID == sel.ItemID {
+				item = &order.Items[i]
+				break
+			}
+		}
+		if item == nil {
+			return errors.New("item does not belong to this order")
+		}
+		if sel.Quantity > item.Quantity-item.RefundedQuantity {
+			return errors.New("item quantity exceeds refundable units")
+		}
+
+		// The line already carries its discount share and KDV
+		// Satır indirim payını ve KDV'sini zaten taşır
+		amount := item.PayableAmount() * int64(sel.Quantity) / int64(item.Quantity)
+		item.RefundedQuantity += sel.Quantity
+		if err := tx.Model(item).UpdateColumn("refunded_quantity", item.RefundedQuantity).Error; err != nil {
+			return err
+		}
+
+		refund.Items = append(refund.Items, models.RefundItem{
+			OrderItemID: item.ID,
+			ProductName: item.ProductName,
+			Quantity:    sel.Quantity,
+			Amount:      amount,
+		})
+		refund.Amount += amount
+	}
+
+	// Once every unit is refunded, give back the exact remainder of what was paid
+	// Tüm adetler iade edildiğinde ödenen tutarın kalanını tam olarak iade et
+	refundable := order.PaidAmount - order.RefundedAmount
+	if fullyRefunded(order.Items) || refund.Amount > refundable {
+		refund.Items[len(refund.Items)-1].Amount += refundable - refund.Amount
+		refund.Amount = refundable
+	}
+	if refund.Amount <= 0 {
+		return errors.New("nothing left to refund")
+	}
+	return nil
+}
+
+// allocateMethods decides which payment methods the refund is paid back through.
+// Refunds cannot exceed what was taken with a method minus what it already gave back.
+// İadenin hangi ödeme yöntemleriyle geri ödeneceğini belirler.
+// İade, bir yöntemle alınan tutardan daha önce iade edileni düşünce kalanı aşamaz.
+func (s *RefundService) allocateMethods(tx *gorm.DB, order *models.Order, amount int64, input RefundInput) (map[string]int64, error) {
+	available := make(map[string]int64)
+	for _, p := range order.Payments {
+		available[p.Method] += p.Amount
+	}
+
+	var refunded []models.Transaction
+	if err := tx.Where("order_id = ? AND type = ?", order.ID, models.TransactionTypeRefund).Find(&refunded).Error; err != nil {
+		return nil, err
+	}
+	for _, t := range refunded {
+		available[t.PaymentMethod] += t.Amount // Stored negative
+	}
+
+	var methods []string
+	for _, method := range refundMethodOrder {
+		if available[method] > 0 {
+			methods = append(methods, method)
+		}
+	}
+
+	switch {
+	case input.PaymentMethod != "":
+		if available[input.PaymentMethod] < amount {
+			return nil, errors.New("refund exceeds the amount paid with this method")
+		}
+		return map[string]int64{input.PaymentMethod: amount}, nil
+	case len(methods) == 1:
+		return map[string]int64{methods[0]: amount}, nil
+	case input.Type != models.RefundTypeVoid:
+		return nil, errors.New("payment method is required for orders paid with several methods")
+	}
+
+	// A void gives every method back what is left of it
+	// İptal, her yönteme kalan tutarını geri verir
+	parts := make(map[string]int64)
+	left := amount
+	for _, method := range methods {
+		if left == 0 {
+			break
+		}
+		share := available[method]
+		if share > left {
+			share = left
+		}
+		parts[method] = share
+		left -= share
+	}
+	if left > 0 {
+		return nil, errors.New("refund exceeds the amount paid")
+	}
+	return parts, nil
+}
+
+// fullyRefunded reports whether every unit of the order has been refunded
+// Siparişin tüm adetlerinin iade edilip edilmediğini belirtir
+func fullyRefunded(items []models.OrderItem) bool {
+	for _, item := range items {
+		if item.RefundedQuantity < item.Quantity {
+			return false
+		}
+	}
+	return true
+}
+
+func refundedUnits(refund *models.Refund, itemID uint) int {
+	units := 0
+	for _, ri := range refund.Items {
+		if ri.OrderItemID == itemID {
+			units += ri.Quantity
+		}
+	}
+	return units
+}
+
+func validRefundReason(code string) bool {
+	switch code {
+	case models.RefundReasonCustomerComplaint, models.RefundReasonWrongItem, models.RefundReasonQuality,
+		models.RefundReasonOvercharge, models.RefundReasonOther:
+		return true
+	}
+	return false
+}
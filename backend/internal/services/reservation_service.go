@@ -0,0 +1,372 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"simple-pos/internal/models"
+	"simple-pos/internal/platform/events"
+	"simple-pos/internal/repositories"
+	"simple-pos/pkg/logger"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Reservation errors
+// Rezervasyon hataları
+var (
+	ErrReservationNotFound = errors.New("reservation not found")
+	ErrReservationConflict = errors.New("table is already reserved for this time")
+	ErrReservationClosed   = errors.New("only booked reservations can be changed")
+)
+
+// ReservationSettings holds the reservation timing read at startup
+// Açılışta okunan rezervasyon zamanlamalarını tutar
+type ReservationSettings struct {
+	Duration time.Duration // Slot length when no end time is given
+	Hold     time.Duration // How long before the slot the tables are marked reserved
+	NoShow   time.Duration // How long after the slot start an unseated party is released
+}
+
+// ReservationInput describes a booking request
+// Rezervasyon isteğini tanımlar
+type ReservationInput struct {
+	GuestName string
+	Phone     string
+	PartySize int
+	StartTime time.Time
+	EndTime   *time.Time // Optional, defaults to StartTime + Duration
+	TableIDs  []uint
+	Note      string
+}
+
+type ReservationService struct {
+	repo      repositories.ReservationRepository
+	tableRepo repositories.TableRepository
+	orders    *OrderService
+	bus       *events.Bus
+	settings  ReservationSettings
+
+	// syncMu keeps the periodic table sync and request triggered syncs from interleaving
+	// Periyodik masa senkronu ile istek kaynaklı senkronların iç içe geçmesini önler
+	syncMu sync.Mutex
+}
+
+func NewReservationService(repo repositories.ReservationRepository, tableRepo repositories.TableRepository, orders *OrderService, bus *events.Bus, settings ReservationSettings) *ReservationService {
+	return &ReservationService{
+		repo:      repo,
+		tableRepo: tableRepo,
+		orders:    orders,
+		bus:       bus,
+		settings:  settings,
+	}
+}
+
+// ListReservations returns reservations matching the filter
+// Filtreye uyan rezervasyonları döndürür
+func (s *ReservationService) ListReservations(filter models.ReservationFilter) ([]models.Reservation, error) {
+	if !filter.Start.IsZero() {
+		filter.Start = filter.Start.UTC()
+	}
+	if !filter.End.IsZero() {
+		filter.End = filter.End.UTC()
+	}
+	return s.repo.Find(filter)
+}
+
+// GetReservation returns a reservation with its tables
+// Rezervasyonu masalarıyla birlikte döndürür
+func (s *ReservationService) GetReservation(id uint) (*models.Reservation, error) {
+	reservation, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, ErrReservationNotFound
+	}
+	return reservation, nil
+}
+
+// CreateReservation books tables for a time slot after checking for overlapping bookings
+// Çakışan rezervasyonları kontrol ettikten sonra masaları bir zaman aralığı için ayırır
+func (s *ReservationService) CreateReservation(input ReservationInput, userID uint) (*models.Reservation, error) {
+	reservation := &models.Reservation{
+		Status:    models.ReservationStatusBooked,
+		CreatedBy: userID,
+	}
+	if err := s.apply(reservation, input); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Save(reservation); err != nil {
+		return nil, err
+	}
+
+	s.changed(reservation)
+	return reservation, nil
+}
+
+// UpdateReservation changes the guest, slot or tables of a booked reservation
+// Aktif bir rezervasyonun misafirini, saatini veya masalarını değiştirir
+func (s *ReservationService) UpdateReservation(id uint, input ReservationInput) (*models.Reservation, error) {
+	reservation, err := s.booked(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.apply(reservation, input); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Save(reservation); err != nil {
+		return nil, err
+	}
+
+	s.changed(reservation)
+	return reservation, nil
+}
+
+// CancelReservation cancels a booked reservation and releases its tables
+// Aktif bir rezervasyonu iptal eder ve masalarını serbest bırakır
+func (s *ReservationService) CancelReservation(id uint) (*models.Reservation, error) {
+	return s.release(id, models.ReservationStatusCancelled)
+}
+
+// MarkNoShow releases the tables of a party that did not arrive
+// Gelmeyen misafirin masalarını serbest bırakır
+func (s *ReservationService) MarkNoShow(id uint) (*models.Reservation, error) {
+	return s.release(id, models.ReservationStatusNoShow)
+}
+
+// SeatReservation opens an order for the arrived party on one of the reserved tables (the first by default).
+// The other tables of the reservation are released.
+// Gelen misafir için ayrılan masalardan birinde (varsayılan ilki) sipariş açar.
+// Rezervasyonun diğer masaları serbest bırakılır.
+func (s *ReservationService) SeatReservation(id uint, tableID *uint, waiterID uint, orderNumber string) (*models.Reservation, *models.Order, error) {
+	reservation, err := s.booked(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(reservation.Tables) == 0 {
+		return nil, nil, errors.New("reservation has no tables")
+	}
+
+	seatAt := reservation.Tables[0].ID
+	if tableID != nil {
+		if !containsID(reservation.TableIDs(), *tableID) {
+			return nil, nil, errors.New("table is not part of the reservation")
+		}
+		seatAt = *tableID
+	}
+
+	note := "Reservation: " + reservation.GuestName
+	if reservation.Note != "" {
+		note += " - " + reservation.Note
+	}
+	if len(note) > 255 {
+		note = note[:255]
+	}
+	order, err := s.orders.CreateOrder(&seatAt, waiterID, orderNumber, note)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now().UTC()
+	reservation.Status = models.ReservationStatusSeated
+	reservation.SeatedAt = &now
+	reservation.OrderID = &order.ID
+	if err := s.repo.Save(reservation); err != nil {
+		logger.Error("Failed to mark reservation seated", logger.Int("reservation_id", int(reservation.ID)), logger.Err(err))
+		return nil, order, err
+	}
+
+	s.changed(reservation)
+	return reservation, order, nil
+}
+
+// SyncTables flips free tables to reserved within the hold window before a booking,
+// releases no-shows and frees reserved tables no longer held by a booking
+// Rezervasyondan önceki bekleme süresinde boş masaları rezerve yapar,
+// gelmeyenleri serbest bırakır ve artık tutulmayan rezerve masaları boşaltır
+func (s *ReservationService) SyncTables(now time.Time) error {
+	s.syncMu.Lock()
+	defer s.syncMu.Unlock()
+
+	now = now.UTC()
+	due, err := s.repo.FindBookedStartingBefore(now.Add(s.settings.Hold))
+	if err != nil {
+		return err
+	}
+
+	held := make(map[uint]bool)
+	for i := range due {
+		reservation := &due[i]
+		if now.After(reservation.StartTime.Add(s.settings.NoShow)) {
+			reservation.Status = models.ReservationStatusNoShow
+			reservation.CancelledAt = &now
+			if err := s.repo.Save(reservation); err != nil {
+				return err
+			}
+			logger.Info("Reservation released as no-show", logger.Int("reservation_id", int(reservation.ID)))
+			s.bus.Publish(events.ReservationUpdated, reservation)
+			continue
+		}
+		for _, id := range reservation.TableIDs() {
+			held[id] = true
+		}
+	}
+
+	tables, err := s.tableRepo.FindAll()
+	if err != nil {
+		return err
+	}
+	for i := range tables {
+		table := &tables[i]
+		switch {
+		case table.Status == models.TableStatusAvailable && held[table.ID]:
+			table.Status = models.TableStatusReserved
+		case table.Status == models.TableStatusReserved && !held[table.ID]:
+			table.Status = models.TableStatusAvailable
+		default:
+			continue
+		}
+		if err := s.tableRepo.Update(table); err != nil {
+			return err
+		}
+		s.bus.Publish(events.TableStatusChanged, table)
+	}
+	return nil
+}
+
+// StartSync runs SyncTables in the background every interval for the lifetime of the process
+// SyncTables'ı süreç boyunca arka planda her aralıkta çalıştırır
+func (s *ReservationService) StartSync(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			if err := s.SyncTables(time.Now()); err != nil {
+				logger.Error("Failed to sync reserved tables", logger.Err(err))
+			}
+			<-ticker.C
+		}
+	}()
+}
+
+// apply validates the input and copies it onto the reservation
+// Girdiyi doğrular ve rezervasyona aktarır
+func (s *ReservationService) apply(reservation *models.Reservation, input ReservationInput) error {
+	name := strings.TrimSpace(input.GuestName)
+	if name == "" {
+		return errors.New("guest name is required")
+	}
+	if input.PartySize <= 0 {
+		return errors.New("party size must be at least 1")
+	}
+	if input.StartTime.IsZero() {
+		return errors.New("start time is required")
+	}
+	start := input.StartTime.UTC()
+	end := start.Add(s.settings.Duration)
+	if input.EndTime != nil {
+		end = input.EndTime.UTC()
+	}
+	if !end.After(start) {
+		return errors.New("end time must be after start time")
+	}
+	if !start.Add(s.settings.NoShow).After(time.Now()) {
+		return errors.New("start time is too far in the past")
+	}
+
+	tableIDs := uniqueIDs(input.TableIDs)
+	if len(tableIDs) == 0 {
+		return errors.New("at least one table is required")
+	}
+	tables := make([]models.Table, 0, len(tableIDs))
+	for _, id := range tableIDs {
+		table, err := s.tableRepo.FindByID(id)
+		if err != nil {
+			return fmt.Errorf("table %d not found", id)
+		}
+		tables = append(tables, *table)
+	}
+
+	conflicts, err := s.repo.FindConflicts(tableIDs, start, end, reservation.ID)
+	if err != nil {
+		return err
+	}
+	if len(conflicts) > 0 {
+		other := conflicts[0]
+		return fmt.Errorf("%w: %s at %s", ErrReservationConflict, other.GuestName, other.StartTime.Format(time.RFC3339))
+	}
+
+	reservation.GuestName = name
+	reservation.Phone = strings.TrimSpace(input.Phone)
+	reservation.PartySize = input.PartySize
+	reservation.StartTime = start
+	reservation.EndTime = end
+	reservation.Note = strings.TrimSpace(input.Note)
+	reservation.Tables = tables
+	return nil
+}
+
+// booked loads a reservation that can still be changed
+// Hâlâ değiştirilebilen bir rezervasyonu yükler
+func (s *ReservationService) booked(id uint) (*models.Reservation, error) {
+	reservation, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, ErrReservationNotFound
+	}
+	if reservation.Status != models.ReservationStatusBooked {
+		return nil, ErrReservationClosed
+	}
+	return reservation, nil
+}
+
+// release ends a booked reservation with the given status
+// Aktif bir rezervasyonu verilen durumla sonlandırır
+func (s *ReservationService) release(id uint, status string) (*models.Reservation, error) {
+	reservation, err := s.booked(id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	reservation.Status = status
+	reservation.CancelledAt = &now
+	if err := s.repo.Save(reservation); err != nil {
+		return nil, err
+	}
+
+	s.changed(reservation)
+	return reservation, nil
+}
+
+// changed publishes the reservation and brings table statuses up to date
+// Rezervasyonu yayınlar ve masa durumlarını günceller
+func (s *ReservationService) changed(reservation *models.Reservation) {
+	s.bus.Publish(events.ReservationUpdated, reservation)
+	if err := s.SyncTables(time.Now()); err != nil {
+		logger.Error("Failed to sync reserved tables", logger.Err(err))
+	}
+}
+
+// uniqueIDs drops zero and duplicate ids, keeping the first occurrence order
+// Sıfır ve tekrar eden id'leri atar, ilk görülme sırasını korur
+func uniqueIDs(ids []uint) []uint {
+	seen := make(map[uint]bool, len(ids))
+	unique := make([]uint, 0, len(ids))
+	for _, id := range ids {
+		if id == 0 || seen[id] {
+			continue
+		}
+		seen[id] = true
+		unique = append(unique, id)
+	}
+	return unique
+}
+
+// containsID reports whether id is in ids
+// id'nin listede olup olmadığını belirtir
+func containsID(ids []uint, id uint) bool {
+	for _, candidate := range ids {
+		if candidate == id {
+			return true
+		}
+	}
+	return false
+}
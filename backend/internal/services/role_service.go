@@ -0,0 +1,172 @@
+package services
+
+import (
+	"errors"
+	"regexp"
+	"simple-pos/internal/models"
+	"simple-pos/internal/repositories"
+	"strings"
+	"sync"
+)
+
+// Role errors
+// Rol hataları
+var (
+	ErrRoleNotFound      = errors.New("role not found")
+	ErrRoleExists        = errors.New("role already exists")
+	ErrRoleIsSystem      = errors.New("system roles cannot be changed")
+	ErrRoleInUse         = errors.New("role is assigned to users")
+	ErrInvalidRoleName   = errors.New("role name must be 2-20 lowercase letters, digits or underscores")
+	ErrUnknownPermission = errors.New("unknown permission")
+)
+
+var roleNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{1,19}$`)
+
+type RoleService struct {
+	repo repositories.RoleRepository
+
+	mu    sync.RWMutex
+	cache map[string][]string // Effective permissions by role name, cleared on every role change
+}
+
+func NewRoleService(repo repositories.RoleRepository) *RoleService {
+	return &RoleService{repo: repo, cache: make(map[string][]string)}
+}
+
+// ListRoles returns all roles with their effective permissions
+// Tüm rolleri geçerli yetkileriyle döndürür
+func (s *RoleService) ListRoles() ([]models.Role, error) {
+	roles, err := s.repo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	for i := range roles {
+		roles[i].Permissions = roles[i].EffectivePermissions()
+	}
+	return roles, nil
+}
+
+// CreateRole adds a role with the given permissions
+// Verilen yetkilerle yeni bir rol ekler
+func (s *RoleService) CreateRole(name, description string, permissions []string) (*models.Role, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if !roleNamePattern.MatchString(name) {
+		return nil, ErrInvalidRoleName
+	}
+	if _, err := s.repo.FindByName(name); err == nil {
+		return nil, ErrRoleExists
+	}
+	permissions, err := normalizePermissions(permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	role := &models.Role{
+		Name:        name,
+		Description: strings.TrimSpace(description),
+		Permissions: permissions,
+	}
+	if err := s.repo.Create(role); err != nil {
+		return nil, err
+	}
+	s.invalidate()
+	return role, nil
+}
+
+// UpdateRole replaces the description and permissions of a role; the name is kept since users refer to it
+// Rolün açıklamasını ve yetkilerini değiştirir; kullanıcılar ada bağlı olduğu için ad korunur
+func (s *RoleService) UpdateRole(id uint, description string, permissions []string) (*models.Role, error) {
+	role, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, ErrRoleNotFound
+	}
+	if role.IsSystem {
+		return nil, ErrRoleIsSystem
+	}
+	permissions, err = normalizePermissions(permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	role.Description = strings.TrimSpace(description)
+	role.Permissions = permissions
+	if err := s.repo.Update(role); err != nil {
+		return nil, err
+	}
+	s.invalidate()
+	return role, nil
+}
+
+// DeleteRole removes a role that no user is assigned to
+// Hiçbir kullanıcıya atanmamış rolü siler
+func (s *RoleService) DeleteRole(id uint) error {
+	role, err := s.repo.FindByID(id)
+	if err != nil {
+		return ErrRoleNotFound
+	}
+	if role.IsSystem {
+		return ErrRoleIsSystem
+	}
+	count, err := s.repo.CountUsers(role.Name)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrRoleInUse
+	}
+	if err := s.repo.Delete(id); err != nil {
+		return err
+	}
+	s.invalidate()
+	return nil
+}
+
+// Permissions returns the effective permissions of the named role, every request asks so they are cached
+// Adı verilen rolün geçerli yetkilerini döndürür, her istek sorduğu için önbelleğe alınır
+func (s *RoleService) Permissions(name string) ([]string, error) {
+	s.mu.RLock()
+	permissions, ok := s.cache[name]
+	s.mu.RUnlock()
+	if ok {
+		return permissions, nil
+	}
+
+	role, err := s.repo.FindByName(name)
+	if err != nil {
+		return nil, ErrRoleNotFound
+	}
+	permissions = role.EffectivePermissions()
+
+	s.mu.Lock()
+	s.cache[name] = permissions
+	s.mu.Unlock()
+	return permissions, nil
+}
+
+// invalidate drops the cached permissions so role changes apply to the next request
+// Önbellekteki yetkileri atar, böylece rol değişiklikleri bir sonraki istekte geçerli olur
+func (s *RoleService) invalidate() {
+	s.mu.Lock()
+	s.cache = make(map[string][]string)
+	s.mu.Unlock()
+}
+
+// normalizePermissions rejects unknown names and drops duplicates, keeping catalogue order
+// Bilinmeyen adları reddeder ve tekrarları atar, katalog sırasını korur
+func normalizePermissions(permissions []string) ([]string, error) {
+	selected := make(map[string]bool, len(permissions))
+	for _, p := range permissions {
+		if !models.IsPermission(p) {
+			return nil, ErrUnknownPermission
+		}
+		selected[p] = true
+	}
+
+	normalized := []string{}
+	for _, p := range models.AllPermissions {
+		if selected[p] {
+			normalized = append(normalized, p)
+		}
+	}
+	return normalized, nil
+}
@@ -2,31 +2,81 @@ package services
 
 import (
 	"errors"
+	"fmt"
 	"simple-pos/internal/models"
+	"simple-pos/internal/platform/events"
 	"simple-pos/internal/repositories"
+	"strings"
 )
 
+// defaultSection is where tables without a section are placed
+const defaultSection = "salon"
+
 type TableService struct {
-	repo repositories.TableRepository
+	repo        repositories.TableRepository
+	sectionRepo repositories.SectionRepository
+	bus         *events.Bus
+}
+
+func NewTableService(repo repositories.TableRepository, sectionRepo repositories.SectionRepository, bus *events.Bus) *TableService {
+	return &TableService{repo: repo, sectionRepo: sectionRepo, bus: bus}
 }
 
-func NewTableService(repo repositories.TableRepository) *TableService {
-	return &TableService{repo: repo}
+// TableLayout is the floor plan placement of a table
+type TableLayout struct {
+	PosX     int
+	PosY     int
+	Shape    string // square, round, rectangle (empty = square)
+	Seats    int    // 0 = default of 4
+	Rotation int    // Degrees clockwise
+}
+
+// TableInput describes a table to create or update.
+// SectionID wins over Section; a Section name that does not exist yet is created.
+type TableInput struct {
+	Name      string
+	Section   string
+	SectionID *uint
+	Layout    *TableLayout // nil keeps the current layout
+}
+
+// TableLayoutInput places one table on the floor plan
+type TableLayoutInput struct {
+	TableID   uint
+	SectionID *uint // nil keeps the current section
+	TableLayout
+}
+
+// SectionTables groups the tables of a section, Section is nil for tables without one
+type SectionTables struct {
+	Section *models.Section `json:"section"`
+	Tables  []models.Table  `json:"tables"`
 }
 
 // CreateTable creates a new table unique by name
-func (s *TableService) CreateTable(name, section string) (*models.Table, error) {
-	if section == "" {
-		section = "salon"
+func (s *TableService) CreateTable(input TableInput) (*models.Table, error) {
+	section, err := s.resolveSection(input.SectionID, input.Section)
+	if err != nil {
+		return nil, err
 	}
 	table := &models.Table{
-		Name:    name,
-		Section: section,
-		Status:  models.TableStatusAvailable,
+		Name:      input.Name,
+		SectionID: &section.ID,
+		Section:   section.Name,
+		Status:    models.TableStatusAvailable,
 	}
+	layout := TableLayout{}
+	if input.Layout != nil {
+		layout = *input.Layout
+	}
+	if err := applyLayout(table, layout); err != nil {
+		return nil, err
+	}
+
 	if err := s.repo.Create(table); err != nil {
 		return nil, err
 	}
+	s.bus.Publish(events.TableUpdated, table)
 	return table, nil
 }
 
@@ -35,24 +85,103 @@ func (s *TableService) ListTables() ([]models.Table, error) {
 	return s.repo.FindAll()
 }
 
-// UpdateTable updates a table name and section
-func (s *TableService) UpdateTable(id uint, name, section string) (*models.Table, error) {
+// ListTablesBySection returns the tables grouped by section in floor plan order.
+// Empty sections are included so they can be shown on the floor plan.
+func (s *TableService) ListTablesBySection() ([]SectionTables, error) {
+	sections, err := s.sectionRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	tables, err := s.repo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make([]SectionTables, 0, len(sections)+1)
+	index := make(map[uint]int, len(sections))
+	for i := range sections {
+		index[sections[i].ID] = len(groups)
+		groups = append(groups, SectionTables{Section: &sections[i], Tables: []models.Table{}})
+	}
+
+	var unassigned []models.Table
+	for _, table := range tables {
+		if table.SectionID != nil {
+			if i, ok := index[*table.SectionID]; ok {
+				groups[i].Tables = append(groups[i].Tables, table)
+				continue
+			}
+		}
+		unassigned = append(unassigned, table)
+	}
+	if len(unassigned) > 0 {
+		groups = append(groups, SectionTables{Tables: unassigned})
+	}
+	return groups, nil
+}
+
+// UpdateTable updates a table name, section and optionally its layout
+func (s *TableService) UpdateTable(id uint, input TableInput) (*models.Table, error) {
 	table, err := s.repo.FindByID(id)
 	if err != nil {
 		return nil, err
 	}
 
-	table.Name = name
-	if section != "" {
-		table.Section = section
+	table.Name = input.Name
+	if input.SectionID != nil || input.Section != "" {
+		section, err := s.resolveSection(input.SectionID, input.Section)
+		if err != nil {
+			return nil, err
+		}
+		table.SectionID = &section.ID
+		table.Section = section.Name
+	}
+	if input.Layout != nil {
+		if err := applyLayout(table, *input.Layout); err != nil {
+			return nil, err
+		}
 	}
 
 	if err := s.repo.Update(table); err != nil {
 		return nil, err
 	}
+	s.bus.Publish(events.TableUpdated, table)
 	return table, nil
 }
 
+// SaveLayout places several tables on the floor plan at once, all or nothing
+func (s *TableService) SaveLayout(inputs []TableLayoutInput) ([]models.Table, error) {
+	sections := make(map[uint]*models.Section)
+	tables := make([]models.Table, 0, len(inputs))
+	for _, input := range inputs {
+		table, err := s.repo.FindByID(input.TableID)
+		if err != nil {
+			return nil, fmt.Errorf("table %d not found", input.TableID)
+		}
+		if input.SectionID != nil {
+			section, ok := sections[*input.SectionID]
+			if !ok {
+				if section, err = s.sectionRepo.FindByID(*input.SectionID); err != nil {
+					return nil, fmt.Errorf("section %d not found", *input.SectionID)
+				}
+				sections[section.ID] = section
+			}
+			table.SectionID = &section.ID
+			table.Section = section.Name
+		}
+		if err := applyLayout(table, input.TableLayout); err != nil {
+			return nil, fmt.Errorf("table %s: %w", table.Name, err)
+		}
+		tables = append(tables, *table)
+	}
+
+	if err := s.repo.SaveLayout(tables); err != nil {
+		return nil, err
+	}
+	s.bus.Publish(events.LayoutUpdated, tables)
+	return tables, nil
+}
+
 // DeleteTable deletes a table if it is not occupied
 func (s *TableService) DeleteTable(id uint) error {
 	table, err := s.repo.FindByID(id)
@@ -65,5 +194,122 @@ func (s *TableService) DeleteTable(id uint) error {
 		return errors.New("cannot delete table: open order or occupied")
 	}
 
-	return s.repo.Delete(id)
+	if err := s.repo.Delete(id); err != nil {
+		return err
+	}
+	s.bus.Publish(events.TableUpdated, map[string]interface{}{"id": id, "deleted": true})
+	return nil
+}
+
+// ListSections returns the floor plan sections in order
+func (s *TableService) ListSections() ([]models.Section, error) {
+	return s.sectionRepo.FindAll()
+}
+
+// CreateSection creates a floor plan section unique by name
+func (s *TableService) CreateSection(name string, sortOrder int) (*models.Section, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, errors.New("section name is required")
+	}
+	if _, err := s.sectionRepo.FindByName(name); err == nil {
+		return nil, errors.New("section with this name already exists")
+	}
+
+	section := &models.Section{Name: name, SortOrder: sortOrder}
+	if err := s.sectionRepo.Create(section); err != nil {
+		return nil, err
+	}
+	s.bus.Publish(events.LayoutUpdated, map[string]interface{}{"section": section})
+	return section, nil
+}
+
+// UpdateSection renames or reorders a section, its tables follow the new name
+func (s *TableService) UpdateSection(id uint, name string, sortOrder int) (*models.Section, error) {
+	section, err := s.sectionRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, errors.New("section name is required")
+	}
+	if other, err := s.sectionRepo.FindByName(name); err == nil && other.ID != id {
+		return nil, errors.New("section with this name already exists")
+	}
+
+	section.Name = name
+	section.SortOrder = sortOrder
+	if err := s.sectionRepo.Update(section); err != nil {
+		return nil, err
+	}
+	s.bus.Publish(events.LayoutUpdated, map[string]interface{}{"section": section})
+	return section, nil
+}
+
+// DeleteSection deletes a section that has no tables left
+func (s *TableService) DeleteSection(id uint) error {
+	if _, err := s.sectionRepo.FindByID(id); err != nil {
+		return err
+	}
+	count, err := s.sectionRepo.CountTables(id)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return errors.New("cannot delete section: move its tables first")
+	}
+
+	if err := s.sectionRepo.Delete(id); err != nil {
+		return err
+	}
+	s.bus.Publish(events.LayoutUpdated, map[string]interface{}{"section_id": id, "deleted": true})
+	return nil
+}
+
+// resolveSection finds the section by id, or by name creating it when missing
+func (s *TableService) resolveSection(id *uint, name string) (*models.Section, error) {
+	if id != nil {
+		section, err := s.sectionRepo.FindByID(*id)
+		if err != nil {
+			return nil, errors.New("section not found")
+		}
+		return section, nil
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = defaultSection
+	}
+	if section, err := s.sectionRepo.FindByName(name); err == nil {
+		return section, nil
+	}
+	section := &models.Section{Name: name}
+	if err := s.sectionRepo.Create(section); err != nil {
+		return nil, err
+	}
+	return section, nil
+}
+
+// applyLayout validates the layout and copies it onto the table
+func applyLayout(table *models.Table, layout TableLayout) error {
+	if layout.Shape == "" {
+		layout.Shape = models.TableShapeSquare
+	}
+	if layout.Shape != models.TableShapeSquare && layout.Shape != models.TableShapeRound && layout.Shape != models.TableShapeRectangle {
+		return errors.New("invalid table shape")
+	}
+	if layout.Seats == 0 {
+		layout.Seats = 4
+	}
+	if layout.Seats < 0 || layout.PosX < 0 || layout.PosY < 0 {
+		return errors.New("seats and position cannot be negative")
+	}
+
+	table.PosX = layout.PosX
+	table.PosY = layout.PosY
+	table.Shape = layout.Shape
+	table.Seats = layout.Seats
+	table.Rotation = ((layout.Rotation % 360) + 360) % 360
+	return nil
 }
@@ -7,17 +7,21 @@ import (
 	"strconv"
 	"strings"
 	"time"
+
+	"gorm.io/gorm"
 )
 
 type TransactionService struct {
 	repo           repositories.TransactionRepository
 	workPeriodRepo repositories.WorkPeriodRepository
+	audit          *AuditService
 }
 
-func NewTransactionService(repo repositories.TransactionRepository, wpRepo repositories.WorkPeriodRepository) *TransactionService {
+func NewTransactionService(repo repositories.TransactionRepository, wpRepo repositories.WorkPeriodRepository, audit *AuditService) *TransactionService {
 	return &TransactionService{
 		repo:           repo,
 		workPeriodRepo: wpRepo,
+		audit:          audit,
 	}
 }
 
@@ -82,7 +86,7 @@ func (s *TransactionService) ListExpenses(startDate, endDate time.Time, scope st
 }
 
 // UpdateExpense updates an existing expense
-func (s *TransactionService) UpdateExpense(id uint, amount int64, description string) (*models.Transaction, error) {
+func (s *TransactionService) UpdateExpense(id uint, amount int64, description string, userID uint) (*models.Transaction, error) {
 	if amount <= 0 {
 		return nil, errors.New("amount must be positive")
 	}
@@ -106,10 +110,17 @@ func (s *TransactionService) UpdateExpense(id uint, amount int64, description st
 		return nil, errors.New("can only modify expenses in active work period")
 	}
 
+	before := *tx
 	tx.Amount = amount
 	tx.Description = description
 
-	if err := s.repo.Update(tx); err != nil {
+	err = s.repo.WithTransaction(func(db *gorm.DB) error {
+		if err := s.repo.UpdateWithTx(db, tx); err != nil {
+			return err
+		}
+		return s.audit.Record(db, userID, models.AuditActionExpenseUpdate, models.AuditEntityTransaction, tx.ID, before, tx, "")
+	})
+	if err != nil {
 		return nil, err
 	}
 
@@ -117,7 +128,7 @@ func (s *TransactionService) UpdateExpense(id uint, amount int64, description st
 }
 
 // DeleteExpense deletes a transaction if it is an expense
-func (s *TransactionService) DeleteExpense(id uint) error {
+func (s *TransactionService) DeleteExpense(id, userID uint) error {
 	tx, err := s.repo.FindByID(id)
 	if err != nil {
 		return err
@@ -137,5 +148,10 @@ func (s *TransactionService) DeleteExpense(id uint) error {
 		return errors.New("can only modify expenses in active work period")
 	}
 
-	return s.repo.Delete(id)
+	return s.repo.WithTransaction(func(db *gorm.DB) error {
+		if err := s.repo.DeleteWithTx(db, id); err != nil {
+			return err
+		}
+		return s.audit.Record(db, userID, models.AuditActionExpenseDelete, models.AuditEntityTransaction, id, tx, nil, "")
+	})
 }
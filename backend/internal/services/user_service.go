@@ -6,14 +6,17 @@ import (
 	"simple-pos/internal/repositories"
 
 	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
 )
 
 type UserService struct {
-	repo repositories.UserRepository
+	repo     repositories.UserRepository
+	roleRepo repositories.RoleRepository
+	audit    *AuditService
 }
 
-func NewUserService(repo repositories.UserRepository) *UserService {
-	return &UserService{repo: repo}
+func NewUserService(repo repositories.UserRepository, roleRepo repositories.RoleRepository, audit *AuditService) *UserService {
+	return &UserService{repo: repo, roleRepo: roleRepo, audit: audit}
 }
 
 // CreateUser handles the creation of a new user with hashed PIN
@@ -25,13 +28,19 @@ func (s *UserService) CreateUser(name, pin, role string) (*models.User, error) {
 		return nil, errors.New("user already exists")
 	}
 
-	// 2. Hash PIN
+	// 2. The role must exist
+	// Rol mevcut olmalı
+	if _, err := s.roleRepo.FindByName(role); err != nil {
+		return nil, ErrRoleNotFound
+	}
+
+	// 3. Hash PIN
 	hashedPin, err := bcrypt.GenerateFromPassword([]byte(pin), bcrypt.DefaultCost)
 	if err != nil {
 		return nil, errors.New("failed to hash pin")
 	}
 
-	// 3. Create User Model
+	// 4. Create User Model
 	user := &models.User{
 		Name:     name,
 		PinCode:  string(hashedPin),
@@ -39,7 +48,7 @@ func (s *UserService) CreateUser(name, pin, role string) (*models.User, error) {
 		IsActive: true,
 	}
 
-	// 4. Save to DB
+	// 5. Save to DB
 	if err := s.repo.Create(user); err != nil {
 		return nil, err
 	}
@@ -66,6 +75,9 @@ func (s *UserService) UpdateUser(id uint, name, role string, isActive bool) (*mo
 	if err != nil {
 		return nil, err
 	}
+	if _, err := s.roleRepo.FindByName(role); err != nil {
+		return nil, ErrRoleNotFound
+	}
 
 	user.Name = name
 	user.Role = role
@@ -83,9 +95,9 @@ func (s *UserService) DeleteUser(id uint) error {
 	return s.repo.Delete(id)
 }
 
-// ChangePin updates the user's PIN
-// Kullanıcı PIN'ini günceller
-func (s *UserService) ChangePin(id uint, newPin string) error {
+// ChangePin updates the user's PIN, actorID is the user performing the change
+// Kullanıcı PIN'ini günceller, actorID değişikliği yapan kullanıcıdır
+func (s *UserService) ChangePin(id uint, newPin string, actorID uint) error {
 	// 1. Hash new PIN
 	hashedPin, err := bcrypt.GenerateFromPassword([]byte(newPin), bcrypt.DefaultCost)
 	if err != nil {
@@ -98,7 +110,13 @@ func (s *UserService) ChangePin(id uint, newPin string) error {
 		return err
 	}
 
-	// 3. Update PIN
+	// 3. Update PIN (the hash never goes into the audit log)
+	// PIN'i güncelle (hash denetim kaydına asla yazılmaz)
 	user.PinCode = string(hashedPin)
-	return s.repo.Update(user)
+	return s.repo.WithTransaction(func(tx *gorm.DB) error {
+		if err := s.repo.UpdateWithTx(tx, user); err != nil {
+			return err
+		}
+		return s.audit.Record(tx, actorID, models.AuditActionUserPinChange, models.AuditEntityUser, user.ID, nil, nil, "")
+	})
 }
@@ -2,6 +2,7 @@ package config
 
 import (
 	"os"
+	"strconv"
 
 	"github.com/joho/godotenv"
 )
@@ -14,6 +15,44 @@ type Config struct {
 	JWTSecret     string
 	SeedAdminName string
 	SeedAdminPin  string
+
+	// Tax (KDV)
+	PricesIncludeTax bool // Menu prices contain KDV (INCLUSIVE) or KDV is added on top (EXCLUSIVE)
+	DefaultTaxRate   int  // KDV % for products and categories without an explicit rate
+
+	// Inventory
+	BlockOnInsufficientStock bool // Reject order items whose recipe cannot be covered by stock
+
+	// Printing (ESC/POS)
+	ReceiptPrinter string // Printer address for receipts, e.g. tcp://192.168.1.50:9100 or file://./spool (empty = disabled)
+	KitchenPrinter string // Printer for kitchen tickets of stations without their own printer (empty = disabled)
+	PrinterWidth   int    // Characters per line (42 for 80mm, 32 for 58mm paper)
+	ReceiptHeader  string // Business name printed on top of receipts
+
+	// Reservations
+	ReservationMinutes       int // Slot length when a booking has no end time
+	ReservationHoldMinutes   int // Tables turn reserved this many minutes before the slot
+	ReservationNoShowMinutes int // Unseated bookings are released this many minutes after the slot start
+
+	// Manager Override
+	OverrideDiscountPercent int // Discounts above this % of the order subtotal need a manager PIN
+
+	// Exports
+	ReportFontPath string // TrueType font for PDF reports, Turkish letters lose their marks without it
+
+	// E-Invoice (e-Arşiv)
+	InvoicePrefix     string // 3 character series of invoice numbers, e.g. EAR -> EAR2026000000001
+	InvoiceIntegrator string // Integrator address, file://./invoices stores the XML locally (empty = invoices stay unsent)
+	SellerTaxID       string // VKN (or TCKN for sole proprietors) of the business
+	SellerName        string // Registered title of the business
+	SellerTaxOffice   string
+	SellerAddress     string
+	SellerDistrict    string
+	SellerCity        string
+	SellerEmail       string
+
+	// Loyalty
+	LoyaltyPointValue int // Discount in kuruş one loyalty point is worth when redeemed
 }
 
 // LoadConfig loads configuration from environment variables
@@ -30,6 +69,36 @@ func LoadConfig() *Config {
 		JWTSecret:     getEnv("JWT_SECRET", "default-secret-do-not-use-in-prod"),
 		SeedAdminName: getEnv("SEED_ADMIN_NAME", ""),
 		SeedAdminPin:  getEnv("SEED_ADMIN_PIN", ""),
+
+		PricesIncludeTax: getEnv("PRICES_INCLUDE_TAX", "true") == "true",
+		DefaultTaxRate:   getEnvInt("DEFAULT_TAX_RATE", 20),
+
+		BlockOnInsufficientStock: getEnv("BLOCK_ON_INSUFFICIENT_STOCK", "false") == "true",
+
+		ReceiptPrinter: getEnv("RECEIPT_PRINTER", ""),
+		KitchenPrinter: getEnv("KITCHEN_PRINTER", ""),
+		PrinterWidth:   getEnvInt("PRINTER_WIDTH", 42),
+		ReceiptHeader:  getEnv("RECEIPT_HEADER", "Simple POS"),
+
+		ReservationMinutes:       getEnvInt("RESERVATION_MINUTES", 90),
+		ReservationHoldMinutes:   getEnvInt("RESERVATION_HOLD_MINUTES", 30),
+		ReservationNoShowMinutes: getEnvInt("RESERVATION_NO_SHOW_MINUTES", 20),
+
+		OverrideDiscountPercent: getEnvInt("OVERRIDE_DISCOUNT_PERCENT", 10),
+
+		ReportFontPath: getEnv("REPORT_FONT_PATH", "/usr/share/fonts/truetype/dejavu/DejaVuSans.ttf"),
+
+		InvoicePrefix:     getEnv("INVOICE_PREFIX", "EAR"),
+		InvoiceIntegrator: getEnv("INVOICE_INTEGRATOR", ""),
+		SellerTaxID:       getEnv("SELLER_TAX_ID", ""),
+		SellerName:        getEnv("SELLER_NAME", ""),
+		SellerTaxOffice:   getEnv("SELLER_TAX_OFFICE", ""),
+		SellerAddress:     getEnv("SELLER_ADDRESS", ""),
+		SellerDistrict:    getEnv("SELLER_DISTRICT", ""),
+		SellerCity:        getEnv("SELLER_CITY", ""),
+		SellerEmail:       getEnv("SELLER_EMAIL", ""),
+
+		LoyaltyPointValue: getEnvInt("LOYALTY_POINT_VALUE", 1),
 	}
 }
 
@@ -39,3 +108,12 @@ func getEnv(key, fallback string) string {
 	}
 	return fallback
 }
+
+func getEnvInt(key string, fallback int) int {
+	if value, exists := os.LookupEnv(key); exists {
+		if parsed, err := strconv.Atoi(value); err == nil {
+			return parsed
+		}
+	}
+	return fallback
+}
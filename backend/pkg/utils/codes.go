@@ -10,4 +10,7 @@ const (
 	CodeUnauthorized      = "UNAUTHORIZED"
 	CodeRateLimitExceeded = "RATE_LIMIT_EXCEEDED"
 	CodeNotFound          = "NOT_FOUND"
+	CodeOverrideRequired  = "OVERRIDE_REQUIRED"
+	CodeOverrideInvalid   = "OVERRIDE_INVALID"
+	CodeOverrideLocked    = "OVERRIDE_LOCKED"
 )
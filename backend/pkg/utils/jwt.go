@@ -18,16 +18,17 @@ func InitJWT(secret string) {
 	JwtSecret = []byte(secret)
 }
 
-// GenerateToken creates a signed JWT token containing the user ID and Role
-// Kullanıcı ID'si ve Rolü içeren imzalı bir JWT token üretir
-func GenerateToken(userID uint, role string) (string, error) {
+// GenerateToken creates a signed JWT token containing the user ID, Role and its permissions
+// Kullanıcı ID'si, Rolü ve yetkilerini içeren imzalı bir JWT token üretir
+func GenerateToken(userID uint, role string, permissions []string) (string, error) {
 	// Claims represent the data stored inside the token (payload)
 	// Claims, token içinde saklanan verilerdir (payload)
 	expirationTime := time.Now().Add(24 * time.Hour) // 1 Day expiration
 
 	claims := &models.JWTClaims{
-		UserID: userID,
-		Role:   role,
+		UserID:      userID,
+		Role:        role,
+		Permissions: permissions,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(expirationTime),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -31,6 +31,7 @@ var (
 	adminPin   = "1234"
 	adminUser  = "admin"
 	logFile    *os.File
+	invoiceDir string // Where the file integrator stores the issued invoices
 )
 
 // TestMain controls the main entry point for E2E tests
@@ -42,6 +43,16 @@ func TestMain(m *testing.M) {
 	os.Setenv("APP_ENV", "test")
 	os.Setenv("JWT_SECRET", "test-secret-key")
 
+	// Seller of the e-Arşiv invoices, delivered into a temporary directory
+	invoiceDir, _ = os.MkdirTemp("", "simple-pos-invoices")
+	os.Setenv("INVOICE_PREFIX", "EAR")
+	os.Setenv("INVOICE_INTEGRATOR", "file://"+invoiceDir)
+	os.Setenv("SELLER_TAX_ID", "1234567890")
+	os.Setenv("SELLER_NAME", "Tostçu Gıda Ltd. Şti.")
+	os.Setenv("SELLER_TAX_OFFICE", "Kadıköy")
+	os.Setenv("SELLER_DISTRICT", "Kadıköy")
+	os.Setenv("SELLER_CITY", "İstanbul")
+
 	cfg = config.LoadConfig()
 
 	// Initialize JWT for tests
@@ -84,6 +95,7 @@ func TestMain(m *testing.M) {
 
 	// 6. Cleanup
 	cleanupDBFiles(testDBPath)
+	os.RemoveAll(invoiceDir)
 	os.Exit(exitCode)
 }
 
@@ -450,3 +462,119 @@ func logAndRequest(t *testing.T, stepDesc, method, path string, payload interfac
 
 	return respBody, resp.StatusCode
 }
+
+// apiToken returns the admin token, logging in when no earlier test did
+func apiToken(t *testing.T) string {
+	if authToken != "" {
+		return authToken
+	}
+	payload := map[string]interface{}{
+		"username": adminUser,
+		"password": adminPin,
+	}
+	resp, code := logAndRequest(t, "Admin Login", "POST", "/auth/login", payload, "")
+	require.Equal(t, http.StatusOK, code)
+
+	var result map[string]interface{}
+	extractData(t, resp, &result)
+	authToken = result["token"].(string)
+	return authToken
+}
+
+// staffToken creates a user with the role and returns its ID and login token
+func staffToken(t *testing.T, token, name, pin, role string) (uint, string) {
+	userID := createResource(t, token, "/api/v1/users", map[string]interface{}{"name": name, "pin": pin, "role": role})
+	resp, code := logAndRequest(t, "Staff Login", "POST", "/auth/login", map[string]interface{}{"username": name, "password": pin}, "")
+	require.Equal(t, http.StatusOK, code, string(resp))
+
+	var result map[string]interface{}
+	extractData(t, resp, &result)
+	return userID, result["token"].(string)
+}
+
+// ensureWorkDay starts a work period unless one is already active.
+// The management routes are rate limited, so the active period is read from the database.
+func ensureWorkDay(t *testing.T, token string) {
+	var count int64
+	database.DB.Model(&models.WorkPeriod{}).Where("is_active = ?", true).Count(&count)
+	if count > 0 {
+		return
+	}
+	_, code := logAndRequest(t, "Start Work Day", "POST", "/api/v1/management/start-day", map[string]interface{}{"user_id": 1}, token)
+	require.Equal(t, http.StatusOK, code)
+}
+
+// createResource posts the payload and returns the ID of the created record
+func createResource(t *testing.T, token, path string, payload map[string]interface{}) uint {
+	resp, code := logAndRequest(t, "Create "+path, "POST", path, payload, token)
+	require.Equal(t, http.StatusCreated, code, string(resp))
+
+	var created struct {
+		ID uint `json:"id"`
+	}
+	extractData(t, resp, &created)
+	return created.ID
+}
+
+var lastOrderSecond int64
+
+// openOrder opens an order without a table
+func openOrder(t *testing.T, token string) uint {
+	waitOrderSecond()
+	return createResource(t, token, "/api/v1/orders", map[string]interface{}{"waiter_id": 1})
+}
+
+// openTableOrder opens an order on the table
+func openTableOrder(t *testing.T, token string, tableID uint) uint {
+	waitOrderSecond()
+	return createResource(t, token, "/api/v1/orders", map[string]interface{}{"waiter_id": 1, "table_id": tableID})
+}
+
+// closeOrder settles the rest of the order in cash
+func closeOrder(t *testing.T, token string, orderID uint) {
+	resp, code := logAndRequest(t, "Close Order", "POST", fmt.Sprintf("/api/v1/orders/%d/close", orderID), map[string]interface{}{"payment_method": "CASH"}, token)
+	require.Equal(t, http.StatusOK, code, string(resp))
+}
+
+// download performs a GET and returns the headers with the raw body, for responses that are not JSON or carry headers
+func download(t *testing.T, token, path string) (http.Header, []byte, int) {
+	req, err := http.NewRequest("GET", baseURL+path, nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer "+token)
+	res, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+	return res.Header, body, res.StatusCode
+}
+
+// waitOrderSecond waits for the next second when needed, order numbers carry the unix second
+func waitOrderSecond() {
+	for time.Now().Unix() == lastOrderSecond {
+		time.Sleep(50 * time.Millisecond)
+	}
+	lastOrderSecond = time.Now().Unix()
+}
+
+// addItem adds the product to the order and returns the created item
+func addItem(t *testing.T, token string, orderID, productID uint, quantity int) models.OrderItem {
+	payload := map[string]interface{}{
+		"product_id": productID,
+		"quantity":   quantity,
+	}
+	resp, code := logAndRequest(t, "Add Item", "POST", fmt.Sprintf("/api/v1/orders/%d/items", orderID), payload, token)
+	require.Equal(t, http.StatusCreated, code, string(resp))
+
+	var item models.OrderItem
+	extractData(t, resp, &item)
+	return item
+}
+
+// loadOrder reads the order with its items and payments straight from the database
+func loadOrder(t *testing.T, orderID uint) models.Order {
+	var order models.Order
+	require.NoError(t, database.DB.Preload("Items").Preload("Payments").First(&order, orderID).Error)
+	return order
+}
@@ -0,0 +1,105 @@
+package e2e
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"simple-pos/internal/models"
+	"simple-pos/internal/platform/events"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// streamEvent is an event read from the SSE stream
+type streamEvent struct {
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+}
+
+// openStream subscribes to the event stream, closed when the test ends
+func openStream(t *testing.T, token, types string) <-chan streamEvent {
+	req, err := http.NewRequest("GET", baseURL+"/api/v1/events?types="+types, nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	t.Cleanup(func() { resp.Body.Close() })
+
+	stream := make(chan streamEvent, 16)
+	connected := make(chan struct{})
+	go func() {
+		defer close(stream)
+		scanner := bufio.NewScanner(resp.Body)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			line := scanner.Text()
+			if line == "event: connected" {
+				close(connected)
+			}
+			if !strings.HasPrefix(line, "data: ") || line == "data: {}" {
+				continue
+			}
+			var event streamEvent
+			if json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &event) == nil {
+				stream <- event
+			}
+		}
+	}()
+
+	select {
+	case <-connected:
+	case <-time.After(2 * time.Second):
+		t.Fatal("event stream did not connect")
+	}
+	return stream
+}
+
+// nextEvent waits briefly for the next event of the stream, false when none came
+func nextEvent(stream <-chan streamEvent) (streamEvent, bool) {
+	select {
+	case event, ok := <-stream:
+		return event, ok
+	case <-time.After(500 * time.Millisecond):
+		return streamEvent{}, false
+	}
+}
+
+// TestE2E_EventStream pushes order changes to subscribed clients, filtered by the requested types
+func TestE2E_EventStream(t *testing.T) {
+	token := apiToken(t)
+	ensureWorkDay(t, token)
+
+	_, code := logAndRequest(t, "Stream Without Token", "GET", "/api/v1/events", nil, "")
+	assert.Equal(t, http.StatusUnauthorized, code)
+
+	_, waiterToken := staffToken(t, token, "ssewaiter", "3141", models.RoleWaiter)
+	everything := openStream(t, token, "")
+	itemsOnly := openStream(t, waiterToken, events.ItemAdded)
+
+	categoryID := createResource(t, token, "/api/v1/categories", map[string]interface{}{"name": "SSE Test"})
+	productID := createResource(t, token, "/api/v1/products", map[string]interface{}{"category_id": categoryID, "name": "SSE Tost", "price": 2000})
+	orderID := openOrder(t, token)
+	addItem(t, token, orderID, productID, 1)
+
+	event, ok := nextEvent(everything)
+	require.True(t, ok)
+	assert.Equal(t, events.OrderCreated, event.Type)
+
+	// The waiter asked only for added items, the new order is skipped
+	event, ok = nextEvent(itemsOnly)
+	require.True(t, ok)
+	assert.Equal(t, events.ItemAdded, event.Type)
+	var data struct {
+		OrderID uint `json:"order_id"`
+	}
+	require.NoError(t, json.Unmarshal(event.Data, &data))
+	assert.Equal(t, orderID, data.OrderID)
+
+	closeOrder(t, token, orderID)
+}
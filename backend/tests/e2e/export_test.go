@@ -0,0 +1,168 @@
+package e2e
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"simple-pos/internal/models"
+	"simple-pos/internal/platform/database"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+// TestE2E_Exports downloads the orders, expenses and periods of a closed day as CSV and XLSX and its Z report as PDF
+func TestE2E_Exports(t *testing.T) {
+	token := apiToken(t)
+	ensureWorkDay(t, token)
+
+	categoryID := createResource(t, token, "/api/v1/categories", map[string]interface{}{"name": "Export Test"})
+	toastID := createResource(t, token, "/api/v1/products", map[string]interface{}{"category_id": categoryID, "name": "Export Tost", "price": 1250})
+	platterID := createResource(t, token, "/api/v1/products", map[string]interface{}{"category_id": categoryID, "name": "Export Tabak", "price": 123450})
+
+	// A closed day of its own with the totals EndDay would have stored
+	start := time.Date(2023, time.May, 12, 9, 0, 0, 0, time.Local)
+	end := start.Add(12 * time.Hour)
+	period := models.WorkPeriod{StartTime: start, EndTime: &end}
+	require.NoError(t, database.DB.Create(&period).Error)
+	require.NoError(t, database.DB.Model(&period).Updates(map[string]interface{}{
+		"is_active": false, "total_orders": 2, "total_sales": 125950, "total_expenses": 1250, "net_profit": 124700,
+		"opening_float": 10000, "expected_cash": 134700,
+	}).Error)
+
+	small := openOrder(t, token)
+	addItem(t, token, small, toastID, 2)
+	closeOrder(t, token, small)
+	large := openOrder(t, token)
+	addItem(t, token, large, platterID, 1)
+	closeOrder(t, token, large)
+	expense, code := logAndRequest(t, "Add Expense", "POST", "/api/v1/transactions/expense",
+		map[string]interface{}{"amount": 1250, "description": "Ekmek", "category": "Malzeme", "payment_method": "CASH"}, token)
+	require.Equal(t, http.StatusCreated, code, string(expense))
+	var expenseTx models.Transaction
+	extractData(t, expense, &expenseTx)
+
+	orderIDs := []uint{small, large}
+	require.NoError(t, database.DB.Model(&models.Order{}).Where("id IN ?", orderIDs).Update("work_period_id", period.ID).Error)
+	require.NoError(t, database.DB.Model(&models.Payment{}).Where("order_id IN ?", orderIDs).Update("work_period_id", period.ID).Error)
+	require.NoError(t, database.DB.Model(&models.Transaction{}).Where("id = ?", expenseTx.ID).Update("work_period_id", period.ID).Error)
+
+	scope := fmt.Sprintf("scope=period_%d", period.ID)
+	orderHeaders := []string{"Sipariş No", "Tarih", "Masa", "Garson", "Durum", "Ara Toplam", "Promosyon", "Kupon", "İndirim",
+		"KDV", "Toplam", "Ödenen", "İade", "Ödeme Yöntemi"}
+
+	// readCSV checks the download is a semicolon separated CSV with a BOM and returns its records
+	readCSV := func(t *testing.T, path string) [][]string {
+		header, body, code := download(t, token, path)
+		require.Equal(t, http.StatusOK, code, string(body))
+		assert.Equal(t, "text/csv; charset=utf-8", header.Get("Content-Type"))
+		assert.Contains(t, header.Get("Content-Disposition"), ".csv")
+		require.True(t, bytes.HasPrefix(body, []byte("\ufeff")), "Excel needs the BOM to read UTF-8")
+
+		reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(body, []byte("\ufeff"))))
+		reader.Comma = ';'
+		records, err := reader.ReadAll()
+		require.NoError(t, err)
+		return records
+	}
+	column := func(headers []string, name string) int {
+		for i, h := range headers {
+			if h == name {
+				return i
+			}
+		}
+		t.Fatalf("no %q column", name)
+		return -1
+	}
+
+	t.Run("Orders_CSV", func(t *testing.T) {
+		records := readCSV(t, "/api/v1/exports/orders?"+scope)
+		require.Len(t, records, 3)
+		assert.Equal(t, orderHeaders, records[0])
+
+		total, status, method := column(orderHeaders, "Toplam"), column(orderHeaders, "Durum"), column(orderHeaders, "Ödeme Yöntemi")
+		var totals []string
+		for _, row := range records[1:] {
+			totals = append(totals, row[total])
+			assert.Equal(t, "COMPLETED", row[status])
+			assert.Equal(t, "CASH", row[method])
+		}
+		assert.ElementsMatch(t, []string{"25,00", "1234,50"}, totals, "kuruş written as lira with a decimal comma")
+	})
+
+	t.Run("Orders_XLSX", func(t *testing.T) {
+		header, body, code := download(t, token, "/api/v1/exports/orders?format=xlsx&"+scope)
+		require.Equal(t, http.StatusOK, code, string(body))
+		assert.Equal(t, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", header.Get("Content-Type"))
+		assert.Contains(t, header.Get("Content-Disposition"), ".xlsx")
+
+		book, err := excelize.OpenReader(bytes.NewReader(body))
+		require.NoError(t, err)
+		defer book.Close()
+		require.Equal(t, []string{"Siparişler"}, book.GetSheetList())
+
+		rows, err := book.GetRows("Siparişler")
+		require.NoError(t, err)
+		require.Len(t, rows, 3)
+		assert.Equal(t, orderHeaders, rows[0])
+
+		// Amounts are numbers in lira so the accountant can sum them
+		col, err := excelize.ColumnNumberToName(column(orderHeaders, "Toplam") + 1)
+		require.NoError(t, err)
+		var sum float64
+		for row := 2; row <= 3; row++ {
+			raw, err := book.GetCellValue("Siparişler", col+strconv.Itoa(row), excelize.Options{RawCellValue: true})
+			require.NoError(t, err)
+			value, err := strconv.ParseFloat(raw, 64)
+			require.NoError(t, err, raw)
+			sum += value
+		}
+		assert.InDelta(t, 1259.50, sum, 0.001)
+	})
+
+	t.Run("Expenses_CSV", func(t *testing.T) {
+		records := readCSV(t, "/api/v1/exports/expenses?"+scope)
+		require.Len(t, records, 2)
+		assert.Equal(t, []string{"Tarih", "Kategori", "Açıklama", "Ödeme Yöntemi", "Tutar", "Dönem"}, records[0])
+		assert.Equal(t, []string{"Malzeme", "Ekmek", "CASH", "12,50", strconv.Itoa(int(period.ID))}, records[1][1:])
+	})
+
+	t.Run("Periods_CSV", func(t *testing.T) {
+		records := readCSV(t, "/api/v1/exports/periods?start_date=2023-05-12&end_date=2023-05-12")
+		require.Len(t, records, 2)
+		headers := records[0]
+		row := records[1]
+		assert.Equal(t, strconv.Itoa(int(period.ID)), row[column(headers, "Dönem")])
+		assert.Equal(t, "12.05.2023 09:00", row[column(headers, "Başlangıç")])
+		assert.Equal(t, "2", row[column(headers, "Sipariş")])
+		assert.Equal(t, "1259,50", row[column(headers, "Brüt Satış")])
+		assert.Equal(t, "1259,50", row[column(headers, "Net Satış")])
+		assert.Equal(t, "12,50", row[column(headers, "Gider")])
+		assert.Equal(t, "1247,00", row[column(headers, "Net Kâr")])
+		assert.Equal(t, "", row[column(headers, "Sayılan Nakit")], "the drawer was not counted")
+	})
+
+	t.Run("Invalid_Format", func(t *testing.T) {
+		_, code := logAndRequest(t, "Export As PDF", "GET", "/api/v1/exports/orders?format=pdf&"+scope, nil, token)
+		assert.Equal(t, http.StatusBadRequest, code)
+	})
+
+	t.Run("Z_Report", func(t *testing.T) {
+		header, body, code := download(t, token, fmt.Sprintf("/api/v1/exports/z-report?period_id=%d", period.ID))
+		require.Equal(t, http.StatusOK, code, string(body))
+		assert.Equal(t, "application/pdf", header.Get("Content-Type"))
+		assert.True(t, strings.HasPrefix(string(body), "%PDF-"))
+
+		var active models.WorkPeriod
+		require.NoError(t, database.DB.Where("is_active = ?", true).First(&active).Error)
+		_, code = logAndRequest(t, "Z Report Of Open Day", "GET", fmt.Sprintf("/api/v1/exports/z-report?period_id=%d", active.ID), nil, token)
+		assert.Equal(t, http.StatusBadRequest, code, "the day is still open")
+	})
+}
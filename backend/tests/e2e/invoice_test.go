@@ -0,0 +1,165 @@
+package e2e
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"simple-pos/internal/models"
+	"simple-pos/internal/platform/database"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ublDocument reads back the parts of a UBL-TR invoice the test checks, elements match by local name
+type ublDocument struct {
+	ID       string `xml:"ID"`
+	Customer struct {
+		ID     string `xml:"Party>PartyIdentification>ID"`
+		Name   string `xml:"Party>PartyName>Name"`
+		Person struct {
+			FirstName  string `xml:"FirstName"`
+			FamilyName string `xml:"FamilyName"`
+		} `xml:"Party>Person"`
+	} `xml:"AccountingCustomerParty"`
+	TaxTotal struct {
+		TaxAmount string `xml:"TaxAmount"`
+		Subtotals []struct {
+			Taxable   string `xml:"TaxableAmount"`
+			TaxAmount string `xml:"TaxAmount"`
+			Percent   string `xml:"Percent"`
+			TypeCode  string `xml:"TaxCategory>TaxScheme>TaxTypeCode"`
+		} `xml:"TaxSubtotal"`
+	} `xml:"TaxTotal"`
+	Monetary struct {
+		LineExtension string `xml:"LineExtensionAmount"`
+		TaxInclusive  string `xml:"TaxInclusiveAmount"`
+		Allowance     string `xml:"AllowanceTotalAmount"`
+		Payable       string `xml:"PayableAmount"`
+	} `xml:"LegalMonetaryTotal"`
+	Lines []struct {
+		LineExtension string `xml:"LineExtensionAmount"`
+	} `xml:"InvoiceLine"`
+}
+
+// TestE2E_Invoices issues e-Arşiv invoices for completed orders and checks their numbers and KDV breakdown
+func TestE2E_Invoices(t *testing.T) {
+	token := apiToken(t)
+	ensureWorkDay(t, token)
+
+	categoryID := createResource(t, token, "/api/v1/categories", map[string]interface{}{"name": "Fatura Test", "tax_rate": 10})
+	foodID := createResource(t, token, "/api/v1/products", map[string]interface{}{"category_id": categoryID, "name": "Fatura Yemek", "price": 11000})
+	alcoholID := createResource(t, token, "/api/v1/products", map[string]interface{}{"category_id": categoryID, "name": "Fatura Bira", "price": 12000, "tax_rate": 20})
+	exemptID := createResource(t, token, "/api/v1/products", map[string]interface{}{"category_id": categoryID, "name": "Fatura Muaf", "price": 5000, "tax_rate": 0})
+
+	// The counter of an earlier year must not carry over
+	year := time.Now().Year()
+	require.NoError(t, database.DB.Create(&models.InvoiceSequence{Year: year - 1, LastNumber: 41}).Error)
+
+	company := map[string]interface{}{"tax_id": "1234567890", "name": "Örnek Yazılım A.Ş.", "tax_office": "Beşiktaş", "city": "İstanbul"}
+	person := map[string]interface{}{"tax_id": "10000000146", "name": "Ayşe Nur Yılmaz", "city": "Ankara"}
+
+	issue := func(orderID uint, customer map[string]interface{}) ([]byte, int) {
+		return logAndRequest(t, "Issue Invoice", "POST", fmt.Sprintf("/api/v1/orders/%d/invoice", orderID), customer, token)
+	}
+	issued := func(orderID uint, customer map[string]interface{}) models.Invoice {
+		resp, code := issue(orderID, customer)
+		require.Equal(t, http.StatusCreated, code, string(resp))
+		var invoice models.Invoice
+		extractData(t, resp, &invoice)
+		return invoice
+	}
+	readUBL := func(invoiceID uint) ublDocument {
+		header, body, code := download(t, token, fmt.Sprintf("/api/v1/invoices/%d/xml", invoiceID))
+		require.Equal(t, http.StatusOK, code, string(body))
+		assert.Equal(t, "application/xml; charset=utf-8", header.Get("Content-Type"))
+		var doc ublDocument
+		require.NoError(t, xml.Unmarshal(body, &doc))
+		return doc
+	}
+
+	// 28000 with one of each rate, 10% off
+	mixed := openOrder(t, token)
+	addItem(t, token, mixed, foodID, 1)
+	addItem(t, token, mixed, alcoholID, 1)
+	addItem(t, token, mixed, exemptID, 1)
+	_, code := logAndRequest(t, "Apply Discount", "POST", fmt.Sprintf("/api/v1/orders/%d/discount", mixed),
+		map[string]interface{}{"type": "PERCENTAGE", "value": 10, "reason": "Fatura testi"}, token)
+	require.Equal(t, http.StatusOK, code)
+
+	t.Run("Only_Completed_Orders", func(t *testing.T) {
+		_, code := issue(mixed, company)
+		assert.Equal(t, http.StatusBadRequest, code, "the order is still open")
+		closeOrder(t, token, mixed)
+
+		_, code = issue(mixed, map[string]interface{}{"tax_id": "1234567891", "name": "Hatalı", "tax_office": "Beşiktaş", "city": "İstanbul"})
+		assert.Equal(t, http.StatusBadRequest, code, "the VKN check digit is wrong")
+		_, code = issue(mixed, map[string]interface{}{"tax_id": "1234567890", "name": "Dairesiz", "city": "İstanbul"})
+		assert.Equal(t, http.StatusBadRequest, code, "a company needs its tax office")
+	})
+
+	var first models.Invoice
+	t.Run("KDV_Breakdown", func(t *testing.T) {
+		first = issued(mixed, company)
+		assert.Equal(t, fmt.Sprintf("EAR%d000000001", year), first.InvoiceNumber)
+		assert.Equal(t, int64(22500), first.NetAmount)
+		assert.Equal(t, int64(2500), first.DiscountAmount)
+		assert.Equal(t, int64(2700), first.TaxAmount)
+		assert.Equal(t, int64(25200), first.PayableAmount, "the invoice pays what the order did")
+		assert.Equal(t, models.InvoiceStatusSent, first.Status)
+
+		doc := readUBL(first.ID)
+		assert.Equal(t, first.InvoiceNumber, doc.ID)
+		assert.Equal(t, "1234567890", doc.Customer.ID)
+		assert.Equal(t, "Örnek Yazılım A.Ş.", doc.Customer.Name)
+		assert.Len(t, doc.Lines, 3)
+
+		assert.Equal(t, "27.00", doc.TaxTotal.TaxAmount)
+		require.Len(t, doc.TaxTotal.Subtotals, 3, "one subtotal per rate")
+		for i, want := range [][3]string{{"0", "45.00", "0.00"}, {"10", "90.00", "9.00"}, {"20", "90.00", "18.00"}} {
+			subtotal := doc.TaxTotal.Subtotals[i]
+			assert.Equal(t, want, [3]string{subtotal.Percent, subtotal.Taxable, subtotal.TaxAmount})
+			assert.Equal(t, "0015", subtotal.TypeCode, "GİB code of KDV")
+		}
+
+		assert.Equal(t, "225.00", doc.Monetary.LineExtension)
+		assert.Equal(t, "25.00", doc.Monetary.Allowance)
+		assert.Equal(t, "252.00", doc.Monetary.TaxInclusive)
+		assert.Equal(t, "252.00", doc.Monetary.Payable)
+
+		// The file integrator keeps the same XML under the invoice number
+		stored, err := os.ReadFile(filepath.Join(invoiceDir, first.InvoiceNumber+".xml"))
+		require.NoError(t, err)
+		_, body, _ := download(t, token, fmt.Sprintf("/api/v1/invoices/%d/xml", first.ID))
+		assert.Equal(t, string(body), string(stored))
+	})
+
+	t.Run("Sequence_Per_Year", func(t *testing.T) {
+		_, code := issue(mixed, company)
+		assert.Equal(t, http.StatusConflict, code, "one invoice per order")
+
+		single := openOrder(t, token)
+		addItem(t, token, single, foodID, 2)
+		closeOrder(t, token, single)
+		second := issued(single, person)
+		assert.Equal(t, fmt.Sprintf("EAR%d000000002", year), second.InvoiceNumber)
+		assert.Equal(t, first.Sequence+1, second.Sequence)
+
+		doc := readUBL(second.ID)
+		assert.Equal(t, "10000000146", doc.Customer.ID)
+		assert.Equal(t, "Ayşe Nur", doc.Customer.Person.FirstName, "a person is named, not titled")
+		assert.Equal(t, "Yılmaz", doc.Customer.Person.FamilyName)
+		require.Len(t, doc.TaxTotal.Subtotals, 1)
+		assert.Equal(t, "200.00", doc.TaxTotal.Subtotals[0].Taxable)
+		assert.Equal(t, "20.00", doc.TaxTotal.Subtotals[0].TaxAmount)
+
+		var sequences []models.InvoiceSequence
+		require.NoError(t, database.DB.Order("year asc").Find(&sequences).Error)
+		assert.Equal(t, []models.InvoiceSequence{{Year: year - 1, LastNumber: 41}, {Year: year, LastNumber: 2}}, sequences)
+	})
+}
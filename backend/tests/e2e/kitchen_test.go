@@ -0,0 +1,122 @@
+package e2e
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"simple-pos/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestE2E_KitchenTickets routes fired items to the station of their category and bumps them through the states
+func TestE2E_KitchenTickets(t *testing.T) {
+	token := apiToken(t)
+	ensureWorkDay(t, token)
+
+	grillID := createResource(t, token, "/api/v1/kitchen/stations", map[string]interface{}{"name": "KDS Izgara"})
+	barID := createResource(t, token, "/api/v1/kitchen/stations", map[string]interface{}{"name": "KDS Bar"})
+	closedID := createResource(t, token, "/api/v1/kitchen/stations", map[string]interface{}{"name": "KDS Kapalı"})
+	_, code := logAndRequest(t, "Deactivate Station", "PUT", fmt.Sprintf("/api/v1/kitchen/stations/%d", closedID), map[string]interface{}{"name": "KDS Kapalı", "is_active": false}, token)
+	require.Equal(t, http.StatusOK, code)
+
+	product := func(name string, stationID uint) uint {
+		categoryID := createResource(t, token, "/api/v1/categories", map[string]interface{}{"name": name, "station_id": stationID})
+		return createResource(t, token, "/api/v1/products", map[string]interface{}{"category_id": categoryID, "name": name + " Ürün", "price": 2000})
+	}
+	kofteID := product("KDS Köfte", grillID)
+	colaID := product("KDS Kola", barID)
+	soupID := product("KDS Çorba", closedID)
+
+	tableID := createResource(t, token, "/api/v1/tables", map[string]interface{}{"name": "KDS Masa"})
+	orderID := openTableOrder(t, token, tableID)
+	kofte := addItem(t, token, orderID, kofteID, 2)
+	addItem(t, token, orderID, colaID, 1)
+	soup := addItem(t, token, orderID, soupID, 1)
+
+	tickets := func(query string) []models.KitchenTicket {
+		resp, code := logAndRequest(t, "List Tickets", "GET", "/api/v1/kitchen/tickets"+query, nil, token)
+		require.Equal(t, http.StatusOK, code)
+		var tickets []models.KitchenTicket
+		extractData(t, resp, &tickets)
+		var own []models.KitchenTicket
+		for _, ticket := range tickets {
+			if ticket.OrderID == orderID {
+				own = append(own, ticket)
+			}
+		}
+		return own
+	}
+	itemStatus := func(itemID uint) string {
+		for _, item := range loadOrder(t, orderID).Items {
+			if item.ID == itemID {
+				return item.KitchenStatus
+			}
+		}
+		t.Fatalf("item %d not on the order", itemID)
+		return ""
+	}
+
+	grill := tickets(fmt.Sprintf("?station_id=%d", grillID))
+	require.Len(t, grill, 1)
+	assert.Equal(t, "KDS Izgara", grill[0].StationName)
+	assert.Equal(t, "KDS Masa", grill[0].TableName)
+	assert.Equal(t, 2, grill[0].Quantity)
+	assert.Equal(t, models.KitchenStatusQueued, grill[0].Status)
+	assert.Len(t, tickets(fmt.Sprintf("?station_id=%d", barID)), 1)
+	assert.Len(t, tickets(fmt.Sprintf("?station_id=%d", closedID)), 0, "an inactive station gets nothing")
+	for _, ticket := range tickets("") {
+		if ticket.OrderItemID == soup.ID {
+			assert.Nil(t, ticket.StationID, "unrouted")
+		}
+	}
+
+	bump := func(ticketID uint) int {
+		_, code := logAndRequest(t, "Bump Ticket", "POST", fmt.Sprintf("/api/v1/kitchen/tickets/%d/bump", ticketID), nil, token)
+		return code
+	}
+	for _, want := range []string{models.KitchenStatusInPreparation, models.KitchenStatusReady, models.KitchenStatusServed} {
+		require.Equal(t, http.StatusOK, bump(grill[0].ID))
+		assert.Equal(t, want, itemStatus(kofte.ID))
+	}
+	assert.Equal(t, http.StatusBadRequest, bump(grill[0].ID), "served is the last state")
+	assert.Empty(t, tickets(fmt.Sprintf("?station_id=%d", grillID)), "served tickets leave the screen")
+	assert.Len(t, tickets(fmt.Sprintf("?station_id=%d&status=served", grillID)), 1)
+
+	// More units of a served item go out as a new ticket, the item waits for the least advanced one
+	_, code = logAndRequest(t, "Add Units", "PUT", fmt.Sprintf("/api/v1/orders/%d/items/%d", orderID, kofte.ID), map[string]interface{}{"quantity": 3}, token)
+	require.Equal(t, http.StatusOK, code)
+	grill = tickets(fmt.Sprintf("?station_id=%d", grillID))
+	require.Len(t, grill, 1)
+	assert.Equal(t, 1, grill[0].Quantity)
+	assert.Equal(t, models.KitchenStatusQueued, itemStatus(kofte.ID))
+
+	statusPath := fmt.Sprintf("/api/v1/kitchen/tickets/%d/status", grill[0].ID)
+	_, code = logAndRequest(t, "Invalid Status", "PUT", statusPath, map[string]interface{}{"status": "burnt"}, token)
+	assert.Equal(t, http.StatusBadRequest, code)
+	_, code = logAndRequest(t, "Mark Ready", "PUT", statusPath, map[string]interface{}{"status": "ready"}, token)
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, models.KitchenStatusReady, itemStatus(kofte.ID))
+
+	resp, code := logAndRequest(t, "Station Stats", "GET", "/api/v1/kitchen/stats", nil, token)
+	require.Equal(t, http.StatusOK, code)
+	var stats []struct {
+		StationID *uint `json:"station_id"`
+		Tickets   int   `json:"tickets"`
+		Served    int   `json:"served"`
+	}
+	extractData(t, resp, &stats)
+	found := false
+	for _, stat := range stats {
+		if stat.StationID != nil && *stat.StationID == grillID {
+			found = true
+			assert.Equal(t, 2, stat.Tickets)
+			assert.Equal(t, 1, stat.Served)
+		}
+	}
+	assert.True(t, found, "the grill has stats")
+
+	closeOrder(t, token, orderID)
+}
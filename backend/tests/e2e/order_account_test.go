@@ -0,0 +1,45 @@
+package e2e
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"simple-pos/internal/models"
+	"simple-pos/internal/platform/database"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestE2E_AccountLedgerBalance charges two orders to a tab and takes a payment, each entry carries the running balance
+func TestE2E_AccountLedgerBalance(t *testing.T) {
+	token := apiToken(t)
+	ensureWorkDay(t, token)
+
+	categoryID := createResource(t, token, "/api/v1/categories", map[string]interface{}{"name": "Account Test"})
+	productID := createResource(t, token, "/api/v1/products", map[string]interface{}{"category_id": categoryID, "name": "Account Tost", "price": 3000})
+	customerID := createResource(t, token, "/api/v1/customers", map[string]interface{}{"name": "Veresiye Ali", "phone": "05550000022"})
+
+	for _, qty := range []int{1, 2} {
+		orderID := openOrder(t, token)
+		addItem(t, token, orderID, productID, qty)
+		payload := map[string]interface{}{"payment_method": models.PaymentMethodOnAccount, "customer_id": customerID}
+		resp, code := logAndRequest(t, "Close On Account", "POST", fmt.Sprintf("/api/v1/orders/%d/close", orderID), payload, token)
+		require.Equal(t, http.StatusOK, code, string(resp))
+	}
+
+	payment := map[string]interface{}{"amount": 5000, "method": models.PaymentMethodCash}
+	resp, code := logAndRequest(t, "Account Payment", "POST", fmt.Sprintf("/api/v1/customers/%d/payments", customerID), payment, token)
+	require.Equal(t, http.StatusCreated, code, string(resp))
+
+	var entries []models.CustomerLedgerEntry
+	require.NoError(t, database.DB.Where("customer_id = ?", customerID).Order("id").Find(&entries).Error)
+	require.Len(t, entries, 3)
+	assert.Equal(t, []int64{3000, 6000, -5000}, []int64{entries[0].Amount, entries[1].Amount, entries[2].Amount})
+	assert.Equal(t, []int64{3000, 9000, 4000}, []int64{entries[0].Balance, entries[1].Balance, entries[2].Balance})
+
+	var customer models.Customer
+	require.NoError(t, database.DB.First(&customer, customerID).Error)
+	assert.Equal(t, int64(4000), customer.Balance)
+}
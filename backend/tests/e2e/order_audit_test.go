@@ -0,0 +1,86 @@
+package e2e
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"simple-pos/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestE2E_AuditLogFilters records who discounted and removed what, and narrows the log by user, entity, action and dates
+func TestE2E_AuditLogFilters(t *testing.T) {
+	token := apiToken(t)
+	ensureWorkDay(t, token)
+
+	managerID, managerToken := staffToken(t, token, "auditmanager", "5791", models.RoleManager)
+	_, waiterToken := staffToken(t, token, "auditwaiter", "1975", models.RoleWaiter)
+
+	categoryID := createResource(t, token, "/api/v1/categories", map[string]interface{}{"name": "Audit Test"})
+	productID := createResource(t, token, "/api/v1/products", map[string]interface{}{"category_id": categoryID, "name": "Audit Tost", "price": 2000})
+	orderID := openOrder(t, token)
+	addItem(t, token, orderID, productID, 1)
+	removed := addItem(t, token, orderID, productID, 1)
+
+	payload := map[string]interface{}{"type": "AMOUNT", "value": 200, "reason": "Regular guest"}
+	_, code := logAndRequest(t, "Manager Discount", "POST", fmt.Sprintf("/api/v1/orders/%d/discount", orderID), payload, managerToken)
+	require.Equal(t, http.StatusOK, code)
+	_, code = logAndRequest(t, "Remove Item", "DELETE", fmt.Sprintf("/api/v1/orders/%d/items/%d", orderID, removed.ID), nil, token)
+	require.Equal(t, http.StatusOK, code)
+
+	logs := func(query string) []models.AuditLog {
+		resp, code := logAndRequest(t, "Audit Logs", "GET", "/api/v1/audit-logs?"+query, nil, token)
+		require.Equal(t, http.StatusOK, code, string(resp))
+		var entries []models.AuditLog
+		extractData(t, resp, &entries)
+		return entries
+	}
+
+	t.Run("By_User", func(t *testing.T) {
+		entries := logs(fmt.Sprintf("user_id=%d", managerID))
+		require.NotEmpty(t, entries)
+		for _, entry := range entries {
+			assert.Equal(t, managerID, entry.UserID)
+		}
+	})
+
+	t.Run("By_Action_And_Entity", func(t *testing.T) {
+		entries := logs(fmt.Sprintf("action=%s&entity_type=%s&entity_id=%d", models.AuditActionOrderDiscount, models.AuditEntityOrder, orderID))
+		require.Len(t, entries, 1)
+		assert.Equal(t, managerID, entries[0].UserID)
+		assert.Equal(t, "Regular guest", entries[0].Reason)
+		var after struct {
+			DiscountAmount int64 `json:"discount_amount"`
+			TotalAmount    int64 `json:"total_amount"`
+		}
+		require.NoError(t, json.Unmarshal(entries[0].After, &after))
+		assert.Equal(t, int64(200), after.DiscountAmount)
+		assert.Equal(t, int64(3800), after.TotalAmount)
+
+		entries = logs(fmt.Sprintf("entity_type=%s&entity_id=%d", models.AuditEntityOrderItem, removed.ID))
+		require.Len(t, entries, 1)
+		assert.Equal(t, models.AuditActionOrderItemRemove, entries[0].Action)
+		assert.Equal(t, uint(1), entries[0].UserID)
+	})
+
+	t.Run("By_Date_And_Limit", func(t *testing.T) {
+		assert.Empty(t, logs(fmt.Sprintf("entity_id=%d&start_date=2020-01-01&end_date=2020-01-31", orderID)))
+		assert.Len(t, logs("limit=1"), 1)
+
+		_, code := logAndRequest(t, "Audit Logs Bad Range", "GET", "/api/v1/audit-logs?start_date=2020-02-01&end_date=2020-01-01", nil, token)
+		assert.Equal(t, http.StatusBadRequest, code)
+		_, code = logAndRequest(t, "Audit Logs Bad User", "GET", "/api/v1/audit-logs?user_id=abc", nil, token)
+		assert.Equal(t, http.StatusBadRequest, code)
+	})
+
+	t.Run("Needs_Permission", func(t *testing.T) {
+		_, code := logAndRequest(t, "Audit Logs As Waiter", "GET", "/api/v1/audit-logs", nil, waiterToken)
+		assert.Equal(t, http.StatusForbidden, code)
+	})
+
+	closeOrder(t, token, orderID)
+}
@@ -0,0 +1,79 @@
+package e2e
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"simple-pos/internal/models"
+	"simple-pos/internal/platform/database"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestE2E_CouponUsageLimit spends a single use coupon, gets it back with a void and reuses a deleted code
+func TestE2E_CouponUsageLimit(t *testing.T) {
+	token := apiToken(t)
+	ensureWorkDay(t, token)
+
+	categoryID := createResource(t, token, "/api/v1/categories", map[string]interface{}{"name": "Coupon Test"})
+	productID := createResource(t, token, "/api/v1/products", map[string]interface{}{"category_id": categoryID, "name": "Coupon Tost", "price": 5000})
+	couponID := createResource(t, token, "/api/v1/coupons", map[string]interface{}{
+		"code": "TEK10", "discount_type": "AMOUNT", "value": 1000, "usage_limit": 1,
+	})
+
+	usedCount := func() int {
+		var coupon models.Coupon
+		require.NoError(t, database.DB.Unscoped().First(&coupon, couponID).Error)
+		return coupon.UsedCount
+	}
+	applyCoupon := func(orderID uint) int {
+		_, code := logAndRequest(t, "Apply Coupon", "POST", fmt.Sprintf("/api/v1/orders/%d/coupon", orderID), map[string]interface{}{"code": "tek10"}, token)
+		return code
+	}
+	closeOrder := func(orderID uint) {
+		resp, code := logAndRequest(t, "Close Order", "POST", fmt.Sprintf("/api/v1/orders/%d/close", orderID), map[string]interface{}{"payment_method": "CASH"}, token)
+		require.Equal(t, http.StatusOK, code, string(resp))
+	}
+
+	first := openOrder(t, token)
+	addItem(t, token, first, productID, 1)
+	require.Equal(t, http.StatusOK, applyCoupon(first))
+	closeOrder(first)
+	assert.Equal(t, 1, usedCount())
+	assert.Equal(t, int64(4000), loadOrder(t, first).TotalAmount)
+
+	second := openOrder(t, token)
+	addItem(t, token, second, productID, 1)
+	assert.Equal(t, http.StatusConflict, applyCoupon(second), "the single use is spent")
+
+	t.Run("Void_Gives_Use_Back", func(t *testing.T) {
+		resp, code := logAndRequest(t, "Void Order", "POST", fmt.Sprintf("/api/v1/orders/%d/refunds", first), map[string]interface{}{"type": "VOID", "reason_code": "OTHER"}, token)
+		require.Equal(t, http.StatusCreated, code, string(resp))
+		assert.Equal(t, 0, usedCount())
+
+		var redemptions int64
+		require.NoError(t, database.DB.Model(&models.CouponRedemption{}).Where("order_id = ?", first).Count(&redemptions).Error)
+		assert.Equal(t, int64(0), redemptions)
+
+		require.Equal(t, http.StatusOK, applyCoupon(second))
+		closeOrder(second)
+		assert.Equal(t, 1, usedCount())
+	})
+
+	t.Run("Deleted_Code_Can_Be_Reissued", func(t *testing.T) {
+		_, code := logAndRequest(t, "Delete Coupon", "DELETE", fmt.Sprintf("/api/v1/coupons/%d", couponID), nil, token)
+		require.Equal(t, http.StatusOK, code)
+
+		reissued := createResource(t, token, "/api/v1/coupons", map[string]interface{}{
+			"code": "TEK10", "discount_type": "PERCENTAGE", "value": 10, "usage_limit": 1,
+		})
+		assert.NotEqual(t, couponID, reissued)
+
+		resp, code := logAndRequest(t, "Duplicate Coupon", "POST", "/api/v1/coupons", map[string]interface{}{
+			"code": "TEK10", "discount_type": "AMOUNT", "value": 500,
+		}, token)
+		assert.Equal(t, http.StatusConflict, code, string(resp))
+	})
+}
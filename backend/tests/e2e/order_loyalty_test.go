@@ -0,0 +1,108 @@
+package e2e
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"simple-pos/internal/models"
+	"simple-pos/internal/platform/database"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestE2E_LoyaltyRedeemAndRefund prices the stamp reward after promotions and reverses loyalty on refunds
+func TestE2E_LoyaltyRedeemAndRefund(t *testing.T) {
+	token := apiToken(t)
+	ensureWorkDay(t, token)
+
+	const phone = "05550000023"
+	categoryID := createResource(t, token, "/api/v1/categories", map[string]interface{}{"name": "Loyalty Kahve"})
+	productID := createResource(t, token, "/api/v1/products", map[string]interface{}{"category_id": categoryID, "name": "Loyalty Latte", "price": 2000})
+	customerID := createResource(t, token, "/api/v1/customers", map[string]interface{}{"name": "Sadik Musteri", "phone": phone})
+	createResource(t, token, "/api/v1/loyalty/rules", map[string]interface{}{
+		"category_id": categoryID, "points_per_lira": 10, "redeem_points": true, "stamps_per_item": 1, "stamps_for_reward": 2,
+	})
+
+	balance := func() (int64, int) {
+		var customer models.Customer
+		require.NoError(t, database.DB.First(&customer, customerID).Error)
+		var card models.LoyaltyStampCard
+		require.NoError(t, database.DB.Where("customer_id = ? AND category_id = ?", customerID, categoryID).First(&card).Error)
+		return customer.LoyaltyPoints, card.Stamps
+	}
+	customerOrder := func(qty int) (uint, models.OrderItem) {
+		orderID := openOrder(t, token)
+		item := addItem(t, token, orderID, productID, qty)
+		_, code := logAndRequest(t, "Attach Customer", "PUT", fmt.Sprintf("/api/v1/orders/%d/customer", orderID), map[string]interface{}{"phone": phone}, token)
+		require.Equal(t, http.StatusOK, code)
+		return orderID, item
+	}
+	closeOrder := func(orderID uint) {
+		resp, code := logAndRequest(t, "Close Order", "POST", fmt.Sprintf("/api/v1/orders/%d/close", orderID), map[string]interface{}{"payment_method": "CASH"}, token)
+		require.Equal(t, http.StatusOK, code, string(resp))
+	}
+	refund := func(orderID uint, payload map[string]interface{}) {
+		resp, code := logAndRequest(t, "Refund Order", "POST", fmt.Sprintf("/api/v1/orders/%d/refunds", orderID), payload, token)
+		require.Equal(t, http.StatusCreated, code, string(resp))
+	}
+
+	// 3 x 2000 earns 600 points and 3 stamps
+	firstID, firstItem := customerOrder(3)
+	closeOrder(firstID)
+	points, stamps := balance()
+	require.Equal(t, int64(600), points)
+	require.Equal(t, 3, stamps)
+
+	t.Run("Item_Refund_Takes_Back_Its_Share", func(t *testing.T) {
+		refund(firstID, map[string]interface{}{
+			"type":        "ITEMS",
+			"items":       []map[string]interface{}{{"item_id": firstItem.ID, "quantity": 1}},
+			"reason_code": "QUALITY",
+		})
+		points, stamps := balance()
+		assert.Equal(t, int64(400), points)
+		assert.Equal(t, 2, stamps)
+
+		var reversals int64
+		require.NoError(t, database.DB.Model(&models.LoyaltyEntry{}).
+			Where("order_id = ? AND type = ? AND (points < 0 OR stamps < 0)", firstID, models.LoyaltyEntryEarn).Count(&reversals).Error)
+		assert.Equal(t, int64(2), reversals, "one points and one stamp reversal")
+	})
+
+	t.Run("Stamp_Reward_After_Promotion_And_Void", func(t *testing.T) {
+		promotionID := createResource(t, token, "/api/v1/promotions", map[string]interface{}{
+			"name": "Yarim Latte", "type": models.PromotionCategoryPercent, "category_id": categoryID, "percent": 50,
+		})
+		defer func() {
+			_, code := logAndRequest(t, "Delete Promotion", "DELETE", fmt.Sprintf("/api/v1/promotions/%d", promotionID), nil, token)
+			assert.Equal(t, http.StatusOK, code)
+		}()
+
+		orderID, _ := customerOrder(2)
+		resp, code := logAndRequest(t, "Redeem Stamps", "POST", fmt.Sprintf("/api/v1/orders/%d/loyalty/redeem", orderID), map[string]interface{}{"category_id": categoryID}, token)
+		require.Equal(t, http.StatusOK, code, string(resp))
+
+		order := loadOrder(t, orderID)
+		assert.Equal(t, int64(1000), order.DiscountAmount, "the free unit is worth its promoted price")
+		assert.Equal(t, int64(1000), order.TotalAmount)
+
+		// Spends 2 stamps, earns 100 points and 2 stamps on what was paid
+		closeOrder(orderID)
+		points, stamps := balance()
+		require.Equal(t, int64(500), points)
+		require.Equal(t, 2, stamps)
+
+		// The void takes back what the order earned and returns the stamps it spent
+		refund(orderID, map[string]interface{}{"type": "VOID", "reason_code": "CUSTOMER_COMPLAINT"})
+		points, stamps = balance()
+		assert.Equal(t, int64(400), points)
+		assert.Equal(t, 2, stamps)
+
+		var giveBack models.LoyaltyEntry
+		require.NoError(t, database.DB.Where("order_id = ? AND type = ? AND stamps > 0", orderID, models.LoyaltyEntryRedeem).First(&giveBack).Error)
+		assert.Equal(t, 2, giveBack.Stamps)
+		assert.Equal(t, int64(-1000), giveBack.Amount)
+	})
+}
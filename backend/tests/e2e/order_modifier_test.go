@@ -0,0 +1,92 @@
+package e2e
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"simple-pos/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestE2E_ModifierSelection enforces the selection limits of product and category groups and prices the picked options
+func TestE2E_ModifierSelection(t *testing.T) {
+	token := apiToken(t)
+	ensureWorkDay(t, token)
+
+	categoryID := createResource(t, token, "/api/v1/categories", map[string]interface{}{"name": "Modifier Test"})
+	productID := createResource(t, token, "/api/v1/products", map[string]interface{}{"category_id": categoryID, "name": "Modifier Tost", "price": 5000})
+	otherID := createResource(t, token, "/api/v1/products", map[string]interface{}{"category_id": categoryID, "name": "Modifier Ayran", "price": 1500})
+
+	// The product must take one or two sauces, every product of the category may take one extra
+	sauces := createResource(t, token, "/api/v1/modifier-groups", map[string]interface{}{
+		"name": "Sos", "product_id": productID, "is_required": true, "min_select": 1, "max_select": 2,
+	})
+	extras := createResource(t, token, "/api/v1/modifier-groups", map[string]interface{}{
+		"name": "Ekstra", "category_id": categoryID, "max_select": 1,
+	})
+	option := func(groupID uint, name string, delta int64) uint {
+		return createResource(t, token, fmt.Sprintf("/api/v1/modifier-groups/%d/options", groupID), map[string]interface{}{"name": name, "price_delta": delta})
+	}
+	ketchup := option(sauces, "Ketçap", 0)
+	mayo := option(sauces, "Mayonez", 0)
+	mustard := option(sauces, "Hardal", 200)
+	cheese := option(extras, "Kaşar", 1000)
+	sucuk := option(extras, "Sucuk", 1500)
+
+	_, code := logAndRequest(t, "Create Invalid Group", "POST", "/api/v1/modifier-groups", map[string]interface{}{
+		"name": "Bozuk", "product_id": productID, "min_select": 3, "max_select": 1,
+	}, token)
+	assert.Equal(t, http.StatusBadRequest, code, "min above max")
+
+	orderID := openOrder(t, token)
+	add := func(productID uint, options ...uint) ([]byte, int) {
+		payload := map[string]interface{}{"product_id": productID, "quantity": 2, "modifier_option_ids": options}
+		return logAndRequest(t, "Add Item With Modifiers", "POST", fmt.Sprintf("/api/v1/orders/%d/items", orderID), payload, token)
+	}
+
+	for name, options := range map[string][]uint{
+		"below_min":          {},
+		"above_max":          {ketchup, mayo, mustard},
+		"above_category_max": {ketchup, cheese, sucuk},
+		"duplicate":          {ketchup, ketchup},
+	} {
+		_, code := add(productID, options...)
+		assert.Equal(t, http.StatusBadRequest, code, name)
+	}
+	_, code = add(otherID, ketchup)
+	assert.Equal(t, http.StatusBadRequest, code, "another product's group")
+
+	resp, code := add(productID, ketchup, mustard, cheese)
+	require.Equal(t, http.StatusCreated, code, string(resp))
+	var item models.OrderItem
+	extractData(t, resp, &item)
+	require.Len(t, item.Modifiers, 3)
+	assert.Equal(t, int64(5000), item.UnitPrice)
+	assert.Equal(t, int64(12400), item.Subtotal, "(5000 + 200 + 1000) x 2")
+
+	// A category group applies to the other products of the category, an extra alone is enough there
+	resp, code = add(otherID, sucuk)
+	require.Equal(t, http.StatusCreated, code, string(resp))
+
+	assert.Len(t, loadOrder(t, orderID).Items, 2)
+	closeOrder(t, token, orderID)
+
+	resp, code = logAndRequest(t, "Modifier Usage", "GET", "/api/v1/analytics/modifiers", nil, token)
+	require.Equal(t, http.StatusOK, code)
+	var usage []struct {
+		OptionID uint  `json:"option_id"`
+		Quantity int64 `json:"quantity"`
+		Revenue  int64 `json:"revenue"`
+	}
+	extractData(t, resp, &usage)
+	byOption := make(map[uint][2]int64)
+	for _, u := range usage {
+		byOption[u.OptionID] = [2]int64{u.Quantity, u.Revenue}
+	}
+	assert.Equal(t, [2]int64{2, 2000}, byOption[cheese])
+	assert.Equal(t, [2]int64{2, 3000}, byOption[sucuk])
+	assert.Equal(t, [2]int64{2, 0}, byOption[ketchup])
+}
@@ -0,0 +1,81 @@
+package e2e
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"simple-pos/internal/models"
+	"simple-pos/internal/platform/database"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestE2E_MoveAndMerge audits item moves and refuses to merge away a discounted order
+func TestE2E_MoveAndMerge(t *testing.T) {
+	token := apiToken(t)
+	ensureWorkDay(t, token)
+
+	categoryID := createResource(t, token, "/api/v1/categories", map[string]interface{}{"name": "Move Test"})
+	productID := createResource(t, token, "/api/v1/products", map[string]interface{}{"category_id": categoryID, "name": "Move Tost", "price": 2500})
+
+	t.Run("Move_Items_Is_Audited", func(t *testing.T) {
+		source := openOrder(t, token)
+		item := addItem(t, token, source, productID, 3)
+		target := openOrder(t, token)
+
+		payload := map[string]interface{}{"target_order_id": target, "items": []map[string]interface{}{{"item_id": item.ID, "quantity": 2}}}
+		resp, code := logAndRequest(t, "Move Items", "POST", fmt.Sprintf("/api/v1/orders/%d/move-items", source), payload, token)
+		require.Equal(t, http.StatusOK, code, string(resp))
+
+		var entry models.AuditLog
+		require.NoError(t, database.DB.Where("action = ? AND entity_id = ?", models.AuditActionOrderItemMove, source).First(&entry).Error)
+		var before struct {
+			Items []struct {
+				ItemID   uint `json:"item_id"`
+				Quantity int  `json:"quantity"`
+			} `json:"items"`
+		}
+		require.NoError(t, json.Unmarshal(entry.Before, &before))
+		require.Len(t, before.Items, 1)
+		assert.Equal(t, item.ID, before.Items[0].ItemID)
+		assert.Equal(t, 2, before.Items[0].Quantity)
+		assert.JSONEq(t, fmt.Sprintf(`{"order_id": %d}`, target), string(entry.After))
+
+		closeOrder(t, token, source)
+		closeOrder(t, token, target)
+	})
+
+	t.Run("Merge_Refuses_Discounted_Source", func(t *testing.T) {
+		sourceTable := createResource(t, token, "/api/v1/tables", map[string]interface{}{"name": "Merge A"})
+		targetTable := createResource(t, token, "/api/v1/tables", map[string]interface{}{"name": "Merge B"})
+
+		source := openTableOrder(t, token, sourceTable)
+		addItem(t, token, source, productID, 2)
+		target := openTableOrder(t, token, targetTable)
+		addItem(t, token, target, productID, 1)
+
+		discount := func(discountType string, value int64) {
+			payload := map[string]interface{}{"type": discountType, "value": value, "reason": "Regular guest"}
+			resp, code := logAndRequest(t, "Discount", "POST", fmt.Sprintf("/api/v1/orders/%d/discount", source), payload, token)
+			require.Equal(t, http.StatusOK, code, string(resp))
+		}
+		merge := func() int {
+			_, code := logAndRequest(t, "Merge Tables", "POST", fmt.Sprintf("/api/v1/tables/%d/merge", sourceTable), map[string]interface{}{"target_table_id": targetTable}, token)
+			return code
+		}
+
+		discount("AMOUNT", 500)
+		assert.Equal(t, http.StatusBadRequest, merge())
+		assert.Equal(t, int64(500), loadOrder(t, source).DiscountAmount, "the refused merge left the source untouched")
+		assert.Len(t, loadOrder(t, target).Items, 1)
+
+		discount("NONE", 0)
+		require.Equal(t, http.StatusOK, merge())
+		assert.Equal(t, int64(7500), loadOrder(t, target).TotalAmount)
+
+		closeOrder(t, token, target)
+	})
+}
@@ -0,0 +1,71 @@
+package e2e
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"simple-pos/internal/models"
+	"simple-pos/internal/platform/database"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestE2E_OrderNotes keeps item and order notes within 255 characters and carries item notes to the kitchen
+func TestE2E_OrderNotes(t *testing.T) {
+	token := apiToken(t)
+	ensureWorkDay(t, token)
+
+	categoryID := createResource(t, token, "/api/v1/categories", map[string]interface{}{"name": "Note Test"})
+	productID := createResource(t, token, "/api/v1/products", map[string]interface{}{"category_id": categoryID, "name": "Note Tost", "price": 3000})
+	tooLong := strings.Repeat("a", 256)
+	longest := strings.Repeat("b", 255)
+
+	waitOrderSecond()
+	_, code := logAndRequest(t, "Create Order With Long Note", "POST", "/api/v1/orders", map[string]interface{}{"waiter_id": 1, "note": tooLong}, token)
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	orderID := openOrder(t, token)
+	itemsPath := fmt.Sprintf("/api/v1/orders/%d/items", orderID)
+
+	_, code = logAndRequest(t, "Add Item With Long Note", "POST", itemsPath, map[string]interface{}{"product_id": productID, "quantity": 1, "note": tooLong}, token)
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	resp, code := logAndRequest(t, "Add Item With Note", "POST", itemsPath, map[string]interface{}{"product_id": productID, "quantity": 1, "note": "  soğansız  "}, token)
+	require.Equal(t, http.StatusCreated, code, string(resp))
+	var item models.OrderItem
+	extractData(t, resp, &item)
+	assert.Equal(t, "soğansız", item.Note)
+
+	ticket := func() models.KitchenTicket {
+		var ticket models.KitchenTicket
+		require.NoError(t, database.DB.Where("order_item_id = ?", item.ID).First(&ticket).Error)
+		return ticket
+	}
+	assert.Equal(t, "soğansız", ticket().Note, "the kitchen sees the note")
+
+	itemPath := fmt.Sprintf("/api/v1/orders/%d/items/%d", orderID, item.ID)
+	_, code = logAndRequest(t, "Update Item Long Note", "PUT", itemPath, map[string]interface{}{"note": tooLong}, token)
+	assert.Equal(t, http.StatusBadRequest, code)
+	_, code = logAndRequest(t, "Update Item Note", "PUT", itemPath, map[string]interface{}{"note": longest}, token)
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, longest, ticket().Note, "a queued ticket follows the edit")
+
+	notePath := fmt.Sprintf("/api/v1/orders/%d/note", orderID)
+	_, code = logAndRequest(t, "Update Long Order Note", "PUT", notePath, map[string]interface{}{"note": tooLong}, token)
+	assert.Equal(t, http.StatusBadRequest, code)
+	_, code = logAndRequest(t, "Update Order Note", "PUT", notePath, map[string]interface{}{"note": "Doğum günü"}, token)
+	require.Equal(t, http.StatusOK, code)
+
+	resp, code = logAndRequest(t, "Get Order", "GET", fmt.Sprintf("/api/v1/orders/%d", orderID), nil, token)
+	require.Equal(t, http.StatusOK, code)
+	var order models.Order
+	extractData(t, resp, &order)
+	assert.Equal(t, "Doğum günü", order.Note)
+	require.Len(t, order.Items, 1)
+	assert.Equal(t, longest, order.Items[0].Note)
+
+	closeOrder(t, token, orderID)
+}
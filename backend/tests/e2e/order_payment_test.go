@@ -0,0 +1,99 @@
+package e2e
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestE2E_SplitPayments covers the EQUAL, ITEMS and AMOUNT splits and closing a fully paid order
+func TestE2E_SplitPayments(t *testing.T) {
+	token := apiToken(t)
+	ensureWorkDay(t, token)
+
+	categoryID := createResource(t, token, "/api/v1/categories", map[string]interface{}{"name": "Split Test"})
+	kebapID := createResource(t, token, "/api/v1/products", map[string]interface{}{"category_id": categoryID, "name": "Split Kebap", "price": 2500, "tax_rate": 10})
+	colaID := createResource(t, token, "/api/v1/products", map[string]interface{}{"category_id": categoryID, "name": "Split Kola", "price": 1000, "tax_rate": 10})
+
+	pay := func(orderID uint, payload map[string]interface{}) ([]byte, int) {
+		return logAndRequest(t, "Add Payment", "POST", fmt.Sprintf("/api/v1/orders/%d/payments", orderID), payload, token)
+	}
+
+	t.Run("Equal_Shares", func(t *testing.T) {
+		orderID := openOrder(t, token)
+		addItem(t, token, orderID, kebapID, 4) // 10000 between 3 guests
+		equal := func(count, shares int) map[string]interface{} {
+			return map[string]interface{}{"method": "CASH", "split_type": "EQUAL", "split_count": count, "shares": shares}
+		}
+
+		_, code := pay(orderID, equal(3, 1))
+		require.Equal(t, http.StatusCreated, code)
+		assert.Equal(t, int64(3333), loadOrder(t, orderID).PaidAmount)
+
+		// The split count is fixed by the first share
+		_, code = pay(orderID, equal(4, 1))
+		assert.Equal(t, http.StatusBadRequest, code)
+
+		// The last share takes the rounding remainder
+		_, code = pay(orderID, equal(3, 2))
+		require.Equal(t, http.StatusCreated, code)
+		order := loadOrder(t, orderID)
+		assert.Equal(t, int64(10000), order.PaidAmount)
+		assert.Equal(t, 3, order.SplitSharesPaid)
+
+		_, code = pay(orderID, equal(3, 1))
+		assert.Equal(t, http.StatusBadRequest, code)
+		closeOrder(t, token, orderID)
+	})
+
+	t.Run("Equal_Shares_Beyond_Count", func(t *testing.T) {
+		orderID := openOrder(t, token)
+		addItem(t, token, orderID, kebapID, 2)
+
+		_, code := pay(orderID, map[string]interface{}{"method": "CASH", "split_type": "EQUAL", "split_count": 2, "shares": 3})
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, int64(0), loadOrder(t, orderID).PaidAmount)
+		closeOrder(t, token, orderID)
+	})
+
+	t.Run("Items_Then_Amount", func(t *testing.T) {
+		orderID := openOrder(t, token)
+		kebap := addItem(t, token, orderID, kebapID, 2)
+		addItem(t, token, orderID, colaID, 2)
+
+		_, code := pay(orderID, map[string]interface{}{
+			"method":     "CREDIT_CARD",
+			"split_type": "ITEMS",
+			"items":      []map[string]interface{}{{"item_id": kebap.ID, "quantity": 1}},
+		})
+		require.Equal(t, http.StatusCreated, code)
+		order := loadOrder(t, orderID)
+		assert.Equal(t, int64(2500), order.PaidAmount)
+
+		// A unit cannot be paid twice
+		_, code = pay(orderID, map[string]interface{}{
+			"method":     "CASH",
+			"split_type": "ITEMS",
+			"items":      []map[string]interface{}{{"item_id": kebap.ID, "quantity": 2}},
+		})
+		assert.Equal(t, http.StatusBadRequest, code)
+
+		_, code = pay(orderID, map[string]interface{}{"method": "CASH", "split_type": "AMOUNT", "amount": 5000})
+		assert.Equal(t, http.StatusBadRequest, code, "amount above the remaining balance")
+
+		_, code = pay(orderID, map[string]interface{}{"method": "CASH", "split_type": "AMOUNT", "amount": 4500})
+		require.Equal(t, http.StatusCreated, code)
+
+		closeOrder(t, token, orderID)
+
+		order = loadOrder(t, orderID)
+		assert.Equal(t, "COMPLETED", order.Status)
+		assert.Equal(t, int64(7000), order.PaidAmount)
+		require.Len(t, order.Payments, 2)
+		assert.Equal(t, "CREDIT_CARD", order.Payments[0].Method)
+		assert.Equal(t, "CASH", order.Payments[1].Method)
+	})
+}
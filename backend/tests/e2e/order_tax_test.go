@@ -0,0 +1,118 @@
+package e2e
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"simple-pos/internal/models"
+	"simple-pos/internal/platform/database"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestE2E_TaxAllocation spreads a manual discount over three KDV rates in both pricing modes
+func TestE2E_TaxAllocation(t *testing.T) {
+	token := apiToken(t)
+	ensureWorkDay(t, token)
+
+	// Products of the category inherit its 10%, the others carry their own rate, 0% included
+	categoryID := createResource(t, token, "/api/v1/categories", map[string]interface{}{"name": "KDV Test", "tax_rate": 10})
+	foodID := createResource(t, token, "/api/v1/products", map[string]interface{}{"category_id": categoryID, "name": "KDV Yemek", "price": 11000})
+	alcoholID := createResource(t, token, "/api/v1/products", map[string]interface{}{"category_id": categoryID, "name": "KDV Bira", "price": 12000, "tax_rate": 20})
+	exemptID := createResource(t, token, "/api/v1/products", map[string]interface{}{"category_id": categoryID, "name": "KDV Muaf", "price": 5000, "tax_rate": 0})
+
+	// openDiscounted opens an order with one of each product and takes 10% off (2800 of 28000)
+	openDiscounted := func(t *testing.T) models.Order {
+		orderID := openOrder(t, token)
+		addItem(t, token, orderID, foodID, 1)
+		addItem(t, token, orderID, alcoholID, 1)
+		addItem(t, token, orderID, exemptID, 1)
+
+		payload := map[string]interface{}{"type": "PERCENTAGE", "value": 10, "reason": "KDV test"}
+		_, code := logAndRequest(t, "Apply Discount", "POST", fmt.Sprintf("/api/v1/orders/%d/discount", orderID), payload, token)
+		require.Equal(t, http.StatusOK, code)
+		return loadOrder(t, orderID)
+	}
+
+	// byRate returns the items keyed by their KDV snapshot
+	byRate := func(order models.Order) map[int]models.OrderItem {
+		items := make(map[int]models.OrderItem)
+		for _, item := range order.Items {
+			items[item.TaxRate] = item
+		}
+		return items
+	}
+
+	t.Run("Inclusive", func(t *testing.T) {
+		order := openDiscounted(t)
+		assert.Equal(t, models.TaxModeInclusive, order.TaxMode)
+		assert.Equal(t, int64(2800), order.DiscountAmount)
+		assert.Equal(t, int64(25200), order.TotalAmount)
+		assert.Equal(t, int64(2700), order.TaxAmount)
+
+		items := byRate(order)
+		require.Len(t, items, 3)
+		assert.Equal(t, int64(1100), items[10].DiscountAmount)
+		assert.Equal(t, int64(9000), items[10].NetAmount)
+		assert.Equal(t, int64(900), items[10].TaxAmount)
+		assert.Equal(t, int64(1200), items[20].DiscountAmount)
+		assert.Equal(t, int64(9000), items[20].NetAmount)
+		assert.Equal(t, int64(1800), items[20].TaxAmount)
+		assert.Equal(t, int64(500), items[0].DiscountAmount)
+		assert.Equal(t, int64(4500), items[0].NetAmount)
+		assert.Equal(t, int64(0), items[0].TaxAmount)
+		closeOrder(t, token, order.ID)
+	})
+
+	t.Run("Exclusive", func(t *testing.T) {
+		models.ConfigureTax(false, models.DefaultTaxRate)
+		defer models.ConfigureTax(true, models.DefaultTaxRate)
+
+		order := openDiscounted(t)
+		assert.Equal(t, models.TaxModeExclusive, order.TaxMode)
+		assert.Equal(t, int64(2800), order.DiscountAmount)
+		assert.Equal(t, int64(3150), order.TaxAmount)
+		assert.Equal(t, int64(28350), order.TotalAmount)
+
+		items := byRate(order)
+		require.Len(t, items, 3)
+		assert.Equal(t, int64(9900), items[10].NetAmount)
+		assert.Equal(t, int64(990), items[10].TaxAmount)
+		assert.Equal(t, int64(10800), items[20].NetAmount)
+		assert.Equal(t, int64(2160), items[20].TaxAmount)
+		assert.Equal(t, int64(4500), items[0].NetAmount)
+		assert.Equal(t, int64(0), items[0].TaxAmount)
+		closeOrder(t, token, order.ID)
+	})
+}
+
+// TestE2E_FreeItemKeepsTaxSnapshot moves part of a free line after its product changed, the copy keeps the rate it was sold at
+func TestE2E_FreeItemKeepsTaxSnapshot(t *testing.T) {
+	token := apiToken(t)
+	ensureWorkDay(t, token)
+
+	categoryID := createResource(t, token, "/api/v1/categories", map[string]interface{}{"name": "KDV Ikram", "tax_rate": 10})
+	productID := createResource(t, token, "/api/v1/products", map[string]interface{}{"category_id": categoryID, "name": "Ikram Cay", "price": 500})
+	require.NoError(t, database.DB.Model(&models.Product{}).Where("id = ?", productID).Update("price", 0).Error)
+
+	source := openOrder(t, token)
+	item := addItem(t, token, source, productID, 2)
+	require.Equal(t, 10, item.TaxRate)
+
+	require.NoError(t, database.DB.Model(&models.Product{}).Where("id = ?", productID).Update("tax_rate", 20).Error)
+
+	target := openOrder(t, token)
+	payload := map[string]interface{}{"target_order_id": target, "items": []map[string]interface{}{{"item_id": item.ID, "quantity": 1}}}
+	resp, code := logAndRequest(t, "Move Free Item", "POST", fmt.Sprintf("/api/v1/orders/%d/move-items", source), payload, token)
+	require.Equal(t, http.StatusOK, code, string(resp))
+
+	moved := loadOrder(t, target)
+	require.Len(t, moved.Items, 1)
+	assert.Equal(t, int64(0), moved.Items[0].UnitPrice, "a free line stays free")
+	assert.Equal(t, 10, moved.Items[0].TaxRate, "the rate it was sold at")
+
+	closeOrder(t, token, source)
+	closeOrder(t, token, target)
+}
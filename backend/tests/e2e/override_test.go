@@ -0,0 +1,99 @@
+package e2e
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"simple-pos/internal/models"
+	"simple-pos/internal/platform/database"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestE2E_OverridePinLockout locks a waiter out of an approver after repeated wrong PINs and audits every failure
+func TestE2E_OverridePinLockout(t *testing.T) {
+	token := apiToken(t)
+	ensureWorkDay(t, token)
+
+	createResource(t, token, "/api/v1/roles", map[string]interface{}{
+		"name":        "pin_waiter",
+		"permissions": []string{models.PermissionVoidItem, models.PermissionApplyDiscount},
+	})
+	waiterID := createResource(t, token, "/api/v1/users", map[string]interface{}{"name": "pinwaiter", "pin": "2468", "role": "pin_waiter"})
+	otherID := createResource(t, token, "/api/v1/users", map[string]interface{}{"name": "pinwaitertwo", "pin": "8642", "role": "pin_waiter"})
+	approverID := createResource(t, token, "/api/v1/users", map[string]interface{}{"name": "pinmanager", "pin": "1357", "role": models.RoleManager})
+
+	login := func(username, password string) string {
+		resp, code := logAndRequest(t, "Waiter Login", "POST", "/auth/login", map[string]interface{}{
+			"username": username, "password": password,
+		}, "")
+		require.Equal(t, http.StatusOK, code, string(resp))
+		var login map[string]interface{}
+		extractData(t, resp, &login)
+		return login["token"].(string)
+	}
+	waiterToken := login("pinwaiter", "2468")
+	otherToken := login("pinwaitertwo", "8642")
+
+	failures := func(actorID uint) int64 {
+		var count int64
+		require.NoError(t, database.DB.Model(&models.AuditLog{}).
+			Where("action = ? AND user_id = ? AND entity_id = ?", models.AuditActionOverrideFailed, actorID, approverID).Count(&count).Error)
+		return count
+	}
+	withPin := func(pin string) map[string]interface{} {
+		return map[string]interface{}{"approver_id": approverID, "pin": pin}
+	}
+
+	categoryID := createResource(t, token, "/api/v1/categories", map[string]interface{}{"name": "Override Test"})
+	productID := createResource(t, token, "/api/v1/products", map[string]interface{}{"category_id": categoryID, "name": "Override Tost", "price": 4000})
+
+	t.Run("Cancel", func(t *testing.T) {
+		orderID := openOrder(t, token)
+		addItem(t, token, orderID, productID, 1)
+		cancel := func(pin, actorToken string) int {
+			payload := map[string]interface{}{"override": withPin(pin)}
+			_, code := logAndRequest(t, "Cancel With PIN", "DELETE", fmt.Sprintf("/api/v1/orders/%d", orderID), payload, actorToken)
+			return code
+		}
+
+		for i := 1; i < 5; i++ {
+			require.Equal(t, http.StatusForbidden, cancel("0000", waiterToken), "attempt %d", i)
+		}
+		assert.Equal(t, http.StatusTooManyRequests, cancel("0000", waiterToken), "fifth wrong PIN locks the waiter out")
+		assert.Equal(t, http.StatusTooManyRequests, cancel("1357", waiterToken), "locked waiter is refused even with the right PIN")
+		assert.Equal(t, int64(5), failures(waiterID))
+
+		// The lockout is the waiter's, the manager still approves for everyone else
+		assert.Equal(t, http.StatusOK, cancel("1357", otherToken))
+	})
+
+	t.Run("Discount_Above_Threshold", func(t *testing.T) {
+		orderID := openOrder(t, token)
+		addItem(t, token, orderID, productID, 1)
+		discount := func(percent int, override map[string]interface{}) int {
+			payload := map[string]interface{}{"type": "PERCENTAGE", "value": percent, "reason": "Regular guest", "override": override}
+			_, code := logAndRequest(t, "Discount With PIN", "POST", fmt.Sprintf("/api/v1/orders/%d/discount", orderID), payload, otherToken)
+			return code
+		}
+
+		require.Equal(t, http.StatusOK, discount(10, nil), "up to the threshold needs no approval")
+		assert.Nil(t, loadOrder(t, orderID).DiscountApprovedBy)
+
+		assert.Equal(t, http.StatusForbidden, discount(25, nil))
+		// The wrong PIN rolls the discount back but its audit row stays
+		assert.Equal(t, http.StatusForbidden, discount(25, withPin("0000")))
+		assert.Equal(t, int64(1), failures(otherID))
+		assert.Equal(t, int64(400), loadOrder(t, orderID).DiscountAmount)
+
+		require.Equal(t, http.StatusOK, discount(25, withPin("1357")))
+		order := loadOrder(t, orderID)
+		assert.Equal(t, int64(1000), order.DiscountAmount)
+		require.NotNil(t, order.DiscountApprovedBy)
+		assert.Equal(t, approverID, *order.DiscountApprovedBy)
+
+		closeOrder(t, token, orderID)
+	})
+}
@@ -0,0 +1,144 @@
+package e2e
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"simple-pos/internal/platform/printing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// escposInit resets the printer and selects the PC857 code page, every document starts with it
+var escposInit = []byte{0x1b, '@', 0x1b, 't', 13}
+
+// escposCut is the partial cut that ends receipts and tickets
+var escposCut = []byte{0x1d, 'V', 66, 0}
+
+// rendered returns the bytes a document writes for the given lines, without the init sequence
+func rendered(build func(doc *printing.Document)) []byte {
+	doc := printing.NewDocument(cfg.PrinterWidth)
+	build(doc)
+	return bytes.TrimPrefix(doc.Bytes(), escposInit)
+}
+
+// TestE2E_EscPosDocument checks the raw bytes of the layout primitives
+func TestE2E_EscPosDocument(t *testing.T) {
+	doc := printing.NewDocument(12)
+	doc.Columns("Çay", "5,00")
+	doc.DoubleSize(true).Line("Köfteli dürüm").DoubleSize(false)
+	doc.Line("a\x07b\tc€")
+	doc.Columns("Uzun ürün adı", "10,00")
+
+	want := append([]byte{}, escposInit...)
+	want = append(want, 0x80, 'a', 'y', ' ', ' ', ' ', ' ', ' ', '5', ',', '0', '0', '\n')
+	want = append(want, 0x1d, '!', 0x11, 'K', 0x94, 'f', 't', 'e', 'l', '\n', 0x1d, '!', 0x00)
+	want = append(want, 'a', 'b', ' ', 'c', '?', '\n')
+	want = append(want, 'U', 'z', 'u', 'n', ' ', 0x81, 'r', 0x81, 'n', ' ', 'a', 'd', '\n')
+	want = append(want, ' ', ' ', ' ', ' ', ' ', ' ', ' ', '1', '0', ',', '0', '0', '\n')
+	assert.Equal(t, want, doc.Bytes())
+}
+
+// TestE2E_ReceiptAndKitchenPrinting renders a paid receipt and spools kitchen tickets to the station printer
+func TestE2E_ReceiptAndKitchenPrinting(t *testing.T) {
+	token := apiToken(t)
+	ensureWorkDay(t, token)
+
+	spool := t.TempDir()
+	_, code := logAndRequest(t, "Station With Bad Printer", "POST", "/api/v1/kitchen/stations", map[string]interface{}{"name": "Fiş Hatalı", "printer": "file://"}, token)
+	assert.Equal(t, http.StatusBadRequest, code)
+	stationID := createResource(t, token, "/api/v1/kitchen/stations", map[string]interface{}{"name": "Fiş Izgara", "printer": "file://" + spool})
+
+	categoryID := createResource(t, token, "/api/v1/categories", map[string]interface{}{"name": "Fiş Test", "tax_rate": 10, "station_id": stationID})
+	productID := createResource(t, token, "/api/v1/products", map[string]interface{}{"category_id": categoryID, "name": "Fiş Köfte", "price": 3000})
+	tableID := createResource(t, token, "/api/v1/tables", map[string]interface{}{"name": "Fiş Masa"})
+	orderID := openTableOrder(t, token, tableID)
+
+	resp, code := logAndRequest(t, "Add Item With Note", "POST", fmt.Sprintf("/api/v1/orders/%d/items", orderID), map[string]interface{}{"product_id": productID, "quantity": 2, "note": "az pişmiş"}, token)
+	require.Equal(t, http.StatusCreated, code, string(resp))
+
+	spooled := func(count int) [][]byte {
+		var jobs [][]byte
+		require.Eventually(t, func() bool {
+			files, _ := filepath.Glob(filepath.Join(spool, "*.bin"))
+			if len(files) < count {
+				return false
+			}
+			jobs = nil
+			for _, file := range files {
+				data, err := os.ReadFile(file)
+				require.NoError(t, err)
+				jobs = append(jobs, data)
+			}
+			return true
+		}, 2*time.Second, 50*time.Millisecond)
+		return jobs
+	}
+
+	t.Run("Kitchen_Ticket", func(t *testing.T) {
+		ticket := spooled(1)[0]
+		assert.True(t, bytes.HasPrefix(ticket, escposInit))
+		assert.True(t, bytes.HasSuffix(ticket, escposCut))
+		assert.Contains(t, string(ticket), string(rendered(func(d *printing.Document) { d.Line("Fiş Izgara") })))
+		assert.Contains(t, string(ticket), string(rendered(func(d *printing.Document) { d.Line("2 x Fiş Köfte") })))
+		assert.Contains(t, string(ticket), string(rendered(func(d *printing.Document) { d.Line("NOT: az pişmiş") })))
+
+		var ticketID uint
+		resp, code := logAndRequest(t, "List Tickets", "GET", fmt.Sprintf("/api/v1/kitchen/tickets?station_id=%d", stationID), nil, token)
+		require.Equal(t, http.StatusOK, code)
+		var tickets []struct {
+			ID uint `json:"id"`
+		}
+		extractData(t, resp, &tickets)
+		require.Len(t, tickets, 1)
+		ticketID = tickets[0].ID
+
+		_, code = logAndRequest(t, "Reprint Ticket", "POST", fmt.Sprintf("/api/v1/kitchen/tickets/%d/print", ticketID), nil, token)
+		require.Equal(t, http.StatusOK, code)
+		jobs := spooled(2)
+		assert.Equal(t, jobs[0], jobs[1], "the reprint is the same ticket")
+	})
+
+	t.Run("Receipt", func(t *testing.T) {
+		payload := map[string]interface{}{"type": "PERCENTAGE", "value": 10, "reason": "Fiş testi"}
+		_, code := logAndRequest(t, "Discount", "POST", fmt.Sprintf("/api/v1/orders/%d/discount", orderID), payload, token)
+		require.Equal(t, http.StatusOK, code)
+		closeOrder(t, token, orderID)
+
+		req, err := http.NewRequest("GET", fmt.Sprintf("%s/api/v1/orders/%d/receipt", baseURL, orderID), nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer "+token)
+		res, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, "application/octet-stream", res.Header.Get("Content-Type"))
+		receipt, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+
+		assert.True(t, bytes.HasPrefix(receipt, escposInit))
+		assert.True(t, bytes.HasSuffix(receipt, escposCut))
+		for _, lines := range []func(d *printing.Document){
+			func(d *printing.Document) { d.Columns("Masa", "Fiş Masa") },
+			func(d *printing.Document) { d.Columns("2 x Fiş Köfte", "60,00") },
+			func(d *printing.Document) { d.Line("  @ 30,00") },
+			func(d *printing.Document) { d.Columns("İndirim %10", "-6,00") },
+			func(d *printing.Document) { d.Columns("KDV %10", "4,91") },
+			func(d *printing.Document) { d.DoubleSize(true).Columns("TOPLAM", "54,00") },
+			func(d *printing.Document) { d.Columns("Nakit", "54,00") },
+		} {
+			want := rendered(lines)
+			assert.True(t, bytes.Contains(receipt, want), "receipt lacks %q", want)
+		}
+
+		_, code = logAndRequest(t, "Receipt Of Missing Order", "GET", "/api/v1/orders/999999/receipt", nil, token)
+		assert.Equal(t, http.StatusNotFound, code)
+	})
+}
@@ -0,0 +1,135 @@
+package e2e
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"simple-pos/internal/models"
+	"simple-pos/internal/platform/database"
+	"simple-pos/internal/repositories/gorm_repo"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestE2E_ProductStatsAfterRefund rebuilds the product stats of a closed period with a partial refund, a void and a cancelled order
+func TestE2E_ProductStatsAfterRefund(t *testing.T) {
+	token := apiToken(t)
+	ensureWorkDay(t, token)
+
+	categoryID := createResource(t, token, "/api/v1/categories", map[string]interface{}{"name": "Stat Test"})
+	toastID := createResource(t, token, "/api/v1/products", map[string]interface{}{"category_id": categoryID, "name": "Stat Tost", "price": 2000})
+	teaID := createResource(t, token, "/api/v1/products", map[string]interface{}{"category_id": categoryID, "name": "Stat Cay", "price": 1500})
+
+	refund := func(orderID uint, payload map[string]interface{}) {
+		resp, code := logAndRequest(t, "Refund Order", "POST", fmt.Sprintf("/api/v1/orders/%d/refunds", orderID), payload, token)
+		require.Equal(t, http.StatusCreated, code, string(resp))
+	}
+
+	// One toast of three comes back
+	partial := openOrder(t, token)
+	toast := addItem(t, token, partial, toastID, 3)
+	addItem(t, token, partial, teaID, 1)
+	closeOrder(t, token, partial)
+	refund(partial, map[string]interface{}{
+		"type":        "ITEMS",
+		"items":       []map[string]interface{}{{"item_id": toast.ID, "quantity": 1}},
+		"reason_code": "QUALITY",
+	})
+
+	// Both teas come back, the order stays in the stats as REFUNDED
+	voided := openOrder(t, token)
+	addItem(t, token, voided, teaID, 2)
+	closeOrder(t, token, voided)
+	refund(voided, map[string]interface{}{"type": "VOID", "reason_code": "CUSTOMER_COMPLAINT"})
+
+	// A cancelled order was never sold
+	cancelled := openOrder(t, token)
+	addItem(t, token, cancelled, toastID, 5)
+	_, code := logAndRequest(t, "Cancel Order", "DELETE", fmt.Sprintf("/api/v1/orders/%d", cancelled), nil, token)
+	require.Equal(t, http.StatusOK, code)
+
+	// The orders belong to a closed day of their own so no other test touches its stats
+	start := time.Date(2020, time.March, 14, 9, 0, 0, 0, time.Local)
+	end := start.Add(14 * time.Hour)
+	period := models.WorkPeriod{StartTime: start, EndTime: &end}
+	require.NoError(t, database.DB.Create(&period).Error)
+	// is_active defaults to true, a false zero value is not inserted
+	require.NoError(t, database.DB.Model(&period).Update("is_active", false).Error)
+	require.NoError(t, database.DB.Model(&models.Order{}).Where("id IN ?", []uint{partial, voided, cancelled}).
+		Update("work_period_id", period.ID).Error)
+
+	repo := gorm_repo.NewProductStatRepository(database.DB)
+	rebuild := func() map[uint]models.ProductSalesStat {
+		n, err := repo.RebuildForPeriodWithTx(database.DB, &period)
+		require.NoError(t, err)
+		assert.Equal(t, 2, n)
+
+		var rows []models.ProductSalesStat
+		require.NoError(t, database.DB.Where("work_period_id = ?", period.ID).Find(&rows).Error)
+		stats := make(map[uint]models.ProductSalesStat, len(rows))
+		for _, row := range rows {
+			stats[row.ProductID] = row
+		}
+		require.Len(t, stats, len(rows), "one row per product")
+		return stats
+	}
+
+	t.Run("Rebuild", func(t *testing.T) {
+		stats := rebuild()
+
+		toast := stats[toastID]
+		assert.Equal(t, "2020-03-14", toast.ReportDate)
+		assert.Equal(t, "Stat Test", toast.CategoryName)
+		assert.Equal(t, 3, toast.QuantitySold, "the cancelled toasts are left out")
+		assert.Equal(t, int64(6000), toast.TotalRevenue)
+		assert.Equal(t, 1, toast.RefundedQuantity)
+		assert.Equal(t, int64(2000), toast.RefundedAmount)
+
+		tea := stats[teaID]
+		assert.Equal(t, 3, tea.QuantitySold)
+		assert.Equal(t, int64(4500), tea.TotalRevenue)
+		assert.Equal(t, 2, tea.RefundedQuantity)
+		assert.Equal(t, int64(3000), tea.RefundedAmount)
+
+		// Running it again replaces the rows instead of adding to them
+		assert.Equal(t, stats[toastID].QuantitySold, rebuild()[toastID].QuantitySold)
+	})
+
+	query := "start_date=2020-03-14&end_date=2020-03-14"
+
+	t.Run("Trend", func(t *testing.T) {
+		resp, code := logAndRequest(t, "Product Trend", "GET", fmt.Sprintf("/api/v1/analytics/products/%d/trend?%s", toastID, query), nil, token)
+		require.Equal(t, http.StatusOK, code, string(resp))
+		var points []models.ProductTrendPoint
+		extractData(t, resp, &points)
+		require.Len(t, points, 1)
+		assert.Equal(t, "2020-03-14", points[0].ReportDate)
+		assert.Equal(t, int64(2), points[0].QuantitySold)
+		assert.Equal(t, int64(4000), points[0].NetRevenue)
+	})
+
+	t.Run("Ranking_And_Mix", func(t *testing.T) {
+		resp, code := logAndRequest(t, "Product Ranking", "GET", "/api/v1/analytics/products?metric=revenue&"+query, nil, token)
+		require.Equal(t, http.StatusOK, code, string(resp))
+		var ranking []models.ProductSalesSummary
+		extractData(t, resp, &ranking)
+		require.GreaterOrEqual(t, len(ranking), 2)
+		assert.Equal(t, toastID, ranking[0].ProductID, "net revenue ranks the toast first")
+		assert.Equal(t, int64(4000), ranking[0].NetRevenue)
+		assert.Equal(t, teaID, ranking[1].ProductID)
+		assert.Equal(t, int64(1), ranking[1].NetQuantity)
+
+		resp, code = logAndRequest(t, "Category Mix", "GET", "/api/v1/analytics/categories?"+query, nil, token)
+		require.Equal(t, http.StatusOK, code, string(resp))
+		var mix []models.CategorySalesMix
+		extractData(t, resp, &mix)
+		require.Len(t, mix, 1)
+		assert.Equal(t, categoryID, mix[0].CategoryID)
+		assert.Equal(t, int64(3), mix[0].QuantitySold)
+		assert.Equal(t, int64(5500), mix[0].NetRevenue)
+		assert.InDelta(t, 100, mix[0].RevenueShare, 0.01)
+	})
+}
@@ -0,0 +1,98 @@
+package e2e
+
+import (
+	"testing"
+	"time"
+
+	"simple-pos/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// promoItem builds an order item added at the given time
+func promoItem(productID uint, price int64, qty int, addedAt time.Time) models.OrderItem {
+	item := models.OrderItem{ProductID: productID, UnitPrice: price, Quantity: qty}
+	item.CreatedAt = addedAt
+	return item
+}
+
+// TestE2E_PromotionEngine runs ApplyPromotions directly on combo, buy-X-get-Y and happy hour rules
+func TestE2E_PromotionEngine(t *testing.T) {
+	uintPtr := func(v uint) *uint { return &v }
+	noon := time.Date(2026, 10, 17, 12, 0, 0, 0, time.Local)
+
+	t.Run("Combo", func(t *testing.T) {
+		const burger, fries, drink = 1, 2, 3
+		items := []models.OrderItem{
+			promoItem(burger, 30000, 1, noon),
+			promoItem(fries, 10000, 1, noon),
+			promoItem(drink, 5000, 2, noon),
+		}
+		combo := models.Promotion{
+			Name:       "Burger Menu",
+			Type:       models.PromotionCombo,
+			ComboPrice: 36000,
+			ComboItems: []models.PromotionComboItem{
+				{ProductID: burger, Quantity: 1},
+				{ProductID: fries, Quantity: 1},
+				{ProductID: drink, Quantity: 1},
+			},
+		}
+		combo.ID = 1
+
+		applied := models.ApplyPromotions(items, []models.Promotion{combo}, map[uint]uint{})
+		require.Len(t, applied, 1)
+		assert.Equal(t, 1, applied[0].Applications, "only one fries, so only one bundle")
+		assert.Equal(t, int64(9000), applied[0].Amount)
+
+		// The saving is split by price, the second drink pays full price
+		assert.Equal(t, int64(6000), items[0].PromotionAmount)
+		assert.Equal(t, int64(2000), items[1].PromotionAmount)
+		assert.Equal(t, int64(1000), items[2].PromotionAmount)
+	})
+
+	t.Run("Buy_X_Get_Y", func(t *testing.T) {
+		const hotDrinks = 10
+		items := []models.OrderItem{
+			promoItem(1, 1500, 2, noon), // Coffee
+			promoItem(2, 1000, 4, noon), // Tea
+		}
+		promo := models.Promotion{Name: "3 al 2 ode", Type: models.PromotionBuyXGetY, CategoryID: uintPtr(hotDrinks), Percent: 100, BuyQuantity: 2, GetQuantity: 1}
+		promo.ID = 2
+
+		applied := models.ApplyPromotions(items, []models.Promotion{promo}, map[uint]uint{1: hotDrinks, 2: hotDrinks})
+		require.Len(t, applied, 1)
+		assert.Equal(t, 2, applied[0].Applications)
+		assert.Equal(t, int64(2000), applied[0].Amount)
+
+		// The free unit of each group of three is its cheapest one
+		assert.Equal(t, int64(0), items[0].PromotionAmount)
+		assert.Equal(t, int64(2000), items[1].PromotionAmount)
+	})
+
+	t.Run("Happy_Hour_Across_Midnight", func(t *testing.T) {
+		const beers = 20
+		lateEvening := time.Date(2026, 10, 17, 23, 30, 0, 0, time.Local)
+		afterMidnight := time.Date(2026, 10, 18, 1, 30, 0, 0, time.Local)
+		closing := time.Date(2026, 10, 18, 2, 0, 0, 0, time.Local)
+		items := []models.OrderItem{
+			promoItem(1, 10000, 1, lateEvening),
+			promoItem(1, 10000, 1, afterMidnight),
+			promoItem(1, 10000, 1, closing),
+			promoItem(1, 10000, 1, noon),
+		}
+		happyHour := models.Promotion{Name: "Gece", Type: models.PromotionHappyHour, CategoryID: uintPtr(beers), Percent: 50, StartTime: "22:00", EndTime: "02:00"}
+		happyHour.ID = 3
+		allDay := models.Promotion{Name: "Bira Haftasi", Type: models.PromotionCategoryPercent, CategoryID: uintPtr(beers), Percent: 10}
+		allDay.ID = 4
+
+		applied := models.ApplyPromotions(items, []models.Promotion{happyHour, allDay}, map[uint]uint{1: beers})
+		require.Len(t, applied, 2)
+
+		assert.Equal(t, int64(5000), items[0].PromotionAmount, "before midnight, inside the window")
+		assert.Equal(t, int64(5000), items[1].PromotionAmount, "after midnight, inside the window")
+		assert.Equal(t, int64(1000), items[2].PromotionAmount, "the window end is exclusive")
+		assert.Equal(t, int64(1000), items[3].PromotionAmount, "outside the window the all day promotion applies")
+	})
+}
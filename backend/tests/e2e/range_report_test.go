@@ -0,0 +1,147 @@
+package e2e
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"simple-pos/internal/models"
+	"simple-pos/internal/platform/database"
+	"simple-pos/internal/services"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestE2E_RangeReport groups closed days of February 2022 by day, week and month and compares them with earlier ranges
+func TestE2E_RangeReport(t *testing.T) {
+	token := apiToken(t)
+	ensureWorkDay(t, token)
+
+	categoryID := createResource(t, token, "/api/v1/categories", map[string]interface{}{"name": "Range Test"})
+	productID := createResource(t, token, "/api/v1/products", map[string]interface{}{"category_id": categoryID, "name": "Range Simit", "price": 1000})
+
+	// closedDay books one cash sale on a closed work period started at 09:00 of the day
+	closedDay := func(year int, month time.Month, day, quantity int) uint {
+		start := time.Date(year, month, day, 9, 0, 0, 0, time.Local)
+		end := start.Add(12 * time.Hour)
+		period := models.WorkPeriod{StartTime: start, EndTime: &end}
+		require.NoError(t, database.DB.Create(&period).Error)
+		require.NoError(t, database.DB.Model(&period).Update("is_active", false).Error)
+
+		orderID := openOrder(t, token)
+		addItem(t, token, orderID, productID, quantity)
+		closeOrder(t, token, orderID)
+		require.NoError(t, database.DB.Model(&models.Order{}).Where("id = ?", orderID).Update("work_period_id", period.ID).Error)
+		require.NoError(t, database.DB.Model(&models.Payment{}).Where("order_id = ?", orderID).Update("work_period_id", period.ID).Error)
+		return period.ID
+	}
+
+	closedDay(2022, time.February, 7, 2)  // Monday
+	closedDay(2022, time.February, 9, 3)  // Wednesday of the same week
+	closedDay(2022, time.February, 15, 5) // Tuesday of the next week
+	closedDay(2022, time.January, 31, 4)  // The two weeks before
+	closedDay(2021, time.February, 10, 1) // A year before
+
+	rangeReport := func(query string) services.RangeReport {
+		resp, code := logAndRequest(t, "Range Report", "GET", "/api/v1/analytics/range?start_date=2022-02-07&end_date=2022-02-20&"+query, nil, token)
+		require.Equal(t, http.StatusOK, code, string(resp))
+		var report services.RangeReport
+		extractData(t, resp, &report)
+		return report
+	}
+	sales := func(buckets []models.DailyReport) map[string]int64 {
+		result := make(map[string]int64, len(buckets))
+		for _, bucket := range buckets {
+			result[bucket.ReportDate] = bucket.TotalSales
+		}
+		return result
+	}
+
+	t.Run("By_Day", func(t *testing.T) {
+		report := rangeReport("group_by=day&compare=none")
+		assert.Equal(t, "2022-02-07", report.StartDate)
+		assert.Equal(t, "2022-02-20", report.EndDate)
+		assert.Nil(t, report.Comparison)
+
+		require.Len(t, report.Buckets, 14, "days without sales are listed too")
+		assert.Equal(t, "2022-02-07", report.Buckets[0].ReportDate)
+		assert.Equal(t, int64(2000), report.Buckets[0].TotalSales)
+		assert.Equal(t, int64(0), report.Buckets[1].TotalSales)
+		assert.Equal(t, int64(3000), report.Buckets[2].TotalSales)
+		assert.Equal(t, int64(5000), report.Buckets[8].TotalSales)
+
+		assert.Equal(t, 3, report.Totals.TotalOrders)
+		assert.Equal(t, int64(10000), report.Totals.TotalSales)
+		assert.Equal(t, int64(10000), report.Totals.CashSales)
+	})
+
+	t.Run("By_Week_And_Month", func(t *testing.T) {
+		weekly := rangeReport("group_by=week&compare=none")
+		assert.Equal(t, map[string]int64{"2022-02-07": 5000, "2022-02-14": 5000}, sales(weekly.Buckets))
+
+		monthly := rangeReport("group_by=month&compare=none")
+		assert.Equal(t, map[string]int64{"2022-02-01": 10000}, sales(monthly.Buckets))
+		assert.Equal(t, weekly.Totals.TotalSales, monthly.Totals.TotalSales)
+	})
+
+	t.Run("Compare_Previous", func(t *testing.T) {
+		comparison := rangeReport("group_by=week").Comparison
+		require.NotNil(t, comparison, "previous is the default")
+		assert.Equal(t, services.ReportComparePrevious, comparison.Mode)
+		assert.Equal(t, "2022-01-24", comparison.StartDate)
+		assert.Equal(t, "2022-02-06", comparison.EndDate)
+		assert.Equal(t, int64(4000), comparison.Totals.TotalSales)
+
+		delta := comparison.Deltas["total_sales"]
+		assert.Equal(t, int64(10000), delta.Current)
+		assert.Equal(t, int64(4000), delta.Previous)
+		assert.Equal(t, int64(6000), delta.Change)
+		require.NotNil(t, delta.ChangePercent)
+		assert.InDelta(t, 150, *delta.ChangePercent, 0.01)
+
+		ticket := comparison.Deltas["average_ticket"]
+		assert.Equal(t, int64(3333), ticket.Current)
+		assert.Equal(t, int64(4000), ticket.Previous)
+	})
+
+	t.Run("Compare_Last_Year", func(t *testing.T) {
+		comparison := rangeReport("group_by=month&compare=last_year").Comparison
+		require.NotNil(t, comparison)
+		assert.Equal(t, "2021-02-07", comparison.StartDate)
+		assert.Equal(t, "2021-02-20", comparison.EndDate)
+		assert.Equal(t, int64(9000), comparison.Deltas["total_sales"].Change)
+		assert.Equal(t, int64(2), comparison.Deltas["total_orders"].Change)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		for _, query := range []string{"group_by=year", "compare=yesterday"} {
+			_, code := logAndRequest(t, "Invalid Range Report", "GET", "/api/v1/analytics/range?"+query, nil, token)
+			assert.Equal(t, http.StatusBadRequest, code, query)
+		}
+		_, code := logAndRequest(t, "Daily Range Too Long", "GET", "/api/v1/analytics/range?start_date=2020-01-01&end_date=2022-01-01&group_by=day", nil, token)
+		assert.Equal(t, http.StatusBadRequest, code)
+	})
+
+	t.Run("History_Paging", func(t *testing.T) {
+		var closed []models.WorkPeriod
+		require.NoError(t, database.DB.Where("is_active = ?", false).Order("end_time desc").Find(&closed).Error)
+		require.GreaterOrEqual(t, len(closed), 5)
+
+		var ids []uint
+		for page := 1; page <= 2; page++ {
+			header, body, code := download(t, token, "/api/v1/analytics/history?limit=2&page="+strconv.Itoa(page))
+			require.Equal(t, http.StatusOK, code, string(body))
+			assert.Equal(t, strconv.Itoa(len(closed)), header.Get("X-Total-Count"))
+
+			var periods []models.WorkPeriod
+			extractData(t, body, &periods)
+			require.Len(t, periods, 2)
+			for _, period := range periods {
+				ids = append(ids, period.ID)
+			}
+		}
+		assert.Equal(t, []uint{closed[0].ID, closed[1].ID, closed[2].ID, closed[3].ID}, ids, "newest first, pages do not overlap")
+	})
+}
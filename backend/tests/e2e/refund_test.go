@@ -0,0 +1,118 @@
+package e2e
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"simple-pos/internal/models"
+	"simple-pos/internal/platform/database"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestE2E_RefundWithRestock refunds a unit back into stock, then voids the rest without restocking
+func TestE2E_RefundWithRestock(t *testing.T) {
+	token := apiToken(t)
+	ensureWorkDay(t, token)
+
+	categoryID := createResource(t, token, "/api/v1/categories", map[string]interface{}{"name": "Refund Test"})
+	productID := createResource(t, token, "/api/v1/products", map[string]interface{}{"category_id": categoryID, "name": "Refund Tost", "price": 3000})
+	breadID := createResource(t, token, "/api/v1/inventory/items", map[string]interface{}{"name": "Refund Ekmek", "initial_quantity": 10})
+
+	recipe := map[string]interface{}{"items": []map[string]interface{}{{"stock_item_id": breadID, "quantity": 2}}}
+	_, code := logAndRequest(t, "Set Recipe", "PUT", fmt.Sprintf("/api/v1/products/%d/recipe", productID), recipe, token)
+	require.Equal(t, http.StatusOK, code)
+
+	stock := func() float64 {
+		var item models.StockItem
+		require.NoError(t, database.DB.First(&item, breadID).Error)
+		return item.Quantity
+	}
+	refund := func(orderID uint, payload map[string]interface{}) models.Refund {
+		resp, code := logAndRequest(t, "Refund Order", "POST", fmt.Sprintf("/api/v1/orders/%d/refunds", orderID), payload, token)
+		require.Equal(t, http.StatusCreated, code, string(resp))
+		var refund models.Refund
+		extractData(t, resp, &refund)
+		return refund
+	}
+
+	orderID := openOrder(t, token)
+	item := addItem(t, token, orderID, productID, 3)
+	_, code = logAndRequest(t, "Close Order", "POST", fmt.Sprintf("/api/v1/orders/%d/close", orderID), map[string]interface{}{"payment_method": "CASH"}, token)
+	require.Equal(t, http.StatusOK, code)
+	assert.InDelta(t, 4, stock(), 0.001, "three units used two breads each")
+
+	partial := refund(orderID, map[string]interface{}{
+		"type":        "ITEMS",
+		"items":       []map[string]interface{}{{"item_id": item.ID, "quantity": 1}},
+		"reason_code": "QUALITY",
+		"restock":     true,
+	})
+	assert.Equal(t, int64(3000), partial.Amount)
+	assert.Equal(t, "CASH", partial.PaymentMethod)
+	assert.InDelta(t, 6, stock(), 0.001, "the refunded unit went back to stock")
+
+	order := loadOrder(t, orderID)
+	assert.Equal(t, "COMPLETED", order.Status)
+	assert.Equal(t, int64(3000), order.RefundedAmount)
+
+	var reversal models.Transaction
+	require.NoError(t, database.DB.Where("refund_id = ?", partial.ID).First(&reversal).Error)
+	assert.Equal(t, int64(-3000), reversal.Amount)
+
+	// The void gives back the rest, thrown away food stays out of stock
+	void := refund(orderID, map[string]interface{}{"type": "VOID", "reason_code": "CUSTOMER_COMPLAINT"})
+	assert.Equal(t, int64(6000), void.Amount)
+	assert.InDelta(t, 6, stock(), 0.001)
+
+	order = loadOrder(t, orderID)
+	assert.Equal(t, "REFUNDED", order.Status)
+	assert.Equal(t, order.PaidAmount, order.RefundedAmount)
+
+	_, code = logAndRequest(t, "Refund Again", "POST", fmt.Sprintf("/api/v1/orders/%d/refunds", orderID), map[string]interface{}{"type": "VOID", "reason_code": "OTHER"}, token)
+	assert.Equal(t, http.StatusBadRequest, code)
+}
+
+// TestE2E_RestockAfterWastedRefund restocks a refund after one that threw its unit away, only the restocked unit comes back
+func TestE2E_RestockAfterWastedRefund(t *testing.T) {
+	token := apiToken(t)
+	ensureWorkDay(t, token)
+
+	categoryID := createResource(t, token, "/api/v1/categories", map[string]interface{}{"name": "Waste Test"})
+	productID := createResource(t, token, "/api/v1/products", map[string]interface{}{"category_id": categoryID, "name": "Waste Tost", "price": 3000})
+	breadID := createResource(t, token, "/api/v1/inventory/items", map[string]interface{}{"name": "Waste Ekmek", "initial_quantity": 10})
+
+	recipe := map[string]interface{}{"items": []map[string]interface{}{{"stock_item_id": breadID, "quantity": 2}}}
+	_, code := logAndRequest(t, "Set Recipe", "PUT", fmt.Sprintf("/api/v1/products/%d/recipe", productID), recipe, token)
+	require.Equal(t, http.StatusOK, code)
+
+	stock := func() float64 {
+		var item models.StockItem
+		require.NoError(t, database.DB.First(&item, breadID).Error)
+		return item.Quantity
+	}
+
+	orderID := openOrder(t, token)
+	item := addItem(t, token, orderID, productID, 3)
+	closeOrder(t, token, orderID)
+	require.InDelta(t, 4, stock(), 0.001)
+
+	refundOne := func(restock bool) {
+		payload := map[string]interface{}{
+			"type":        "ITEMS",
+			"items":       []map[string]interface{}{{"item_id": item.ID, "quantity": 1}},
+			"reason_code": "QUALITY",
+			"restock":     restock,
+		}
+		resp, code := logAndRequest(t, "Refund Unit", "POST", fmt.Sprintf("/api/v1/orders/%d/refunds", orderID), payload, token)
+		require.Equal(t, http.StatusCreated, code, string(resp))
+	}
+
+	refundOne(false)
+	assert.InDelta(t, 4, stock(), 0.001, "the wasted unit stays out of stock")
+
+	refundOne(true)
+	assert.InDelta(t, 6, stock(), 0.001, "only the restocked unit came back")
+}
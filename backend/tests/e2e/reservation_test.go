@@ -0,0 +1,120 @@
+package e2e
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"simple-pos/internal/models"
+	"simple-pos/internal/platform/database"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestE2E_Reservations refuses overlapping bookings, holds tables before the slot, releases no-shows and seats parties
+func TestE2E_Reservations(t *testing.T) {
+	token := apiToken(t)
+	ensureWorkDay(t, token)
+
+	terrace := createResource(t, token, "/api/v1/tables", map[string]interface{}{"name": "Rez Teras"})
+	window := createResource(t, token, "/api/v1/tables", map[string]interface{}{"name": "Rez Cam"})
+	corner := createResource(t, token, "/api/v1/tables", map[string]interface{}{"name": "Rez Köşe"})
+
+	now := time.Now()
+	slot := time.Duration(cfg.ReservationMinutes) * time.Minute
+	hold := time.Duration(cfg.ReservationHoldMinutes) * time.Minute
+	noShow := time.Duration(cfg.ReservationNoShowMinutes) * time.Minute
+
+	book := func(name string, start time.Time, tables ...uint) ([]byte, int) {
+		payload := map[string]interface{}{"guest_name": name, "party_size": 4, "start_time": start.Format(time.RFC3339), "table_ids": tables}
+		return logAndRequest(t, "Book "+name, "POST", "/api/v1/reservations", payload, token)
+	}
+	booked := func(name string, start time.Time, tables ...uint) uint {
+		resp, code := book(name, start, tables...)
+		require.Equal(t, http.StatusCreated, code, string(resp))
+		var reservation models.Reservation
+		extractData(t, resp, &reservation)
+		return reservation.ID
+	}
+	tableStatus := func(tableID uint) string {
+		var table models.Table
+		require.NoError(t, database.DB.First(&table, tableID).Error)
+		return table.Status
+	}
+	reservationStatus := func(id uint) string {
+		var reservation models.Reservation
+		require.NoError(t, database.DB.First(&reservation, id).Error)
+		return reservation.Status
+	}
+
+	soon := now.Add(hold / 2)
+	ayse := booked("Ayşe", soon, terrace)
+	assert.Equal(t, models.TableStatusReserved, tableStatus(terrace), "inside the hold window")
+
+	t.Run("Overlap", func(t *testing.T) {
+		_, code := book("Mehmet", soon.Add(slot/2), terrace, window)
+		assert.Equal(t, http.StatusConflict, code)
+
+		// Back to back is not an overlap
+		later := booked("Mehmet", soon.Add(slot), terrace)
+		late := booked("Zeynep", soon.Add(2*slot), window)
+		assert.Equal(t, models.TableStatusAvailable, tableStatus(window), "outside the hold window")
+
+		payload := map[string]interface{}{"guest_name": "Zeynep", "party_size": 2, "start_time": soon.Add(slot / 2).Format(time.RFC3339), "table_ids": []uint{terrace}}
+		_, code = logAndRequest(t, "Move Onto Ayşe", "PUT", fmt.Sprintf("/api/v1/reservations/%d", late), payload, token)
+		assert.Equal(t, http.StatusConflict, code)
+
+		for _, id := range []uint{later, late} {
+			_, code := logAndRequest(t, "Cancel Reservation", "DELETE", fmt.Sprintf("/api/v1/reservations/%d", id), nil, token)
+			require.Equal(t, http.StatusOK, code)
+		}
+	})
+
+	t.Run("No_Show", func(t *testing.T) {
+		_, code := logAndRequest(t, "Mark No Show", "POST", fmt.Sprintf("/api/v1/reservations/%d/no-show", ayse), nil, token)
+		require.Equal(t, http.StatusOK, code)
+		assert.Equal(t, models.ReservationStatusNoShow, reservationStatus(ayse))
+		assert.Equal(t, models.TableStatusAvailable, tableStatus(terrace))
+
+		_, code = logAndRequest(t, "Cancel Released", "DELETE", fmt.Sprintf("/api/v1/reservations/%d", ayse), nil, token)
+		assert.Equal(t, http.StatusBadRequest, code, "only booked reservations change")
+
+		// A party late beyond the no-show window is released by the next sync
+		ali := booked("Ali", soon, corner)
+		require.Equal(t, models.TableStatusReserved, tableStatus(corner))
+		past := now.Add(-noShow - time.Minute)
+		require.NoError(t, database.DB.Model(&models.Reservation{}).Where("id = ?", ali).
+			Updates(map[string]interface{}{"start_time": past.UTC(), "end_time": past.Add(slot).UTC()}).Error)
+
+		trigger := booked("Tetik", soon.Add(3*slot), window)
+		assert.Equal(t, models.ReservationStatusNoShow, reservationStatus(ali))
+		assert.Equal(t, models.TableStatusAvailable, tableStatus(corner))
+		_, code = logAndRequest(t, "Cancel Trigger", "DELETE", fmt.Sprintf("/api/v1/reservations/%d", trigger), nil, token)
+		require.Equal(t, http.StatusOK, code)
+	})
+
+	t.Run("Seat", func(t *testing.T) {
+		party := booked("Kalabalık", soon, terrace, window)
+		require.Equal(t, models.TableStatusReserved, tableStatus(window))
+
+		waitOrderSecond()
+		resp, code := logAndRequest(t, "Seat Party", "POST", fmt.Sprintf("/api/v1/reservations/%d/seat", party), map[string]interface{}{"table_id": window}, token)
+		require.Equal(t, http.StatusCreated, code, string(resp))
+		var seated struct {
+			Reservation models.Reservation `json:"reservation"`
+			Order       models.Order       `json:"order"`
+		}
+		extractData(t, resp, &seated)
+
+		assert.Equal(t, models.ReservationStatusSeated, seated.Reservation.Status)
+		require.NotNil(t, seated.Order.TableID)
+		assert.Equal(t, window, *seated.Order.TableID)
+		assert.Equal(t, "Reservation: Kalabalık", seated.Order.Note)
+		assert.Equal(t, models.TableStatusOccupied, tableStatus(window))
+		assert.Equal(t, models.TableStatusAvailable, tableStatus(terrace), "the other table is released")
+
+		closeOrder(t, token, seated.Order.ID)
+	})
+}
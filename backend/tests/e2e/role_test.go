@@ -0,0 +1,61 @@
+package e2e
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"simple-pos/internal/models"
+	"simple-pos/pkg/utils"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestE2E_RoleChangesApplyImmediately checks that role edits and deactivation reach tokens already issued
+func TestE2E_RoleChangesApplyImmediately(t *testing.T) {
+	token := apiToken(t)
+
+	roleID := createResource(t, token, "/api/v1/roles", map[string]interface{}{
+		"name":        "kitchen_lead",
+		"permissions": []string{models.PermissionManageKitchen},
+	})
+	userID := createResource(t, token, "/api/v1/users", map[string]interface{}{
+		"name": "kitchenlead", "pin": "4321", "role": "kitchen_lead",
+	})
+
+	resp, code := logAndRequest(t, "Kitchen Lead Login", "POST", "/auth/login", map[string]interface{}{
+		"username": "kitchenlead", "password": "4321",
+	}, "")
+	require.Equal(t, http.StatusOK, code, string(resp))
+	var login map[string]interface{}
+	extractData(t, resp, &login)
+	leadToken := login["token"].(string)
+
+	// The token lists the permissions for the UI, the server still asks the role
+	claims, err := utils.ParseToken(leadToken)
+	require.NoError(t, err)
+	require.Equal(t, []string{models.PermissionManageKitchen}, claims.Permissions)
+
+	_, code = logAndRequest(t, "Stats With Permission", "GET", "/api/v1/kitchen/stats", nil, leadToken)
+	require.Equal(t, http.StatusOK, code)
+
+	t.Run("Permission_Removed", func(t *testing.T) {
+		_, code := logAndRequest(t, "Drop Permission", "PUT", fmt.Sprintf("/api/v1/roles/%d", roleID), map[string]interface{}{
+			"permissions": []string{models.PermissionViewReports},
+		}, token)
+		require.Equal(t, http.StatusOK, code)
+
+		_, code = logAndRequest(t, "Stats Without Permission", "GET", "/api/v1/kitchen/stats", nil, leadToken)
+		require.Equal(t, http.StatusForbidden, code)
+	})
+
+	t.Run("User_Deactivated", func(t *testing.T) {
+		_, code := logAndRequest(t, "Deactivate User", "PUT", fmt.Sprintf("/api/v1/users/%d", userID), map[string]interface{}{
+			"name": "kitchenlead", "role": "kitchen_lead", "is_active": false,
+		}, token)
+		require.Equal(t, http.StatusOK, code)
+
+		_, code = logAndRequest(t, "Request After Deactivation", "GET", "/api/v1/orders", nil, leadToken)
+		require.Equal(t, http.StatusUnauthorized, code)
+	})
+}
@@ -0,0 +1,118 @@
+package e2e
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"simple-pos/internal/models"
+	"simple-pos/internal/platform/database"
+	"simple-pos/internal/services"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestE2E_SalesHeatmapAndWaiters moves a few orders into a past week and reads them back by hour, weekday and waiter
+func TestE2E_SalesHeatmapAndWaiters(t *testing.T) {
+	token := apiToken(t)
+	ensureWorkDay(t, token)
+
+	firstID := createResource(t, token, "/api/v1/users", map[string]interface{}{"name": "heatwaiter", "pin": "4826", "role": models.RoleWaiter})
+	secondID := createResource(t, token, "/api/v1/users", map[string]interface{}{"name": "heatwaitertwo", "pin": "6284", "role": models.RoleWaiter})
+	categoryID := createResource(t, token, "/api/v1/categories", map[string]interface{}{"name": "Heatmap Test"})
+	productID := createResource(t, token, "/api/v1/products", map[string]interface{}{"category_id": categoryID, "name": "Heatmap Pide", "price": 2000})
+
+	open := func(waiterID uint, quantity int) uint {
+		waitOrderSecond()
+		orderID := createResource(t, token, "/api/v1/orders", map[string]interface{}{"waiter_id": waiterID})
+		addItem(t, token, orderID, productID, quantity)
+		return orderID
+	}
+	// openedAt puts the order at a local time of the week of 14 June 2021, a Monday
+	openedAt := func(orderID uint, day, hour, minute int) {
+		at := time.Date(2021, time.June, day, hour, minute, 0, 0, time.Local)
+		require.NoError(t, database.DB.Unscoped().Model(&models.Order{}).Where("id = ?", orderID).Update("created_at", at).Error)
+	}
+
+	// Tuesday 13:20, 4000
+	lunch := open(firstID, 2)
+	closeOrder(t, token, lunch)
+	openedAt(lunch, 15, 13, 20)
+
+	// Tuesday 13:50, 2000 less 10%, refunded in full
+	discounted := open(firstID, 1)
+	_, code := logAndRequest(t, "Apply Discount", "POST", fmt.Sprintf("/api/v1/orders/%d/discount", discounted),
+		map[string]interface{}{"type": "PERCENTAGE", "value": 10, "reason": "Heatmap"}, token)
+	require.Equal(t, http.StatusOK, code)
+	closeOrder(t, token, discounted)
+	_, code = logAndRequest(t, "Refund Order", "POST", fmt.Sprintf("/api/v1/orders/%d/refunds", discounted),
+		map[string]interface{}{"type": "VOID", "reason_code": "CUSTOMER_COMPLAINT"}, token)
+	require.Equal(t, http.StatusCreated, code)
+	openedAt(discounted, 15, 13, 50)
+
+	// Sunday 19:05, 6000
+	dinner := open(secondID, 3)
+	closeOrder(t, token, dinner)
+	openedAt(dinner, 20, 19, 5)
+
+	// Wednesday 10:00, cancelled while open
+	cancelled := open(secondID, 1)
+	_, code = logAndRequest(t, "Cancel Order", "DELETE", fmt.Sprintf("/api/v1/orders/%d", cancelled), nil, token)
+	require.Equal(t, http.StatusOK, code)
+	openedAt(cancelled, 16, 10, 0)
+
+	query := "?start_date=2021-06-14&end_date=2021-06-20"
+
+	t.Run("Heatmap", func(t *testing.T) {
+		resp, code := logAndRequest(t, "Sales Heatmap", "GET", "/api/v1/analytics/heatmap"+query, nil, token)
+		require.Equal(t, http.StatusOK, code, string(resp))
+		var heatmap services.SalesHeatmap
+		extractData(t, resp, &heatmap)
+		require.Len(t, heatmap.ByHour, 24)
+		require.Len(t, heatmap.ByWeekday, 7)
+		require.Len(t, heatmap.Cells, 7*24)
+
+		assert.Equal(t, services.SalesBucket{Hour: intPtr(13), Orders: 2, Sales: 5800, AverageTicket: 2900}, heatmap.ByHour[13])
+		assert.Equal(t, services.SalesBucket{Hour: intPtr(19), Orders: 1, Sales: 6000, AverageTicket: 6000}, heatmap.ByHour[19])
+		assert.Equal(t, int64(0), heatmap.ByHour[10].Orders, "the cancelled order is not a sale")
+
+		assert.Equal(t, int64(2), heatmap.ByWeekday[int(time.Tuesday)].Orders)
+		assert.Equal(t, int64(6000), heatmap.ByWeekday[int(time.Sunday)].Sales)
+
+		tuesdayLunch := heatmap.Cells[int(time.Tuesday)*24+13]
+		require.NotNil(t, tuesdayLunch.DayOfWeek)
+		require.NotNil(t, tuesdayLunch.Hour)
+		assert.Equal(t, int(time.Tuesday), *tuesdayLunch.DayOfWeek)
+		assert.Equal(t, 13, *tuesdayLunch.Hour)
+		assert.Equal(t, int64(5800), tuesdayLunch.Sales)
+
+		var orders int64
+		for _, cell := range heatmap.Cells {
+			orders += cell.Orders
+		}
+		assert.Equal(t, int64(3), orders)
+	})
+
+	t.Run("Waiters", func(t *testing.T) {
+		resp, code := logAndRequest(t, "Waiter Performance", "GET", "/api/v1/analytics/waiters"+query, nil, token)
+		require.Equal(t, http.StatusOK, code, string(resp))
+		var waiters []services.WaiterPerformance
+		extractData(t, resp, &waiters)
+		require.Len(t, waiters, 2)
+
+		// Highest net revenue first
+		assert.Equal(t, services.WaiterPerformance{
+			WaiterID: secondID, WaiterName: "heatwaitertwo", Orders: 1, Revenue: 6000, NetRevenue: 6000, AverageTicket: 6000, Cancellations: 1,
+		}, waiters[0])
+		assert.Equal(t, services.WaiterPerformance{
+			WaiterID: firstID, WaiterName: "heatwaiter", Orders: 2, Revenue: 5800, Refunds: 1800, NetRevenue: 4000, AverageTicket: 2900,
+			Discounts: 1, DiscountAmount: 200,
+		}, waiters[1])
+	})
+}
+
+func intPtr(v int) *int {
+	return &v
+}
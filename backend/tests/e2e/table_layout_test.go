@@ -0,0 +1,121 @@
+package e2e
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"simple-pos/internal/models"
+	"simple-pos/internal/platform/database"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestE2E_FloorPlanLayout saves a floor plan in one request and reads it back grouped by section
+func TestE2E_FloorPlanLayout(t *testing.T) {
+	token := apiToken(t)
+
+	// The terrace is created first but sorts before the garden
+	gardenID := createResource(t, token, "/api/v1/sections", map[string]interface{}{"name": "Plan Bahce", "sort_order": 20})
+	terraceID := createResource(t, token, "/api/v1/sections", map[string]interface{}{"name": "Plan Teras", "sort_order": 10})
+	firstID := createResource(t, token, "/api/v1/tables", map[string]interface{}{"name": "Plan 1", "section_id": gardenID})
+	secondID := createResource(t, token, "/api/v1/tables", map[string]interface{}{
+		"name": "Plan 2", "section_id": gardenID, "layout": map[string]interface{}{"pos_x": 10, "pos_y": 10, "shape": "round", "seats": 2},
+	})
+
+	loadTable := func(id uint) models.Table {
+		var table models.Table
+		require.NoError(t, database.DB.First(&table, id).Error)
+		return table
+	}
+	saveLayout := func(tables ...map[string]interface{}) ([]byte, int) {
+		return logAndRequest(t, "Save Layout", "PUT", "/api/v1/tables/layout", map[string]interface{}{"tables": tables}, token)
+	}
+
+	t.Run("Defaults", func(t *testing.T) {
+		table := loadTable(firstID)
+		assert.Equal(t, models.TableShapeSquare, table.Shape)
+		assert.Equal(t, 4, table.Seats)
+		assert.Equal(t, "Plan Bahce", table.Section, "the name is kept for older clients")
+	})
+
+	t.Run("Bulk_Save", func(t *testing.T) {
+		resp, code := saveLayout(
+			map[string]interface{}{"table_id": firstID, "section_id": terraceID, "pos_x": 120, "pos_y": 40, "shape": "rectangle", "seats": 6, "rotation": -90},
+			map[string]interface{}{"table_id": secondID, "pos_x": 200, "pos_y": 80, "shape": "round", "seats": 2},
+		)
+		require.Equal(t, http.StatusOK, code, string(resp))
+
+		first := loadTable(firstID)
+		require.NotNil(t, first.SectionID)
+		assert.Equal(t, terraceID, *first.SectionID)
+		assert.Equal(t, "Plan Teras", first.Section)
+		assert.Equal(t, 120, first.PosX)
+		assert.Equal(t, 40, first.PosY)
+		assert.Equal(t, models.TableShapeRectangle, first.Shape)
+		assert.Equal(t, 6, first.Seats)
+		assert.Equal(t, 270, first.Rotation, "rotation is normalised to 0-359")
+
+		second := loadTable(secondID)
+		require.NotNil(t, second.SectionID)
+		assert.Equal(t, gardenID, *second.SectionID, "no section keeps the current one")
+		assert.Equal(t, 200, second.PosX)
+	})
+
+	t.Run("All_Or_Nothing", func(t *testing.T) {
+		_, code := saveLayout(
+			map[string]interface{}{"table_id": secondID, "pos_x": 999, "pos_y": 999},
+			map[string]interface{}{"table_id": 999999, "pos_x": 1, "pos_y": 1},
+		)
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, 200, loadTable(secondID).PosX, "the valid table is not moved either")
+
+		_, code = saveLayout(map[string]interface{}{"table_id": secondID, "shape": "hexagon"})
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, "round", loadTable(secondID).Shape)
+	})
+
+	t.Run("Grouped_By_Section", func(t *testing.T) {
+		resp, code := logAndRequest(t, "List Tables By Section", "GET", "/api/v1/tables?group=section", nil, token)
+		require.Equal(t, http.StatusOK, code, string(resp))
+
+		var groups []struct {
+			Section *models.Section `json:"section"`
+			Tables  []models.Table  `json:"tables"`
+		}
+		extractData(t, resp, &groups)
+
+		position := make(map[uint]int)
+		for i, group := range groups {
+			if group.Section != nil {
+				position[group.Section.ID] = i
+			}
+		}
+		require.Contains(t, position, gardenID)
+		require.Contains(t, position, terraceID)
+		assert.Less(t, position[terraceID], position[gardenID], "sections follow their sort order")
+
+		names := func(tables []models.Table) []string {
+			result := make([]string, 0, len(tables))
+			for _, table := range tables {
+				result = append(result, table.Name)
+			}
+			return result
+		}
+		assert.Equal(t, []string{"Plan 1"}, names(groups[position[terraceID]].Tables))
+		assert.Equal(t, []string{"Plan 2"}, names(groups[position[gardenID]].Tables))
+	})
+
+	t.Run("Delete_Section", func(t *testing.T) {
+		path := fmt.Sprintf("/api/v1/sections/%d", gardenID)
+		_, code := logAndRequest(t, "Delete Section With Tables", "DELETE", path, nil, token)
+		assert.Equal(t, http.StatusBadRequest, code, "a section with tables cannot be deleted")
+
+		resp, code := saveLayout(map[string]interface{}{"table_id": secondID, "section_id": terraceID, "pos_x": 200, "pos_y": 80, "shape": "round", "seats": 2})
+		require.Equal(t, http.StatusOK, code, string(resp))
+
+		_, code = logAndRequest(t, "Delete Empty Section", "DELETE", path, nil, token)
+		assert.Equal(t, http.StatusOK, code)
+	})
+}
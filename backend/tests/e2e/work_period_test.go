@@ -0,0 +1,125 @@
+package e2e
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"simple-pos/internal/models"
+	"simple-pos/internal/platform/database"
+	"simple-pos/internal/platform/events"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestE2E_EndDayCashVariance reconciles the drawer on day close.
+// It closes the day the other tests shared and runs in a day of its own,
+// so the drawer holds nothing but its own movements.
+func TestE2E_EndDayCashVariance(t *testing.T) {
+	token := apiToken(t)
+	ensureWorkDay(t, token)
+
+	// Every test settles its own orders, an open one here is a leak
+	var open []models.Order
+	require.NoError(t, database.DB.Where("status = ?", "OPEN").Find(&open).Error)
+	for _, order := range open {
+		t.Errorf("order #%s was left open", order.OrderNumber)
+	}
+	if len(open) > 0 {
+		t.FailNow()
+	}
+
+	// The shared day is closed in the DB, the management routes allow only five calls a minute
+	require.NoError(t, database.DB.Model(&models.WorkPeriod{}).Where("is_active = ?", true).
+		Updates(map[string]interface{}{"is_active": false, "end_time": time.Now()}).Error)
+	ensureWorkDay(t, token)
+
+	drawer := func() models.CashDrawer {
+		resp, code := logAndRequest(t, "Active Day Report", "GET", "/api/v1/analytics/daily?scope=active", nil, token)
+		require.Equal(t, http.StatusOK, code)
+		var report models.DailyReport
+		extractData(t, resp, &report)
+		require.NotNil(t, report.CashDrawer)
+		return *report.CashDrawer
+	}
+	require.Equal(t, int64(0), drawer().ExpectedCash, "the day starts with an empty drawer")
+
+	categoryID := createResource(t, token, "/api/v1/categories", map[string]interface{}{"name": "Drawer Test"})
+	productID := createResource(t, token, "/api/v1/products", map[string]interface{}{"category_id": categoryID, "name": "Drawer Tost", "price": 5000})
+
+	// A cash sale counts, a card sale does not
+	cashOrder := openOrder(t, token)
+	addItem(t, token, cashOrder, productID, 1)
+	_, code := logAndRequest(t, "Close Cash Order", "POST", fmt.Sprintf("/api/v1/orders/%d/close", cashOrder), map[string]interface{}{"payment_method": "CASH"}, token)
+	require.Equal(t, http.StatusOK, code)
+
+	cardOrder := openOrder(t, token)
+	addItem(t, token, cardOrder, productID, 1)
+	_, code = logAndRequest(t, "Close Card Order", "POST", fmt.Sprintf("/api/v1/orders/%d/close", cardOrder), map[string]interface{}{"payment_method": "CREDIT_CARD"}, token)
+	require.Equal(t, http.StatusOK, code)
+
+	for _, movement := range []map[string]interface{}{
+		{"type": "CASH_IN", "amount": 2000, "reason": "Bozuk para"},
+		{"type": "DROP", "amount": 4000, "reason": "Kasadan alım"},
+		{"type": "PAYOUT", "amount": 1000, "reason": "Kurye"},
+	} {
+		createResource(t, token, "/api/v1/cash-drawer/movements", movement)
+	}
+	createResource(t, token, "/api/v1/transactions/expense", map[string]interface{}{
+		"amount":         1500,
+		"description":    "Ekmek",
+		"category":       "Market",
+		"payment_method": "CASH",
+	})
+
+	after := drawer()
+	assert.Equal(t, int64(5000), after.CashSales)
+	assert.Equal(t, int64(2000), after.CashIn)
+	assert.Equal(t, int64(4000), after.CashDrops)
+	assert.Equal(t, int64(1000), after.Payouts)
+	assert.Equal(t, int64(1500), after.CashExpenses)
+	// 5000 + 2000 - 4000 - 1000 - 1500
+	require.Equal(t, int64(500), after.ExpectedCash)
+
+	// Everyone hears the day ended, only report viewers get the report with it
+	_, waiterToken := staffToken(t, token, "drawerwaiter", "2718", models.RoleWaiter)
+	managerStream := openStream(t, token, events.DayEnded)
+	waiterStream := openStream(t, waiterToken, events.DayEnded)
+
+	// The blind count comes up 100 short
+	counted := after.ExpectedCash - 100
+	resp, code := logAndRequest(t, "End Work Day", "POST", "/api/v1/management/end-day", map[string]interface{}{"user_id": 1, "counted_cash": counted}, token)
+	require.Equal(t, http.StatusOK, code, string(resp))
+
+	var result struct {
+		Report models.DailyReport `json:"report"`
+	}
+	extractData(t, resp, &result)
+	require.NotNil(t, result.Report.CashDrawer)
+	assert.Equal(t, after.ExpectedCash, result.Report.CashDrawer.ExpectedCash)
+	require.NotNil(t, result.Report.CashDrawer.Variance)
+	assert.Equal(t, int64(-100), *result.Report.CashDrawer.Variance)
+
+	dayEnded := func(stream <-chan streamEvent) map[string]json.RawMessage {
+		event, ok := nextEvent(stream)
+		require.True(t, ok)
+		require.Equal(t, events.DayEnded, event.Type)
+		var data map[string]json.RawMessage
+		require.NoError(t, json.Unmarshal(event.Data, &data))
+		_, again := nextEvent(stream)
+		assert.False(t, again, "one day_ended per client")
+		return data
+	}
+	assert.Contains(t, dayEnded(managerStream), "report")
+	assert.NotContains(t, dayEnded(waiterStream), "report")
+
+	var period models.WorkPeriod
+	require.NoError(t, database.DB.Order("id desc").First(&period).Error)
+	assert.False(t, period.IsActive)
+	assert.Equal(t, after.ExpectedCash, period.ExpectedCash)
+	require.NotNil(t, period.CashVariance)
+	assert.Equal(t, int64(-100), *period.CashVariance)
+}